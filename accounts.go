@@ -4,11 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"strings"
-
-	"github.com/google/go-querystring/query"
-	"github.com/shopspring/decimal"
+	"time"
 )
 
 type Accounts []*Account
@@ -20,15 +20,15 @@ type Account struct {
 type _Instrument Instrument
 
 type Instrument struct {
-	AssetType string `json:"assetType"`
+	AssetType AssetType `json:"assetType"`
 	Data      interface{}
 }
 
 type OptionDeliverable struct {
-	Symbol           string  `json:"symbol"`
-	DeliverableUnits float64 `json:"deliverableUnits"`
-	CurrencyType     string  `json:"currencyType"`
-	AssetType        string  `json:"assetType"`
+	Symbol           string    `json:"symbol"`
+	DeliverableUnits float64   `json:"deliverableUnits"`
+	CurrencyType     string    `json:"currencyType"`
+	AssetType        AssetType `json:"assetType"`
 }
 
 type OptionA struct {
@@ -36,7 +36,7 @@ type OptionA struct {
 	Symbol             string               `json:"symbol"`
 	Description        string               `json:"description,omitempty"`
 	Type               string               `json:"type,omitempty"`
-	PutCall            string               `json:"putCall,omitempty"`
+	PutCall            PutCall              `json:"putCall,omitempty"`
 	UnderlyingSymbol   string               `json:"underlyingSymbol,omitempty"`
 	OptionMultiplier   float64              `json:"optionMultiplier,omitempty"`
 	OptionDeliverables []*OptionDeliverable `json:"optionDeliverables,omitempty"`
@@ -72,77 +72,74 @@ type FixedIncome struct {
 }
 
 type SecuritiesAccount struct {
-	Type                    string  `json:"type"`
-	AccountID               string  `json:"accountId"`
-	RoundTrips              float64 `json:"roundTrips"`
-	IsDayTrader             bool    `json:"isDayTrader"`
-	IsClosingOnlyRestricted bool    `json:"isClosingOnlyRestricted"`
-	Positions               []struct {
-		ShortQuantity                  float64    `json:"shortQuantity"`
-		AveragePrice                   float64    `json:"averagePrice"`
-		CurrentDayProfitLoss           float64    `json:"currentDayProfitLoss"`
-		CurrentDayProfitLossPercentage float64    `json:"currentDayProfitLossPercentage"`
-		LongQuantity                   float64    `json:"longQuantity"`
-		SettledLongQuantity            float64    `json:"settledLongQuantity"`
-		SettledShortQuantity           float64    `json:"settledShortQuantity"`
-		AgedQuantity                   float64    `json:"agedQuantity"`
-		Instrument                     Instrument `json:"instrument"`
-		MarketValue                    float64    `json:"marketValue"`
-	} `json:"positions"`
-	OrderStrategies []struct {
-		Session    string `json:"session"`
-		Duration   string `json:"duration"`
-		OrderType  string `json:"orderType"`
-		CancelTime struct {
-			Date        string `json:"date"`
-			ShortFormat bool   `json:"shortFormat"`
-		} `json:"cancelTime"`
-		ComplexOrderStrategyType string  `json:"complexOrderStrategyType"`
-		Quantity                 float64 `json:"quantity"`
-		FilledQuantity           float64 `json:"filledQuantity"`
-		RemainingQuantity        float64 `json:"remainingQuantity"`
-		RequestedDestination     string  `json:"requestedDestination"`
-		DestinationLinkName      string  `json:"destinationLinkName"`
-		ReleaseTime              string  `json:"releaseTime"`
-		StopPrice                float64 `json:"stopPrice"`
-		StopPriceLinkBasis       string  `json:"stopPriceLinkBasis"`
-		StopPriceLinkType        string  `json:"stopPriceLinkType"`
-		StopPriceOffset          float64 `json:"stopPriceOffset"`
-		StopType                 string  `json:"stopType"`
-		PriceLinkBasis           string  `json:"priceLinkBasis"`
-		PriceLinkType            string  `json:"priceLinkType"`
-		Price                    float64 `json:"price"`
-		TaxLotMethod             string  `json:"taxLotMethod"`
-		OrderLegCollection       []struct {
-			OrderLegType   string  `json:"orderLegType"`
-			LegID          int64   `json:"legId"`
-			Instrument     string  `json:"instrument"`
-			Instruction    string  `json:"instruction"`
-			PositionEffect string  `json:"positionEffect"`
-			Quantity       float64 `json:"quantity"`
-			QuantityType   string  `json:"quantityType"`
-		} `json:"orderLegCollection"`
-		ActivationPrice          float64  `json:"activationPrice"`
-		SpecialInstruction       string   `json:"specialInstruction"`
-		OrderStrategyType        string   `json:"orderStrategyType"`
-		OrderID                  int64    `json:"orderId"`
-		Cancelable               bool     `json:"cancelable"`
-		Editable                 bool     `json:"editable"`
-		Status                   string   `json:"status"`
-		EnteredTime              string   `json:"enteredTime"`
-		CloseTime                string   `json:"closeTime"`
-		Tag                      string   `json:"tag"`
-		AccountID                int64    `json:"accountId"`
-		OrderActivityCollection  []string `json:"orderActivityCollection"`
-		ReplacingOrderCollection []struct {
-		} `json:"replacingOrderCollection"`
-		ChildOrderStrategies []struct {
-		} `json:"childOrderStrategies"`
-		StatusDescription string `json:"statusDescription"`
-	} `json:"orderStrategies"`
-	InitialBalances   Balance `json:"initialBalances"`
-	CurrentBalances   Balance `json:"currentBalances"`
-	ProjectedBalances Balance `json:"projectedBalances"`
+	Type                    string     `json:"type"`
+	AccountID               string     `json:"accountId"`
+	RoundTrips              float64    `json:"roundTrips"`
+	IsDayTrader             bool       `json:"isDayTrader"`
+	IsClosingOnlyRestricted bool       `json:"isClosingOnlyRestricted"`
+	Positions               []Position `json:"positions"`
+	OrderStrategies         []Order    `json:"orderStrategies"`
+	InitialBalances         Balance    `json:"initialBalances"`
+	CurrentBalances         Balance    `json:"currentBalances"`
+	ProjectedBalances       Balance    `json:"projectedBalances"`
+}
+
+// Position is a holding in a securities account.
+type Position struct {
+	ShortQuantity                  float64    `json:"shortQuantity"`
+	AveragePrice                   float64    `json:"averagePrice"`
+	CurrentDayProfitLoss           float64    `json:"currentDayProfitLoss"`
+	CurrentDayProfitLossPercentage float64    `json:"currentDayProfitLossPercentage"`
+	LongQuantity                   float64    `json:"longQuantity"`
+	SettledLongQuantity            float64    `json:"settledLongQuantity"`
+	SettledShortQuantity           float64    `json:"settledShortQuantity"`
+	AgedQuantity                   float64    `json:"agedQuantity"`
+	Instrument                     Instrument `json:"instrument"`
+	MarketValue                    float64    `json:"marketValue"`
+
+	// AcquiredDate is when the position was opened, used by DaysHeld.
+	// TD Ameritrade does not return it, so it must be set by the caller, e.g. from the position's transaction history.
+	AcquiredDate time.Time `json:"-"`
+}
+
+// multiplier returns the number of shares per unit of the position: the option multiplier for options, 1 otherwise.
+func (p *Position) multiplier() float64 {
+	if option, ok := p.Instrument.Data.(*OptionA); ok {
+		if option.OptionMultiplier != 0 {
+			return option.OptionMultiplier
+		}
+		return 100
+	}
+	return 1
+}
+
+// costBasis returns what the position cost to open, negative for short positions.
+func (p *Position) costBasis() float64 {
+	return p.AveragePrice * (p.LongQuantity - p.ShortQuantity) * p.multiplier()
+}
+
+// UnrealizedPnL returns the profit or loss of the position if it were closed at its market value.
+// It is (current price - AveragePrice) * (LongQuantity - ShortQuantity) * multiplier, with the current price
+// implied by MarketValue.
+func (p *Position) UnrealizedPnL() float64 {
+	return p.MarketValue - p.costBasis()
+}
+
+// UnrealizedPnLPercent returns UnrealizedPnL as a percentage of the cost of the position, or 0 if it cost nothing.
+func (p *Position) UnrealizedPnLPercent() float64 {
+	cost := math.Abs(p.costBasis())
+	if cost == 0 {
+		return 0
+	}
+	return p.UnrealizedPnL() / cost * 100
+}
+
+// DaysHeld returns the number of whole days since AcquiredDate, or 0 if AcquiredDate is not set.
+func (p *Position) DaysHeld() int {
+	if p.AcquiredDate.IsZero() {
+		return 0
+	}
+	return int(time.Since(p.AcquiredDate).Hours() / 24)
 }
 
 type Balance struct {
@@ -166,86 +163,163 @@ type Balance struct {
 	BondValue                    float64 `json:"bondValue"`
 	CashDebitCallValue           float64 `json:"cashDebitCallValue"`
 	UnsettledCash                float64 `json:"unsettledCash"`
+
+	// The following balances are only returned for margin accounts.
+	AvailableFunds                float64 `json:"availableFunds"`
+	BuyingPower                   float64 `json:"buyingPower"`
+	BuyingPowerNonMarginableTrade float64 `json:"buyingPowerNonMarginableTrade"`
+	DayTradingBuyingPower         float64 `json:"dayTradingBuyingPower"`
+	Equity                        float64 `json:"equity"`
+	MaintenanceRequirement        float64 `json:"maintenanceRequirement"`
+	MarginBalance                 float64 `json:"marginBalance"`
+	OptionBuyingPower             float64 `json:"optionBuyingPower"`
+	StockBuyingPower              float64 `json:"stockBuyingPower"`
+
+	// MaintenanceCall is the amount needed to meet the maintenance requirement, see IsInMarginCall.
+	MaintenanceCall float64 `json:"maintenanceCall"`
+}
+
+// isMargin reports whether the account trades on margin, in which case its balances include buying power.
+func (a *Account) isMargin() bool {
+	return a.Type == "MARGIN"
+}
+
+// NetLiquidation returns the value of the account if all of its positions were closed.
+// It is TD Ameritrade's liquidation value when present, otherwise the market value of the positions plus the cash balance.
+func (a *Account) NetLiquidation() float64 {
+	if v := a.CurrentBalances.LiquidationValue; v != 0 {
+		return v
+	}
+
+	total := a.CurrentBalances.CashBalance
+	for _, p := range a.Positions {
+		total += p.MarketValue
+	}
+	return total
+}
+
+// EquityBuyingPower returns the amount available to buy stock: cash available for trading in cash accounts
+// and buying power, which includes margin, in margin accounts.
+func (a *Account) EquityBuyingPower() float64 {
+	if a.isMargin() {
+		return a.CurrentBalances.BuyingPower
+	}
+	return a.CurrentBalances.CashAvailableForTrading
+}
+
+// OptionBuyingPower returns the amount available to buy options, which cannot be bought on margin:
+// cash available for trading in cash accounts and option buying power in margin accounts.
+func (a *Account) OptionBuyingPower() float64 {
+	if a.isMargin() {
+		return a.CurrentBalances.OptionBuyingPower
+	}
+	return a.CurrentBalances.CashAvailableForTrading
+}
+
+// DayTradingBuyingPower returns the amount available to open and close positions on the same day:
+// day trading buying power in margin accounts and, as cash accounts can only day trade with settled funds,
+// cash available for trading in cash accounts.
+func (a *Account) DayTradingBuyingPower() float64 {
+	if a.isMargin() {
+		return a.CurrentBalances.DayTradingBuyingPower
+	}
+	return a.CurrentBalances.CashAvailableForTrading
+}
+
+// Limits of the pattern day trader rule, which applies to margin accounts.
+const (
+	// maxRoundTrips is the number of day trades an account not flagged as a pattern day trader may make in five business days.
+	maxRoundTrips = 3
+	// dayTraderMinimumEquity is the equity an account flagged as a pattern day trader must have to day trade.
+	dayTraderMinimumEquity = 25000
+)
+
+// UnlimitedDayTrades is returned by RemainingDayTrades for accounts the pattern day trader rule does not limit.
+const UnlimitedDayTrades = -1
+
+// GetPositionBySymbol returns the position in symbol, and whether the account has one.
+// The account must have been fetched with the "positions" field.
+func (a *Account) GetPositionBySymbol(symbol string) (*Position, bool) {
+	for i := range a.Positions {
+		if a.Positions[i].Instrument.symbol() == symbol {
+			return &a.Positions[i], true
+		}
+	}
+	return nil, false
 }
 
-type OrderLegCollection struct {
-	OrderLegType   string     `json:"orderLegType,omitempty"`
-	LegID          int        `json:"legId,omitempty"`
-	Instrument     Instrument `json:"instrument"`
-	Instruction    string     `json:"instruction"`
-	PositionEffect string     `json:"positionEffect,omitempty"`
-	Quantity       float64    `json:"quantity"`
-	QuantityType   string     `json:"quantityType,omitempty"`
-}
-
-type CancelTime struct {
-	Date        string `json:"date,omitempty"`
-	ShortFormat bool   `json:"shortFormat,omitempty"`
-}
-
-type Orders []*Order
-
-//Per TD TDAmeritrade documentation, CancelTime should be a struct...
-// cancelTime": {
-//     "date": "string",
-//     "shortFormat": false
-//   }
-
-//However, the actual response is simply a string: YYYY-MM-DD
-//This will only apply to orders that are a limit order where the expiry is set.
-type Order struct {
-	Session                  string                `json:"session"`
-	Duration                 string                `json:"duration"`
-	OrderType                string                `json:"orderType"`
-	CancelTime               string                `json:"cancelTime,omitempty"`
-	ComplexOrderStrategyType string                `json:"complexOrderStrategyType,omitempty"`
-	Quantity                 float64               `json:"quantity,omitempty"`
-	FilledQuantity           float64               `json:"filledQuantity,omitempty"`
-	RemainingQuantity        float64               `json:"remainingQuantity,omitempty"`
-	RequestedDestination     string                `json:"requestedDestination,omitempty"`
-	DestinationLinkName      string                `json:"destinationLinkName,omitempty"`
-	ReleaseTime              string                `json:"releaseTime,omitempty"`
-	StopPrice                float64               `json:"stopPrice,omitempty"`
-	StopPriceLinkBasis       string                `json:"stopPriceLinkBasis,omitempty"`
-	StopPriceLinkType        string                `json:"stopPriceLinkType,omitempty"`
-	StopPriceOffset          float64               `json:"stopPriceOffset,omitempty"`
-	StopType                 string                `json:"stopType,omitempty"`
-	PriceLinkBasis           string                `json:"priceLinkBasis,omitempty"`
-	PriceLinkType            string                `json:"priceLinkType,omitempty"`
-	Price                    decimal.Decimal       `json:"price,omitempty"`
-	TaxLotMethod             string                `json:"taxLotMethod,omitempty"`
-	OrderLegCollection       []*OrderLegCollection `json:"orderLegCollection"`
-	ActivationPrice          float64               `json:"activationPrice,omitempty"`
-	SpecialInstruction       string                `json:"specialInstruction,omitempty"`
-	OrderStrategyType        string                `json:"orderStrategyType"`
-	OrderID                  int64                 `json:"orderId,omitempty"`
-	Cancelable               bool                  `json:"cancelable,omitempty"`
-	Editable                 bool                  `json:"editable,omitempty"`
-	Status                   string                `json:"status,omitempty"`
-	EnteredTime              string                `json:"enteredTime,omitempty"`
-	CloseTime                string                `json:"closeTime,omitempty"`
-	Tag                      string                `json:"tag,omitempty"`
-	AccountID                float64               `json:"accountId,omitempty"`
-	OrderActivityCollection  []*Execution          `json:"orderActivityCollection,omitempty"`
-	ReplacingOrderCollection []*Order              `json:"replacingOrderCollection,omitempty"`
-	ChildOrderStrategies     []*Order              `json:"childOrderStrategies,omitempty"`
-	StatusDescription        string                `json:"statusDescription,omitempty"`
-}
-
-type ExecutionLeg struct {
-	LegID             int64   `json:"legId"`
-	Quantity          float64 `json:"quantity"`
-	MismarkedQuantity float64 `json:"mismarkedQuantity"`
-	Price             float64 `json:"price"`
-	Time              string  `json:"time"`
-}
-
-type Execution struct {
-	ActivityType           string          `json:"activityType"`  //"'EXECUTION' or 'ORDER_ACTION'",
-	ExecutionType          string          `json:"executionType"` //"'FILL'",
-	Quantity               float64         `json:"quantity"`
-	OrderRemainingQuantity float64         `json:"orderRemainingQuantity"`
-	ExecutionLegs          []*ExecutionLeg `json:"executionLegs"`
+// GetPositionsByAssetType returns the positions in instruments of assetType, such as EQUITY or OPTION.
+func (a *Account) GetPositionsByAssetType(assetType AssetType) []*Position {
+	var positions []*Position
+	for i := range a.Positions {
+		if a.Positions[i].Instrument.AssetType == assetType {
+			positions = append(positions, &a.Positions[i])
+		}
+	}
+	return positions
+}
+
+// TotalUnrealizedPnL returns the sum of the UnrealizedPnL of the account's positions.
+func (a *Account) TotalUnrealizedPnL() float64 {
+	total := 0.0
+	for i := range a.Positions {
+		total += a.Positions[i].UnrealizedPnL()
+	}
+	return total
+}
+
+// HasPattern reports whether TD Ameritrade's pattern day trader flag on the account is dayTrader.
+func (a *Account) HasPattern(dayTrader bool) bool {
+	return a.IsDayTrader == dayTrader
+}
+
+// RemainingDayTrades returns the number of day trades the account may still make under the pattern day trader rule.
+// Margin accounts not flagged as pattern day traders may make 3 in five business days, so it returns 3 - RoundTrips,
+// and at least 0. Flagged accounts may not day trade with less than $25,000 of equity, and may make any number
+// of day trades otherwise. Cash accounts are not subject to the rule, although they may only trade with settled funds.
+// It returns UnlimitedDayTrades for accounts the rule does not limit.
+func (a *Account) RemainingDayTrades() int {
+	if !a.isMargin() {
+		return UnlimitedDayTrades
+	}
+	if a.IsDayTrader {
+		if a.CurrentBalances.Equity < dayTraderMinimumEquity {
+			return 0
+		}
+		return UnlimitedDayTrades
+	}
+	if remaining := maxRoundTrips - int(a.RoundTrips); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// MarginUtilization returns the share of the margin account's equity already used, 1 - BuyingPower / Equity,
+// from the current buying power and the equity at the start of the day. It is 0 for cash accounts and accounts
+// without equity.
+func (a *Account) MarginUtilization() float64 {
+	if !a.isMargin() || a.InitialBalances.Equity == 0 {
+		return 0
+	}
+	return 1 - a.CurrentBalances.BuyingPower/a.InitialBalances.Equity
+}
+
+// IsInMarginCall reports whether the account has a maintenance call to meet, which TD Ameritrade may meet
+// by liquidating positions if the account does not.
+func (a *Account) IsInMarginCall() bool {
+	return a.CurrentBalances.MaintenanceCall > 0
+}
+
+// DaysToCoverMarginCall returns the number of days the account would take to cover its maintenance call
+// making dailyPnL a day. It is 0 without a call and +Inf if dailyPnL is not a profit.
+func (a *Account) DaysToCoverMarginCall(dailyPnL float64) float64 {
+	if !a.IsInMarginCall() {
+		return 0
+	}
+	if dailyPnL <= 0 {
+		return math.Inf(1)
+	}
+	return a.CurrentBalances.MaintenanceCall / dailyPnL
 }
 
 // AccountsService handles communication with the account related methods of
@@ -256,19 +330,6 @@ type AccountsService struct {
 	client *Client
 }
 
-type AccountOptions struct {
-	Position bool
-	Orders   bool
-}
-
-type OrderParams struct {
-	AccountId  string `url:"accountId"`
-	MaxResults int    `url:"maxResults,omitempty"`
-	From       string `url:"fromEnteredTime,omitempty"`
-	To         string `url:"toEnteredTime,omitempty"`
-	Status     string `url:"status,omitempty"`
-}
-
 func (i *Instrument) UnmarshalJSON(bs []byte) (err error) {
 	instrument := _Instrument{}
 
@@ -297,11 +358,28 @@ func (i *Instrument) UnmarshalJSON(bs []byte) (err error) {
 	return err
 }
 
+// symbol returns the symbol of the instrument, or "" if it has no data.
+func (i *Instrument) symbol() string {
+	switch data := i.Data.(type) {
+	case *Equity:
+		return data.Symbol
+	case *OptionA:
+		return data.Symbol
+	case *MutualFund:
+		return data.Symbol
+	case *CashEquivalent:
+		return data.Symbol
+	case *FixedIncome:
+		return data.Symbol
+	}
+	return ""
+}
+
 func (i *Instrument) MarshalJSON() ([]byte, error) {
 	switch data := i.Data.(type) {
 	case *Equity:
 		return json.Marshal(&struct {
-			AssetType string `json:"assetType"`
+			AssetType AssetType `json:"assetType"`
 			*Equity
 		}{
 			AssetType: i.AssetType,
@@ -309,7 +387,7 @@ func (i *Instrument) MarshalJSON() ([]byte, error) {
 		})
 	case *OptionA:
 		return json.Marshal(&struct {
-			AssetType string `json:"assetType"`
+			AssetType AssetType `json:"assetType"`
 			*OptionA
 		}{
 			AssetType: i.AssetType,
@@ -317,7 +395,7 @@ func (i *Instrument) MarshalJSON() ([]byte, error) {
 		})
 	case *MutualFund:
 		return json.Marshal(&struct {
-			AssetType string `json:"assetType"`
+			AssetType AssetType `json:"assetType"`
 			*MutualFund
 		}{
 			AssetType:  i.AssetType,
@@ -325,7 +403,7 @@ func (i *Instrument) MarshalJSON() ([]byte, error) {
 		})
 	case *CashEquivalent:
 		return json.Marshal(&struct {
-			AssetType string `json:"assetType"`
+			AssetType AssetType `json:"assetType"`
 			*CashEquivalent
 		}{
 			AssetType:      i.AssetType,
@@ -333,7 +411,7 @@ func (i *Instrument) MarshalJSON() ([]byte, error) {
 		})
 	case *FixedIncome:
 		return json.Marshal(&struct {
-			AssetType string `json:"assetType"`
+			AssetType AssetType `json:"assetType"`
 			*FixedIncome
 		}{
 			AssetType:   i.AssetType,
@@ -344,46 +422,40 @@ func (i *Instrument) MarshalJSON() ([]byte, error) {
 	}
 }
 
-func (s *AccountsService) GetAccounts(ctx context.Context, opts *AccountOptions) (*Accounts, *Response, error) {
+// GetAccounts returns all of the user's linked accounts.
+// Valid values for `fields` are "positions" and "orders"; balances are always returned.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/get/accounts-0
+func (s *AccountsService) GetAccounts(ctx context.Context, fields []string) ([]*Account, *Response, error) {
 	u := "accounts"
-	if opts != nil {
-		if opts.Position {
-			u = fmt.Sprintf("%s?fields=%s", u, "positions")
-		}
-		if opts.Orders {
-			u = fmt.Sprintf("%s,%s", u, "orders")
-		}
+	if len(fields) > 0 {
+		u = fmt.Sprintf("%s?fields=%s", u, url.QueryEscape(strings.Join(fields, ",")))
 	}
 	req, err := s.client.NewRequest("GET", u, nil)
-
 	if err != nil {
 		return nil, nil, err
-
 	}
-	accounts := new(Accounts)
-	resp, err := s.client.Do(ctx, req, accounts)
+
+	accounts := Accounts{}
+	resp, err := s.client.Do(ctx, req, &accounts)
 	if err != nil {
 		return nil, resp, err
 	}
 	return accounts, resp, err
 }
 
-func (s *AccountsService) GetAccount(ctx context.Context, accountID string, opts *AccountOptions) (*Account, *Response, error) {
+// GetAccount returns a single account.
+// Valid values for `fields` are "positions" and "orders"; balances are always returned.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/get/accounts/%7BaccountId%7D-0
+func (s *AccountsService) GetAccount(ctx context.Context, accountID string, fields []string) (*Account, *Response, error) {
 	u := fmt.Sprintf("accounts/%s", accountID)
-	if opts != nil {
-		if opts.Position {
-			u = fmt.Sprintf("%s?fields=%s", u, "positions")
-		}
-		if opts.Orders {
-			u = fmt.Sprintf("%s,%s", u, "orders")
-		}
+	if len(fields) > 0 {
+		u = fmt.Sprintf("%s?fields=%s", u, url.QueryEscape(strings.Join(fields, ",")))
 	}
 	req, err := s.client.NewRequest("GET", u, nil)
-
 	if err != nil {
 		return nil, nil, err
-
 	}
+
 	account := new(Account)
 	resp, err := s.client.Do(ctx, req, account)
 	if err != nil {
@@ -392,137 +464,125 @@ func (s *AccountsService) GetAccount(ctx context.Context, accountID string, opts
 	return account, resp, err
 }
 
-func (s *AccountsService) PlaceOrder(ctx context.Context, accountID string, order *Order) (*Response, error) {
-	u := fmt.Sprintf("accounts/%s/orders", accountID)
-	if order == nil {
-		return nil, fmt.Errorf("order is nil")
-	}
-
-	req, err := s.client.NewRequest("POST", u, order)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	return s.client.Do(ctx, req, nil)
+// AccountOptions selects the fields returned with accounts.
+//
+// Deprecated: pass the fields to GetAccounts or GetAccount, e.g. with AccountOptions.Fields.
+type AccountOptions struct {
+	Position bool
+	Orders   bool
 }
 
-func (s *AccountsService) CancelOrder(ctx context.Context, accountID, orderID string) (*Response, error) {
-	u := fmt.Sprintf("accounts/%s/orders/%s", accountID, orderID)
-	req, err := s.client.NewRequest("DELETE", u, nil)
-	if err != nil {
-		return nil, err
+// Fields returns the fields selected by o, to pass to GetAccounts or GetAccount.
+func (o *AccountOptions) Fields() []string {
+	var fields []string
+	if o == nil {
+		return fields
+	}
+	if o.Position {
+		fields = append(fields, "positions")
 	}
-	return s.client.Do(ctx, req, nil)
+	if o.Orders {
+		fields = append(fields, "orders")
+	}
+	return fields
 }
 
-func (s *AccountsService) ReplaceOrder(ctx context.Context, accountID string, orderID string, order *Order) (*Response, error) {
-	u := fmt.Sprintf("accounts/%s/orders/%s", accountID, orderID)
-	if order == nil {
-		return nil, fmt.Errorf("order is nil")
-	}
+// OrderParams filters the orders returned by TD Ameritrade.
+//
+// Deprecated: use OrderQueryParams.
+type OrderParams = OrderQueryParams
 
-	req, err := s.client.NewRequest("PUT", u, order)
-	if err != nil {
-		return nil, err
-	}
-	return s.client.Do(ctx, req, nil)
+// PlaceOrder places an order for an account.
+//
+// Deprecated: use OrdersService.PlaceOrder.
+func (s *AccountsService) PlaceOrder(ctx context.Context, accountID string, order *Order) (*Response, error) {
+	return s.client.Orders.PlaceOrder(ctx, accountID, order)
+}
+
+// CancelOrder cancels an open order of an account.
+//
+// Deprecated: use OrdersService.CancelOrder.
+func (s *AccountsService) CancelOrder(ctx context.Context, accountID, orderID string) (*Response, error) {
+	return s.client.Orders.CancelOrder(ctx, accountID, orderID)
 }
 
+// GetOrder requests an order of an account.
+//
+// Deprecated: use OrdersService.GetOrder, which returns the order.
 func (s *AccountsService) GetOrder(ctx context.Context, accountID, orderID string) (*Response, error) {
-	u := fmt.Sprintf("accounts/%s/orders/%s", accountID, orderID)
-	req, err := s.client.NewRequest("GET", u, nil)
-	if err != nil {
-		return nil, err
-	}
-	return s.client.Do(ctx, req, nil)
+	_, resp, err := s.client.Orders.GetOrder(ctx, accountID, orderID)
+	return resp, err
 }
 
+// GetOrderByPath returns the orders of an account matching orderParams.
+//
+// Deprecated: use OrdersService.GetOrdersByAccount.
 func (s *AccountsService) GetOrderByPath(ctx context.Context, accountID string, orderParams *OrderParams) (*Orders, *Response, error) {
-	u := fmt.Sprintf("accounts/%s/orders", accountID)
-	req, err := s.client.NewRequest("GET", u, nil)
-	if err != nil {
-		return nil, nil, err
+	var params OrderQueryParams
+	if orderParams != nil {
+		params = *orderParams
 	}
-	ords := new(Orders)
-	resp, err := s.client.Do(ctx, req, ords)
+	orders, resp, err := s.client.Orders.GetOrdersByAccount(ctx, accountID, params)
 	if err != nil {
 		return nil, resp, err
 	}
+	ords := Orders(orders)
+	return &ords, resp, nil
+}
 
-	return ords, resp, nil
+// ReplaceOrder replaces an open order of an account.
+//
+// Deprecated: use OrdersService.ReplaceOrder.
+func (s *AccountsService) ReplaceOrder(ctx context.Context, accountID string, orderID string, order *Order) (*Response, error) {
+	return s.client.Orders.ReplaceOrder(ctx, accountID, orderID, order)
 }
 
-func (s *AccountsService) GetOrdersByQuery(ctx context.Context, orderParams *OrderParams) (*Orders, *Response, error) {
-	u := fmt.Sprintf("orders")
+// GetOrdersByQuery returns the orders of all of the caller's accounts matching orderParams.
+//
+// Deprecated: use OrdersService.GetOrdersByQuery.
+func (s *AccountsService) GetOrdersByQuery(ctx context.Context, orderParams *OrderQueryParams) (*Orders, *Response, error) {
+	var params OrderQueryParams
 	if orderParams != nil {
-		q, err := query.Values(orderParams)
-		if err != nil {
-			return nil, nil, err
-		}
-		u = fmt.Sprintf("%s?%s", u, q.Encode())
+		params = *orderParams
 	}
-	req, err := s.client.NewRequest("GET", u, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	ords := new(Orders)
-	resp, err := s.client.Do(ctx, req, ords)
+	orders, resp, err := s.client.Orders.GetOrdersByQuery(ctx, params)
 	if err != nil {
 		return nil, resp, err
 	}
-
-	return ords, resp, nil
-
+	ords := Orders(orders)
+	return &ords, resp, nil
 }
 
+// CreateSavedOrder saves an order for an account.
+//
+// Deprecated: use SavedOrdersService.CreateSavedOrder.
 func (s *AccountsService) CreateSavedOrder(ctx context.Context, accountID string, order *Order) (*Response, error) {
-	u := fmt.Sprintf("accounts/%s/savedorders", accountID)
-	if order == nil {
-		return nil, fmt.Errorf("order is nil")
-	}
-
-	req, err := s.client.NewRequest("POST", u, order)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	return s.client.Do(ctx, req, nil)
-}
-
-func (s *AccountsService) DeleteSavedOrder(ctx context.Context, accountID, savedOrderID string) (*Response, error) {
-	u := fmt.Sprintf("accounts/%s/savedorders/%s", accountID, savedOrderID)
-	req, err := s.client.NewRequest("DELETE", u, nil)
-	if err != nil {
-		return nil, err
-	}
-	return s.client.Do(ctx, req, nil)
+	return s.client.SavedOrders.CreateSavedOrder(ctx, accountID, order)
 }
 
-func (s *AccountsService) GetSavedOrder(ctx context.Context, accountID, savedOrderID string, orderParams *OrderParams) (*Response, error) {
-	u := fmt.Sprintf("accounts/%s/savedorders/%s", accountID, savedOrderID)
-	req, err := s.client.NewRequest("GET", u, nil)
-	if err != nil {
-		return nil, err
-	}
-	return s.client.Do(ctx, req, nil)
+// GetSavedOrder requests a saved order of an account. orderParams is ignored.
+//
+// Deprecated: use SavedOrdersService.GetSavedOrder, which returns the saved order.
+func (s *AccountsService) GetSavedOrder(ctx context.Context, accountID, savedOrderID string, orderParams *OrderQueryParams) (*Response, error) {
+	_, resp, err := s.client.SavedOrders.GetSavedOrder(ctx, accountID, savedOrderID)
+	return resp, err
 }
 
+// ReplaceSavedOrder replaces a saved order of an account.
+//
+// Deprecated: use SavedOrdersService.ReplaceSavedOrder.
 func (s *AccountsService) ReplaceSavedOrder(ctx context.Context, accountID, savedOrderID string, order *Order) (*Response, error) {
-	u := fmt.Sprintf("accounts/%s/savedorders/%s", accountID, savedOrderID)
-	if order == nil {
-		return nil, fmt.Errorf("order is nil")
-	}
+	return s.client.SavedOrders.ReplaceSavedOrder(ctx, accountID, savedOrderID, order)
+}
 
-	req, err := s.client.NewRequest("PUT", u, order)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	return s.client.Do(ctx, req, nil)
+// DeleteSavedOrder deletes a saved order of an account.
+//
+// Deprecated: use SavedOrdersService.DeleteSavedOrder.
+func (s *AccountsService) DeleteSavedOrder(ctx context.Context, accountID, savedOrderID string) (*Response, error) {
+	return s.client.SavedOrders.DeleteSavedOrder(ctx, accountID, savedOrderID)
 }
 
-//Utility for printing out requests for debugging.
+// Utility for printing out requests for debugging.
 func PrintRequest(r *http.Request) string {
 	// Create return string
 	var request []string
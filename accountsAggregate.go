@@ -0,0 +1,69 @@
+package tdameritrade
+
+import "context"
+
+// AggregatedPositions are the positions of several accounts merged by symbol, see GetAggregatedPositions.
+type AggregatedPositions struct {
+	BySymbol map[string]*AggregatedPosition
+}
+
+// AggregatedPosition is the total of the positions in a symbol across accounts.
+type AggregatedPosition struct {
+	Symbol           string
+	TotalLongQty     float64
+	TotalShortQty    float64
+	TotalMarketValue float64
+	// AccountBreakdown are the positions merged, in the order of the accounts holding them.
+	AccountBreakdown []AccountPosition
+}
+
+// AccountPosition is the position of an account.
+type AccountPosition struct {
+	AccountID string
+	Position  Position
+}
+
+// GetAggregatedPositions returns the positions of all the accounts of the user merged by symbol,
+// for users holding the same symbols in several accounts.
+func (s *AccountsService) GetAggregatedPositions(ctx context.Context) (*AggregatedPositions, error) {
+	accounts, _, err := s.GetAccounts(ctx, []string{"positions"})
+	if err != nil {
+		return nil, err
+	}
+	return aggregatePositions(accounts), nil
+}
+
+func aggregatePositions(accounts []*Account) *AggregatedPositions {
+	aggregated := &AggregatedPositions{BySymbol: map[string]*AggregatedPosition{}}
+	for _, account := range accounts {
+		for _, p := range account.Positions {
+			symbol := p.Instrument.symbol()
+			a, ok := aggregated.BySymbol[symbol]
+			if !ok {
+				a = &AggregatedPosition{Symbol: symbol}
+				aggregated.BySymbol[symbol] = a
+			}
+			a.TotalLongQty += p.LongQuantity
+			a.TotalShortQty += p.ShortQuantity
+			a.TotalMarketValue += p.MarketValue
+			a.AccountBreakdown = append(a.AccountBreakdown, AccountPosition{AccountID: account.AccountID, Position: p})
+		}
+	}
+	return aggregated
+}
+
+// NetQuantity returns the long quantity of symbol held across accounts less the short quantity,
+// or 0 if no account holds it.
+func (a *AggregatedPositions) NetQuantity(symbol string) float64 {
+	p, ok := a.BySymbol[symbol]
+	if !ok {
+		return 0
+	}
+	return p.TotalLongQty - p.TotalShortQty
+}
+
+// NetDollarExposure returns the value of the net quantity of symbol at price, negative for net short positions.
+// For options, price must be that of a contract, i.e. the option price times its multiplier.
+func (a *AggregatedPositions) NetDollarExposure(symbol string, price float64) float64 {
+	return a.NetQuantity(symbol) * price
+}
@@ -0,0 +1,53 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetAggregatedPositions(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "fields", "positions")
+		fmt.Fprint(w, `[
+			{"securitiesAccount":{"accountId":"1","positions":[
+				{"longQuantity":10,"marketValue":3100,"instrument":{"assetType":"EQUITY","symbol":"SPY"}},
+				{"shortQuantity":2,"marketValue":-500,"instrument":{"assetType":"OPTION","symbol":"SPY_071720C310"}}
+			]}},
+			{"securitiesAccount":{"accountId":"2","positions":[
+				{"shortQuantity":4,"marketValue":-1240,"instrument":{"assetType":"EQUITY","symbol":"SPY"}}
+			]}},
+			{"securitiesAccount":{"accountId":"3"}}
+		]`)
+	})
+
+	positions, err := client.Account.GetAggregatedPositions(context.Background())
+	if err != nil {
+		t.Fatalf("GetAggregatedPositions returned error: %v", err)
+	}
+	if len(positions.BySymbol) != 2 {
+		t.Fatalf("got positions in %d symbols, want 2", len(positions.BySymbol))
+	}
+
+	spy := positions.BySymbol["SPY"]
+	if spy.TotalLongQty != 10 || spy.TotalShortQty != 4 || spy.TotalMarketValue != 1860 {
+		t.Errorf("unexpected SPY position: %+v", spy)
+	}
+	if len(spy.AccountBreakdown) != 2 || spy.AccountBreakdown[0].AccountID != "1" || spy.AccountBreakdown[1].AccountID != "2" {
+		t.Errorf("unexpected SPY breakdown: %+v", spy.AccountBreakdown)
+	}
+	if got := positions.NetQuantity("SPY"); got != 6 {
+		t.Errorf("NetQuantity(SPY) = %v, want 6", got)
+	}
+	if got := positions.NetDollarExposure("SPY_071720C310", 250); got != -500 {
+		t.Errorf("NetDollarExposure(SPY_071720C310) = %v, want -500", got)
+	}
+	if got := positions.NetQuantity("QQQ"); got != 0 {
+		t.Errorf("NetQuantity(QQQ) = %v, want 0", got)
+	}
+}
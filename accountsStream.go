@@ -0,0 +1,183 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// StreamPositions sends the positions of an account on the returned channel as they are decoded from the response,
+// rather than decoding the whole account first like GetAccount, which bounds the memory used by accounts holding
+// many positions. The channel is closed after the last position, or early if ctx is done or the response could
+// not be decoded, e.g. because it was cut short. The error channel then receives the reason, ctx.Err() or the
+// decoding error, before being closed; it is closed without an error once all positions have been sent.
+// An error is returned if the account could not be requested.
+func (s *AccountsService) StreamPositions(ctx context.Context, accountID string) (<-chan Position, <-chan error, error) {
+	dec, closeBody, err := s.openAccountArray(ctx, accountID, "positions")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan Position, streamBufferSize)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer closeBody()
+		defer close(ch)
+		err := decodeArray(dec, func() error {
+			var p Position
+			if err := dec.Decode(&p); err != nil {
+				return err
+			}
+			select {
+			case ch <- p:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return ch, errs, nil
+}
+
+// StreamOrders sends the orders of an account on the returned channel as they are decoded from the response,
+// and errors on the error channel, like StreamPositions.
+func (s *AccountsService) StreamOrders(ctx context.Context, accountID string) (<-chan Order, <-chan error, error) {
+	dec, closeBody, err := s.openAccountArray(ctx, accountID, "orders")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan Order, streamBufferSize)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer closeBody()
+		defer close(ch)
+		err := decodeArray(dec, func() error {
+			var o Order
+			if err := dec.Decode(&o); err != nil {
+				return err
+			}
+			select {
+			case ch <- o:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return ch, errs, nil
+}
+
+// decodeArray calls decode for each element of the array dec is positioned in, and then reads the end of the array,
+// so that a response cut short between elements is an error too. A nil dec is an empty array.
+func decodeArray(dec *json.Decoder, decode func() error) error {
+	if dec == nil {
+		return nil
+	}
+	for dec.More() {
+		if err := decode(); err != nil {
+			return err
+		}
+	}
+	err := expectDelim(dec, ']')
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// accountArrayKeys are the keys of the arrays of a securities account returned for each of the values of
+// the fields parameter of GetAccount.
+var accountArrayKeys = map[string]string{
+	"positions": "positions",
+	"orders":    "orderStrategies",
+}
+
+// openAccountArray requests the account of accountID with field, and returns a decoder positioned at the first
+// element of the array of field, and a function closing the response.
+// The decoder is nil if the account has no such array.
+func (s *AccountsService) openAccountArray(ctx context.Context, accountID, field string) (*json.Decoder, func(), error) {
+	req, err := s.client.NewRequest("GET", fmt.Sprintf("accounts/%s?fields=%s", accountID, field), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Do copies the response to a Writer as it is read, so the decoder reads it through a pipe.
+	r, w := io.Pipe()
+	go func() {
+		_, err := s.client.Do(ctx, req, w)
+		w.CloseWithError(err)
+	}()
+	closeBody := func() { r.Close() }
+
+	dec := json.NewDecoder(r)
+	found, err := seekArray(dec, "securitiesAccount", accountArrayKeys[field])
+	if err != nil {
+		closeBody()
+		return nil, nil, err
+	}
+	if !found {
+		closeBody()
+		return nil, func() {}, nil
+	}
+	return dec, closeBody, nil
+}
+
+// seekArray reads the tokens of dec up to the first element of the array found by following the keys of path
+// through nested objects, skipping the values of other keys. It reports whether the array was found.
+func seekArray(dec *json.Decoder, path ...string) (bool, error) {
+	for i, key := range path {
+		if err := expectDelim(dec, '{'); err != nil {
+			return false, err
+		}
+		for {
+			if !dec.More() {
+				return false, nil
+			}
+			t, err := dec.Token()
+			if err != nil {
+				return false, err
+			}
+			if t == key {
+				break
+			}
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return false, err
+			}
+		}
+		if i == len(path)-1 {
+			t, err := dec.Token()
+			if err != nil {
+				return false, err
+			}
+			if t == nil {
+				return false, nil
+			}
+			if t != json.Delim('[') {
+				return false, fmt.Errorf("unexpected JSON token %v, want [", t)
+			}
+		}
+	}
+	return true, nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != want {
+		return fmt.Errorf("unexpected JSON token %v, want %v", t, want)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestStreamPositions(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	account, err := ioutil.ReadFile("testdata/account.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc("/accounts/123456789", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		_, _ = w.Write(account)
+	})
+
+	positions, errs, err := client.Account.StreamPositions(context.Background(), "123456789")
+	if err != nil {
+		t.Fatalf("StreamPositions returned error: %v", err)
+	}
+	var symbols []string
+	for p := range positions {
+		symbols = append(symbols, p.Instrument.symbol())
+	}
+	if fmt.Sprint(symbols) != "[SPY SPY_071720C310 MMDA1]" {
+		t.Errorf("streamed positions of %v, want SPY, SPY_071720C310 and MMDA1", symbols)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("StreamPositions sent error: %v", err)
+	}
+
+	orders, errs, err := client.Account.StreamOrders(context.Background(), "123456789")
+	if err != nil {
+		t.Fatalf("StreamOrders returned error: %v", err)
+	}
+	n := 0
+	for range orders {
+		n++
+	}
+	if n == 0 {
+		t.Error("StreamOrders sent no orders")
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("StreamOrders sent error: %v", err)
+	}
+}
+
+func TestStreamPositionsLarge(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	const numPositions = 50000
+	position := `{"shortQuantity":0,"averagePrice":300.25,"currentDayProfitLoss":52,"longQuantity":10,` +
+		`"instrument":{"assetType":"EQUITY","cusip":"78462F103","symbol":"SYM%d"},"marketValue":3105.2}`
+	mux.HandleFunc("/accounts/123456789", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "fields", "positions")
+		fmt.Fprint(w, `{"securitiesAccount":{"type":"MARGIN","accountId":"123456789","currentBalances":{"cashBalance":1},"positions":[`)
+		for i := 0; i < numPositions; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, position, i)
+		}
+		fmt.Fprint(w, `]}}`)
+	})
+
+	positions, errs, err := client.Account.StreamPositions(context.Background(), "123456789")
+	if err != nil {
+		t.Fatalf("StreamPositions returned error: %v", err)
+	}
+	n := 0
+	for p := range positions {
+		if want := fmt.Sprintf("SYM%d", n); p.Instrument.symbol() != want {
+			t.Fatalf("position %d is of %s, want %s", n, p.Instrument.symbol(), want)
+		}
+		n++
+	}
+	if n != numPositions {
+		t.Fatalf("streamed %d positions, want %d", n, numPositions)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("StreamPositions sent error: %v", err)
+	}
+}
+
+func TestStreamPositionsErrors(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/404", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/accounts/empty", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"securitiesAccount":{"type":"CASH","accountId":"empty"}}`)
+	})
+
+	var apiErr *APIError
+	if _, _, err := client.Account.StreamPositions(context.Background(), "404"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("StreamPositions error = %v, want a 404 APIError", err)
+	}
+
+	positions, errs, err := client.Account.StreamPositions(context.Background(), "empty")
+	if err != nil {
+		t.Fatalf("StreamPositions returned error: %v", err)
+	}
+	for p := range positions {
+		t.Errorf("unexpected position %+v", p)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("StreamPositions sent error: %v", err)
+	}
+
+	const prefix = `{"securitiesAccount":{"positions":[{"longQuantity":1,"instrument":{"assetType":"EQUITY","symbol":"SPY"}}`
+	for i, body := range []string{prefix, prefix + ",", prefix + `,{"longQuantity":1,"instr`} {
+		body := body
+		accountID := fmt.Sprintf("truncated%d", i)
+		mux.HandleFunc("/accounts/"+accountID, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		})
+		positions, errs, err := client.Account.StreamPositions(context.Background(), accountID)
+		if err != nil {
+			t.Fatalf("StreamPositions returned error: %v", err)
+		}
+		n := 0
+		for range positions {
+			n++
+		}
+		if n != 1 {
+			t.Errorf("streamed %d positions of %s, want 1", n, body)
+		}
+		if err := <-errs; err == nil {
+			t.Errorf("expected an error decoding %s", body)
+		}
+	}
+}
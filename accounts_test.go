@@ -0,0 +1,247 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetAccountWithPositions(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.FormValue("fields") != "positions" {
+			fmt.Fprint(w, `{"securitiesAccount":{"type":"MARGIN","accountId":"123"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"securitiesAccount":{"type":"MARGIN","accountId":"123","roundTrips":1,
+			"positions":[{"longQuantity":10,"averagePrice":300,"marketValue":3100,"instrument":{"assetType":"EQUITY","symbol":"SPY"}}],
+			"currentBalances":{"liquidationValue":5100,"cashBalance":2000}}}`)
+	})
+
+	account, _, err := client.Account.GetAccount(context.Background(), "123", []string{"positions"})
+	if err != nil {
+		t.Fatalf("GetAccount returned error: %v", err)
+	}
+
+	if account.AccountID != "123" || account.Type != "MARGIN" || account.RoundTrips != 1 {
+		t.Errorf("unexpected account: %+v", account.SecuritiesAccount)
+	}
+	if len(account.Positions) != 1 {
+		t.Fatalf("expected 1 position, got %d", len(account.Positions))
+	}
+	equity, ok := account.Positions[0].Instrument.Data.(*Equity)
+	if !ok || equity.Symbol != "SPY" {
+		t.Errorf("unexpected instrument: %+v", account.Positions[0].Instrument)
+	}
+	if account.CurrentBalances.LiquidationValue != 5100 {
+		t.Errorf("unexpected balances: %+v", account.CurrentBalances)
+	}
+
+	account, _, err = client.Account.GetAccount(context.Background(), "123", nil)
+	if err != nil {
+		t.Fatalf("GetAccount returned error: %v", err)
+	}
+	if len(account.Positions) != 0 {
+		t.Errorf("positions returned without being requested")
+	}
+}
+
+func TestGetAccounts(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "fields", "positions,orders")
+		fmt.Fprint(w, `[{"securitiesAccount":{"accountId":"1","orderStrategies":[{"orderType":"LIMIT","price":1.5,"status":"WORKING"}]}},{"securitiesAccount":{"accountId":"2"}}]`)
+	})
+
+	// The fields of the deprecated AccountOptions select the same fields.
+	accounts, _, err := client.Account.GetAccounts(context.Background(), (&AccountOptions{Position: true, Orders: true}).Fields())
+	if err != nil {
+		t.Fatalf("GetAccounts returned error: %v", err)
+	}
+
+	if len(accounts) != 2 || accounts[0].AccountID != "1" || accounts[1].AccountID != "2" {
+		t.Fatalf("unexpected accounts: %+v", accounts)
+	}
+	if len(accounts[0].OrderStrategies) != 1 || accounts[0].OrderStrategies[0].Status != "WORKING" {
+		t.Errorf("unexpected orders: %+v", accounts[0].OrderStrategies)
+	}
+}
+
+func loadAccounts(t testing.TB) Accounts {
+	t.Helper()
+	b, err := ioutil.ReadFile("testdata/accounts.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var accounts Accounts
+	if err := json.Unmarshal(b, &accounts); err != nil {
+		t.Fatalf("could not decode accounts: %v", err)
+	}
+	return accounts
+}
+
+func TestAccountBalances(t *testing.T) {
+	accounts := loadAccounts(t)
+	margin, cash := accounts[0], accounts[1]
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"margin NetLiquidation", margin.NetLiquidation(), 12843.2},
+		{"margin EquityBuyingPower", margin.EquityBuyingPower(), 18200},
+		{"margin OptionBuyingPower", margin.OptionBuyingPower(), 9100},
+		{"margin DayTradingBuyingPower", margin.DayTradingBuyingPower(), 36400},
+		// The cash account has no liquidation value, so it is the market value of the positions plus cash.
+		{"cash NetLiquidation", cash.NetLiquidation(), 1510},
+		{"cash EquityBuyingPower", cash.EquityBuyingPower(), 450},
+		{"cash OptionBuyingPower", cash.OptionBuyingPower(), 450},
+		{"cash DayTradingBuyingPower", cash.DayTradingBuyingPower(), 450},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestPositionUnrealizedPnL(t *testing.T) {
+	positions := loadAccounts(t)[0].Positions
+	equity, shortCall := positions[0], positions[1]
+
+	if got, want := equity.UnrealizedPnL(), 3105.2-3002.5; math.Abs(got-want) > 1e-9 {
+		t.Errorf("equity UnrealizedPnL = %v, want %v", got, want)
+	}
+	if got, want := equity.UnrealizedPnLPercent(), (3105.2-3002.5)/3002.5*100; math.Abs(got-want) > 1e-9 {
+		t.Errorf("equity UnrealizedPnLPercent = %v, want %v", got, want)
+	}
+
+	// The call was sold for 250 and would cost 262 to buy back.
+	if got := shortCall.UnrealizedPnL(); got != -12 {
+		t.Errorf("short call UnrealizedPnL = %v, want -12", got)
+	}
+	if got := shortCall.UnrealizedPnLPercent(); got != -4.8 {
+		t.Errorf("short call UnrealizedPnLPercent = %v, want -4.8", got)
+	}
+
+	if got := (&Position{}).UnrealizedPnLPercent(); got != 0 {
+		t.Errorf("UnrealizedPnLPercent of an empty position = %v, want 0", got)
+	}
+}
+
+func TestPositionDaysHeld(t *testing.T) {
+	p := Position{}
+	if got := p.DaysHeld(); got != 0 {
+		t.Errorf("DaysHeld without AcquiredDate = %d, want 0", got)
+	}
+
+	p.AcquiredDate = time.Now().Add(-(10*24 + 1) * time.Hour)
+	if got := p.DaysHeld(); got != 10 {
+		t.Errorf("DaysHeld = %d, want 10", got)
+	}
+}
+
+func TestUnmarshalAccount(t *testing.T) {
+	testJSONRoundTrip(t, "testdata/account.json", &Account{})
+}
+
+func TestAccountPositions(t *testing.T) {
+	margin := loadAccounts(t)[0]
+
+	position, ok := margin.GetPositionBySymbol("SPY_071720C310")
+	if !ok || position.ShortQuantity != 1 {
+		t.Errorf("GetPositionBySymbol(SPY_071720C310) = %+v, %v", position, ok)
+	}
+	position.AcquiredDate = time.Now()
+	if margin.Positions[1].AcquiredDate.IsZero() {
+		t.Error("expected GetPositionBySymbol to return the account's position")
+	}
+	if position, ok := margin.GetPositionBySymbol("QQQ"); ok || position != nil {
+		t.Errorf("GetPositionBySymbol(QQQ) = %+v, %v, want nil, false", position, ok)
+	}
+
+	if equities := margin.GetPositionsByAssetType("EQUITY"); len(equities) != 1 || equities[0].MarketValue != 3105.2 {
+		t.Errorf("unexpected equity positions: %+v", equities)
+	}
+	if options := margin.GetPositionsByAssetType("OPTION"); len(options) != 1 {
+		t.Errorf("unexpected option positions: %+v", options)
+	}
+	if bonds := margin.GetPositionsByAssetType("FIXED_INCOME"); len(bonds) != 0 {
+		t.Errorf("unexpected bond positions: %+v", bonds)
+	}
+
+	if got, want := margin.TotalUnrealizedPnL(), 3105.2-3002.5-12; math.Abs(got-want) > 1e-9 {
+		t.Errorf("TotalUnrealizedPnL = %v, want %v", got, want)
+	}
+}
+
+func TestAccountDayTrades(t *testing.T) {
+	accounts := loadAccounts(t)
+	margin, cash := accounts[0], accounts[1]
+
+	if !margin.HasPattern(false) || margin.HasPattern(true) {
+		t.Error("expected the margin account not to be flagged as a pattern day trader")
+	}
+
+	for roundTrips, want := range map[float64]int{0: 3, 2: 1, 3: 0, 5: 0} {
+		margin.RoundTrips = roundTrips
+		if got := margin.RemainingDayTrades(); got != want {
+			t.Errorf("RemainingDayTrades with %v round trips = %d, want %d", roundTrips, got, want)
+		}
+	}
+
+	// The flagged account has less than $25,000 of equity.
+	margin.IsDayTrader = true
+	if got := margin.RemainingDayTrades(); got != 0 {
+		t.Errorf("RemainingDayTrades of a flagged account under the minimum equity = %d, want 0", got)
+	}
+	margin.CurrentBalances.Equity = 30000
+	if got := margin.RemainingDayTrades(); got != UnlimitedDayTrades {
+		t.Errorf("RemainingDayTrades of a flagged account = %d, want UnlimitedDayTrades", got)
+	}
+
+	if got := cash.RemainingDayTrades(); got != UnlimitedDayTrades {
+		t.Errorf("RemainingDayTrades of a cash account = %d, want UnlimitedDayTrades", got)
+	}
+}
+
+func TestAccountMarginCall(t *testing.T) {
+	accounts := loadAccounts(t)
+	margin, cash := accounts[0], accounts[1]
+
+	margin.InitialBalances.Equity = 20000
+	margin.CurrentBalances.BuyingPower = 5000
+	if got := margin.MarginUtilization(); got != 0.75 {
+		t.Errorf("MarginUtilization = %v, want 0.75", got)
+	}
+	if got := cash.MarginUtilization(); got != 0 {
+		t.Errorf("MarginUtilization of a cash account = %v, want 0", got)
+	}
+
+	margin.CurrentBalances.MaintenanceCall = 0
+	if margin.IsInMarginCall() || margin.DaysToCoverMarginCall(100) != 0 {
+		t.Error("expected an account without a maintenance call not to be in a margin call")
+	}
+	margin.CurrentBalances.MaintenanceCall = 1500
+	if !margin.IsInMarginCall() {
+		t.Error("expected an account with a maintenance call to be in a margin call")
+	}
+	if got := margin.DaysToCoverMarginCall(500); got != 3 {
+		t.Errorf("DaysToCoverMarginCall(500) = %v, want 3", got)
+	}
+	if got := margin.DaysToCoverMarginCall(-200); !math.IsInf(got, 1) {
+		t.Errorf("DaysToCoverMarginCall(-200) = %v, want +Inf", got)
+	}
+}
@@ -0,0 +1,267 @@
+// Package alerts calls functions when the streaming quotes of the tdameritrade package meet conditions,
+// such as a stock trading above a price.
+//
+// Usage example:
+// engine := alerts.NewAlertEngine(streamingClient)
+// id, err := engine.AddPriceAlert("SPY", 320, true, func(q tdameritrade.L1EquityQuote) { ... })
+// engine.RemoveAlert(id)
+// engine.Close()
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// ErrClosed is returned when adding an alert to a closed AlertEngine.
+var ErrClosed = errors.New("alert engine closed")
+
+// Source is the streaming data an AlertEngine watches. It is implemented by *tdameritrade.StreamingClient.
+type Source interface {
+	SubscribeLevelOneEquity(symbols []string, fields []tdameritrade.L1EquityField) (<-chan tdameritrade.L1EquityQuote, error)
+	SubscribeLevelOneOption(symbols []string, fields []tdameritrade.L1OptionField) (<-chan tdameritrade.L1OptionQuote, error)
+}
+
+// AlertEngine routes the level one quotes of a Source through alert rules, calling the function of each rule
+// met in its own goroutine.
+//
+// The engine subscribes to the quotes of the symbols of its alerts as they are added, replacing any previous
+// equity or option subscription of the Source, and reads the channels of those subscriptions, which must not be
+// read elsewhere.
+type AlertEngine struct {
+	source Source
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu            sync.Mutex
+	closed        bool
+	nextID        int
+	priceAlerts   map[string]*priceAlert
+	deltaAlerts   map[string]*deltaAlert
+	equityStarted bool
+	optionStarted bool
+}
+
+type priceAlert struct {
+	symbol    string
+	threshold float64
+	above     bool
+	fn        func(tdameritrade.L1EquityQuote)
+	met       bool
+}
+
+type deltaAlert struct {
+	symbol   string
+	crossing float64
+	fn       func(tdameritrade.L1OptionQuote)
+	seen     bool
+	last     float64
+}
+
+// NewAlertEngine returns an AlertEngine watching the quotes of source.
+func NewAlertEngine(source Source) *AlertEngine {
+	return &AlertEngine{
+		source:      source,
+		done:        make(chan struct{}),
+		priceAlerts: map[string]*priceAlert{},
+		deltaAlerts: map[string]*deltaAlert{},
+	}
+}
+
+// AddPriceAlert calls fn when the last price of symbol rises to threshold or above if above is true,
+// or falls to threshold or below otherwise. It returns the ID of the alert, to remove it with RemoveAlert.
+// fn is called again each time the price crosses threshold afresh, rather than on every quote beyond it.
+func (e *AlertEngine) AddPriceAlert(symbol string, threshold float64, above bool, fn func(tdameritrade.L1EquityQuote)) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return "", ErrClosed
+	}
+
+	id := e.newID()
+	e.priceAlerts[id] = &priceAlert{symbol: symbol, threshold: threshold, above: above, fn: fn}
+	if err := e.subscribeEquities(); err != nil {
+		delete(e.priceAlerts, id)
+		return "", err
+	}
+	return id, nil
+}
+
+// AddDeltaAlert calls fn when the delta of optionSymbol, e.g. SPY_121621C450, crosses deltaCrossing in either
+// direction between two quotes. Quotes without a delta, which is 0 until TD Ameritrade sends one, are ignored.
+// It returns the ID of the alert, to remove it with RemoveAlert.
+func (e *AlertEngine) AddDeltaAlert(optionSymbol string, deltaCrossing float64, fn func(tdameritrade.L1OptionQuote)) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return "", ErrClosed
+	}
+
+	id := e.newID()
+	e.deltaAlerts[id] = &deltaAlert{symbol: optionSymbol, crossing: deltaCrossing, fn: fn}
+	if err := e.subscribeOptions(); err != nil {
+		delete(e.deltaAlerts, id)
+		return "", err
+	}
+	return id, nil
+}
+
+// RemoveAlert removes the alert of id. Calls of its function already started are not waited for.
+func (e *AlertEngine) RemoveAlert(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.priceAlerts, id)
+	delete(e.deltaAlerts, id)
+}
+
+// Close stops routing quotes through the alerts and waits for the engine to stop reading the Source,
+// which is left open. Calls of the functions of alerts already started are not waited for.
+func (e *AlertEngine) Close() {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return
+	}
+	e.closed = true
+	close(e.done)
+	e.mu.Unlock()
+
+	e.wg.Wait()
+}
+
+func (e *AlertEngine) newID() string {
+	e.nextID++
+	return fmt.Sprintf("alert-%d", e.nextID)
+}
+
+// subscribeEquities subscribes to the symbols of every price alert, starting to route their quotes if needed.
+func (e *AlertEngine) subscribeEquities() error {
+	var symbols []string
+	for _, a := range e.priceAlerts {
+		symbols = append(symbols, a.symbol)
+	}
+	ch, err := e.source.SubscribeLevelOneEquity(unique(symbols), nil)
+	if err != nil {
+		return fmt.Errorf("could not subscribe to quotes: %w", err)
+	}
+	if !e.equityStarted {
+		e.equityStarted = true
+		e.wg.Add(1)
+		go e.routeEquities(ch)
+	}
+	return nil
+}
+
+// subscribeOptions subscribes to the symbols of every delta alert, starting to route their quotes if needed.
+func (e *AlertEngine) subscribeOptions() error {
+	var symbols []string
+	for _, a := range e.deltaAlerts {
+		symbols = append(symbols, a.symbol)
+	}
+	ch, err := e.source.SubscribeLevelOneOption(unique(symbols), nil)
+	if err != nil {
+		return fmt.Errorf("could not subscribe to option quotes: %w", err)
+	}
+	if !e.optionStarted {
+		e.optionStarted = true
+		e.wg.Add(1)
+		go e.routeOptions(ch)
+	}
+	return nil
+}
+
+func (e *AlertEngine) routeEquities(ch <-chan tdameritrade.L1EquityQuote) {
+	defer e.wg.Done()
+	for {
+		select {
+		case <-e.done:
+			return
+		case quote, ok := <-ch:
+			if !ok {
+				return
+			}
+			e.checkPrice(quote)
+		}
+	}
+}
+
+func (e *AlertEngine) routeOptions(ch <-chan tdameritrade.L1OptionQuote) {
+	defer e.wg.Done()
+	for {
+		select {
+		case <-e.done:
+			return
+		case quote, ok := <-ch:
+			if !ok {
+				return
+			}
+			e.checkDelta(quote)
+		}
+	}
+}
+
+func (e *AlertEngine) checkPrice(quote tdameritrade.L1EquityQuote) {
+	if _, ok := quote.Fields[tdameritrade.L1EquityLastPrice]; !ok {
+		return
+	}
+	price := quote.LastPrice()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	for _, a := range e.priceAlerts {
+		if a.symbol != quote.Symbol {
+			continue
+		}
+		met := price <= a.threshold
+		if a.above {
+			met = price >= a.threshold
+		}
+		if met && !a.met {
+			go a.fn(quote)
+		}
+		a.met = met
+	}
+}
+
+func (e *AlertEngine) checkDelta(quote tdameritrade.L1OptionQuote) {
+	if quote.Delta == 0 || math.IsNaN(quote.Delta) {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	for _, a := range e.deltaAlerts {
+		if a.symbol != quote.Symbol {
+			continue
+		}
+		if a.seen && (a.last < a.crossing && quote.Delta >= a.crossing || a.last > a.crossing && quote.Delta <= a.crossing) {
+			go a.fn(quote)
+		}
+		a.seen, a.last = true, quote.Delta
+	}
+}
+
+// unique returns the distinct values of symbols, sorted.
+func unique(symbols []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range symbols {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,160 @@
+package alerts
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// mockSource is a Source whose quotes are sent by the tests.
+type mockSource struct {
+	mu             sync.Mutex
+	equitySymbols  []string
+	optionSymbols  []string
+	equities       chan tdameritrade.L1EquityQuote
+	options        chan tdameritrade.L1OptionQuote
+	subscribeError error
+}
+
+func newMockSource() *mockSource {
+	return &mockSource{
+		equities: make(chan tdameritrade.L1EquityQuote),
+		options:  make(chan tdameritrade.L1OptionQuote),
+	}
+}
+
+func (m *mockSource) SubscribeLevelOneEquity(symbols []string, fields []tdameritrade.L1EquityField) (<-chan tdameritrade.L1EquityQuote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.subscribeError != nil {
+		return nil, m.subscribeError
+	}
+	m.equitySymbols = symbols
+	return m.equities, nil
+}
+
+func (m *mockSource) SubscribeLevelOneOption(symbols []string, fields []tdameritrade.L1OptionField) (<-chan tdameritrade.L1OptionQuote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.subscribeError != nil {
+		return nil, m.subscribeError
+	}
+	m.optionSymbols = symbols
+	return m.options, nil
+}
+
+func equityQuote(symbol string, lastPrice float64) tdameritrade.L1EquityQuote {
+	return tdameritrade.L1EquityQuote{
+		Symbol: symbol,
+		Fields: map[tdameritrade.L1EquityField]interface{}{tdameritrade.L1EquityLastPrice: lastPrice},
+	}
+}
+
+// expectCalls fails t unless exactly want values are received on calls within a second.
+func expectCalls(t *testing.T, name string, calls <-chan float64, want ...float64) {
+	t.Helper()
+	var got []float64
+	for range want {
+		select {
+		case v := <-calls:
+			got = append(got, v)
+		case <-time.After(time.Second):
+			t.Fatalf("%s: got calls %v, want %v", name, got, want)
+		}
+	}
+	select {
+	case v := <-calls:
+		t.Fatalf("%s: unexpected call with %v after %v", name, v, got)
+	case <-time.After(50 * time.Millisecond):
+	}
+	// Callbacks run in their own goroutines, so their order is not guaranteed.
+	sort.Float64s(got)
+	want = append([]float64(nil), want...)
+	sort.Float64s(want)
+	if len(want) > 0 && !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got calls %v, want %v", name, got, want)
+	}
+}
+
+func TestPriceAlert(t *testing.T) {
+	source := newMockSource()
+	engine := NewAlertEngine(source)
+	defer engine.Close()
+
+	above := make(chan float64, 10)
+	if _, err := engine.AddPriceAlert("SPY", 320, true, func(q tdameritrade.L1EquityQuote) { above <- q.LastPrice() }); err != nil {
+		t.Fatalf("AddPriceAlert returned error: %v", err)
+	}
+	below := make(chan float64, 10)
+	belowID, err := engine.AddPriceAlert("QQQ", 250, false, func(q tdameritrade.L1EquityQuote) { below <- q.LastPrice() })
+	if err != nil {
+		t.Fatalf("AddPriceAlert returned error: %v", err)
+	}
+	if want := []string{"QQQ", "SPY"}; !reflect.DeepEqual(source.equitySymbols, want) {
+		t.Errorf("subscribed to %v, want %v", source.equitySymbols, want)
+	}
+
+	for _, q := range []tdameritrade.L1EquityQuote{
+		equityQuote("SPY", 319),
+		equityQuote("SPY", 320.5),
+		// Still above, which must not fire again.
+		equityQuote("SPY", 321),
+		{Symbol: "SPY", Fields: map[tdameritrade.L1EquityField]interface{}{tdameritrade.L1EquityBidPrice: 310.0}},
+		equityQuote("SPY", 318),
+		equityQuote("SPY", 322),
+		equityQuote("QQQ", 249),
+	} {
+		source.equities <- q
+	}
+	expectCalls(t, "above", above, 320.5, 322)
+	expectCalls(t, "below", below, 249)
+
+	engine.RemoveAlert(belowID)
+	source.equities <- equityQuote("QQQ", 251)
+	source.equities <- equityQuote("QQQ", 248)
+	expectCalls(t, "removed", below)
+}
+
+func TestDeltaAlert(t *testing.T) {
+	source := newMockSource()
+	engine := NewAlertEngine(source)
+	defer engine.Close()
+
+	calls := make(chan float64, 10)
+	if _, err := engine.AddDeltaAlert("SPY_071720C310", 0.5, func(q tdameritrade.L1OptionQuote) { calls <- q.Delta }); err != nil {
+		t.Fatalf("AddDeltaAlert returned error: %v", err)
+	}
+	if want := []string{"SPY_071720C310"}; !reflect.DeepEqual(source.optionSymbols, want) {
+		t.Errorf("subscribed to %v, want %v", source.optionSymbols, want)
+	}
+
+	// Quotes without a delta neither trigger the alert nor count as the first reading.
+	for _, delta := range []float64{0, 0.52, 0, 0.55, 0.45, 0.48, 0.52, 0.55, 0.47} {
+		source.options <- tdameritrade.L1OptionQuote{Symbol: "SPY_071720C310", Delta: delta}
+	}
+	source.options <- tdameritrade.L1OptionQuote{Symbol: "SPY_071720C315", Delta: 0.6}
+	expectCalls(t, "delta", calls, 0.45, 0.52, 0.47)
+}
+
+func TestAlertEngineErrors(t *testing.T) {
+	source := newMockSource()
+	source.subscribeError = errors.New("not connected")
+	engine := NewAlertEngine(source)
+
+	if _, err := engine.AddPriceAlert("SPY", 320, true, func(tdameritrade.L1EquityQuote) {}); !errors.Is(err, source.subscribeError) {
+		t.Errorf("AddPriceAlert error = %v, want %v", err, source.subscribeError)
+	}
+	if len(engine.priceAlerts) != 0 {
+		t.Error("AddPriceAlert kept an alert it could not subscribe to")
+	}
+
+	engine.Close()
+	if _, err := engine.AddDeltaAlert("SPY_071720C310", 0.5, func(tdameritrade.L1OptionQuote) {}); !errors.Is(err, ErrClosed) {
+		t.Errorf("AddDeltaAlert after Close error = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"math"
+	"time"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// EarlyExerciseProbability returns how likely the holder of option is to exercise it before its underlying goes
+// ex-dividend on dividendDate, from 0 when holding it is clearly better to 1 when exercising it is.
+//
+// Following Black's approximation, exercising a call just before the ex-dividend date is worthwhile when the
+// dividend it captures exceeds the time value given up. The time value left on dividendDate is the option's
+// TimeValue less its Theta for each day from its quote to dividendDate, and the result is
+// dividendAmount / (dividendAmount + time value left): a half when they are equal, and close to 1 for deep in the
+// money calls with little time value left. It is a score for comparing options rather than a probability implied
+// by a pricing model.
+//
+// Puts, calls out of the money and options expiring before dividendDate are never exercised for the dividend,
+// and return 0.
+func EarlyExerciseProbability(option tdameritrade.ExpDateOption, dividendAmount float64, dividendDate time.Time) float64 {
+	if option.PutCall != "CALL" || !option.InTheMoney || dividendAmount <= 0 {
+		return 0
+	}
+	if option.ExpirationDate != 0 && !dividendDate.Before(msTime(int64(option.ExpirationDate))) {
+		return 0
+	}
+
+	quoted := time.Now()
+	if option.QuoteTimeInLong != 0 {
+		quoted = msTime(int64(option.QuoteTimeInLong))
+	}
+	days := math.Max(dividendDate.Sub(quoted).Hours()/24, 0)
+	timeValue := option.TimeValue
+	if option.Theta.IsValid() {
+		timeValue += float64(option.Theta) * days
+	}
+	timeValue = math.Max(timeValue, 0)
+
+	return dividendAmount / (dividendAmount + timeValue)
+}
+
+// ShoulderDate returns the last day on which option can be exercised to receive the dividend of its underlying
+// going ex-dividend on dividendDate: the weekday before dividendDate, ignoring market holidays.
+// It returns the zero time for puts and for options expiring before that day.
+func ShoulderDate(option tdameritrade.ExpDateOption, dividendDate time.Time) time.Time {
+	if option.PutCall != "CALL" {
+		return time.Time{}
+	}
+	day := time.Date(dividendDate.Year(), dividendDate.Month(), dividendDate.Day(), 0, 0, 0, 0, dividendDate.Location())
+	day = day.AddDate(0, 0, -1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	if option.ExpirationDate != 0 {
+		expiration := msTime(int64(option.ExpirationDate)).In(day.Location())
+		if expiration.Before(day) {
+			return time.Time{}
+		}
+	}
+	return day
+}
+
+// msTime returns the time of ms milliseconds since the Unix epoch.
+func msTime(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
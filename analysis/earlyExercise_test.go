@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+func TestEarlyExerciseProbability(t *testing.T) {
+	quoted := time.Date(2020, 6, 1, 14, 30, 0, 0, time.UTC)
+	exDividend := quoted.AddDate(0, 0, 5)
+	expiration := quoted.AddDate(0, 0, 46)
+	call := func(inTheMoney bool, timeValue, theta float64) tdameritrade.ExpDateOption {
+		return tdameritrade.ExpDateOption{
+			PutCall:         "CALL",
+			InTheMoney:      inTheMoney,
+			TimeValue:       timeValue,
+			Theta:           tdameritrade.Float64WithSpecial(theta),
+			QuoteTimeInLong: int(tdameritrade.ConvertToEpoch(quoted)),
+			ExpirationDate:  int(tdameritrade.ConvertToEpoch(expiration)),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		option   tdameritrade.ExpDateOption
+		dividend float64
+		date     time.Time
+		want     float64
+	}{
+		{"deep in the money call with a large dividend", call(true, 0.05, -0.01), 2, exDividend, 1},
+		{"dividend equal to the time value left", call(true, 1.5, -0.1), 1, exDividend, 0.5},
+		{"time value far above the dividend", call(true, 9.5, -0.1), 1, exDividend, 0.1},
+		{"out of the money call", call(false, 0.05, -0.01), 2, exDividend, 0},
+		{"dividend after expiration", call(true, 0.05, -0.01), 2, expiration.AddDate(0, 0, 1), 0},
+		{"put", tdameritrade.ExpDateOption{PutCall: "PUT", InTheMoney: true}, 2, exDividend, 0},
+	}
+	for _, tt := range tests {
+		if got := EarlyExerciseProbability(tt.option, tt.dividend, tt.date); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: probability = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestShoulderDate(t *testing.T) {
+	expiration := time.Date(2020, 7, 17, 20, 0, 0, 0, time.UTC)
+	call := tdameritrade.ExpDateOption{PutCall: "CALL", ExpirationDate: int(tdameritrade.ConvertToEpoch(expiration))}
+
+	// Monday June 8th goes ex-dividend, so the call must be exercised by Friday June 5th.
+	if got, want := ShoulderDate(call, time.Date(2020, 6, 8, 0, 0, 0, 0, time.UTC)), time.Date(2020, 6, 5, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("ShoulderDate = %v, want %v", got, want)
+	}
+	if got := ShoulderDate(call, time.Date(2020, 7, 21, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("ShoulderDate after expiration = %v, want the zero time", got)
+	}
+	put := call
+	put.PutCall = "PUT"
+	if got := ShoulderDate(put, time.Date(2020, 6, 8, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("ShoulderDate of a put = %v, want the zero time", got)
+	}
+}
@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/kuzmak/go-tdameritrade"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// The sensitivities below are approximated from the Greeks TD Ameritrade reports for each option, rather than
+// from a pricing model of its own. They assume that:
+//   - the underlying pays no dividends and interest rates are zero;
+//   - options behave like European options, which understates the early exercise value of deep in the money
+//     American options;
+//   - the Greeks hold for small moves, so the further a move is from the reported values the rougher the result.
+// Options missing the Greeks used return NaN.
+
+// DeltaOfDelta returns the dollar gamma of each of options for a move of the underlying by dS: the change of the
+// dollar delta of one contract, Delta * underlyingPrice * Multiplier, when its delta changes by the first order
+// finite difference Gamma * dS, that is Gamma * dS * underlyingPrice * Multiplier. With dS of 1% of
+// underlyingPrice it is the DollarGamma of tdameritrade.NormalizeGreeks.
+// Options without a Multiplier are taken to be for 100 shares.
+func DeltaOfDelta(options []tdameritrade.ExpDateOption, underlyingPrice float64, dS float64) []float64 {
+	out := make([]float64, len(options))
+	for i, o := range options {
+		if !o.Gamma.IsValid() {
+			out[i] = math.NaN()
+			continue
+		}
+		multiplier := o.Multiplier
+		if multiplier == 0 {
+			multiplier = 100
+		}
+		out[i] = float64(o.Gamma) * dS * underlyingPrice * multiplier
+	}
+	return out
+}
+
+// ThetaDecay returns the value option loses on each of the next days days, in dollars per share and negative as
+// TD Ameritrade reports Theta. The time value is taken to be proportional to the square root of the days left,
+// fitted so that its rate of change today is Theta, and the loss of day d is the finite difference of that time
+// value between DaysToExpiration-d and DaysToExpiration-d-1 days left: 2 * Theta * sqrt(DaysToExpiration) *
+// (sqrt(DaysToExpiration-d) - sqrt(DaysToExpiration-d-1)). It is zero from the day of expiration on.
+func ThetaDecay(option tdameritrade.ExpDateOption, days int) []float64 {
+	if days < 1 {
+		return nil
+	}
+	out := make([]float64, days)
+	dte := float64(option.DaysToExpiration)
+	for d := range out {
+		left := dte - float64(d)
+		switch {
+		case !option.Theta.IsValid():
+			out[d] = math.NaN()
+		case left <= 0:
+			out[d] = 0
+		default:
+			out[d] = 2 * float64(option.Theta) * math.Sqrt(dte) * (math.Sqrt(left) - math.Sqrt(math.Max(left-1, 0)))
+		}
+	}
+	return out
+}
+
+// VegaVsIV returns the vega of option at steps implied volatilities evenly spaced from ivRange[0] to ivRange[1],
+// both included, in percent as TD Ameritrade reports Volatility.
+//
+// The moneyness of the option is recovered from its Delta and Volatility, which is then used to scale its Vega
+// by the normal density of d1 at each volatility. Options expiring today have no vega left to scale.
+func VegaVsIV(option tdameritrade.ExpDateOption, ivRange [2]float64, steps int) []float64 {
+	if steps < 1 {
+		return nil
+	}
+	out := make([]float64, steps)
+
+	t := float64(option.DaysToExpiration) / 365
+	sigma0 := float64(option.Volatility) / 100
+	delta := float64(option.Delta)
+	if option.PutCall == "PUT" {
+		delta++
+	}
+	valid := option.Vega.IsValid() && option.Delta.IsValid() && option.Volatility.IsValid() &&
+		t > 0 && sigma0 > 0 && delta > 0 && delta < 1
+
+	// With no dividends or rates, N(d1) is the call delta, and d1 = (m + sigma^2 t / 2) / (sigma sqrt(t)),
+	// m being the log of the underlying price over the strike.
+	var m, density0 float64
+	if valid {
+		d1 := distuv.UnitNormal.Quantile(delta)
+		m = d1*sigma0*math.Sqrt(t) - sigma0*sigma0*t/2
+		density0 = distuv.UnitNormal.Prob(d1)
+	}
+
+	for i := range out {
+		iv := ivRange[0]
+		if steps > 1 {
+			iv += (ivRange[1] - ivRange[0]) * float64(i) / float64(steps-1)
+		}
+		sigma := iv / 100
+		if !valid || sigma <= 0 {
+			out[i] = math.NaN()
+			continue
+		}
+		d1 := (m + sigma*sigma*t/2) / (sigma * math.Sqrt(t))
+		out[i] = float64(option.Vega) * distuv.UnitNormal.Prob(d1) / density0
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+func TestDeltaOfDelta(t *testing.T) {
+	options := []tdameritrade.ExpDateOption{
+		{Gamma: 0.05, Multiplier: 100},
+		{Gamma: 0.02},
+		{Gamma: tdameritrade.Float64WithSpecial(math.NaN())},
+	}
+	got := DeltaOfDelta(options, 100, 1)
+	if want := []float64{500, 200, math.NaN()}; !equalNaN(got, want) {
+		t.Errorf("DeltaOfDelta = %v, want %v", got, want)
+	}
+
+	// A 1% move is the dollar gamma of NormalizeGreeks.
+	if got, want := DeltaOfDelta(options[:1], 250, 2.5)[0], tdameritrade.NormalizeGreeks(&options[0], 250).DollarGamma; math.Abs(got-want) > 1e-9 {
+		t.Errorf("DeltaOfDelta for a 1%% move = %v, want DollarGamma %v", got, want)
+	}
+}
+
+func TestThetaDecay(t *testing.T) {
+	got := ThetaDecay(tdameritrade.ExpDateOption{Theta: -0.04, DaysToExpiration: 4}, 5)
+	want := []float64{-0.16 * (2 - math.Sqrt(3)), -0.16 * (math.Sqrt(3) - math.Sqrt(2)), -0.16 * (math.Sqrt(2) - 1), -0.16, 0}
+	if !equalNaN(got, want) {
+		t.Errorf("ThetaDecay = %v, want %v", got, want)
+	}
+	// The losses add up to the time value, 2 * Theta * DaysToExpiration.
+	var total float64
+	for _, loss := range got {
+		total += loss
+	}
+	if math.Abs(total+0.32) > 1e-9 {
+		t.Errorf("ThetaDecay adds up to %v, want -0.32", total)
+	}
+	if got := ThetaDecay(tdameritrade.ExpDateOption{Theta: -0.04, DaysToExpiration: 4}, 0); got != nil {
+		t.Errorf("ThetaDecay over no days = %v, want nil", got)
+	}
+}
+
+func TestVegaVsIV(t *testing.T) {
+	call := tdameritrade.ExpDateOption{PutCall: "CALL", Delta: 0.5, Vega: 0.4, Volatility: 20, DaysToExpiration: 365}
+	got := VegaVsIV(call, [2]float64{20, 40}, 2)
+	// At 40% d1 = (-0.02 + 0.08) / 0.4 = 0.15, so vega is scaled by exp(-0.15^2 / 2).
+	want := []float64{0.4, 0.4 * math.Exp(-0.15*0.15/2)}
+	if len(got) != len(want) || math.Abs(got[0]-want[0]) > 1e-9 || math.Abs(got[1]-want[1]) > 1e-9 {
+		t.Errorf("VegaVsIV = %v, want %v", got, want)
+	}
+
+	put := call
+	put.PutCall, put.Delta = "PUT", -0.5
+	if got := VegaVsIV(put, [2]float64{20, 20}, 1); len(got) != 1 || math.Abs(got[0]-0.4) > 1e-9 {
+		t.Errorf("VegaVsIV of a put at its own volatility = %v, want [0.4]", got)
+	}
+
+	expiring := call
+	expiring.DaysToExpiration = 0
+	if got := VegaVsIV(expiring, [2]float64{20, 40}, 3); !equalNaN(got, []float64{math.NaN(), math.NaN(), math.NaN()}) {
+		t.Errorf("VegaVsIV of an expiring option = %v, want NaNs", got)
+	}
+}
@@ -0,0 +1,115 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// Methods accepted by ExtrapolateIVByExpiry.
+const (
+	// ExtrapolateFlat copies the implied volatility of the nearest strike with one.
+	ExtrapolateFlat = "flat"
+	// ExtrapolateLinear continues the slope between the two nearest strikes with an implied volatility.
+	ExtrapolateLinear = "linear"
+)
+
+// InterpolateIVSurface returns a copy of surface in which NaN implied volatilities between two strikes of the
+// same expiration with a known volatility are interpolated linearly in strike. Volatilities beyond the lowest
+// and highest known strikes are left NaN, see ExtrapolateIVByExpiry.
+//
+// The values are approximations, good enough to draw a smile but not to price options. Only each point's
+// ImpliedVolatility is filled; its ExpDateOption is left as TD Ameritrade reported it.
+func InterpolateIVSurface(surface *tdameritrade.GreeksSurface) *tdameritrade.GreeksSurface {
+	filled := surface.Clone()
+	for _, expiry := range filled.Expirations {
+		points := expiryPoints(filled, expiry)
+		known := knownIndexes(points)
+		for k := 1; k < len(known); k++ {
+			lo, hi := points[known[k-1]], points[known[k]]
+			for _, p := range points[known[k-1]+1 : known[k]] {
+				p.ImpliedVolatility = lerp(lo.StrikePrice, lo.ImpliedVolatility, hi.StrikePrice, hi.ImpliedVolatility, p.StrikePrice)
+			}
+		}
+	}
+	return filled
+}
+
+// ExtrapolateIVByExpiry returns a copy of surface in which, for each expiration, NaN implied volatilities
+// at strikes below the lowest or above the highest strike with a known volatility are extrapolated with
+// method, ExtrapolateFlat or ExtrapolateLinear. It returns nil for any other method.
+// Linear extrapolation falls back to flat for expirations with a single known volatility and never goes
+// below zero. Volatilities between known strikes are left NaN, see InterpolateIVSurface.
+//
+// The values are approximations, and the further they are from the known strikes the rougher they get.
+// Only each point's ImpliedVolatility is filled; its ExpDateOption is left as TD Ameritrade reported it.
+func ExtrapolateIVByExpiry(surface *tdameritrade.GreeksSurface, method string) *tdameritrade.GreeksSurface {
+	if method != ExtrapolateFlat && method != ExtrapolateLinear {
+		return nil
+	}
+
+	filled := surface.Clone()
+	for _, expiry := range filled.Expirations {
+		points := expiryPoints(filled, expiry)
+		known := knownIndexes(points)
+		if len(known) == 0 {
+			continue
+		}
+		first, last := known[0], known[len(known)-1]
+		linear := method == ExtrapolateLinear && len(known) > 1
+
+		// Below the lowest strike, from the two lowest known strikes.
+		a, b := points[first], points[first]
+		if linear {
+			b = points[known[1]]
+		}
+		for _, p := range points[:first] {
+			p.ImpliedVolatility = extrapolate(a, b, p.StrikePrice, linear)
+		}
+
+		// Above the highest strike, from the two highest known strikes.
+		a, b = points[last], points[last]
+		if linear {
+			a = points[known[len(known)-2]]
+		}
+		for _, p := range points[last+1:] {
+			p.ImpliedVolatility = extrapolate(a, b, p.StrikePrice, linear)
+		}
+	}
+	return filled
+}
+
+// expiryPoints returns the points of expiry on surface in ascending order of strike.
+func expiryPoints(surface *tdameritrade.GreeksSurface, expiry string) []*tdameritrade.GreekPoint {
+	var points []*tdameritrade.GreekPoint
+	for _, strike := range surface.Strikes {
+		if p := surface.Get(expiry, strike); p != nil {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// knownIndexes returns the indexes of the points with an implied volatility.
+func knownIndexes(points []*tdameritrade.GreekPoint) []int {
+	var known []int
+	for i, p := range points {
+		if !math.IsNaN(p.ImpliedVolatility) && !math.IsInf(p.ImpliedVolatility, 0) {
+			known = append(known, i)
+		}
+	}
+	return known
+}
+
+// extrapolate returns the volatility at strike on the line through a and b, or a's volatility if not linear.
+func extrapolate(a, b *tdameritrade.GreekPoint, strike float64, linear bool) float64 {
+	if !linear {
+		return a.ImpliedVolatility
+	}
+	return math.Max(lerp(a.StrikePrice, a.ImpliedVolatility, b.StrikePrice, b.ImpliedVolatility, strike), 0)
+}
+
+// lerp returns the y at x on the line through (x0, y0) and (x1, y1).
+func lerp(x0, y0, x1, y1, x float64) float64 {
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// ivChain returns a chain of calls expiring on 2020-07-17 with the given implied volatility at each strike.
+func ivChain(ivs map[float64]float64) *tdameritrade.Chains {
+	strikes := map[string][]tdameritrade.ExpDateOption{}
+	for strike, iv := range ivs {
+		strikes[fmt.Sprintf("%.1f", strike)] = []tdameritrade.ExpDateOption{{PutCall: "CALL", StrikePrice: strike, Volatility: tdameritrade.Float64WithSpecial(iv)}}
+	}
+	return &tdameritrade.Chains{CallExpDateMap: tdameritrade.ExpDateMap{"2020-07-17:10": strikes}}
+}
+
+func skew(surface *tdameritrade.GreeksSurface) []float64 {
+	var ivs []float64
+	for _, p := range surface.VolatilitySkew("2020-07-17") {
+		ivs = append(ivs, p.ImpliedVolatility)
+	}
+	return ivs
+}
+
+// equalNaN reports whether a and b are equal, treating NaNs as equal.
+func equalNaN(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && !(math.IsNaN(a[i]) && math.IsNaN(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestInterpolateIVSurface(t *testing.T) {
+	nan := math.NaN()
+	surface := tdameritrade.BuildGreeksSurface(ivChain(map[float64]float64{90: nan, 100: 20, 105: nan, 110: nan, 120: 30, 130: nan}), "CALL")
+
+	filled := InterpolateIVSurface(surface)
+	if want := []float64{nan, 20, 22.5, 25, 30, nan}; !equalNaN(skew(filled), want) {
+		t.Errorf("interpolated skew = %v, want %v", skew(filled), want)
+	}
+	if want := []float64{nan, 20, nan, nan, 30, nan}; !equalNaN(skew(surface), want) {
+		t.Errorf("the surface was changed: %v", skew(surface))
+	}
+	if p := filled.Get("2020-07-17", 110); !math.IsNaN(float64(p.Volatility)) {
+		t.Errorf("expected the option's Volatility to be left NaN, got %v", p.Volatility)
+	}
+}
+
+func TestExtrapolateIVByExpiry(t *testing.T) {
+	nan := math.NaN()
+	surface := tdameritrade.BuildGreeksSurface(ivChain(map[float64]float64{80: nan, 90: nan, 100: 20, 110: nan, 120: 30, 130: nan}), "CALL")
+
+	tests := []struct {
+		method string
+		want   []float64
+	}{
+		{ExtrapolateFlat, []float64{20, 20, 20, nan, 30, 30}},
+		{ExtrapolateLinear, []float64{10, 15, 20, nan, 30, 35}},
+	}
+	for _, tt := range tests {
+		if got := skew(ExtrapolateIVByExpiry(surface, tt.method)); !equalNaN(got, tt.want) {
+			t.Errorf("%s: extrapolated skew = %v, want %v", tt.method, got, tt.want)
+		}
+	}
+
+	if got := ExtrapolateIVByExpiry(surface, "cubic"); got != nil {
+		t.Errorf("expected nil for an unknown method, got %v", got)
+	}
+
+	// A steep wing is not extrapolated below zero.
+	steep := tdameritrade.BuildGreeksSurface(ivChain(map[float64]float64{60: nan, 100: 5, 110: 20}), "CALL")
+	if got := skew(ExtrapolateIVByExpiry(steep, ExtrapolateLinear)); !reflect.DeepEqual(got, []float64{0, 5, 20}) {
+		t.Errorf("steep skew = %v, want [0 5 20]", got)
+	}
+
+	// A single known volatility is extrapolated flat.
+	single := tdameritrade.BuildGreeksSurface(ivChain(map[float64]float64{90: nan, 100: 20, 110: nan}), "CALL")
+	if got := skew(ExtrapolateIVByExpiry(single, ExtrapolateLinear)); !reflect.DeepEqual(got, []float64{20, 20, 20}) {
+		t.Errorf("single skew = %v, want [20 20 20]", got)
+	}
+}
@@ -0,0 +1,40 @@
+// Package analysis converts TD Ameritrade data for use with gonum and approximates data missing from it,
+// such as the implied volatility of illiquid options or how the Greeks of an option move with the market.
+// It is separate from the tdameritrade package so that only its users depend on gonum.
+package analysis
+
+import (
+	"errors"
+	"math"
+
+	"github.com/kuzmak/go-tdameritrade"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ChainsToMatrix returns a matrix of fieldFn applied to the calls of chain, with a row for each expiration and
+// a column for each strike, along with the expirations (e.g. 2020-07-17) and strikes labelling the rows and columns,
+// both in ascending order. Cells for strikes an expiration does not have are NaN.
+//
+// Usage example:
+// deltas, expirations, strikes, err := analysis.ChainsToMatrix(chain, func(o *tdameritrade.ExpDateOption) float64 { return float64(o.Delta) })
+func ChainsToMatrix(chain *tdameritrade.Chains, fieldFn func(*tdameritrade.ExpDateOption) float64) (*mat.Dense, []string, []float64, error) {
+	if chain == nil {
+		return nil, nil, nil, errors.New("no chain present")
+	}
+	surface := tdameritrade.BuildGreeksSurface(chain, "CALL")
+	if len(surface.Expirations) == 0 {
+		return nil, nil, nil, errors.New("chain has no calls")
+	}
+
+	m := mat.NewDense(len(surface.Expirations), len(surface.Strikes), nil)
+	for i, expiry := range surface.Expirations {
+		for j, strike := range surface.Strikes {
+			v := math.NaN()
+			if p := surface.Get(expiry, strike); p != nil {
+				v = fieldFn(&p.ExpDateOption)
+			}
+			m.Set(i, j, v)
+		}
+	}
+	return m, surface.Expirations, surface.Strikes, nil
+}
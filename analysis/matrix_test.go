@@ -0,0 +1,49 @@
+package analysis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+func TestChainsToMatrix(t *testing.T) {
+	b, err := ioutil.ReadFile("../testdata/chains.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := new(tdameritrade.Chains)
+	if err := json.Unmarshal(b, chain); err != nil {
+		t.Fatal(err)
+	}
+	// Drop a strike from the second expiration to leave a gap in the matrix.
+	for key := range chain.CallExpDateMap {
+		if key[:10] == "2020-08-21" {
+			delete(chain.CallExpDateMap[key], "315.0")
+		}
+	}
+
+	m, expirations, strikes, err := ChainsToMatrix(chain, func(o *tdameritrade.ExpDateOption) float64 { return o.Mark })
+	if err != nil {
+		t.Fatalf("ChainsToMatrix returned error: %v", err)
+	}
+	if !reflect.DeepEqual(expirations, []string{"2020-07-17", "2020-08-21"}) || !reflect.DeepEqual(strikes, []float64{305, 310, 315}) {
+		t.Errorf("unexpected labels: %v %v", expirations, strikes)
+	}
+	if rows, cols := m.Dims(); rows != 2 || cols != 3 {
+		t.Fatalf("matrix is %dx%d, want 2x3", rows, cols)
+	}
+	if m.At(0, 0) != 7.22 || m.At(1, 1) != 6.02 {
+		t.Errorf("unexpected marks: %v", m.RawMatrix().Data)
+	}
+	if !math.IsNaN(m.At(1, 2)) {
+		t.Errorf("expected a missing strike to be NaN, got %v", m.At(1, 2))
+	}
+
+	if _, _, _, err := ChainsToMatrix(&tdameritrade.Chains{}, nil); err == nil {
+		t.Error("expected an error for a chain without calls")
+	}
+}
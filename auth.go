@@ -3,13 +3,29 @@ package tdameritrade
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"golang.org/x/oauth2"
 )
 
+// Endpoint is TD Ameritrade's OAuth2 endpoint.
+// TD Ameritrade apps have no client secret, so the client ID is sent in the request body.
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://auth.tdameritrade.com/auth",
+	TokenURL:  "https://api.tdameritrade.com/v1/oauth2/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
 var (
 	// ErrNoCode is returned when the TD Ameritrade request is missing a code.
 	ErrNoCode = fmt.Errorf("missing code in request from TD Ameritrade")
@@ -36,6 +52,22 @@ type PersistentStore interface {
 type Authenticator struct {
 	Store  PersistentStore
 	OAuth2 oauth2.Config
+
+	// OnTokenRefresh is called with the new token each time a TokenSource refreshes its access token.
+	// Use it to persist the refresh token, which TD Ameritrade may rotate, so it survives process restarts.
+	OnTokenRefresh func(newToken *oauth2.Token)
+
+	// OnTokenExpired is called when TD Ameritrade rejects the refresh token of a TokenSource,
+	// usually because it has expired. Users need to log in again.
+	// It is not called for other errors refreshing the access token, such as network errors.
+	OnTokenExpired func()
+
+	// InteractiveLoginTimeout is how long StartInteractiveLogin waits for the user to log in, 5 minutes if zero.
+	InteractiveLoginTimeout time.Duration
+
+	// verifier is the PKCE code verifier of the flow started by the last call to AuthURL.
+	mu       sync.Mutex
+	verifier string
 }
 
 // NewAuthenticator will automatically append @AMER.OAUTHAP to the client ID to save callers hours of frustration.
@@ -47,6 +79,86 @@ func NewAuthenticator(store PersistentStore, oauth2 oauth2.Config) *Authenticato
 	}
 }
 
+// NewOAuth2Config returns an oauth2.Config for TD Ameritrade's Endpoint.
+// Pass it to NewAuthenticator, which appends @AMER.OAUTHAP to clientID.
+func NewOAuth2Config(clientID, redirectURI string) oauth2.Config {
+	return oauth2.Config{
+		ClientID:    clientID,
+		Endpoint:    Endpoint,
+		RedirectURL: redirectURI,
+	}
+}
+
+// AuthURL returns TD Ameritrade's Auth URL for state.
+// Unlike StartOAuth2Flow, callers are responsible for generating state and checking it when the user returns.
+// Use this for applications that do not serve HTTP requests, such as command line tools.
+//
+// Each call starts a PKCE flow: the URL carries the code_challenge of a new code verifier, which Exchange sends
+// with the code. A flow started by an earlier call can no longer be completed.
+func (a *Authenticator) AuthURL(state string) string {
+	verifier, err := randomState()
+	if err != nil {
+		// Without a verifier the flow continues without PKCE.
+		return a.OAuth2.AuthCodeURL(state)
+	}
+	a.mu.Lock()
+	a.verifier = verifier
+	a.mu.Unlock()
+	return a.OAuth2.AuthCodeURL(state, pkceChallenge(verifier)...)
+}
+
+// Exchange trades the code TD Ameritrade sends to the redirect URI for a token, with the code verifier of the
+// flow started by AuthURL.
+// The token includes a refresh token, so its access token can be refreshed by TokenSource.
+func (a *Authenticator) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	opts := []oauth2.AuthCodeOption{oauth2.AccessTypeOffline}
+	a.mu.Lock()
+	verifier := a.verifier
+	a.mu.Unlock()
+	if verifier != "" {
+		opts = append(opts, pkceVerifier(verifier))
+	}
+	return a.OAuth2.Exchange(ctx, code, opts...)
+}
+
+// pkceChallenge returns the parameters of the auth URL challenging the client to present verifier on exchange,
+// as described in RFC 7636.
+func pkceChallenge(verifier string) []oauth2.AuthCodeOption {
+	sum := sha256.Sum256([]byte(verifier))
+	return []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(sum[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	}
+}
+
+// pkceVerifier returns the parameter of the token request presenting verifier.
+func pkceVerifier(verifier string) oauth2.AuthCodeOption {
+	return oauth2.SetAuthURLParam("code_verifier", verifier)
+}
+
+// TokenSource returns a TokenSource that refreshes t's access token when it expires.
+// Pass it to oauth2.NewClient to create the *http.Client NewClient takes.
+// OnTokenRefresh and OnTokenExpired are called as the token is refreshed.
+func (a *Authenticator) TokenSource(ctx context.Context, t *oauth2.Token) oauth2.TokenSource {
+	ts := &notifyingTokenSource{
+		source:    a.OAuth2.TokenSource(ctx, t),
+		onRefresh: a.OnTokenRefresh,
+		onExpired: a.OnTokenExpired,
+	}
+	if t != nil {
+		ts.accessToken = t.AccessToken
+	}
+	return ts
+}
+
+// RefreshToken returns a new token for refreshToken.
+// Access tokens expire after 30 minutes and are refreshed automatically by TokenSource,
+// but refresh tokens expire after 90 days. Store the refresh token of the returned token before then.
+func (a *Authenticator) RefreshToken(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+	// A token with no access token is always expired, so the TokenSource refreshes it straight away.
+	return a.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+}
+
 // AuthenticatedClient tries to create an authenticated `Client` from a user's request
 func (a *Authenticator) AuthenticatedClient(ctx context.Context, req *http.Request) (*Client, error) {
 	token, err := a.Store.GetToken(req)
@@ -54,7 +166,7 @@ func (a *Authenticator) AuthenticatedClient(ctx context.Context, req *http.Reque
 		return nil, err
 	}
 
-	authenticatedClient := a.OAuth2.Client(ctx, token)
+	authenticatedClient := oauth2.NewClient(ctx, a.TokenSource(ctx, token))
 	return NewClient(authenticatedClient)
 }
 
@@ -63,14 +175,13 @@ func (a *Authenticator) AuthenticatedClient(ctx context.Context, req *http.Reque
 func (a *Authenticator) StartOAuth2Flow(w http.ResponseWriter, req *http.Request) (string, error) {
 	// Do not leave state generation up to callers.
 	// Experience has shown that people often do not know what OAuth2 state is and leave themselves vulnerable to CSRF attacks.
-	b := make([]byte, 32)
-	if _, err := rand.Read(b); err != nil {
+	state, err := randomState()
+	if err != nil {
 		return "", err
 	}
 
 	// Instead, have callers store the state we give them and present it to us when we ask for it again.
-	state := base64.RawURLEncoding.EncodeToString(b)
-	err := a.Store.StoreState(state, w, req)
+	err = a.Store.StoreState(state, w, req)
 	if err != nil {
 		return "", err
 	}
@@ -78,6 +189,15 @@ func (a *Authenticator) StartOAuth2Flow(w http.ResponseWriter, req *http.Request
 	return a.OAuth2.AuthCodeURL(state), nil
 }
 
+// randomState returns a random OAuth2 state value.
+func randomState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 // FinishOAuth2Flow finishes authenticating a user returning from TD Ameritrade.
 // It verifies that TD Ameritrade has returned the expected state to prevent CSRF attacks and returns an authenticated `Client` on success.
 func (a *Authenticator) FinishOAuth2Flow(ctx context.Context, w http.ResponseWriter, req *http.Request) (*Client, error) {
@@ -115,6 +235,105 @@ func (a *Authenticator) FinishOAuth2Flow(ctx context.Context, w http.ResponseWri
 		return nil, err
 	}
 
-	authenticatedClient := a.OAuth2.Client(ctx, token)
+	authenticatedClient := oauth2.NewClient(ctx, a.TokenSource(ctx, token))
 	return NewClient(authenticatedClient)
 }
+
+// notifyingTokenSource calls its callbacks when the wrapped TokenSource refreshes a token.
+// The wrapped TokenSource only refreshes expired tokens, so a refresh is detected by a change of access token.
+type notifyingTokenSource struct {
+	source    oauth2.TokenSource
+	onRefresh func(*oauth2.Token)
+	onExpired func()
+
+	mu          sync.Mutex
+	accessToken string
+}
+
+func (s *notifyingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := s.source.Token()
+	if err != nil {
+		if s.onExpired != nil && isRejectedTokenError(err) {
+			s.onExpired()
+		}
+		return nil, err
+	}
+
+	if token.AccessToken != s.accessToken {
+		s.accessToken = token.AccessToken
+		if s.onRefresh != nil {
+			s.onRefresh(token)
+		}
+	}
+
+	return token, nil
+}
+
+// isRejectedTokenError reports whether err is TD Ameritrade rejecting a refresh token:
+// an invalid_grant error or a 400 or 401 response from the token endpoint.
+func isRejectedTokenError(err error) bool {
+	var re *oauth2.RetrieveError
+	if !errors.As(err, &re) {
+		return false
+	}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(re.Body, &body) == nil && body.Error == "invalid_grant" {
+		return true
+	}
+	return re.Response != nil &&
+		(re.Response.StatusCode == http.StatusBadRequest || re.Response.StatusCode == http.StatusUnauthorized)
+}
+
+// SaveTokenToFile writes t to path as JSON, readable only by the current user.
+// The token is written to a temporary file in the same directory which then replaces path, so a crash while
+// saving leaves the previous token intact.
+// It can be used as an OnTokenRefresh callback:
+// authenticator.OnTokenRefresh = func(t *oauth2.Token) { tdameritrade.SaveTokenToFile(path, t) }
+func SaveTokenToFile(path string, t *oauth2.Token) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadTokenFromFile reads a token written by SaveTokenToFile.
+func LoadTokenFromFile(path string) (*oauth2.Token, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	token := new(oauth2.Token)
+	if err := json.Unmarshal(b, token); err != nil {
+		return nil, fmt.Errorf("could not decode token in %s: %w", path, err)
+	}
+	return token, nil
+}
@@ -0,0 +1,130 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrLoginTimeout is sent by StartInteractiveLogin when the user does not log in within InteractiveLoginTimeout.
+var ErrLoginTimeout = errors.New("timed out waiting for login")
+
+const defaultInteractiveLoginTimeout = 5 * time.Minute
+
+// StartInteractiveLogin logs a user in without a web application of the caller's, for command line tools and
+// other headless applications run by someone with a browser at hand.
+// It serves the redirect from TD Ameritrade on a random port of localhost, and returns the auth URL to show the user,
+// whose redirect URI is http://localhost:{port}/callback. TD Ameritrade only redirects to the redirect URIs of
+// the app, so the app's callback URL must be set to http://localhost for any port to be accepted. If the
+// Authenticator's RedirectURL names another loopback host, such as 127.0.0.1, that host is used instead.
+//
+// Requests to the callback without the state of the login are answered with 400 Bad Request and ignored, so
+// that other pages cannot end the login. When the user is redirected back, the code is exchanged and the token
+// is sent on doneCh. Otherwise a single error is sent on errCh: a redirect without a code, a failed exchange, or
+// ErrLoginTimeout after InteractiveLoginTimeout. The local server is shut down either way.
+func (a *Authenticator) StartInteractiveLogin() (authURL string, doneCh <-chan *oauth2.Token, errCh <-chan error) {
+	done := make(chan *oauth2.Token, 1)
+	errs := make(chan error, 1)
+	var verifier string
+
+	state, err := randomState()
+	if err == nil {
+		verifier, err = randomState()
+	}
+	if err != nil {
+		errs <- err
+		return "", done, errs
+	}
+	host := interactiveLoginHost(a.OAuth2.RedirectURL)
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		errs <- fmt.Errorf("could not listen for the login redirect: %w", err)
+		return "", done, errs
+	}
+
+	config := a.OAuth2
+	config.RedirectURL = fmt.Sprintf("http://%s/callback", net.JoinHostPort(host, strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)))
+
+	timeout := a.InteractiveLoginTimeout
+	if timeout == 0 {
+		timeout = defaultInteractiveLoginTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	mux := http.NewServeMux()
+	server := &http.Server{Handler: mux}
+	var once sync.Once
+	finish := func(token *oauth2.Token, err error) {
+		once.Do(func() {
+			if err != nil {
+				errs <- err
+			} else {
+				done <- token
+			}
+			cancel()
+		})
+	}
+
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		switch {
+		case query.Get("state") == "":
+			// Not a redirect from TD Ameritrade, e.g. a request from another page; keep waiting for it.
+			http.Error(w, ErrNoState.Error(), http.StatusBadRequest)
+			return
+		case query.Get("state") != state:
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		case query.Get("code") == "":
+			http.Error(w, ErrNoCode.Error(), http.StatusBadRequest)
+			finish(nil, ErrNoCode)
+			return
+		}
+
+		token, err := config.Exchange(ctx, query.Get("code"), oauth2.AccessTypeOffline, pkceVerifier(verifier))
+		if err != nil {
+			http.Error(w, "could not log in", http.StatusInternalServerError)
+			finish(nil, err)
+			return
+		}
+		fmt.Fprint(w, "Logged in to TD Ameritrade. You can close this window.")
+		finish(token, nil)
+	})
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	go func() {
+		<-ctx.Done()
+		if ctx.Err() == context.DeadlineExceeded {
+			finish(nil, ErrLoginTimeout)
+		}
+		// Let the response of the callback be written before shutting down.
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelShutdown()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	return config.AuthCodeURL(state, pkceChallenge(verifier)...), done, errs
+}
+
+// interactiveLoginHost returns the host of redirectURL if it is a loopback host, and localhost otherwise.
+func interactiveLoginHost(redirectURL string) string {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return "localhost"
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host == "localhost" || ip != nil && ip.IsLoopback() {
+		return host
+	}
+	return "localhost"
+}
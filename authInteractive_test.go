@@ -0,0 +1,113 @@
+package tdameritrade
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartInteractiveLogin(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "code", "CODE")
+		if got := r.FormValue("redirect_uri"); !strings.HasPrefix(got, "http://localhost:") {
+			t.Errorf("exchanged with redirect_uri %q, want the local callback", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"ACCESSTOKEN","refresh_token":"REFRESHTOKEN","token_type":"Bearer","expires_in":1800}`))
+	}))
+	defer tokenServer.Close()
+
+	config := NewOAuth2Config("CLIENTID", "")
+	config.Endpoint.TokenURL = tokenServer.URL
+	authenticator := NewAuthenticator(nil, config)
+
+	authURL, doneCh, errCh := authenticator.StartInteractiveLogin()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	redirect, err := url.Parse(u.Query().Get("redirect_uri"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redirect.Hostname() != "localhost" || redirect.Port() == "" || redirect.Path != "/callback" {
+		t.Fatalf("unexpected redirect_uri: %v", redirect)
+	}
+
+	resp, err := http.Get(redirect.String() + "?code=CODE&state=" + url.QueryEscape(u.Query().Get("state")))
+	if err != nil {
+		t.Fatalf("callback returned error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("callback returned status %d, want 200", resp.StatusCode)
+	}
+
+	select {
+	case token := <-doneCh:
+		if token.AccessToken != "ACCESSTOKEN" || token.RefreshToken != "REFRESHTOKEN" {
+			t.Errorf("unexpected token: %+v", token)
+		}
+	case err := <-errCh:
+		t.Fatalf("StartInteractiveLogin failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the token")
+	}
+}
+
+func TestStartInteractiveLoginErrors(t *testing.T) {
+	authenticator := NewAuthenticator(nil, NewOAuth2Config("CLIENTID", "http://127.0.0.1/callback"))
+	authURL, _, errCh := authenticator.StartInteractiveLogin()
+	u, _ := url.Parse(authURL)
+	redirect, _ := url.Parse(u.Query().Get("redirect_uri"))
+	if redirect.Hostname() != "127.0.0.1" {
+		t.Errorf("redirect_uri %v, want the host of the configured redirect URL", redirect)
+	}
+
+	// Forged callbacks are rejected without ending the login.
+	for _, query := range []string{"?code=CODE&state=forged", "?code=CODE"} {
+		resp, err := http.Get(redirect.String() + query)
+		if err != nil {
+			t.Fatalf("callback returned error: %v", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("callback %s returned status %d, want 400", query, resp.StatusCode)
+		}
+		if strings.Contains(string(body), u.Query().Get("state")) {
+			t.Errorf("callback %s answered with the expected state: %s", query, body)
+		}
+	}
+	select {
+	case err := <-errCh:
+		t.Fatalf("forged callbacks ended the login: %v", err)
+	default:
+	}
+
+	// A redirect from TD Ameritrade without a code, as when the user denies access, ends it.
+	resp, err := http.Get(redirect.String() + "?state=" + url.QueryEscape(u.Query().Get("state")))
+	if err != nil {
+		t.Fatalf("callback returned error: %v", err)
+	}
+	resp.Body.Close()
+	if err := <-errCh; !errors.Is(err, ErrNoCode) {
+		t.Errorf("StartInteractiveLogin error = %v, want ErrNoCode", err)
+	}
+
+	authenticator.InteractiveLoginTimeout = 10 * time.Millisecond
+	_, _, errCh = authenticator.StartInteractiveLogin()
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrLoginTimeout) {
+			t.Errorf("StartInteractiveLogin error = %v, want ErrLoginTimeout", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("StartInteractiveLogin did not time out")
+	}
+}
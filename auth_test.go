@@ -2,9 +2,15 @@ package tdameritrade
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -176,3 +182,219 @@ func TestFinishOAuth2RejectsEmptyCode(t *testing.T) {
 		t.Fatalf("client returned despite empty code.")
 	}
 }
+
+func TestExchangeAndRefreshToken(t *testing.T) {
+	var grants []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		testFormValue(t, r, "client_id", "CLIENTID@AMER.OAUTHAP")
+		grants = append(grants, r.Form.Get("grant_type"))
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			testFormValue(t, r, "code", "code")
+			testFormValue(t, r, "access_type", "offline")
+		case "refresh_token":
+			testFormValue(t, r, "refresh_token", "REFRESHTOKEN")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"ACCESSTOKEN","refresh_token":"REFRESHTOKEN","token_type":"Bearer","expires_in":1800}`))
+	}))
+	defer ts.Close()
+
+	config := NewOAuth2Config("CLIENTID", "https://localhost:8080/callback")
+	config.Endpoint.TokenURL = ts.URL
+	authenticator := NewAuthenticator(nil, config)
+
+	ctx := context.Background()
+	token, err := authenticator.Exchange(ctx, "code")
+	if err != nil {
+		t.Fatalf("Exchange returned error: %v", err)
+	}
+	if token.AccessToken != "ACCESSTOKEN" || token.RefreshToken != "REFRESHTOKEN" {
+		t.Errorf("unexpected token: %+v", token)
+	}
+
+	token, err = authenticator.RefreshToken(ctx, "REFRESHTOKEN")
+	if err != nil {
+		t.Fatalf("RefreshToken returned error: %v", err)
+	}
+	if token.AccessToken != "ACCESSTOKEN" {
+		t.Errorf("unexpected token: %+v", token)
+	}
+
+	if len(grants) != 2 || grants[0] != "authorization_code" || grants[1] != "refresh_token" {
+		t.Errorf("unexpected grants: %v", grants)
+	}
+}
+
+func TestAuthURL(t *testing.T) {
+	authenticator := NewAuthenticator(nil, NewOAuth2Config("CLIENTID", "https://localhost:8080/callback"))
+
+	u, err := url.Parse(authenticator.AuthURL("state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "auth.tdameritrade.com" || u.Query().Get("state") != "state" || u.Query().Get("client_id") != "CLIENTID@AMER.OAUTHAP" {
+		t.Errorf("unexpected auth URL: %v", u)
+	}
+}
+
+func TestPKCE(t *testing.T) {
+	var verifier string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("could not parse the token request: %v", err)
+			return
+		}
+		verifier = r.Form.Get("code_verifier")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"ACCESSTOKEN","refresh_token":"REFRESHTOKEN","token_type":"Bearer","expires_in":1800}`))
+	}))
+	defer ts.Close()
+
+	config := NewOAuth2Config("CLIENTID", "https://localhost:8080/callback")
+	config.Endpoint.TokenURL = ts.URL
+	authenticator := NewAuthenticator(nil, config)
+
+	first, err := url.Parse(authenticator.AuthURL("state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authenticator.AuthURL("state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge := u.Query().Get("code_challenge")
+	if u.Query().Get("code_challenge_method") != "S256" || challenge == "" {
+		t.Fatalf("auth URL %v has no S256 code challenge", u)
+	}
+	if challenge == first.Query().Get("code_challenge") {
+		t.Error("expected a new code challenge for each flow")
+	}
+
+	if _, err := authenticator.Exchange(context.Background(), "code"); err != nil {
+		t.Fatalf("Exchange returned error: %v", err)
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	if verifier == "" || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+		t.Errorf("code_verifier %q does not match the code_challenge %q of the last auth URL", verifier, challenge)
+	}
+}
+
+func TestTokenSourceCallbacks(t *testing.T) {
+	failStatus, failBody := 0, ""
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failStatus != 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(failStatus)
+			w.Write([]byte(failBody))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"NEWACCESSTOKEN","refresh_token":"NEWREFRESHTOKEN","token_type":"Bearer","expires_in":1800}`))
+	}))
+	defer ts.Close()
+
+	config := NewOAuth2Config("CLIENTID", "https://localhost:8080/callback")
+	config.Endpoint.TokenURL = ts.URL
+	authenticator := NewAuthenticator(nil, config)
+
+	var refreshed []*oauth2.Token
+	expired := 0
+	authenticator.OnTokenRefresh = func(t *oauth2.Token) { refreshed = append(refreshed, t) }
+	authenticator.OnTokenExpired = func() { expired++ }
+
+	ctx := context.Background()
+
+	valid := &oauth2.Token{AccessToken: "ACCESSTOKEN", RefreshToken: "REFRESHTOKEN", Expiry: time.Now().Add(time.Hour)}
+	if _, err := authenticator.TokenSource(ctx, valid).Token(); err != nil {
+		t.Fatal(err)
+	}
+	if len(refreshed) != 0 {
+		t.Fatalf("OnTokenRefresh called for a valid token")
+	}
+
+	source := authenticator.TokenSource(ctx, &oauth2.Token{AccessToken: "ACCESSTOKEN", RefreshToken: "REFRESHTOKEN", Expiry: time.Now().Add(-time.Hour)})
+	for i := 0; i < 2; i++ {
+		if _, err := source.Token(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(refreshed) != 1 || refreshed[0].RefreshToken != "NEWREFRESHTOKEN" {
+		t.Fatalf("expected one refresh with the new token, got %v", refreshed)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		status int
+		body   string
+		want   int
+	}{
+		{"server error", http.StatusInternalServerError, `{"error":"server_error"}`, 0},
+		{"invalid_grant", http.StatusBadRequest, `{"error":"invalid_grant"}`, 1},
+		{"unauthorized", http.StatusUnauthorized, `{}`, 1},
+	} {
+		expired, failStatus, failBody = 0, tt.status, tt.body
+		if _, err := authenticator.TokenSource(ctx, &oauth2.Token{RefreshToken: "EXPIRED"}).Token(); err == nil {
+			t.Fatalf("%s: expected refresh to fail", tt.name)
+		}
+		if expired != tt.want {
+			t.Errorf("%s: OnTokenExpired called %d times, want %d", tt.name, expired, tt.want)
+		}
+	}
+
+	// Network errors do not mean that the refresh token has expired.
+	ts.Close()
+	expired = 0
+	if _, err := authenticator.TokenSource(ctx, &oauth2.Token{RefreshToken: "REFRESHTOKEN"}).Token(); err == nil {
+		t.Fatal("expected refresh to fail")
+	}
+	if expired != 0 {
+		t.Errorf("OnTokenExpired called %d times after a network error, want 0", expired)
+	}
+}
+
+func TestSaveAndLoadToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tdameritrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A token saved before with broader permissions is replaced by one readable only by the current user.
+	path := filepath.Join(dir, "token.json")
+	if err := ioutil.WriteFile(path, []byte(`{"access_token":"OLD"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := &oauth2.Token{
+		AccessToken:  "ACCESSTOKEN",
+		TokenType:    "Bearer",
+		RefreshToken: "REFRESHTOKEN",
+		Expiry:       time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := SaveTokenToFile(path, want); err != nil {
+		t.Fatalf("SaveTokenToFile returned error: %v", err)
+	}
+
+	got, err := LoadTokenFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadTokenFromFile returned error: %v", err)
+	}
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken || !got.Expiry.Equal(want.Expiry) {
+		t.Errorf("LoadTokenFromFile = %+v, want %+v", got, want)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("token file mode = %v, want 0600", info.Mode().Perm())
+	}
+	if files, err := ioutil.ReadDir(dir); err != nil || len(files) != 1 {
+		t.Errorf("expected only the token file to be left, got %d files, err %v", len(files), err)
+	}
+}
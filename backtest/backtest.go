@@ -0,0 +1,271 @@
+// Package backtest replays the candles of the tdameritrade package, such as those of
+// PriceHistoryService.GetPriceHistory, through a trading strategy and measures how it would have performed.
+//
+// Usage example:
+// history, _, err := client.PriceHistory.GetPriceHistory(ctx, "SPY", params)
+// result := backtest.Backtest(history.Candles, strategy)
+// fmt.Println(result.TotalReturn, result.MaxDrawdown)
+package backtest
+
+import (
+	"math"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// DefaultCash is the cash the portfolio of Backtest starts with.
+const DefaultCash = 100000
+
+// FillModel is the price an Order is filled at.
+type FillModel int
+
+const (
+	// FillAtClose fills the order at the close of the bar it is placed on.
+	FillAtClose FillModel = iota
+	// FillAtNextOpen fills the order at the open of the bar after the one it is placed on.
+	FillAtNextOpen
+	// FillAtLimit fills the order on the bar after the one it is placed on if it trades at LimitPrice or better,
+	// at the open if that is already better, and cancels it otherwise.
+	FillAtLimit
+)
+
+// Order is a trade a Strategy places of Quantity units of Symbol, positive to buy and negative to sell.
+// Orders that are not filled by the end of the candles are dropped.
+type Order struct {
+	Symbol     string
+	Quantity   float64
+	Fill       FillModel
+	LimitPrice float64
+}
+
+// Strategy decides the orders to place on each bar, after the orders of the previous bar were filled.
+type Strategy interface {
+	OnBar(bar tdameritrade.Candle, portfolio *Portfolio) []Order
+}
+
+// Portfolio is the cash and positions of a strategy, keyed by symbol, and the commission paid per trade.
+// Cash may become negative, as if bought on margin.
+type Portfolio struct {
+	Cash       float64
+	Positions  map[string]float64
+	Commission float64
+
+	averagePrices map[string]float64
+}
+
+// Value returns the cash and the positions valued at price.
+func (p *Portfolio) Value(price float64) float64 {
+	value := p.Cash
+	for _, quantity := range p.Positions {
+		value += quantity * price
+	}
+	return value
+}
+
+// TradeRecord is a filled Order. RealizedPnL is the profit or loss, after commission, of the part of the trade
+// reducing a position, relative to the average price the position was opened at.
+type TradeRecord struct {
+	Symbol      string
+	Quantity    float64
+	Price       float64
+	Commission  float64
+	DateTime    int64
+	RealizedPnL float64
+
+	closing bool
+}
+
+// BacktestResult is the performance of a strategy over the candles. Returns are fractions, e.g. 0.1 for 10%.
+// CAGR and SharpeRatio are annualized from the time spanned by the candles and are 0 when it is not positive.
+// MaxDrawdown is the largest fall of the portfolio value from a previous peak, as a positive fraction, and
+// WinRate is the fraction of the trades reducing a position that made a profit.
+type BacktestResult struct {
+	TotalReturn float64
+	CAGR        float64
+	SharpeRatio float64
+	MaxDrawdown float64
+	WinRate     float64
+	Trades      []TradeRecord
+}
+
+// Backtest runs strategy over the candles of a single instrument, oldest first, with a portfolio of DefaultCash
+// and no commission. See Run.
+func Backtest(candles []tdameritrade.Candle, strategy Strategy) *BacktestResult {
+	return Run(candles, strategy, &Portfolio{Cash: DefaultCash})
+}
+
+// Run runs strategy over the candles of a single instrument, oldest first, trading in portfolio. All symbols
+// are priced from the candles. The portfolio is valued at the close of each bar.
+func Run(candles []tdameritrade.Candle, strategy Strategy, portfolio *Portfolio) *BacktestResult {
+	if portfolio.Positions == nil {
+		portfolio.Positions = map[string]float64{}
+	}
+	if portfolio.averagePrices == nil {
+		portfolio.averagePrices = map[string]float64{}
+	}
+
+	result := &BacktestResult{}
+	initial := portfolio.Cash
+	if len(candles) > 0 {
+		initial = portfolio.Value(candles[0].Open)
+	}
+
+	var pending []Order
+	values := make([]float64, 0, len(candles))
+	for _, bar := range candles {
+		for _, order := range pending {
+			if price, ok := fillPrice(order, bar); ok {
+				result.Trades = append(result.Trades, portfolio.fill(order, price, bar.DateTime))
+			}
+		}
+		pending = pending[:0]
+
+		for _, order := range strategy.OnBar(bar, portfolio) {
+			if order.Quantity == 0 {
+				continue
+			}
+			if order.Fill == FillAtClose {
+				result.Trades = append(result.Trades, portfolio.fill(order, bar.Close, bar.DateTime))
+				continue
+			}
+			pending = append(pending, order)
+		}
+		values = append(values, portfolio.Value(bar.Close))
+	}
+
+	if len(values) == 0 || initial == 0 {
+		return result
+	}
+	final := values[len(values)-1]
+	result.TotalReturn = final/initial - 1
+	result.MaxDrawdown = maxDrawdown(values)
+	result.WinRate = winRate(result.Trades)
+
+	years := float64(candles[len(candles)-1].DateTime-candles[0].DateTime) / millisecondsPerYear
+	if years > 0 {
+		if final > 0 {
+			result.CAGR = math.Pow(final/initial, 1/years) - 1
+		} else {
+			result.CAGR = -1
+		}
+		result.SharpeRatio = sharpeRatio(values, float64(len(values)-1)/years)
+	}
+	return result
+}
+
+// millisecondsPerYear is the length of an average year in the milliseconds of Candle.DateTime.
+const millisecondsPerYear = 365.25 * 24 * 60 * 60 * 1000
+
+// fillPrice returns the price a pending order fills at on bar, and false if it does not.
+func fillPrice(order Order, bar tdameritrade.Candle) (float64, bool) {
+	if order.Fill != FillAtLimit {
+		return bar.Open, true
+	}
+	if order.Quantity > 0 {
+		if bar.Low > order.LimitPrice {
+			return 0, false
+		}
+		return math.Min(bar.Open, order.LimitPrice), true
+	}
+	if bar.High < order.LimitPrice {
+		return 0, false
+	}
+	return math.Max(bar.Open, order.LimitPrice), true
+}
+
+// fill trades order at price, updating the average price of the position it opens or adds to.
+func (p *Portfolio) fill(order Order, price float64, dateTime int64) TradeRecord {
+	trade := TradeRecord{
+		Symbol:     order.Symbol,
+		Quantity:   order.Quantity,
+		Price:      price,
+		Commission: p.Commission,
+		DateTime:   dateTime,
+	}
+	p.Cash -= order.Quantity*price + p.Commission
+
+	held, average := p.Positions[order.Symbol], p.averagePrices[order.Symbol]
+	after := held + order.Quantity
+	switch {
+	case held == 0 || (held > 0) == (order.Quantity > 0):
+		// Opening or adding to a position.
+		p.averagePrices[order.Symbol] = (average*held + price*order.Quantity) / after
+	default:
+		closed := math.Min(math.Abs(held), math.Abs(order.Quantity))
+		sign := math.Copysign(1, held)
+		trade.RealizedPnL = (price-average)*closed*sign - p.Commission
+		trade.closing = true
+		if after != 0 && (after > 0) != (held > 0) {
+			// The trade reversed the position, opening the rest at price.
+			p.averagePrices[order.Symbol] = price
+		}
+	}
+
+	if after == 0 {
+		delete(p.Positions, order.Symbol)
+		delete(p.averagePrices, order.Symbol)
+	} else {
+		p.Positions[order.Symbol] = after
+	}
+	return trade
+}
+
+func maxDrawdown(values []float64) float64 {
+	var peak, drawdown float64
+	for _, v := range values {
+		peak = math.Max(peak, v)
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-v)/peak)
+		}
+	}
+	return drawdown
+}
+
+// sharpeRatio returns the mean return between values over its standard deviation, annualized from the number
+// of returns per year, assuming a risk-free rate of 0.
+func sharpeRatio(values []float64, perYear float64) float64 {
+	if len(values) < 3 {
+		return 0
+	}
+	returns := make([]float64, 0, len(values)-1)
+	var mean float64
+	for i := 1; i < len(values); i++ {
+		if values[i-1] == 0 {
+			continue
+		}
+		r := values[i]/values[i-1] - 1
+		returns = append(returns, r)
+		mean += r
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(returns)-1))
+	if stddev == 0 {
+		return 0
+	}
+	return mean / stddev * math.Sqrt(perYear)
+}
+
+func winRate(trades []TradeRecord) float64 {
+	var closing, wins int
+	for _, t := range trades {
+		if !t.closing {
+			continue
+		}
+		closing++
+		if t.RealizedPnL > 0 {
+			wins++
+		}
+	}
+	if closing == 0 {
+		return 0
+	}
+	return float64(wins) / float64(closing)
+}
@@ -0,0 +1,104 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// dailyCandles returns a candle per day from 2020-01-01 for each pair of open and close.
+func dailyCandles(openCloses ...float64) []tdameritrade.Candle {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]tdameritrade.Candle, len(openCloses)/2)
+	for i := range candles {
+		o, c := openCloses[2*i], openCloses[2*i+1]
+		candles[i] = tdameritrade.Candle{
+			Open:     o,
+			High:     math.Max(o, c),
+			Low:      math.Min(o, c),
+			Close:    c,
+			DateTime: start.AddDate(0, 0, i).UnixNano() / int64(time.Millisecond),
+		}
+	}
+	return candles
+}
+
+// scriptedStrategy places the orders of each bar in turn.
+type scriptedStrategy [][]Order
+
+func (s *scriptedStrategy) OnBar(bar tdameritrade.Candle, portfolio *Portfolio) []Order {
+	if len(*s) == 0 {
+		return nil
+	}
+	orders := (*s)[0]
+	*s = (*s)[1:]
+	return orders
+}
+
+func TestBacktest(t *testing.T) {
+	candles := dailyCandles(100, 100, 102, 110, 110, 99, 99, 105)
+	strategy := &scriptedStrategy{
+		{{Symbol: "SPY", Quantity: 10, Fill: FillAtNextOpen}},
+		{{Symbol: "SPY", Quantity: -10}},
+		{{Symbol: "SPY", Quantity: 10, Fill: FillAtLimit, LimitPrice: 100}},
+		{{Symbol: "SPY", Quantity: -10}},
+	}
+
+	result := Backtest(candles, strategy)
+	if len(result.Trades) != 4 {
+		t.Fatalf("got %d trades, want 4: %+v", len(result.Trades), result.Trades)
+	}
+	for i, want := range []float64{102, 110, 99, 105} {
+		if result.Trades[i].Price != want {
+			t.Errorf("trade %d filled at %v, want %v", i, result.Trades[i].Price, want)
+		}
+	}
+	if result.Trades[1].RealizedPnL != 80 || result.Trades[3].RealizedPnL != 60 {
+		t.Errorf("unexpected realized profits: %+v", result.Trades)
+	}
+	if got, want := result.TotalReturn, 140.0/DefaultCash; math.Abs(got-want) > 1e-12 {
+		t.Errorf("TotalReturn = %v, want %v", got, want)
+	}
+	if result.WinRate != 1 {
+		t.Errorf("WinRate = %v, want 1", result.WinRate)
+	}
+	if result.MaxDrawdown != 0 || result.CAGR <= 0 || result.SharpeRatio <= 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestRunCommissionAndDrawdown(t *testing.T) {
+	candles := dailyCandles(10, 10, 10, 8, 8, 9)
+	strategy := &scriptedStrategy{
+		{{Symbol: "XYZ", Quantity: 100}},
+		{},
+		{{Symbol: "XYZ", Quantity: -100}},
+	}
+	portfolio := &Portfolio{Cash: 1000, Commission: 1}
+
+	result := Run(candles, strategy, portfolio)
+	if portfolio.Cash != 898 || len(portfolio.Positions) != 0 {
+		t.Errorf("unexpected portfolio: %+v", portfolio)
+	}
+	if result.Trades[1].RealizedPnL != -101 || result.WinRate != 0 {
+		t.Errorf("unexpected trades: %+v", result.Trades)
+	}
+	if got, want := result.MaxDrawdown, 200.0/999; math.Abs(got-want) > 1e-12 {
+		t.Errorf("MaxDrawdown = %v, want %v", got, want)
+	}
+	if got, want := result.TotalReturn, -0.102; math.Abs(got-want) > 1e-12 {
+		t.Errorf("TotalReturn = %v, want %v", got, want)
+	}
+}
+
+func TestBacktestUnfilledLimit(t *testing.T) {
+	candles := dailyCandles(100, 101, 102, 103)
+	strategy := &scriptedStrategy{{{Symbol: "SPY", Quantity: 1, Fill: FillAtLimit, LimitPrice: 95}}}
+
+	result := Backtest(candles, strategy)
+	if len(result.Trades) != 0 || result.TotalReturn != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
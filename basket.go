@@ -0,0 +1,82 @@
+package tdameritrade
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMissingQuote is returned by AggregateQuotes when a symbol of the basket has no quote.
+var ErrMissingQuote = errors.New("missing quote")
+
+// BasketQuote is the quote of a basket of symbols: the prices of its symbols weighted by their share of TotalWeight.
+type BasketQuote struct {
+	WeightedBid  float64
+	WeightedAsk  float64
+	WeightedLast float64
+	WeightedMark float64
+	TotalWeight  float64
+}
+
+// AggregateQuotes returns the quote of the basket of the symbols of weights, e.g. {"SPY": 2, "QQQ": 1} for
+// a basket two thirds SPY, from quotes such as those of QuotesService.GetQuotes.
+// Weights are normalized by their sum, which may not be zero, so they need not add up to 1.
+func AggregateQuotes(quotes map[string]*Quote, weights map[string]float64) (*BasketQuote, error) {
+	basket := &BasketQuote{}
+	for symbol, weight := range weights {
+		if q, ok := quotes[symbol]; !ok || q == nil {
+			return nil, fmt.Errorf("%w: %s", ErrMissingQuote, symbol)
+		}
+		basket.TotalWeight += weight
+	}
+	if basket.TotalWeight == 0 {
+		return nil, fmt.Errorf("%w: weights add up to zero", ErrInvalidParams)
+	}
+
+	for symbol, weight := range weights {
+		q, share := quotes[symbol], weight/basket.TotalWeight
+		basket.WeightedBid += share * q.BidPrice
+		basket.WeightedAsk += share * q.AskPrice
+		basket.WeightedLast += share * q.LastPrice
+		basket.WeightedMark += share * q.Mark
+	}
+	return basket, nil
+}
+
+// BasketDelta returns the dollar delta of optionPositions: the sum of the delta, price of the underlying and
+// multiplier of each position, times its quantity.
+// underlyingQuotes must hold the quotes of the options of the positions, for their Delta and Multiplier, as well
+// as those of their underlyings, for their LastPrice. Positions with an empty PutCall are of Quantity shares of
+// Symbol, with a delta of 1. Positions missing a quote are skipped, and options without a Multiplier are taken
+// to be for 100 shares.
+func BasketDelta(underlyingQuotes map[string]*Quote, optionPositions []OptionPosition) float64 {
+	total := 0.0
+	for _, p := range optionPositions {
+		if p.PutCall == "" {
+			if q, ok := underlyingQuotes[p.Symbol]; ok && q != nil {
+				total += float64(p.Quantity) * q.LastPrice
+			}
+			continue
+		}
+
+		option, ok := underlyingQuotes[p.Symbol]
+		if !ok || option == nil {
+			continue
+		}
+		underlyingSymbol := option.Underlying
+		if underlyingSymbol == "" {
+			// Option symbols start with their underlying, e.g. SPY_071720C310.
+			underlyingSymbol = strings.SplitN(p.Symbol, "_", 2)[0]
+		}
+		underlying, ok := underlyingQuotes[underlyingSymbol]
+		if !ok || underlying == nil {
+			continue
+		}
+		multiplier := option.Multiplier
+		if multiplier == 0 {
+			multiplier = 100
+		}
+		total += float64(p.Quantity) * option.Delta * underlying.LastPrice * multiplier
+	}
+	return total
+}
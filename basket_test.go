@@ -0,0 +1,61 @@
+package tdameritrade
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAggregateQuotes(t *testing.T) {
+	quotes := map[string]*Quote{
+		"SPY": {Symbol: "SPY", BidPrice: 310, AskPrice: 311, LastPrice: 310.5, Mark: 310.5},
+		"QQQ": {Symbol: "QQQ", BidPrice: 250, AskPrice: 251, LastPrice: 250.5, Mark: 250.6},
+	}
+
+	basket, err := AggregateQuotes(quotes, map[string]float64{"SPY": 2, "QQQ": 1})
+	if err != nil {
+		t.Fatalf("AggregateQuotes returned error: %v", err)
+	}
+	want := BasketQuote{WeightedBid: 290, WeightedAsk: 291, WeightedLast: 290.5, WeightedMark: 290.5 + 0.1/3, TotalWeight: 3}
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"WeightedBid", basket.WeightedBid, want.WeightedBid},
+		{"WeightedAsk", basket.WeightedAsk, want.WeightedAsk},
+		{"WeightedLast", basket.WeightedLast, want.WeightedLast},
+		{"WeightedMark", basket.WeightedMark, want.WeightedMark},
+		{"TotalWeight", basket.TotalWeight, want.TotalWeight},
+	} {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	if _, err := AggregateQuotes(quotes, map[string]float64{"SPY": 1, "IWM": 1}); !errors.Is(err, ErrMissingQuote) {
+		t.Errorf("AggregateQuotes error = %v, want ErrMissingQuote", err)
+	}
+	if _, err := AggregateQuotes(quotes, map[string]float64{"SPY": 1, "QQQ": -1}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("AggregateQuotes error = %v, want ErrInvalidParams", err)
+	}
+}
+
+func TestBasketDelta(t *testing.T) {
+	quotes := map[string]*Quote{
+		"SPY":            {Symbol: "SPY", LastPrice: 310},
+		"SPY_071720C310": {Symbol: "SPY_071720C310", Underlying: "SPY", Multiplier: 100, Delta: 0.5},
+		// Without Underlying or Multiplier, which are taken from the symbol and for 100 shares.
+		"SPY_071720P300": {Symbol: "SPY_071720P300", Delta: -0.25},
+	}
+	positions := []OptionPosition{
+		{Symbol: "SPY", Quantity: 10},
+		{Symbol: "SPY_071720C310", PutCall: "CALL", Strike: 310, Expiry: "2020-07-17", Quantity: -2},
+		{Symbol: "SPY_071720P300", PutCall: "PUT", Strike: 300, Expiry: "2020-07-17", Quantity: 1},
+		{Symbol: "QQQ_071720C250", PutCall: "CALL", Strike: 250, Expiry: "2020-07-17", Quantity: 1},
+	}
+
+	want := 10*310.0 - 2*0.5*310*100 - 0.25*310*100
+	if got := BasketDelta(quotes, positions); math.Abs(got-want) > 1e-9 {
+		t.Errorf("BasketDelta = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package tdameritrade
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ToTime returns the start of the candle, which TD Ameritrade sends in milliseconds since the epoch, in UTC.
+func (c Candle) ToTime() time.Time {
+	return time.Unix(0, c.DateTime*int64(time.Millisecond)).UTC()
+}
+
+// CandlesToMap returns a map per candle keyed by the JSON names of its fields, e.g. "open" and "datetime",
+// for libraries working on generic records. The datetime is in milliseconds since the epoch, as in JSON.
+func CandlesToMap(candles []Candle) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(candles))
+	for i, c := range candles {
+		out[i] = map[string]interface{}{
+			"open":     c.Open,
+			"high":     c.High,
+			"low":      c.Low,
+			"close":    c.Close,
+			"volume":   c.Volume,
+			"datetime": c.DateTime,
+		}
+	}
+	return out
+}
+
+// CandlesToTSV writes the candles as tab separated values, after a header row of the columns
+// datetime, open, high, low, close and volume. The datetime is written in RFC 3339 form in UTC.
+func CandlesToTSV(candles []Candle, w io.Writer) error {
+	tw := csv.NewWriter(w)
+	tw.Comma = '\t'
+	if err := tw.Write([]string{"datetime", "open", "high", "low", "close", "volume"}); err != nil {
+		return err
+	}
+
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, c := range candles {
+		row := []string{c.ToTime().Format(time.RFC3339), format(c.Open), format(c.High), format(c.Low), format(c.Close), format(c.Volume)}
+		if err := tw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	tw.Flush()
+	return tw.Error()
+}
+
+// CandlesFromOHLCV returns the candles built from the columns of data from elsewhere, one candle per time.
+// Every column must have as many values as times.
+func CandlesFromOHLCV(times []time.Time, opens, highs, lows, closes, volumes []float64) ([]Candle, error) {
+	for _, column := range []struct {
+		name   string
+		values []float64
+	}{{"opens", opens}, {"highs", highs}, {"lows", lows}, {"closes", closes}, {"volumes", volumes}} {
+		if len(column.values) != len(times) {
+			return nil, fmt.Errorf("%w: %d %s for %d times", ErrInvalidParams, len(column.values), column.name, len(times))
+		}
+	}
+
+	candles := make([]Candle, len(times))
+	for i, t := range times {
+		candles[i] = Candle{
+			Open:     opens[i],
+			High:     highs[i],
+			Low:      lows[i],
+			Close:    closes[i],
+			Volume:   volumes[i],
+			DateTime: t.UnixNano() / int64(time.Millisecond),
+		}
+	}
+	return candles, nil
+}
+
+// Sessions a candle is classified in by ClassifyCandles.
+const (
+	CandleSessionPreMarket  = "PRE_MARKET"
+	CandleSessionRegular    = "REGULAR"
+	CandleSessionAfterHours = "AFTER_HOURS"
+)
+
+// ClassifiedCandle is a candle with the session it started in, one of CandleSessionPreMarket,
+// CandleSessionRegular or CandleSessionAfterHours.
+type ClassifiedCandle struct {
+	Candle
+	Session string
+}
+
+// ClassifyCandles returns the session each of candles started in, for candles of price history fetched with
+// extended hours data. marketHours are the regular sessions of the days of the candles, such as the
+// "regularMarket" SessionHours of MarketHours. A candle within a regular session, from its start up to its end,
+// is regular. Otherwise it is pre-market if it is on the same day as the next regular session, in the location
+// of its start, and after hours if not, as on days without a regular session.
+func ClassifyCandles(candles []Candle, marketHours []SessionHour) []ClassifiedCandle {
+	sessions := make([]SessionHour, len(marketHours))
+	copy(sessions, marketHours)
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Start.Before(sessions[j].Start) })
+
+	out := make([]ClassifiedCandle, len(candles))
+	for i, c := range candles {
+		t := c.ToTime()
+		// The first session not over by t.
+		next := sort.Search(len(sessions), func(j int) bool { return sessions[j].End.After(t) })
+		session := CandleSessionAfterHours
+		if next < len(sessions) {
+			s := sessions[next]
+			switch {
+			case !t.Before(s.Start):
+				session = CandleSessionRegular
+			case sameDay(t.In(s.Start.Location()), s.Start):
+				session = CandleSessionPreMarket
+			}
+		}
+		out[i] = ClassifiedCandle{Candle: c, Session: session}
+	}
+	return out
+}
+
+// FilterCandlesBySession returns the candles of session, e.g. CandleSessionRegular to drop extended hours bars.
+func FilterCandlesBySession(candles []ClassifiedCandle, session string) []Candle {
+	var out []Candle
+	for _, c := range candles {
+		if c.Session == session {
+			out = append(out, c.Candle)
+		}
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package tdameritrade
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCandleConversions(t *testing.T) {
+	times := []time.Time{
+		time.Date(2020, 7, 6, 13, 30, 0, 0, time.UTC),
+		time.Date(2020, 7, 6, 13, 31, 0, 0, time.UTC),
+	}
+	candles, err := CandlesFromOHLCV(times, []float64{310, 310.5}, []float64{311, 310.9}, []float64{309.5, 310.1}, []float64{310.5, 310.2}, []float64{1000, 1500})
+	if err != nil {
+		t.Fatalf("CandlesFromOHLCV returned error: %v", err)
+	}
+	want := []Candle{
+		{Open: 310, High: 311, Low: 309.5, Close: 310.5, Volume: 1000, DateTime: 1594042200000},
+		{Open: 310.5, High: 310.9, Low: 310.1, Close: 310.2, Volume: 1500, DateTime: 1594042260000},
+	}
+	if !reflect.DeepEqual(candles, want) {
+		t.Errorf("CandlesFromOHLCV = %+v, want %+v", candles, want)
+	}
+	if got := candles[1].ToTime(); !got.Equal(times[1]) || got.Location() != time.UTC {
+		t.Errorf("ToTime = %v, want %v", got, times[1])
+	}
+
+	maps := CandlesToMap(candles)
+	if len(maps) != 2 || maps[0]["close"] != 310.5 || maps[1]["datetime"] != int64(1594042260000) {
+		t.Errorf("unexpected maps: %v", maps)
+	}
+
+	var buf bytes.Buffer
+	if err := CandlesToTSV(candles, &buf); err != nil {
+		t.Fatalf("CandlesToTSV returned error: %v", err)
+	}
+	wantTSV := "datetime\topen\thigh\tlow\tclose\tvolume\n" +
+		"2020-07-06T13:30:00Z\t310\t311\t309.5\t310.5\t1000\n" +
+		"2020-07-06T13:31:00Z\t310.5\t310.9\t310.1\t310.2\t1500\n"
+	if buf.String() != wantTSV {
+		t.Errorf("CandlesToTSV wrote %q, want %q", buf.String(), wantTSV)
+	}
+
+	if _, err := CandlesFromOHLCV(times, []float64{310}, nil, nil, nil, nil); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("CandlesFromOHLCV of uneven columns error = %v, want ErrInvalidParams", err)
+	}
+}
+
+func TestClassifyCandles(t *testing.T) {
+	ny := time.FixedZone("EDT", -4*60*60)
+	at := func(day, hour, minute int) Candle {
+		return Candle{Close: float64(day*100 + hour), DateTime: time.Date(2020, 7, day, hour, minute, 0, 0, ny).UnixNano() / int64(time.Millisecond)}
+	}
+	regular := []SessionHour{
+		{Start: time.Date(2020, 7, 7, 9, 30, 0, 0, ny), End: time.Date(2020, 7, 7, 16, 0, 0, 0, ny)},
+		{Start: time.Date(2020, 7, 6, 9, 30, 0, 0, ny), End: time.Date(2020, 7, 6, 16, 0, 0, 0, ny)},
+	}
+	// Sunday evening, then Monday before, during and after the session, then Tuesday's open.
+	candles := []Candle{at(5, 19, 0), at(6, 7, 0), at(6, 9, 30), at(6, 15, 59), at(6, 16, 0), at(6, 19, 0), at(7, 9, 30)}
+
+	classified := ClassifyCandles(candles, regular)
+	var got []string
+	for _, c := range classified {
+		got = append(got, c.Session)
+	}
+	want := []string{CandleSessionAfterHours, CandleSessionPreMarket, CandleSessionRegular, CandleSessionRegular,
+		CandleSessionAfterHours, CandleSessionAfterHours, CandleSessionRegular}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sessions = %v, want %v", got, want)
+	}
+
+	if got, want := FilterCandlesBySession(classified, CandleSessionRegular), []Candle{candles[2], candles[3], candles[6]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("regular candles = %v, want %v", got, want)
+	}
+}
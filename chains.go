@@ -3,10 +3,14 @@ package tdameritrade
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/url"
+	"sort"
 	"strconv"
+	"sync"
+	"time"
 )
 
 // ChainsService handles communication with the chains related methods of
@@ -57,6 +61,35 @@ func (v Float64WithSpecial) MarshalJSON() ([]byte, error) {
 	return []byte(s), nil
 }
 
+// IsNaN reports whether v is NaN, as TD Ameritrade reports the greeks of options it cannot price.
+func (v Float64WithSpecial) IsNaN() bool {
+	return math.IsNaN(float64(v))
+}
+
+// IsInf reports whether v is an infinity, according to sign as for math.IsInf.
+func (v Float64WithSpecial) IsInf(sign int) bool {
+	return math.IsInf(float64(v), sign)
+}
+
+// IsValid reports whether v is a finite number, neither NaN nor an infinity.
+func (v Float64WithSpecial) IsValid() bool {
+	return !v.IsNaN() && !v.IsInf(0)
+}
+
+// OrDefault returns v as a float64, or d if v is NaN or an infinity.
+func (v Float64WithSpecial) OrDefault(d float64) float64 {
+	if !v.IsValid() {
+		return d
+	}
+	return float64(v)
+}
+
+// String formats v as it is encoded in JSON, with NaN and infinities quoted, e.g. "NaN".
+func (v Float64WithSpecial) String() string {
+	b, _ := v.MarshalJSON()
+	return string(b)
+}
+
 type Underlying struct {
 	Symbol            string  `json:"symbol"`
 	Description       string  `json:"description"`
@@ -84,7 +117,7 @@ type Underlying struct {
 }
 
 type ExpDateOption struct {
-	PutCall                string             `json:"putCall"`
+	PutCall                PutCall            `json:"putCall"`
 	Symbol                 string             `json:"symbol"`
 	Description            string             `json:"description"`
 	ExchangeName           string             `json:"exchangeName"`
@@ -119,7 +152,7 @@ type ExpDateOption struct {
 	StrikePrice            float64            `json:"strikePrice"`
 	ExpirationDate         int                `json:"expirationDate"`
 	DaysToExpiration       int                `json:"daysToExpiration"`
-	ExpirationType         string             `json:"expirationType"`
+	ExpirationType         ExpirationType     `json:"expirationType"`
 	LastTradingDate        int                `json:"lastTradingDay"`
 	Multiplier             float64            `json:"multiplier"`
 	SettlementType         string             `json:"settlementType"`
@@ -140,7 +173,7 @@ type Chains struct {
 	Symbol            string     `json:"symbol"`
 	Status            string     `json:"status"`
 	Underlying        Underlying `json:"underlying"`
-	Strategy          string     `json:"strategy"`
+	Strategy          Strategy   `json:"strategy"`
 	Interval          float64    `json:"interval"`
 	IsDelayed         bool       `json:"isDelayed"`
 	IsIndex           bool       `json:"isIndex"`
@@ -153,11 +186,173 @@ type Chains struct {
 	PutExpDateMap     ExpDateMap `json:"putExpDateMap"`
 }
 
-// Users must provide the required URL queryValues for this function to work.
+// FlattenCalls returns all of the calls in the chain, ordered by expiration date and then strike.
+func (c *Chains) FlattenCalls() []ExpDateOption {
+	return c.CallExpDateMap.Flatten()
+}
+
+// FlattenPuts returns all of the puts in the chain, ordered by expiration date and then strike.
+func (c *Chains) FlattenPuts() []ExpDateOption {
+	return c.PutExpDateMap.Flatten()
+}
+
+// FlattenAll returns the calls in the chain followed by the puts.
+func (c *Chains) FlattenAll() []ExpDateOption {
+	options := make([]ExpDateOption, 0, c.CallExpDateMap.len()+c.PutExpDateMap.len())
+	options = c.CallExpDateMap.appendFlattened(options)
+	return c.PutExpDateMap.appendFlattened(options)
+}
+
+// ChainsParams holds the query options for an option chain request.
+// Zero values are left out of the request so TD Ameritrade's defaults apply.
 // TD Ameritrade url values: https://developer.tdameritrade.com/option-chains/apis/get/marketdata/chains
-// Instructions for using url.Values: https://golang.org/pkg/net/url/#Values
-func (s *ChainsService) GetChains(ctx context.Context, queryValues url.Values) (*Chains, *Response, error) {
-	u := fmt.Sprintf("marketdata/chains?%s", queryValues.Encode())
+type ChainsParams struct {
+	Symbol           string
+	ContractType     ContractType
+	StrikeCount      int
+	IncludeQuotes    bool
+	Strategy         Strategy
+	Interval         float64
+	Strike           float64
+	Range            RangeFilter
+	FromDate         time.Time
+	ToDate           time.Time
+	Volatility       float64
+	UnderlyingPrice  float64
+	InterestRate     float64
+	DaysToExpiration int
+	ExpMonth         string
+	OptionType       string
+}
+
+// ToURLValues translates the params to the query options expected by TD Ameritrade.
+func (p ChainsParams) ToURLValues() url.Values {
+	q := url.Values{}
+	setString := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	setInt := func(key string, value int) {
+		if value != 0 {
+			q.Set(key, strconv.Itoa(value))
+		}
+	}
+	setFloat := func(key string, value float64) {
+		if value != 0 {
+			q.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+	}
+	setDate := func(key string, value time.Time) {
+		if !value.IsZero() {
+			q.Set(key, value.Format("2006-01-02"))
+		}
+	}
+
+	setString("symbol", p.Symbol)
+	setString("contractType", string(p.ContractType))
+	setInt("strikeCount", p.StrikeCount)
+	if p.IncludeQuotes {
+		q.Set("includeQuotes", "TRUE")
+	}
+	setString("strategy", string(p.Strategy))
+	setFloat("interval", p.Interval)
+	setFloat("strike", p.Strike)
+	setString("range", string(p.Range))
+	setDate("fromDate", p.FromDate)
+	setDate("toDate", p.ToDate)
+	setFloat("volatility", p.Volatility)
+	setFloat("underlyingPrice", p.UnderlyingPrice)
+	setFloat("interestRate", p.InterestRate)
+	setInt("daysToExpiration", p.DaysToExpiration)
+	setString("expMonth", p.ExpMonth)
+	setString("optionType", p.OptionType)
+	return q
+}
+
+// ChainsParamsBuilder builds ChainsParams with method chaining.
+//
+// Usage example:
+// params := tdameritrade.NewChainsParamsBuilder("SPY").ContractType("CALL").StrikeCount(10).Build()
+type ChainsParamsBuilder struct {
+	params ChainsParams
+}
+
+// NewChainsParamsBuilder returns a builder for the option chain of symbol.
+func NewChainsParamsBuilder(symbol string) *ChainsParamsBuilder {
+	return &ChainsParamsBuilder{params: ChainsParams{Symbol: symbol}}
+}
+
+func (b *ChainsParamsBuilder) ContractType(contractType ContractType) *ChainsParamsBuilder {
+	b.params.ContractType = contractType
+	return b
+}
+
+func (b *ChainsParamsBuilder) StrikeCount(strikeCount int) *ChainsParamsBuilder {
+	b.params.StrikeCount = strikeCount
+	return b
+}
+
+func (b *ChainsParamsBuilder) IncludeQuotes(includeQuotes bool) *ChainsParamsBuilder {
+	b.params.IncludeQuotes = includeQuotes
+	return b
+}
+
+func (b *ChainsParamsBuilder) Strategy(strategy Strategy) *ChainsParamsBuilder {
+	b.params.Strategy = strategy
+	return b
+}
+
+func (b *ChainsParamsBuilder) Interval(interval float64) *ChainsParamsBuilder {
+	b.params.Interval = interval
+	return b
+}
+
+func (b *ChainsParamsBuilder) Strike(strike float64) *ChainsParamsBuilder {
+	b.params.Strike = strike
+	return b
+}
+
+func (b *ChainsParamsBuilder) Range(r RangeFilter) *ChainsParamsBuilder {
+	b.params.Range = r
+	return b
+}
+
+// Dates sets the range of expiration dates to return.
+func (b *ChainsParamsBuilder) Dates(from, to time.Time) *ChainsParamsBuilder {
+	b.params.FromDate = from
+	b.params.ToDate = to
+	return b
+}
+
+// Analytical sets the inputs used to calculate theoretical values when Strategy is ANALYTICAL.
+func (b *ChainsParamsBuilder) Analytical(volatility, underlyingPrice, interestRate float64, daysToExpiration int) *ChainsParamsBuilder {
+	b.params.Volatility = volatility
+	b.params.UnderlyingPrice = underlyingPrice
+	b.params.InterestRate = interestRate
+	b.params.DaysToExpiration = daysToExpiration
+	return b
+}
+
+func (b *ChainsParamsBuilder) ExpMonth(expMonth string) *ChainsParamsBuilder {
+	b.params.ExpMonth = expMonth
+	return b
+}
+
+func (b *ChainsParamsBuilder) OptionType(optionType string) *ChainsParamsBuilder {
+	b.params.OptionType = optionType
+	return b
+}
+
+// Build returns the configured ChainsParams.
+func (b *ChainsParamsBuilder) Build() ChainsParams {
+	return b.params
+}
+
+// GetChains returns the option chain described by params.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/option-chains/apis/get/marketdata/chains
+func (s *ChainsService) GetChains(ctx context.Context, params ChainsParams) (*Chains, *Response, error) {
+	u := fmt.Sprintf("marketdata/chains?%s", params.ToURLValues().Encode())
 
 	req, err := s.client.NewRequest("GET", u, nil)
 
@@ -174,3 +369,232 @@ func (s *ChainsService) GetChains(ctx context.Context, queryValues url.Values) (
 
 	return chains, resp, nil
 }
+
+// chainsRetryDelay is the time GetChainsReliable waits between attempts.
+var chainsRetryDelay = 500 * time.Millisecond
+
+// ErrChainsFailed is matched with errors.Is by the ChainsFailedError returned by GetChainsReliable.
+var ErrChainsFailed = errors.New("chains failed")
+
+// ChainsFailedError is returned by GetChainsReliable when every attempt returned a chain with status FAILED.
+// Chains and Response are those of the last attempt.
+type ChainsFailedError struct {
+	Symbol   string
+	Attempts int
+	Chains   *Chains
+	Response *Response
+}
+
+func (e *ChainsFailedError) Error() string {
+	return fmt.Sprintf("chain of %s failed after %d attempts", e.Symbol, e.Attempts)
+}
+
+// Is reports whether target is ErrChainsFailed.
+func (e *ChainsFailedError) Is(target error) bool {
+	return target == ErrChainsFailed
+}
+
+// IsValid reports whether TD Ameritrade built the chain and it holds options.
+func (c *Chains) IsValid() bool {
+	return c.Status == "SUCCESS" && c.NumberOfContracts > 0
+}
+
+// GetChainsReliable returns the option chain described by params like GetChains, but retries up to maxAttempts
+// times, 500ms apart, while TD Ameritrade answers with a chain of status FAILED, as it sometimes does for chains
+// it could build a moment later. If every attempt fails, it returns the last chain and response along with a
+// *ChainsFailedError. Request errors are returned at once, as are context errors while waiting to retry.
+func (s *ChainsService) GetChainsReliable(ctx context.Context, params ChainsParams, maxAttempts int) (*Chains, *Response, error) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		chains, resp, err := s.GetChains(ctx, params)
+		if err != nil || chains.Status != "FAILED" {
+			return chains, resp, err
+		}
+		if attempt == maxAttempts {
+			return chains, resp, &ChainsFailedError{Symbol: params.Symbol, Attempts: attempt, Chains: chains, Response: resp}
+		}
+
+		timer := time.NewTimer(chainsRetryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return chains, resp, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// GetExpirationDates returns the dates the options of symbol expire on, in ascending order.
+// TD Ameritrade has no endpoint listing expirations, so it fetches the chain of the at-the-money strike alone
+// and returns the expirations in it.
+func (s *ChainsService) GetExpirationDates(ctx context.Context, symbol string) ([]time.Time, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("no symbol present")
+	}
+	chains, _, err := s.GetChains(ctx, ChainsParams{Symbol: symbol, StrikeCount: 1, Strategy: StrategySingle})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[time.Time]bool{}
+	var dates []time.Time
+	for _, m := range []ExpDateMap{chains.CallExpDateMap, chains.PutExpDateMap} {
+		for key := range m {
+			date, _, err := ParseExpDateKey(key)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[date] {
+				seen[date] = true
+				dates = append(dates, date)
+			}
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, nil
+}
+
+// ChainsResult is the outcome of fetching the chain described by Params in GetChainsBatch.
+// Chains is nil if Err is set.
+type ChainsResult struct {
+	Params   ChainsParams
+	Chains   *Chains
+	Response *Response
+	Err      error
+}
+
+// GetChainsBatch fetches the chain described by each of params, making up to concurrency requests at a time,
+// and returns a result for each in the order of params. A failed request does not stop the others; its error
+// is set on its result. concurrency is at least 1.
+// If ctx is done before every chain is fetched, no more requests are started and, once the requests in flight
+// have finished, the results of the requests made are returned along with an error wrapping ctx.Err().
+func (s *ChainsService) GetChainsBatch(ctx context.Context, params []ChainsParams, concurrency int) ([]*ChainsResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		results = make([]*ChainsResult, len(params))
+		pending = make(chan int)
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				chains, resp, err := s.GetChains(ctx, params[i])
+				results[i] = &ChainsResult{Params: params[i], Chains: chains, Response: resp, Err: err}
+			}
+		}()
+	}
+
+	var err error
+send:
+	for i := range params {
+		// Check first, as select picks at random when a worker is also ready.
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break send
+		case pending <- i:
+		}
+	}
+	close(pending)
+	wg.Wait()
+
+	if err == nil {
+		return results, nil
+	}
+	fetched := results[:0]
+	for _, r := range results {
+		if r != nil {
+			fetched = append(fetched, r)
+		}
+	}
+	return fetched, fmt.Errorf("fetching chains: %w", err)
+}
+
+// watchlistChainsConcurrency is the number of requests GetChainsForWatchlist makes at a time.
+const watchlistChainsConcurrency = 4
+
+// GetChainsForWatchlist fetches the chain of each symbol in a watchlist with GetChainsBatch, described by params
+// but for the symbol, and returns a result per symbol in the order of the watchlist. Symbols listed more than
+// once are fetched once. Like GetChainsBatch, a failed request does not stop the others, and the results fetched
+// are returned along with the error if ctx is done.
+func (s *ChainsService) GetChainsForWatchlist(ctx context.Context, accountID, watchlistID string, params ChainsParams) ([]*ChainsResult, error) {
+	watchlist, _, err := s.client.Watchlist.GetWatchlist(ctx, accountID, watchlistID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching watchlist %s: %w", watchlistID, err)
+	}
+
+	var batch []ChainsParams
+	seen := map[string]bool{}
+	for _, item := range watchlist.WatchlistItems {
+		symbol := item.Instrument.Symbol
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		p := params
+		p.Symbol = symbol
+		batch = append(batch, p)
+	}
+	return s.GetChainsBatch(ctx, batch, watchlistChainsConcurrency)
+}
+
+// GetChainsPerExpiry returns the chain of symbol described by baseParams like GetChains, but fetches each
+// expiration with its own request, making up to concurrency requests at a time, and merges the results.
+// Requesting the full chain of an underlying with many expirations can time out; the chain of a single
+// expiration is a fraction of the size.
+//
+// The expirations are found with GetExpirationDates, keeping those between baseParams.FromDate and ToDate if set.
+// The chain-wide fields, such as UnderlyingPrice, are those of the nearest expiration, and the Response is that
+// of the request for the last. If a request fails, its error is returned.
+func (s *ChainsService) GetChainsPerExpiry(ctx context.Context, symbol string, baseParams ChainsParams, concurrency int) (*Chains, *Response, error) {
+	expirations, err := s.GetExpirationDates(ctx, symbol)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching the expirations of %s: %w", symbol, err)
+	}
+
+	var params []ChainsParams
+	for _, expiration := range expirations {
+		if !baseParams.FromDate.IsZero() && expiration.Before(baseParams.FromDate) ||
+			!baseParams.ToDate.IsZero() && expiration.After(baseParams.ToDate) {
+			continue
+		}
+		p := baseParams
+		p.Symbol = symbol
+		p.FromDate, p.ToDate = expiration, expiration
+		params = append(params, p)
+	}
+	if len(params) == 0 {
+		return nil, nil, fmt.Errorf("no options found in the chain of %s", symbol)
+	}
+
+	results, err := s.GetChainsBatch(ctx, params, concurrency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var merged *Chains
+	for _, r := range results {
+		if r.Err != nil {
+			return nil, r.Response, fmt.Errorf("fetching the chain of %s expiring %s: %w", symbol, r.Params.FromDate.Format("2006-01-02"), r.Err)
+		}
+		if merged == nil {
+			merged = r.Chains
+			continue
+		}
+		merged.CallExpDateMap = mergeExpDateMaps(merged.CallExpDateMap, r.Chains.CallExpDateMap)
+		merged.PutExpDateMap = mergeExpDateMaps(merged.PutExpDateMap, r.Chains.PutExpDateMap)
+	}
+	merged.NumberOfContracts = merged.CallExpDateMap.len() + merged.PutExpDateMap.len()
+	return merged, results[len(results)-1].Response, nil
+}
@@ -0,0 +1,165 @@
+package tdameritrade
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// chainsCSVColumns are the columns written by WriteCSV after the expiration and strike, named after the JSON fields
+// of ExpDateOption. Spreadsheets rely on their order, so new columns must only be added at the end.
+var chainsCSVColumns = []string{
+	"putCall", "symbol", "description", "exchangeName", "bid", "ask", "last", "mark", "bidSize", "askSize",
+	"bidAskSize", "lastSize", "highPrice", "lowPrice", "openPrice", "closePrice", "totalVolume", "tradeDate",
+	"tradeTimeInLong", "quoteTimeInLong", "netChange", "volatility", "delta", "gamma", "theta", "vega", "rho",
+	"openInterest", "timeValue", "theoreticalOptionValue", "theoreticalVolatility", "optionDeliverablesList",
+	"strikePrice", "expirationDate", "daysToExpiration", "expirationType", "lastTradingDay", "multiplier",
+	"settlementType", "deliverableNote", "isIndexOption", "percentChange", "markChange", "markPercentChange",
+	"inTheMoney", "mini", "nonStandard",
+}
+
+// expDateOptionFields maps the JSON names of ExpDateOption's fields to their indexes.
+var expDateOptionFields = func() map[string]int {
+	fields := map[string]int{}
+	t := reflect.TypeOf(ExpDateOption{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		fields[name] = i
+	}
+	return fields
+}()
+
+// WriteCSV writes the calls, puts or both (putCall ALL) of the chain as CSV, one option per row,
+// ordered by expiration and strike.
+// The first two columns are the expiration and strike keys of the chain, e.g. 2020-07-17:10 and 310.0,
+// followed by a column for each field of ExpDateOption. NaN and infinite values are written as NaN, +Inf and -Inf.
+func (c *Chains) WriteCSV(w io.Writer, putCall string) error {
+	var maps []ExpDateMap
+	switch strings.ToUpper(putCall) {
+	case "CALL":
+		maps = []ExpDateMap{c.CallExpDateMap}
+	case "PUT":
+		maps = []ExpDateMap{c.PutExpDateMap}
+	case "ALL":
+		maps = []ExpDateMap{c.CallExpDateMap, c.PutExpDateMap}
+	default:
+		return fmt.Errorf("%w: putCall must be CALL, PUT or ALL, got %q", ErrInvalidParams, putCall)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"expiration", "strike"}, chainsCSVColumns...)); err != nil {
+		return err
+	}
+
+	row := make([]string, 2+len(chainsCSVColumns))
+	for _, m := range maps {
+		for _, expiration := range m.sortedKeys() {
+			strikes := m[expiration]
+			for _, strike := range sortedStrikes(strikes) {
+				for _, o := range strikes[strike] {
+					row[0], row[1] = expiration, strike
+					v := reflect.ValueOf(o)
+					for i, column := range chainsCSVColumns {
+						row[2+i] = formatCSVField(v.Field(expDateOptionFields[column]))
+					}
+					if err := cw.Write(row); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadChainsFromCSV reads a chain written by WriteCSV.
+// Only the expiration maps are restored; options are added to the calls or puts by their putCall column.
+// Columns are matched by name, so files written by older versions of WriteCSV can still be read.
+func ReadChainsFromCSV(r io.Reader) (*Chains, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %w", err)
+	}
+	if len(header) < 2 || header[0] != "expiration" || header[1] != "strike" {
+		return nil, fmt.Errorf("CSV does not start with expiration and strike columns: %v", header)
+	}
+
+	chain := &Chains{CallExpDateMap: ExpDateMap{}, PutExpDateMap: ExpDateMap{}}
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return chain, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var o ExpDateOption
+		v := reflect.ValueOf(&o).Elem()
+		for i, column := range header[2:] {
+			index, ok := expDateOptionFields[column]
+			if !ok {
+				continue
+			}
+			if err := parseCSVField(v.Field(index), record[2+i]); err != nil {
+				return nil, fmt.Errorf("line %d: could not parse %s: %w", line, column, err)
+			}
+		}
+
+		m := chain.CallExpDateMap
+		if o.PutCall == "PUT" {
+			m = chain.PutExpDateMap
+		}
+		expiration, strike := record[0], record[1]
+		if m[expiration] == nil {
+			m[expiration] = map[string][]ExpDateOption{}
+		}
+		m[expiration][strike] = append(m[expiration][strike], o)
+	}
+}
+
+func formatCSVField(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Int:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	panic(fmt.Sprintf("unsupported CSV field kind %s", v.Kind()))
+}
+
+func parseCSVField(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Int:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	}
+	return nil
+}
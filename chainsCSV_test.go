@@ -0,0 +1,91 @@
+package tdameritrade
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// chainsCSVHeader is the header WriteCSV must keep writing so spreadsheets built on it keep working.
+const chainsCSVHeader = "expiration,strike,putCall,symbol,description,exchangeName,bid,ask,last,mark,bidSize,askSize," +
+	"bidAskSize,lastSize,highPrice,lowPrice,openPrice,closePrice,totalVolume,tradeDate,tradeTimeInLong,quoteTimeInLong," +
+	"netChange,volatility,delta,gamma,theta,vega,rho,openInterest,timeValue,theoreticalOptionValue,theoreticalVolatility," +
+	"optionDeliverablesList,strikePrice,expirationDate,daysToExpiration,expirationType,lastTradingDay,multiplier," +
+	"settlementType,deliverableNote,isIndexOption,percentChange,markChange,markPercentChange,inTheMoney,mini,nonStandard"
+
+func TestChainsCSVRoundTrip(t *testing.T) {
+	chain := loadChains(t)
+	// Far out of the money options often have no greeks.
+	for _, strikes := range chain.CallExpDateMap {
+		for _, options := range strikes {
+			options[0].Delta = Float64WithSpecial(math.NaN())
+			options[0].Gamma = Float64WithSpecial(math.Inf(1))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := chain.WriteCSV(&buf, "ALL"); err != nil {
+		t.Fatalf("WriteCSV returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != chainsCSVHeader {
+		t.Errorf("header changed:\n%s\nwant\n%s", lines[0], chainsCSVHeader)
+	}
+	if len(lines) != 13 {
+		t.Fatalf("expected 12 options, got %d rows", len(lines)-1)
+	}
+	if !strings.HasPrefix(lines[1], "2020-07-17:10,305.0,CALL,SPY_071720C305,") || !strings.Contains(lines[1], ",NaN,+Inf,") {
+		t.Errorf("unexpected first row: %s", lines[1])
+	}
+
+	read, err := ReadChainsFromCSV(&buf)
+	if err != nil {
+		t.Fatalf("ReadChainsFromCSV returned error: %v", err)
+	}
+	// NaN never equals itself, so compare the rest of the options.
+	for _, m := range []ExpDateMap{chain.CallExpDateMap, read.CallExpDateMap} {
+		for _, strikes := range m {
+			for _, options := range strikes {
+				if !math.IsNaN(float64(options[0].Delta)) {
+					t.Fatalf("expected delta of %s to be NaN, got %v", options[0].Symbol, options[0].Delta)
+				}
+				options[0].Delta = 0
+			}
+		}
+	}
+	if !reflect.DeepEqual(read.CallExpDateMap, chain.CallExpDateMap) || !reflect.DeepEqual(read.PutExpDateMap, chain.PutExpDateMap) {
+		t.Error("chain read from CSV differs from the chain written")
+	}
+}
+
+func TestChainsCSVColumnsCoverExpDateOption(t *testing.T) {
+	if n := reflect.TypeOf(ExpDateOption{}).NumField(); len(chainsCSVColumns) != n || len(expDateOptionFields) != n {
+		t.Errorf("ExpDateOption has %d fields but there are %d CSV columns; append new fields to chainsCSVColumns", n, len(chainsCSVColumns))
+	}
+}
+
+func TestChainsWriteCSVPuts(t *testing.T) {
+	chain := loadChains(t)
+
+	var buf bytes.Buffer
+	if err := chain.WriteCSV(&buf, "put"); err != nil {
+		t.Fatalf("WriteCSV returned error: %v", err)
+	}
+	read, err := ReadChainsFromCSV(&buf)
+	if err != nil {
+		t.Fatalf("ReadChainsFromCSV returned error: %v", err)
+	}
+	if len(read.CallExpDateMap) != 0 || !reflect.DeepEqual(read.PutExpDateMap, chain.PutExpDateMap) {
+		t.Error("expected only the puts to be written")
+	}
+
+	if err := chain.WriteCSV(&buf, "BOTH"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams, got %v", err)
+	}
+	if _, err := ReadChainsFromCSV(strings.NewReader("symbol,strike\n")); err == nil {
+		t.Error("expected an error for a CSV without the expiration column")
+	}
+}
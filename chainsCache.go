@@ -0,0 +1,78 @@
+package tdameritrade
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ChainsGetter returns option chains. It is implemented by ChainsService and CachedChainsService.
+type ChainsGetter interface {
+	GetChains(ctx context.Context, params ChainsParams) (*Chains, *Response, error)
+}
+
+// CachedChainsService is a ChainsGetter returning the chains of a ChainsService, caching them for a TTL so that
+// fetching the same chain repeatedly only calls TD Ameritrade once per TTL.
+// Chains are cached by symbol and the rest of their ChainsParams, and the cached *Chains is returned to every
+// caller, so it must not be modified. Failed requests are not cached.
+// It is safe for concurrent use.
+type CachedChainsService struct {
+	underlying *ChainsService
+	ttl        time.Duration
+	entries    sync.Map // of chainsCacheKey to *chainsCacheEntry
+}
+
+type chainsCacheKey struct {
+	symbol string
+	query  string
+}
+
+type chainsCacheEntry struct {
+	chains    *Chains
+	resp      *Response
+	fetchedAt time.Time
+}
+
+// NewCachedChainsService returns a CachedChainsService caching the chains of underlying for ttl.
+func NewCachedChainsService(underlying *ChainsService, ttl time.Duration) *CachedChainsService {
+	return &CachedChainsService{underlying: underlying, ttl: ttl}
+}
+
+// GetChains returns the option chain described by params, from the cache if it was fetched less than the TTL ago.
+// Responses from the cache are copies of the response of the cached chain with FromCache set.
+func (s *CachedChainsService) GetChains(ctx context.Context, params ChainsParams) (*Chains, *Response, error) {
+	key := chainsCacheKey{symbol: params.Symbol, query: params.ToURLValues().Encode()}
+	if v, ok := s.entries.Load(key); ok {
+		entry := v.(*chainsCacheEntry)
+		if time.Since(entry.fetchedAt) < s.ttl {
+			resp := *entry.resp
+			resp.FromCache = true
+			return entry.chains, &resp, nil
+		}
+	}
+
+	chains, resp, err := s.underlying.GetChains(ctx, params)
+	if err != nil {
+		return chains, resp, err
+	}
+	s.entries.Store(key, &chainsCacheEntry{chains: chains, resp: resp, fetchedAt: time.Now()})
+	return chains, resp, nil
+}
+
+// Invalidate removes the cached chains of symbol, whatever their other parameters.
+func (s *CachedChainsService) Invalidate(symbol string) {
+	s.entries.Range(func(k, _ interface{}) bool {
+		if k.(chainsCacheKey).symbol == symbol {
+			s.entries.Delete(k)
+		}
+		return true
+	})
+}
+
+// InvalidateAll removes every cached chain.
+func (s *CachedChainsService) InvalidateAll() {
+	s.entries.Range(func(k, _ interface{}) bool {
+		s.entries.Delete(k)
+		return true
+	})
+}
@@ -0,0 +1,85 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCachedChainsService(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	requests := map[string]int{}
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		requests[r.FormValue("symbol")]++
+		fmt.Fprintf(w, `{"symbol":%q,"status":"SUCCESS","numberOfContracts":1}`, r.FormValue("symbol"))
+	})
+
+	var _ ChainsGetter = client.Chains
+	var cache ChainsGetter = NewCachedChainsService(client.Chains, time.Minute)
+	ctx := context.Background()
+
+	_, resp, err := cache.GetChains(ctx, ChainsParams{Symbol: "SPY"})
+	if err != nil {
+		t.Fatalf("GetChains returned error: %v", err)
+	}
+	if resp.FromCache {
+		t.Error("first GetChains was served from the cache")
+	}
+	chains, resp, err := cache.GetChains(ctx, ChainsParams{Symbol: "SPY"})
+	if err != nil {
+		t.Fatalf("GetChains returned error: %v", err)
+	}
+	if !resp.FromCache || resp.StatusCode != http.StatusOK || chains.Symbol != "SPY" {
+		t.Errorf("second GetChains = %+v, %+v, want the cached chain", chains, resp)
+	}
+	if requests["SPY"] != 1 {
+		t.Errorf("fetched SPY %d times within the TTL, want once", requests["SPY"])
+	}
+
+	// Other parameters are another chain.
+	if _, _, err := cache.GetChains(ctx, ChainsParams{Symbol: "SPY", StrikeCount: 1}); err != nil {
+		t.Fatalf("GetChains returned error: %v", err)
+	}
+	if _, _, err := cache.GetChains(ctx, ChainsParams{Symbol: "QQQ"}); err != nil {
+		t.Fatalf("GetChains returned error: %v", err)
+	}
+	if requests["SPY"] != 2 || requests["QQQ"] != 1 {
+		t.Errorf("unexpected requests: %v", requests)
+	}
+
+	cache.(*CachedChainsService).Invalidate("SPY")
+	cache.GetChains(ctx, ChainsParams{Symbol: "SPY"})
+	cache.GetChains(ctx, ChainsParams{Symbol: "QQQ"})
+	if requests["SPY"] != 3 || requests["QQQ"] != 1 {
+		t.Errorf("unexpected requests after Invalidate: %v", requests)
+	}
+
+	cache.(*CachedChainsService).InvalidateAll()
+	cache.GetChains(ctx, ChainsParams{Symbol: "QQQ"})
+	if requests["QQQ"] != 2 {
+		t.Errorf("unexpected requests after InvalidateAll: %v", requests)
+	}
+}
+
+func TestCachedChainsServiceExpiry(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"symbol":"SPY","status":"SUCCESS"}`)
+	})
+
+	cache := NewCachedChainsService(client.Chains, 10*time.Millisecond)
+	cache.GetChains(context.Background(), ChainsParams{Symbol: "SPY"})
+	time.Sleep(20 * time.Millisecond)
+	cache.GetChains(context.Background(), ChainsParams{Symbol: "SPY"})
+	if requests != 2 {
+		t.Errorf("fetched %d times around the TTL, want 2", requests)
+	}
+}
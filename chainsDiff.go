@@ -0,0 +1,96 @@
+package tdameritrade
+
+import (
+	"math"
+	"reflect"
+	"strings"
+)
+
+// ChainsDiff is the difference between two snapshots of an option chain, its contracts being matched by symbol.
+// Contracts are in the order of Chains.FlattenAll.
+type ChainsDiff struct {
+	NewContracts     []ExpDateOption
+	RemovedContracts []ExpDateOption
+	ChangedContracts []ContractChange
+}
+
+// ContractChange is a contract whose values differ between two snapshots of a chain.
+// ChangedFields are the JSON names of the fields that differ, e.g. "bid" and "delta", in the order of ExpDateOption.
+type ContractChange struct {
+	Symbol        string
+	Prev          *ExpDateOption
+	Curr          *ExpDateOption
+	ChangedFields []string
+}
+
+// DiffChains returns the contracts added, removed and changed from prev to curr. A nil prev or curr has no contracts.
+// NaN values, which TD Ameritrade sends for Greeks it could not compute, are equal to each other.
+func DiffChains(prev, curr *Chains) *ChainsDiff {
+	prevOptions, currOptions := flattenChains(prev), flattenChains(curr)
+	prevBySymbol := make(map[string]*ExpDateOption, len(prevOptions))
+	for i := range prevOptions {
+		prevBySymbol[prevOptions[i].Symbol] = &prevOptions[i]
+	}
+
+	diff := &ChainsDiff{}
+	currSymbols := make(map[string]bool, len(currOptions))
+	for i := range currOptions {
+		c := &currOptions[i]
+		currSymbols[c.Symbol] = true
+		p, ok := prevBySymbol[c.Symbol]
+		if !ok {
+			diff.NewContracts = append(diff.NewContracts, *c)
+			continue
+		}
+		if fields := changedFields(p, c); len(fields) > 0 {
+			diff.ChangedContracts = append(diff.ChangedContracts, ContractChange{Symbol: c.Symbol, Prev: p, Curr: c, ChangedFields: fields})
+		}
+	}
+	for _, p := range prevOptions {
+		if !currSymbols[p.Symbol] {
+			diff.RemovedContracts = append(diff.RemovedContracts, p)
+		}
+	}
+	return diff
+}
+
+// HasSignificantGreeksChange reports whether the delta of a changed contract moved by deltaThreshold or more.
+// Contracts without a valid delta in both snapshots are ignored.
+func (d *ChainsDiff) HasSignificantGreeksChange(deltaThreshold float64) bool {
+	for _, c := range d.ChangedContracts {
+		if !c.Prev.Delta.IsValid() || !c.Curr.Delta.IsValid() {
+			continue
+		}
+		if math.Abs(float64(c.Curr.Delta-c.Prev.Delta)) >= deltaThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+func flattenChains(c *Chains) []ExpDateOption {
+	if c == nil {
+		return nil
+	}
+	return c.FlattenAll()
+}
+
+// changedFields returns the JSON names of the fields of a and b whose values differ.
+func changedFields(a, b *ExpDateOption) []string {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	var fields []string
+	for i := 0; i < va.NumField(); i++ {
+		fa, fb := va.Field(i), vb.Field(i)
+		if fa.Kind() == reflect.Float64 {
+			x, y := fa.Float(), fb.Float()
+			if x == y || math.IsNaN(x) && math.IsNaN(y) {
+				continue
+			}
+		} else if reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			continue
+		}
+		name := strings.Split(va.Type().Field(i).Tag.Get("json"), ",")[0]
+		fields = append(fields, name)
+	}
+	return fields
+}
@@ -0,0 +1,57 @@
+package tdameritrade
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDiffChains(t *testing.T) {
+	prev := &Chains{
+		CallExpDateMap: ExpDateMap{"2020-07-17:10": {
+			"310.0": {{PutCall: "CALL", Symbol: "SPY_071720C310", Bid: 2.5, Ask: 2.6, Delta: 0.52, Gamma: Float64WithSpecial(math.NaN())}},
+			"315.0": {{PutCall: "CALL", Symbol: "SPY_071720C315", Bid: 1.1, Ask: 1.2, Delta: 0.3}},
+		}},
+		PutExpDateMap: ExpDateMap{"2020-07-17:10": {
+			"310.0": {{PutCall: "PUT", Symbol: "SPY_071720P310", Bid: 2.1, Ask: 2.2, Delta: -0.48}},
+		}},
+	}
+	curr := &Chains{
+		CallExpDateMap: ExpDateMap{"2020-07-17:10": {
+			"310.0": {{PutCall: "CALL", Symbol: "SPY_071720C310", Bid: 2.7, Ask: 2.8, Delta: 0.56, Gamma: Float64WithSpecial(math.NaN())}},
+			"320.0": {{PutCall: "CALL", Symbol: "SPY_071720C320", Bid: 0.5, Ask: 0.6, Delta: 0.15}},
+		}},
+		PutExpDateMap: ExpDateMap{"2020-07-17:10": {
+			"310.0": {{PutCall: "PUT", Symbol: "SPY_071720P310", Bid: 2.1, Ask: 2.2, Delta: -0.48}},
+		}},
+	}
+
+	diff := DiffChains(prev, curr)
+	if len(diff.NewContracts) != 1 || diff.NewContracts[0].Symbol != "SPY_071720C320" {
+		t.Errorf("NewContracts = %+v, want SPY_071720C320", diff.NewContracts)
+	}
+	if len(diff.RemovedContracts) != 1 || diff.RemovedContracts[0].Symbol != "SPY_071720C315" {
+		t.Errorf("RemovedContracts = %+v, want SPY_071720C315", diff.RemovedContracts)
+	}
+	if len(diff.ChangedContracts) != 1 {
+		t.Fatalf("ChangedContracts = %+v, want SPY_071720C310 alone", diff.ChangedContracts)
+	}
+	change := diff.ChangedContracts[0]
+	if change.Symbol != "SPY_071720C310" || change.Prev.Bid != 2.5 || change.Curr.Bid != 2.7 {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	if want := []string{"bid", "ask", "delta"}; !reflect.DeepEqual(change.ChangedFields, want) {
+		t.Errorf("ChangedFields = %v, want %v", change.ChangedFields, want)
+	}
+
+	if !diff.HasSignificantGreeksChange(0.03) {
+		t.Error("HasSignificantGreeksChange(0.03) = false for a delta move of 0.04")
+	}
+	if diff.HasSignificantGreeksChange(0.05) {
+		t.Error("HasSignificantGreeksChange(0.05) = true for a delta move of 0.04")
+	}
+
+	if diff := DiffChains(nil, curr); len(diff.NewContracts) != 3 || len(diff.RemovedContracts) != 0 {
+		t.Errorf("DiffChains(nil, curr) = %+v, want every contract new", diff)
+	}
+}
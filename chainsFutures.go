@@ -0,0 +1,88 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// futuresMonthCodes are the codes of the contract months of futures, from January (F) to December (Z).
+const futuresMonthCodes = "FGHJKMNQUVXZ"
+
+// FuturesChainParams describes the chain of options of a futures contract.
+// The Symbol of ChainsParams is ignored for the symbol passed to GetFuturesOptionChain, and OptionType selects
+// standard (S) or non-standard (NS) options as for equity chains.
+type FuturesChainParams struct {
+	ChainsParams
+
+	// Month is the code of the month of the futures contract, one of F, G, H, J, K, M, N, Q, U, V, X and Z
+	// for January to December, and Year its year, e.g. 2021 or 21.
+	// The options of the active contract are returned when Month is empty.
+	Month string
+	Year  int
+}
+
+// FuturesUnderlying is the futures contract underlying a FuturesChain.
+// FutureExpirationDate is in milliseconds since the epoch.
+type FuturesUnderlying struct {
+	Underlying
+	FutureMultiplier     float64 `json:"futureMultiplier"`
+	FutureExpirationDate int64   `json:"futureExpirationDate"`
+	FutureIsActive       bool    `json:"futureIsActive"`
+	FutureActiveSymbol   string  `json:"futureActiveSymbol"`
+}
+
+// FuturesChain is the option chain of a futures contract.
+// Its Underlying replaces that of Chains, which is left empty.
+type FuturesChain struct {
+	Chains
+	Underlying FuturesUnderlying `json:"underlying"`
+}
+
+// futuresContractSymbol returns the symbol of the contract of root for month and year, e.g. /ESZ21,
+// or root alone, e.g. /ES, when month is empty.
+func futuresContractSymbol(root, month string, year int) (string, error) {
+	root = strings.ToUpper(strings.TrimSpace(root))
+	if root == "" {
+		return "", fmt.Errorf("%w: no symbol present", ErrInvalidParams)
+	}
+	if !strings.HasPrefix(root, "/") {
+		root = "/" + root
+	}
+	if month == "" {
+		return root, nil
+	}
+
+	month = strings.ToUpper(month)
+	if len(month) != 1 || !strings.Contains(futuresMonthCodes, month) {
+		return "", fmt.Errorf("%w: invalid futures month code %q", ErrInvalidParams, month)
+	}
+	if year <= 0 {
+		return "", fmt.Errorf("%w: a futures month needs a year", ErrInvalidParams)
+	}
+	return fmt.Sprintf("%s%s%02d", root, month, year%100), nil
+}
+
+// GetFuturesOptionChain returns the chain of the options of the futures of symbol, e.g. /ES, described by params.
+// TD Ameritrade names the futures of a contract month by appending its month code and year to the root,
+// e.g. /ESZ21, which is the symbol the chain is requested for when params has a Month.
+func (s *ChainsService) GetFuturesOptionChain(ctx context.Context, symbol string, params FuturesChainParams) (*FuturesChain, *Response, error) {
+	contract, err := futuresContractSymbol(symbol, params.Month, params.Year)
+	if err != nil {
+		return nil, nil, err
+	}
+	chainsParams := params.ChainsParams
+	chainsParams.Symbol = contract
+
+	req, err := s.client.NewRequest("GET", fmt.Sprintf("marketdata/chains?%s", chainsParams.ToURLValues().Encode()), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chain := new(FuturesChain)
+	resp, err := s.client.Do(ctx, req, chain)
+	if err != nil {
+		return nil, resp, err
+	}
+	return chain, resp, nil
+}
@@ -0,0 +1,72 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestGetFuturesOptionChain(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	fixture, err := ioutil.ReadFile("testdata/futures_chain.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "symbol", "/ESZ21")
+		testFormValue(t, r, "contractType", "CALL")
+		w.Write(fixture)
+	})
+
+	params := FuturesChainParams{ChainsParams: ChainsParams{ContractType: "CALL"}, Month: "z", Year: 2021}
+	chain, _, err := client.Chains.GetFuturesOptionChain(context.Background(), "ES", params)
+	if err != nil {
+		t.Fatalf("GetFuturesOptionChain returned error: %v", err)
+	}
+
+	u := chain.Underlying
+	if u.Symbol != "/ESZ21" || u.Last != 4557.75 || u.FutureMultiplier != 50 || u.FutureExpirationDate != 1639746000000 ||
+		!u.FutureIsActive || u.FutureActiveSymbol != "/ESZ21" {
+		t.Errorf("unexpected underlying: %+v", u)
+	}
+	calls := chain.FlattenCalls()
+	if len(calls) != 1 || calls[0].Symbol != "./EW3X21C4550" || calls[0].Multiplier != 50 || calls[0].Delta != 0.54 {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	if puts := chain.FlattenPuts(); len(puts) != 1 || puts[0].Symbol != "./EW3X21P4550" {
+		t.Errorf("unexpected puts: %+v", puts)
+	}
+}
+
+func TestFuturesContractSymbol(t *testing.T) {
+	tests := []struct {
+		root, month string
+		year        int
+		want        string
+	}{
+		{"/ES", "", 0, "/ES"},
+		{"es", "H", 22, "/ESH22"},
+		{"/CL", "f", 2030, "/CLF30"},
+		{"/GC", "M", 2005, "/GCM05"},
+	}
+	for _, tt := range tests {
+		got, err := futuresContractSymbol(tt.root, tt.month, tt.year)
+		if err != nil || got != tt.want {
+			t.Errorf("futuresContractSymbol(%q, %q, %d) = %q, %v, want %q", tt.root, tt.month, tt.year, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		root, month string
+		year        int
+	}{{"", "", 0}, {"/ES", "A", 2021}, {"/ES", "ZZ", 2021}, {"/ES", "Z", 0}} {
+		if _, err := futuresContractSymbol(tt.root, tt.month, tt.year); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("futuresContractSymbol(%q, %q, %d) error = %v, want ErrInvalidParams", tt.root, tt.month, tt.year, err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChainsPager fetches option chains a page of strikes at a time, for underlyings such as SPX whose full chain
+// holds thousands of contracts.
+type ChainsPager struct {
+	chains *ChainsService
+}
+
+// NewChainsPager returns a pager fetching chains with s.
+func NewChainsPager(s *ChainsService) *ChainsPager {
+	return &ChainsPager{chains: s}
+}
+
+// ChainsPages iterates over the pages of a chain, in the manner of bufio.Scanner:
+//
+// pages := pager.Pages(ctx, params, 10)
+// for pages.Next() { use(pages.Chains()) }
+// if err := pages.Err(); err != nil { ... }
+//
+// It is an iterator type rather than an iter.Seq because the module supports Go versions without range over
+// functions, and so that the error stopping the iteration can be returned by Err.
+type ChainsPages struct {
+	ctx            context.Context
+	chains         *ChainsService
+	params         ChainsParams
+	strikesPerPage int
+
+	page    int
+	maxPage int
+	seen    map[string]bool
+	current *Chains
+	err     error
+	done    bool
+}
+
+// Pages returns an iterator over the chain described by params, strikesPerPage strikes above and below the
+// at-the-money strike at a time, working outwards.
+//
+// This is not real paging: TD Ameritrade can only select strikes around the money, with StrikeCount, or a
+// single strike, with Strike, not a window of strikes away from the money. So page n requests the
+// n*strikesPerPage strikes on each side of the money, refetching those of the earlier pages, and keeps only the
+// options not returned yet. Each page holds at most 2*strikesPerPage strikes per expiration, which bounds the
+// memory held by the caller, but the responses grow with every page, and fetching n pages downloads about n/2
+// times the strikes of the chain. To get the whole chain at once, CollectAllPages costs more than a single
+// GetChains; use the pager to stop early, once the strikes of interest have been found.
+// Iteration stops at the first page adding no options, or once params.StrikeCount strikes on each side have been
+// fetched if it is set.
+func (p *ChainsPager) Pages(ctx context.Context, params ChainsParams, strikesPerPage int) *ChainsPages {
+	pages := &ChainsPages{
+		ctx:            ctx,
+		chains:         p.chains,
+		params:         params,
+		strikesPerPage: strikesPerPage,
+		seen:           map[string]bool{},
+	}
+	switch {
+	case strikesPerPage < 1:
+		pages.err = fmt.Errorf("%w: strikesPerPage must be positive, got %d", ErrInvalidParams, strikesPerPage)
+	case params.Strike != 0:
+		pages.err = fmt.Errorf("%w: cannot page through the single strike %v", ErrInvalidParams, params.Strike)
+	case params.StrikeCount < 0:
+		pages.err = fmt.Errorf("%w: StrikeCount must not be negative, got %d", ErrInvalidParams, params.StrikeCount)
+	}
+	if params.StrikeCount > 0 {
+		pages.maxPage = (params.StrikeCount + strikesPerPage - 1) / strikesPerPage
+	}
+	return pages
+}
+
+// Next fetches the next page, reporting whether there is one. It returns false at the end of the chain or
+// on an error, which Err returns. Each call requests all the strikes of the earlier pages again, see Pages.
+func (it *ChainsPages) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	if it.maxPage > 0 && it.page >= it.maxPage {
+		it.done = true
+		return false
+	}
+
+	it.page++
+	params := it.params
+	params.StrikeCount = it.page * it.strikesPerPage
+	if it.params.StrikeCount > 0 && params.StrikeCount > it.params.StrikeCount {
+		params.StrikeCount = it.params.StrikeCount
+	}
+
+	chains, _, err := it.chains.GetChains(it.ctx, params)
+	if err != nil {
+		it.err = fmt.Errorf("fetching page %d of the chain of %s: %w", it.page, it.params.Symbol, err)
+		return false
+	}
+
+	chains.CallExpDateMap = it.unseen("CALL", chains.CallExpDateMap)
+	chains.PutExpDateMap = it.unseen("PUT", chains.PutExpDateMap)
+	chains.NumberOfContracts = chains.CallExpDateMap.len() + chains.PutExpDateMap.len()
+	if chains.NumberOfContracts == 0 {
+		it.done = true
+		return false
+	}
+
+	it.current = chains
+	return true
+}
+
+// Chains returns the page fetched by the last call to Next, holding only options not on earlier pages.
+func (it *ChainsPages) Chains() *Chains {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ChainsPages) Err() error {
+	return it.err
+}
+
+// unseen returns the options of m not returned on earlier pages and marks them seen.
+func (it *ChainsPages) unseen(putCall string, m ExpDateMap) ExpDateMap {
+	fresh := ExpDateMap{}
+	for expiration, strikes := range m {
+		for strike, options := range strikes {
+			key := putCall + " " + expiration + " " + strike
+			if it.seen[key] {
+				continue
+			}
+			it.seen[key] = true
+			if fresh[expiration] == nil {
+				fresh[expiration] = map[string][]ExpDateOption{}
+			}
+			fresh[expiration][strike] = options
+		}
+	}
+	return fresh
+}
+
+// CollectAllPages fetches every page of the chain described by params and merges them into one chain.
+// The chain-wide fields, such as UnderlyingPrice, are those of the last page.
+func (p *ChainsPager) CollectAllPages(ctx context.Context, params ChainsParams, strikesPerPage int) (*Chains, error) {
+	pages := p.Pages(ctx, params, strikesPerPage)
+
+	var merged *Chains
+	for pages.Next() {
+		page := pages.Chains()
+		if merged == nil {
+			merged = &Chains{CallExpDateMap: ExpDateMap{}, PutExpDateMap: ExpDateMap{}}
+		}
+		calls, puts := merged.CallExpDateMap, merged.PutExpDateMap
+		*merged = *page
+		merged.CallExpDateMap = mergeExpDateMaps(calls, page.CallExpDateMap)
+		merged.PutExpDateMap = mergeExpDateMaps(puts, page.PutExpDateMap)
+		merged.NumberOfContracts = merged.CallExpDateMap.len() + merged.PutExpDateMap.len()
+	}
+	if err := pages.Err(); err != nil {
+		return nil, err
+	}
+	if merged == nil {
+		return nil, fmt.Errorf("no options found in the chain of %s", params.Symbol)
+	}
+	return merged, nil
+}
+
+// mergeExpDateMaps adds the options of src to dst, replacing those at the same expiration and strike.
+func mergeExpDateMaps(dst, src ExpDateMap) ExpDateMap {
+	for expiration, strikes := range src {
+		if dst[expiration] == nil {
+			dst[expiration] = map[string][]ExpDateOption{}
+		}
+		for strike, options := range strikes {
+			dst[expiration][strike] = options
+		}
+	}
+	return dst
+}
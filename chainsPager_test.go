@@ -0,0 +1,152 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// serveWideChain serves a chain with strikes from 100 to 200, 5 apart, at two expirations,
+// returning the strikeCount strikes on either side of the at-the-money strike of 150.
+func serveWideChain(t *testing.T, mux *http.ServeMux) *[]int {
+	var counts []int
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		count, err := strconv.Atoi(r.FormValue("strikeCount"))
+		if err != nil {
+			t.Errorf("invalid strikeCount %q", r.FormValue("strikeCount"))
+		}
+		counts = append(counts, count)
+
+		chain := Chains{Symbol: "SPX", UnderlyingPrice: 150, CallExpDateMap: ExpDateMap{}, PutExpDateMap: ExpDateMap{}}
+		for _, expiration := range []string{"2020-07-17:10", "2020-08-21:45"} {
+			chain.CallExpDateMap[expiration] = map[string][]ExpDateOption{}
+			chain.PutExpDateMap[expiration] = map[string][]ExpDateOption{}
+			for strike := 100.0; strike <= 200; strike += 5 {
+				if math.Abs(strike-150) > float64(count)*5 {
+					continue
+				}
+				key := strconv.FormatFloat(strike, 'f', 1, 64)
+				chain.CallExpDateMap[expiration][key] = []ExpDateOption{{PutCall: "CALL", StrikePrice: strike}}
+				chain.PutExpDateMap[expiration][key] = []ExpDateOption{{PutCall: "PUT", StrikePrice: strike}}
+			}
+		}
+		b, err := json.Marshal(chain)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Write(b)
+	})
+	return &counts
+}
+
+func TestChainsPagerPages(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+	counts := serveWideChain(t, mux)
+
+	pages := NewChainsPager(client.Chains).Pages(context.Background(), ChainsParams{Symbol: "SPX"}, 3)
+	var sizes []int
+	seen := map[string]bool{}
+	for pages.Next() {
+		page := pages.Chains()
+		sizes = append(sizes, page.NumberOfContracts)
+		for putCall, m := range map[string]ExpDateMap{"CALL": page.CallExpDateMap, "PUT": page.PutExpDateMap} {
+			for expiration, strikes := range m {
+				for strike := range strikes {
+					key := putCall + " " + expiration + " " + strike
+					if seen[key] {
+						t.Errorf("%s returned on more than one page", key)
+					}
+					seen[key] = true
+				}
+			}
+		}
+	}
+	if err := pages.Err(); err != nil {
+		t.Fatalf("Pages returned error: %v", err)
+	}
+
+	// 7 strikes on the first page, 6 more on each of the next two and the last 2 on the fourth,
+	// each at two expirations for calls and puts.
+	if want := []int{28, 24, 24, 8}; fmt.Sprint(sizes) != fmt.Sprint(want) {
+		t.Errorf("page sizes = %v, want %v", sizes, want)
+	}
+	if want := []int{3, 6, 9, 12, 15}; fmt.Sprint(*counts) != fmt.Sprint(want) {
+		t.Errorf("requested strike counts = %v, want %v", *counts, want)
+	}
+}
+
+func TestChainsPagerCollectAllPages(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+	counts := serveWideChain(t, mux)
+
+	chain, err := NewChainsPager(client.Chains).CollectAllPages(context.Background(), ChainsParams{Symbol: "SPX"}, 4)
+	if err != nil {
+		t.Fatalf("CollectAllPages returned error: %v", err)
+	}
+	if chain.NumberOfContracts != 84 || len(chain.FlattenAll()) != 84 {
+		t.Errorf("expected 84 contracts, got %d in %d options", chain.NumberOfContracts, len(chain.FlattenAll()))
+	}
+	for _, expiration := range []string{"2020-07-17:10", "2020-08-21:45"} {
+		if n := len(chain.CallExpDateMap[expiration]); n != 21 {
+			t.Errorf("expected 21 call strikes expiring %s, got %d", expiration, n)
+		}
+		if n := len(chain.PutExpDateMap[expiration]); n != 21 {
+			t.Errorf("expected 21 put strikes expiring %s, got %d", expiration, n)
+		}
+	}
+	if chain.Symbol != "SPX" || chain.UnderlyingPrice != 150 {
+		t.Errorf("expected the chain-wide fields of the pages, got %s at %v", chain.Symbol, chain.UnderlyingPrice)
+	}
+	// The fourth request finds no more strikes.
+	if want := []int{4, 8, 12, 16}; fmt.Sprint(*counts) != fmt.Sprint(want) {
+		t.Errorf("requested strike counts = %v, want %v", *counts, want)
+	}
+}
+
+func TestChainsPagerStrikeCount(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+	counts := serveWideChain(t, mux)
+
+	chain, err := NewChainsPager(client.Chains).CollectAllPages(context.Background(), ChainsParams{Symbol: "SPX", StrikeCount: 4}, 3)
+	if err != nil {
+		t.Fatalf("CollectAllPages returned error: %v", err)
+	}
+	if n := len(chain.CallExpDateMap["2020-07-17:10"]); n != 9 {
+		t.Errorf("expected the 9 strikes within 4 of the money, got %d", n)
+	}
+	if want := []int{3, 4}; fmt.Sprint(*counts) != fmt.Sprint(want) {
+		t.Errorf("requested strike counts = %v, want %v", *counts, want)
+	}
+}
+
+func TestChainsPagerInvalidParams(t *testing.T) {
+	client, _, teardown := setup(t)
+	defer teardown()
+	pager := NewChainsPager(client.Chains)
+
+	for _, tt := range []struct {
+		params         ChainsParams
+		strikesPerPage int
+	}{
+		{ChainsParams{Symbol: "SPX"}, 0},
+		{ChainsParams{Symbol: "SPX", Strike: 150}, 5},
+		{ChainsParams{Symbol: "SPX", StrikeCount: -1}, 5},
+	} {
+		pages := pager.Pages(context.Background(), tt.params, tt.strikesPerPage)
+		if pages.Next() {
+			t.Errorf("Pages(%+v, %d): expected no pages", tt.params, tt.strikesPerPage)
+		}
+		if !errors.Is(pages.Err(), ErrInvalidParams) {
+			t.Errorf("Pages(%+v, %d): expected ErrInvalidParams, got %v", tt.params, tt.strikesPerPage, pages.Err())
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Strategy is the strategy TD Ameritrade builds an option chain for, set with ChainsParams.Strategy.
+type Strategy string
+
+// Strategies accepted by ChainsService.GetChains.
+const (
+	StrategySingle     Strategy = "SINGLE"
+	StrategyAnalytical Strategy = "ANALYTICAL"
+	StrategyCovered    Strategy = "COVERED"
+	StrategyVertical   Strategy = "VERTICAL"
+	StrategyCalendar   Strategy = "CALENDAR"
+	StrategyStrangle   Strategy = "STRANGLE"
+	StrategyStraddle   Strategy = "STRADDLE"
+	StrategyButterfly  Strategy = "BUTTERFLY"
+	StrategyCondor     Strategy = "CONDOR"
+	StrategyDiagonal   Strategy = "DIAGONAL"
+	StrategyCollar     Strategy = "COLLAR"
+	StrategyRoll       Strategy = "ROLL"
+)
+
+// The strategies returned by the helpers below are priced at the mark of each option, per contract of each leg.
+// MaxProfit and MaxLoss are positive amounts in dollars and +Inf when unlimited, as for SpreadPnL.
+
+// VerticalSpread is a debit spread: a long option at the money and a short option further out of the money.
+// NetDebit is the price per share paid for the spread.
+type VerticalSpread struct {
+	LongLeg   *ExpDateOption
+	ShortLeg  *ExpDateOption
+	NetDebit  float64
+	MaxProfit float64
+	MaxLoss   float64
+}
+
+// IronCondor is a short put spread below and a short call spread above the underlying price.
+// NetCredit is the price per share received for the condor.
+type IronCondor struct {
+	LongPut   *ExpDateOption
+	ShortPut  *ExpDateOption
+	ShortCall *ExpDateOption
+	LongCall  *ExpDateOption
+	NetCredit float64
+	MaxProfit float64
+	MaxLoss   float64
+}
+
+// Straddle is a long call and a long put at the same strike, at the money.
+// NetDebit is the price per share paid for both options.
+type Straddle struct {
+	Call      *ExpDateOption
+	Put       *ExpDateOption
+	NetDebit  float64
+	MaxProfit float64
+	MaxLoss   float64
+	BreakEven []float64
+}
+
+// Strangle is a long call above and a long put below the at-the-money strike.
+// NetDebit is the price per share paid for both options.
+type Strangle struct {
+	Call      *ExpDateOption
+	Put       *ExpDateOption
+	NetDebit  float64
+	MaxProfit float64
+	MaxLoss   float64
+	BreakEven []float64
+}
+
+// GetVerticalSpread returns the debit spread of putCall options expiring on expiry, e.g. 2020-07-17, width apart:
+// a bull call spread buying the at-the-money call and selling the call width above it, or a bear put spread
+// buying the at-the-money put and selling the put width below it.
+// The at-the-money strike is the strike nearest to the underlying price.
+func (s *ChainsService) GetVerticalSpread(ctx context.Context, symbol, expiry string, width float64, putCall string) (*VerticalSpread, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("%w: width must be positive, got %v", ErrInvalidParams, width)
+	}
+	if putCall != "CALL" && putCall != "PUT" {
+		return nil, fmt.Errorf("%w: putCall must be CALL or PUT, got %q", ErrInvalidParams, putCall)
+	}
+	sc, err := s.getStrategyChain(ctx, symbol, expiry, ContractType(putCall))
+	if err != nil {
+		return nil, err
+	}
+
+	atm := sc.atTheMoney(putCall)
+	shortStrike := atm + width
+	if putCall == "PUT" {
+		shortStrike = atm - width
+	}
+	long, err := sc.option(putCall, atm)
+	if err != nil {
+		return nil, err
+	}
+	short, err := sc.option(putCall, shortStrike)
+	if err != nil {
+		return nil, err
+	}
+
+	legs := []SpreadLeg{strategyLeg(1, long), strategyLeg(-1, short)}
+	pnl := SpreadPnL(legs, sc.chain.UnderlyingPrice)
+	return &VerticalSpread{
+		LongLeg:   long,
+		ShortLeg:  short,
+		NetDebit:  netPremium(legs),
+		MaxProfit: pnl.MaxProfit,
+		MaxLoss:   pnl.MaxLoss,
+	}, nil
+}
+
+// GetIronCondor returns the iron condor of options expiring on expiry, e.g. 2020-07-17, selling the put and the
+// call shortDistance below and above the at-the-money strike and buying the put and the call wingWidth further out.
+func (s *ChainsService) GetIronCondor(ctx context.Context, symbol, expiry string, shortDistance, wingWidth float64) (*IronCondor, error) {
+	if shortDistance <= 0 || wingWidth <= 0 {
+		return nil, fmt.Errorf("%w: shortDistance and wingWidth must be positive, got %v and %v", ErrInvalidParams, shortDistance, wingWidth)
+	}
+	sc, err := s.getStrategyChain(ctx, symbol, expiry, "ALL")
+	if err != nil {
+		return nil, err
+	}
+
+	atm := sc.atTheMoney("CALL")
+	condor := &IronCondor{}
+	for _, leg := range []struct {
+		option  **ExpDateOption
+		putCall string
+		strike  float64
+	}{
+		{&condor.LongPut, "PUT", atm - shortDistance - wingWidth},
+		{&condor.ShortPut, "PUT", atm - shortDistance},
+		{&condor.ShortCall, "CALL", atm + shortDistance},
+		{&condor.LongCall, "CALL", atm + shortDistance + wingWidth},
+	} {
+		if *leg.option, err = sc.option(leg.putCall, leg.strike); err != nil {
+			return nil, err
+		}
+	}
+
+	legs := []SpreadLeg{
+		strategyLeg(1, condor.LongPut),
+		strategyLeg(-1, condor.ShortPut),
+		strategyLeg(-1, condor.ShortCall),
+		strategyLeg(1, condor.LongCall),
+	}
+	pnl := SpreadPnL(legs, sc.chain.UnderlyingPrice)
+	condor.NetCredit = -netPremium(legs)
+	condor.MaxProfit, condor.MaxLoss = pnl.MaxProfit, pnl.MaxLoss
+	return condor, nil
+}
+
+// GetStraddle returns the straddle of options expiring on expiry, e.g. 2020-07-17, at the at-the-money strike.
+func (s *ChainsService) GetStraddle(ctx context.Context, symbol, expiry string) (*Straddle, error) {
+	sc, err := s.getStrategyChain(ctx, symbol, expiry, "ALL")
+	if err != nil {
+		return nil, err
+	}
+
+	atm := sc.atTheMoney("CALL")
+	call, err := sc.option("CALL", atm)
+	if err != nil {
+		return nil, err
+	}
+	put, err := sc.option("PUT", atm)
+	if err != nil {
+		return nil, err
+	}
+
+	legs := []SpreadLeg{strategyLeg(1, call), strategyLeg(1, put)}
+	pnl := SpreadPnL(legs, sc.chain.UnderlyingPrice)
+	return &Straddle{
+		Call:      call,
+		Put:       put,
+		NetDebit:  netPremium(legs),
+		MaxProfit: pnl.MaxProfit,
+		MaxLoss:   pnl.MaxLoss,
+		BreakEven: pnl.BreakEven,
+	}, nil
+}
+
+// GetStrangle returns the strangle of options expiring on expiry, e.g. 2020-07-17, buying the call width above
+// and the put width below the at-the-money strike.
+func (s *ChainsService) GetStrangle(ctx context.Context, symbol, expiry string, width float64) (*Strangle, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("%w: width must be positive, got %v", ErrInvalidParams, width)
+	}
+	sc, err := s.getStrategyChain(ctx, symbol, expiry, "ALL")
+	if err != nil {
+		return nil, err
+	}
+
+	atm := sc.atTheMoney("CALL")
+	call, err := sc.option("CALL", atm+width)
+	if err != nil {
+		return nil, err
+	}
+	put, err := sc.option("PUT", atm-width)
+	if err != nil {
+		return nil, err
+	}
+
+	legs := []SpreadLeg{strategyLeg(1, call), strategyLeg(1, put)}
+	pnl := SpreadPnL(legs, sc.chain.UnderlyingPrice)
+	return &Strangle{
+		Call:      call,
+		Put:       put,
+		NetDebit:  netPremium(legs),
+		MaxProfit: pnl.MaxProfit,
+		MaxLoss:   pnl.MaxLoss,
+		BreakEven: pnl.BreakEven,
+	}, nil
+}
+
+// strategyChain is the chain of a single expiration the strategy helpers pick options from.
+type strategyChain struct {
+	chain    *Chains
+	expiry   string
+	surfaces map[string]*GreeksSurface
+}
+
+// getStrategyChain fetches the contractType options of symbol expiring on expiry.
+func (s *ChainsService) getStrategyChain(ctx context.Context, symbol, expiry string, contractType ContractType) (*strategyChain, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: no symbol present", ErrInvalidParams)
+	}
+	date, err := time.Parse("2006-01-02", expiry)
+	if err != nil {
+		return nil, fmt.Errorf("%w: expiry must be a date such as 2020-07-17, got %q", ErrInvalidParams, expiry)
+	}
+
+	chain, _, err := s.GetChains(ctx, ChainsParams{
+		Symbol:       symbol,
+		ContractType: contractType,
+		Strategy:     StrategySingle,
+		FromDate:     date,
+		ToDate:       date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &strategyChain{
+		chain:  chain,
+		expiry: expiry,
+		surfaces: map[string]*GreeksSurface{
+			"CALL": BuildGreeksSurface(chain, "CALL"),
+			"PUT":  BuildGreeksSurface(chain, "PUT"),
+		},
+	}, nil
+}
+
+// atTheMoney returns the putCall strike of the expiration nearest to the underlying price, or NaN if there is none.
+func (c *strategyChain) atTheMoney(putCall string) float64 {
+	surface := c.surfaces[putCall]
+	atm := math.NaN()
+	for _, strike := range surface.Strikes {
+		if surface.Get(c.expiry, strike) == nil {
+			continue
+		}
+		if math.IsNaN(atm) || math.Abs(strike-c.chain.UnderlyingPrice) < math.Abs(atm-c.chain.UnderlyingPrice) {
+			atm = strike
+		}
+	}
+	return atm
+}
+
+// option returns the putCall option of the expiration at strike.
+func (c *strategyChain) option(putCall string, strike float64) (*ExpDateOption, error) {
+	p := c.surfaces[putCall].Get(c.expiry, strike)
+	if p == nil {
+		return nil, fmt.Errorf("no %s expiring %s at strike %v in the chain of %s", putCall, c.expiry, strike, c.chain.Symbol)
+	}
+	return &p.ExpDateOption, nil
+}
+
+// strategyLeg returns quantity contracts of option bought or sold at its mark.
+func strategyLeg(quantity int, option *ExpDateOption) SpreadLeg {
+	return SpreadLeg{
+		Quantity:    quantity,
+		StrikePrice: option.StrikePrice,
+		PutCall:     string(option.PutCall),
+		PremiumPaid: option.Mark,
+		Multiplier:  option.Multiplier,
+	}
+}
+
+// netPremium returns the price per share paid for legs, negative if they bring in a credit.
+func netPremium(legs []SpreadLeg) float64 {
+	net := 0.0
+	for _, l := range legs {
+		net += float64(l.Quantity) * l.PremiumPaid
+	}
+	return net
+}
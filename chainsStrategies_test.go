@@ -0,0 +1,149 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+)
+
+// serveStrategyChain serves chain, checking that the request asks for the single options of 2020-07-17.
+func serveStrategyChain(t *testing.T, mux *http.ServeMux, chain *Chains, contractType string) {
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "symbol", "SPY")
+		testFormValue(t, r, "contractType", contractType)
+		testFormValue(t, r, "strategy", "SINGLE")
+		testFormValue(t, r, "fromDate", "2020-07-17")
+		testFormValue(t, r, "toDate", "2020-07-17")
+		b, err := json.Marshal(chain)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Write(b)
+	})
+}
+
+func TestGetVerticalSpread(t *testing.T) {
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-6 }
+
+	tests := []struct {
+		putCall                      string
+		long, short                  string
+		netDebit, maxProfit, maxLoss float64
+	}{
+		{"CALL", "SPY_071720C310", "SPY_071720C315", 0.92, 408, 92},
+		{"PUT", "SPY_071720P310", "SPY_071720P305", 0.4, 460, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.putCall, func(t *testing.T) {
+			client, mux, teardown := setup(t)
+			defer teardown()
+			serveStrategyChain(t, mux, loadChains(t), tt.putCall)
+
+			spread, err := client.Chains.GetVerticalSpread(context.Background(), "SPY", "2020-07-17", 5, tt.putCall)
+			if err != nil {
+				t.Fatalf("GetVerticalSpread returned error: %v", err)
+			}
+			if spread.LongLeg.Symbol != tt.long || spread.ShortLeg.Symbol != tt.short {
+				t.Errorf("legs = %s/%s, want %s/%s", spread.LongLeg.Symbol, spread.ShortLeg.Symbol, tt.long, tt.short)
+			}
+			if !near(spread.NetDebit, tt.netDebit) || !near(spread.MaxProfit, tt.maxProfit) || !near(spread.MaxLoss, tt.maxLoss) {
+				t.Errorf("debit %v, max profit %v, max loss %v, want %v, %v, %v", spread.NetDebit, spread.MaxProfit, spread.MaxLoss, tt.netDebit, tt.maxProfit, tt.maxLoss)
+			}
+		})
+	}
+}
+
+func TestGetVerticalSpreadErrors(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+	serveStrategyChain(t, mux, loadChains(t), "CALL")
+
+	ctx := context.Background()
+	if _, err := client.Chains.GetVerticalSpread(ctx, "SPY", "2020-07-17", 0, "CALL"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for a zero width, got %v", err)
+	}
+	if _, err := client.Chains.GetVerticalSpread(ctx, "SPY", "July 17", 5, "CALL"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for an invalid expiry, got %v", err)
+	}
+	if _, err := client.Chains.GetVerticalSpread(ctx, "SPY", "2020-07-17", 5, "BOTH"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for an invalid putCall, got %v", err)
+	}
+	if _, err := client.Chains.GetVerticalSpread(ctx, "", "2020-07-17", 5, "CALL"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams without a symbol, got %v", err)
+	}
+	if _, err := client.Chains.GetVerticalSpread(ctx, "SPY", "2020-07-17", 10, "CALL"); err == nil {
+		t.Error("expected an error for a strike missing from the chain")
+	}
+}
+
+func TestGetStraddleAndStrangle(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+	serveStrategyChain(t, mux, loadChains(t), "ALL")
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-6 }
+
+	straddle, err := client.Chains.GetStraddle(context.Background(), "SPY", "2020-07-17")
+	if err != nil {
+		t.Fatalf("GetStraddle returned error: %v", err)
+	}
+	if straddle.Call.Symbol != "SPY_071720C310" || straddle.Put.Symbol != "SPY_071720P310" {
+		t.Errorf("straddle legs = %s/%s", straddle.Call.Symbol, straddle.Put.Symbol)
+	}
+	if !near(straddle.NetDebit, 4.72) || !near(straddle.MaxLoss, 472) || !math.IsInf(straddle.MaxProfit, 1) {
+		t.Errorf("straddle debit %v, max loss %v, max profit %v", straddle.NetDebit, straddle.MaxLoss, straddle.MaxProfit)
+	}
+	if len(straddle.BreakEven) != 2 || !near(straddle.BreakEven[0], 305.28) || !near(straddle.BreakEven[1], 314.72) {
+		t.Errorf("straddle break even = %v, want [305.28 314.72]", straddle.BreakEven)
+	}
+
+	strangle, err := client.Chains.GetStrangle(context.Background(), "SPY", "2020-07-17", 5)
+	if err != nil {
+		t.Fatalf("GetStrangle returned error: %v", err)
+	}
+	if strangle.Call.Symbol != "SPY_071720C315" || strangle.Put.Symbol != "SPY_071720P305" {
+		t.Errorf("strangle legs = %s/%s", strangle.Call.Symbol, strangle.Put.Symbol)
+	}
+	if !near(strangle.NetDebit, 3.4) || !near(strangle.MaxLoss, 340) || !math.IsInf(strangle.MaxProfit, 1) {
+		t.Errorf("strangle debit %v, max loss %v, max profit %v", strangle.NetDebit, strangle.MaxLoss, strangle.MaxProfit)
+	}
+	if len(strangle.BreakEven) != 2 || !near(strangle.BreakEven[0], 301.6) || !near(strangle.BreakEven[1], 318.4) {
+		t.Errorf("strangle break even = %v, want [301.6 318.4]", strangle.BreakEven)
+	}
+}
+
+func TestGetIronCondor(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	// Add the wings the recorded chain is missing.
+	chain := loadChains(t)
+	chain.PutExpDateMap["2020-07-17:10"]["300.0"] = []ExpDateOption{{PutCall: "PUT", Symbol: "SPY_071720P300", StrikePrice: 300, Mark: 1, Multiplier: 100}}
+	chain.CallExpDateMap["2020-07-17:10"]["320.0"] = []ExpDateOption{{PutCall: "CALL", Symbol: "SPY_071720C320", StrikePrice: 320, Mark: 0.8, Multiplier: 100}}
+	serveStrategyChain(t, mux, chain, "ALL")
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-6 }
+
+	condor, err := client.Chains.GetIronCondor(context.Background(), "SPY", "2020-07-17", 5, 5)
+	if err != nil {
+		t.Fatalf("GetIronCondor returned error: %v", err)
+	}
+	got := []string{condor.LongPut.Symbol, condor.ShortPut.Symbol, condor.ShortCall.Symbol, condor.LongCall.Symbol}
+	want := []string{"SPY_071720P300", "SPY_071720P305", "SPY_071720C315", "SPY_071720C320"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("legs = %v, want %v", got, want)
+			break
+		}
+	}
+	if !near(condor.NetCredit, 1.6) || !near(condor.MaxProfit, 160) || !near(condor.MaxLoss, 340) {
+		t.Errorf("credit %v, max profit %v, max loss %v, want 1.6, 160, 340", condor.NetCredit, condor.MaxProfit, condor.MaxLoss)
+	}
+
+	if _, err := client.Chains.GetIronCondor(context.Background(), "SPY", "2020-07-17", 5, 10); err == nil {
+		t.Error("expected an error for wings missing from the chain")
+	}
+}
@@ -0,0 +1,424 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChainsParamsToURLValues(t *testing.T) {
+	params := NewChainsParamsBuilder("SPY").
+		ContractType("CALL").
+		StrikeCount(4).
+		IncludeQuotes(true).
+		Strategy("SINGLE").
+		Interval(2.5).
+		Range("OTM").
+		Dates(time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 7, 17, 0, 0, 0, 0, time.UTC)).
+		Build()
+
+	want := "contractType=CALL&fromDate=2020-07-01&includeQuotes=TRUE&interval=2.5&range=OTM&strategy=SINGLE&strikeCount=4&symbol=SPY&toDate=2020-07-17"
+	if got := params.ToURLValues().Encode(); got != want {
+		t.Errorf("ToURLValues() = %q, want %q", got, want)
+	}
+
+	if got := (ChainsParams{Symbol: "SPY"}).ToURLValues().Encode(); got != "symbol=SPY" {
+		t.Errorf("zero values were encoded: %q", got)
+	}
+}
+
+func TestGetChains(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "symbol", "SPY")
+		testFormValue(t, r, "strikeCount", "1")
+		fmt.Fprint(w, `{"symbol":"SPY","status":"SUCCESS","numberOfContracts":1,"callExpDateMap":{"2020-07-17:10":{"310.0":[{"putCall":"CALL","symbol":"SPY_071720C310","delta":"NaN"}]}}}`)
+	})
+
+	chains, _, err := client.Chains.GetChains(context.Background(), ChainsParams{Symbol: "SPY", StrikeCount: 1})
+	if err != nil {
+		t.Fatalf("GetChains returned error: %v", err)
+	}
+
+	options := chains.CallExpDateMap["2020-07-17:10"]["310.0"]
+	if len(options) != 1 || options[0].Symbol != "SPY_071720C310" {
+		t.Errorf("unexpected call map: %+v", chains.CallExpDateMap)
+	}
+}
+
+func TestChainsFlatten(t *testing.T) {
+	chains := &Chains{
+		CallExpDateMap: ExpDateMap{"2020-07-17:10": {"310.0": {{PutCall: "CALL", Symbol: "SPY_071720C310", Delta: 0.45}}}},
+		PutExpDateMap:  ExpDateMap{"2020-07-17:10": {"310.0": {{PutCall: "PUT", Symbol: "SPY_071720P310", Delta: -0.55}}}},
+	}
+
+	if calls := chains.FlattenCalls(); len(calls) != 1 || calls[0].Symbol != "SPY_071720C310" || calls[0].Delta != 0.45 {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	if puts := chains.FlattenPuts(); len(puts) != 1 || puts[0].Symbol != "SPY_071720P310" || puts[0].Delta != -0.55 {
+		t.Errorf("unexpected puts: %+v", puts)
+	}
+	if all := chains.FlattenAll(); len(all) != 2 || all[0].PutCall != "CALL" || all[1].PutCall != "PUT" {
+		t.Errorf("unexpected options: %+v", all)
+	}
+}
+
+// benchmarkChains returns a chain of 5,000 contracts: 25 expirations of 100 strikes of calls and puts.
+func benchmarkChains() *Chains {
+	chains := &Chains{CallExpDateMap: ExpDateMap{}, PutExpDateMap: ExpDateMap{}}
+	expiration := time.Date(2020, 7, 17, 0, 0, 0, 0, time.UTC)
+	for e := 0; e < 25; e++ {
+		key := fmt.Sprintf("%s:%d", expiration.AddDate(0, 0, 7*e).Format("2006-01-02"), 10+7*e)
+		chains.CallExpDateMap[key] = map[string][]ExpDateOption{}
+		chains.PutExpDateMap[key] = map[string][]ExpDateOption{}
+		for s := 0; s < 100; s++ {
+			strike := 250 + float64(s)
+			strikeKey := fmt.Sprintf("%.1f", strike)
+			chains.CallExpDateMap[key][strikeKey] = []ExpDateOption{{PutCall: "CALL", StrikePrice: strike, DaysToExpiration: 10 + 7*e}}
+			chains.PutExpDateMap[key][strikeKey] = []ExpDateOption{{PutCall: "PUT", StrikePrice: strike, DaysToExpiration: 10 + 7*e}}
+		}
+	}
+	return chains
+}
+
+func BenchmarkChainsFlattenAll(b *testing.B) {
+	chains := benchmarkChains()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if options := chains.FlattenAll(); len(options) != 5000 {
+			b.Fatalf("expected 5000 options, got %d", len(options))
+		}
+	}
+}
+
+func BenchmarkChainsFlattenCalls(b *testing.B) {
+	chains := benchmarkChains()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		chains.FlattenCalls()
+	}
+}
+
+func TestUnmarshalChains(t *testing.T) {
+	testJSONRoundTrip(t, "testdata/chains.json", &Chains{})
+}
+
+func TestFloat64WithSpecial(t *testing.T) {
+	tests := []struct {
+		v          Float64WithSpecial
+		nan, valid bool
+		posInf     bool
+		negInf     bool
+		orDefault  float64
+		str        string
+	}{
+		{v: 0.25, valid: true, orDefault: 0.25, str: "0.25"},
+		{v: Float64WithSpecial(math.NaN()), nan: true, orDefault: -1, str: `"NaN"`},
+		{v: Float64WithSpecial(math.Inf(1)), posInf: true, orDefault: -1, str: `"+Inf"`},
+		{v: Float64WithSpecial(math.Inf(-1)), negInf: true, orDefault: -1, str: `"-Inf"`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.v.IsNaN(); got != tt.nan {
+			t.Errorf("%v.IsNaN() = %v, want %v", tt.str, got, tt.nan)
+		}
+		if got := tt.v.IsInf(1); got != tt.posInf {
+			t.Errorf("%v.IsInf(1) = %v, want %v", tt.str, got, tt.posInf)
+		}
+		if got := tt.v.IsInf(-1); got != tt.negInf {
+			t.Errorf("%v.IsInf(-1) = %v, want %v", tt.str, got, tt.negInf)
+		}
+		if got := tt.v.IsInf(0); got != (tt.posInf || tt.negInf) {
+			t.Errorf("%v.IsInf(0) = %v, want %v", tt.str, got, tt.posInf || tt.negInf)
+		}
+		if got := tt.v.IsValid(); got != tt.valid {
+			t.Errorf("%v.IsValid() = %v, want %v", tt.str, got, tt.valid)
+		}
+		if got := tt.v.OrDefault(-1); got != tt.orDefault {
+			t.Errorf("%v.OrDefault(-1) = %v, want %v", tt.str, got, tt.orDefault)
+		}
+		if got := fmt.Sprintf("%v", tt.v); got != tt.str {
+			t.Errorf("Sprintf(%%v) = %s, want %s", got, tt.str)
+		}
+	}
+}
+
+// serveSlowChains serves the chain of each symbol after the symbol's delay, recording the most requests in flight.
+func serveSlowChains(mux *http.ServeMux, delays map[string]time.Duration) *int32 {
+	var inFlight, maxInFlight int32
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		symbol := r.FormValue("symbol")
+		time.Sleep(delays[symbol])
+		if symbol == "FAIL" {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"symbol":%q,"status":"SUCCESS"}`, symbol)
+	})
+	return &maxInFlight
+}
+
+func TestGetChainsBatch(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	delays := map[string]time.Duration{"SPY": 50 * time.Millisecond, "QQQ": 10 * time.Millisecond, "IWM": 30 * time.Millisecond, "FAIL": 0, "DIA": 20 * time.Millisecond, "GLD": 40 * time.Millisecond}
+	maxInFlight := serveSlowChains(mux, delays)
+
+	symbols := []string{"SPY", "QQQ", "IWM", "FAIL", "DIA", "GLD"}
+	var params []ChainsParams
+	for _, symbol := range symbols {
+		params = append(params, ChainsParams{Symbol: symbol})
+	}
+
+	results, err := client.Chains.GetChainsBatch(context.Background(), params, 2)
+	if err != nil {
+		t.Fatalf("GetChainsBatch returned error: %v", err)
+	}
+	if len(results) != len(symbols) {
+		t.Fatalf("expected %d results, got %d", len(symbols), len(results))
+	}
+	for i, r := range results {
+		if r.Params.Symbol != symbols[i] {
+			t.Errorf("result %d is for %s, want %s", i, r.Params.Symbol, symbols[i])
+		}
+		switch {
+		case symbols[i] == "FAIL":
+			if r.Err == nil || r.Chains != nil {
+				t.Errorf("expected an error for FAIL, got %v, %v", r.Chains, r.Err)
+			}
+		case r.Err != nil:
+			t.Errorf("unexpected error for %s: %v", symbols[i], r.Err)
+		case r.Chains.Symbol != symbols[i]:
+			t.Errorf("result %d has the chain of %s, want %s", i, r.Chains.Symbol, symbols[i])
+		}
+	}
+	if n := atomic.LoadInt32(maxInFlight); n > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", n)
+	}
+}
+
+func TestGetChainsBatchCancel(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	delays := map[string]time.Duration{}
+	var params []ChainsParams
+	for _, symbol := range []string{"A", "B", "C", "D", "E"} {
+		delays[symbol] = 100 * time.Millisecond
+		params = append(params, ChainsParams{Symbol: symbol})
+	}
+	maxInFlight := serveSlowChains(mux, delays)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	results, err := client.Chains.GetChainsBatch(ctx, params, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+	// A finishes, B is in flight when the deadline passes and C to E are never requested.
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Params.Symbol != "A" || results[0].Err != nil {
+		t.Errorf("expected the chain of A, got %+v", results[0])
+	}
+	if results[1].Params.Symbol != "B" || results[1].Err == nil {
+		t.Errorf("expected the request for B to fail, got %+v", results[1])
+	}
+	if n := atomic.LoadInt32(maxInFlight); n > 1 {
+		t.Errorf("expected at most 1 request in flight, got %d", n)
+	}
+}
+
+func TestGetChainsForWatchlist(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	serveSlowChains(mux, map[string]time.Duration{})
+	mux.HandleFunc("/accounts/123/watchlists/456", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"theta","watchlistItems":[{"instrument":{"symbol":"SPY"}},{"instrument":{"symbol":"FAIL"}},{"instrument":{"symbol":"QQQ"}},{"instrument":{"symbol":"SPY"}}]}`)
+	})
+
+	results, err := client.Chains.GetChainsForWatchlist(context.Background(), "123", "456", ChainsParams{StrikeCount: 5})
+	if err != nil {
+		t.Fatalf("GetChainsForWatchlist returned error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, symbol := range []string{"SPY", "FAIL", "QQQ"} {
+		r := results[i]
+		if r.Params.Symbol != symbol || r.Params.StrikeCount != 5 {
+			t.Errorf("result %d has params %+v, want those of %s", i, r.Params, symbol)
+		}
+		if (r.Err != nil) != (symbol == "FAIL") {
+			t.Errorf("unexpected error for %s: %v", symbol, r.Err)
+		}
+	}
+
+	if _, err := client.Chains.GetChainsForWatchlist(context.Background(), "123", "789", ChainsParams{}); err == nil {
+		t.Error("expected an error for a missing watchlist")
+	}
+}
+
+func TestGetExpirationDates(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	// Twelve weekly expirations, listed out of order, with puts expiring on the same dates as calls.
+	first := time.Date(2020, 7, 10, 0, 0, 0, 0, time.UTC)
+	calls, puts := ExpDateMap{}, ExpDateMap{}
+	for _, week := range []int{5, 0, 11, 3, 8, 1, 10, 2, 7, 4, 9, 6} {
+		key := FormatExpDateKey(first.AddDate(0, 0, 7*week), 3+7*week)
+		calls[key] = map[string][]ExpDateOption{"310.0": {{PutCall: "CALL", StrikePrice: 310}}}
+		puts[key] = map[string][]ExpDateOption{"310.0": {{PutCall: "PUT", StrikePrice: 310}}}
+	}
+
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "symbol", "SPY")
+		testFormValue(t, r, "strikeCount", "1")
+		testFormValue(t, r, "strategy", "SINGLE")
+		b, err := json.Marshal(Chains{Symbol: "SPY", CallExpDateMap: calls, PutExpDateMap: puts})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Write(b)
+	})
+
+	dates, err := client.Chains.GetExpirationDates(context.Background(), "SPY")
+	if err != nil {
+		t.Fatalf("GetExpirationDates returned error: %v", err)
+	}
+	if len(dates) != 12 {
+		t.Fatalf("expected 12 expirations, got %d: %v", len(dates), dates)
+	}
+	for i, date := range dates {
+		if want := first.AddDate(0, 0, 7*i); !date.Equal(want) {
+			t.Errorf("expiration %d = %v, want %v", i, date, want)
+		}
+	}
+}
+
+func TestGetChainsPerExpiry(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	expirations := map[string]string{"2020-07-17": "2020-07-17:11", "2020-07-24": "2020-07-24:18", "2020-07-31": "2020-07-31:25"}
+	var requested []string
+	var mu sync.Mutex
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "symbol", "SPY")
+		chains := Chains{Symbol: "SPY", Status: "SUCCESS", CallExpDateMap: ExpDateMap{}, PutExpDateMap: ExpDateMap{}}
+		if r.FormValue("strikeCount") == "1" {
+			for _, key := range expirations {
+				chains.CallExpDateMap[key] = map[string][]ExpDateOption{"310.0": {{PutCall: "CALL", StrikePrice: 310}}}
+			}
+		} else {
+			testFormValue(t, r, "contractType", "CALL")
+			date := r.FormValue("fromDate")
+			if r.FormValue("toDate") != date {
+				t.Errorf("requested expirations from %s to %s, want a single one", date, r.FormValue("toDate"))
+			}
+			mu.Lock()
+			requested = append(requested, date)
+			mu.Unlock()
+			// Each expiration has the same strikes, priced differently.
+			chains.UnderlyingPrice = 310.5
+			key := expirations[date]
+			chains.CallExpDateMap[key] = map[string][]ExpDateOption{
+				"310.0": {{PutCall: "CALL", StrikePrice: 310, Symbol: "SPY_" + date + "C310"}},
+				"315.0": {{PutCall: "CALL", StrikePrice: 315, Symbol: "SPY_" + date + "C315"}},
+			}
+		}
+		b, err := json.Marshal(chains)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Write(b)
+	})
+
+	params := ChainsParams{ContractType: "CALL", ToDate: time.Date(2020, 7, 24, 0, 0, 0, 0, time.UTC)}
+	chains, resp, err := client.Chains.GetChainsPerExpiry(context.Background(), "SPY", params, 2)
+	if err != nil {
+		t.Fatalf("GetChainsPerExpiry returned error: %v", err)
+	}
+	if resp == nil {
+		t.Error("expected a response")
+	}
+	sort.Strings(requested)
+	if want := []string{"2020-07-17", "2020-07-24"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested expirations %v, want %v", requested, want)
+	}
+	if chains.UnderlyingPrice != 310.5 || chains.NumberOfContracts != 4 || len(chains.CallExpDateMap) != 2 {
+		t.Fatalf("unexpected merged chain: %+v", chains)
+	}
+	for _, date := range []string{"2020-07-17", "2020-07-24"} {
+		strikes := chains.CallExpDateMap[expirations[date]]
+		if len(strikes["310.0"]) != 1 || strikes["310.0"][0].Symbol != "SPY_"+date+"C310" || strikes["315.0"][0].Symbol != "SPY_"+date+"C315" {
+			t.Errorf("unexpected strikes expiring %s: %+v", date, strikes)
+		}
+	}
+}
+
+func TestGetChainsReliable(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+	defer func(d time.Duration) { chainsRetryDelay = d }(chainsRetryDelay)
+	chainsRetryDelay = time.Millisecond
+
+	var requests int32
+	failures := int32(2)
+	mux.HandleFunc("/marketdata/chains", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= atomic.LoadInt32(&failures) {
+			fmt.Fprint(w, `{"symbol":"SPY","status":"FAILED","numberOfContracts":0}`)
+			return
+		}
+		fmt.Fprint(w, `{"symbol":"SPY","status":"SUCCESS","numberOfContracts":1,"callExpDateMap":{"2020-07-17:10":{"310.0":[{"putCall":"CALL","strikePrice":310}]}}}`)
+	})
+
+	chains, _, err := client.Chains.GetChainsReliable(context.Background(), ChainsParams{Symbol: "SPY"}, 3)
+	if err != nil {
+		t.Fatalf("GetChainsReliable returned error: %v", err)
+	}
+	if !chains.IsValid() || requests != 3 {
+		t.Errorf("expected a valid chain after 3 requests, got %+v after %d", chains, requests)
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	atomic.StoreInt32(&failures, 5)
+	chains, _, err = client.Chains.GetChainsReliable(context.Background(), ChainsParams{Symbol: "SPY"}, 2)
+	if !errors.Is(err, ErrChainsFailed) {
+		t.Fatalf("expected ErrChainsFailed, got %v", err)
+	}
+	var failed *ChainsFailedError
+	if !errors.As(err, &failed) || failed.Attempts != 2 || failed.Chains != chains || failed.Response == nil {
+		t.Errorf("unexpected error: %+v", failed)
+	}
+	if chains.IsValid() || requests != 2 {
+		t.Errorf("expected the failed chain after 2 requests, got %+v after %d", chains, requests)
+	}
+}
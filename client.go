@@ -8,10 +8,15 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
 )
 
 const (
@@ -32,27 +37,211 @@ type Client struct {
 	// services used for talking to different parts of the tdameritrade api
 	PriceHistory       *PriceHistoryService
 	Account            *AccountsService
+	Orders             *OrdersService
+	SavedOrders        *SavedOrdersService
 	MarketHours        *MarketHoursService
 	Quotes             *QuotesService
-	Instrument         *InstrumentService
+	Instrument         *InstrumentsService
+	Fundamentals       *FundamentalsService
 	Chains             *ChainsService
-	Mover              *MoverService
-	TransactionHistory *TransactionHistoryService
-	User               *UserService
+	Mover              *MoversService
+	TransactionHistory *TransactionsService
+	User               *UserInfoService
 	Watchlist          *WatchlistService
+
+	rateLimiter RateLimiter
+	// orderRateLimiter limits requests placing, replacing and cancelling orders, see WithOrderRateLimiter.
+	orderRateLimiter RateLimiter
+	retryPolicy      RetryPolicy
+	logger           Logger
+	defaultTimeout   time.Duration
+	// requestIDGenerator returns the X-Request-ID of requests without one, see WithRequestIDGenerator.
+	requestIDGenerator func() string
+	// apiKey is sent as the apikey query parameter of every request when set, see NewSandboxClient.
+	apiKey string
+
+	// optionErr is the first error of an option, returned by NewClient.
+	optionErr error
+}
+
+// ClientOption configures optional behaviour of a Client.
+type ClientOption func(*Client)
+
+// WithRateLimiter makes the Client wait for rl before sending each request.
+// Requests placing, replacing and cancelling orders wait for the limiter of WithOrderRateLimiter instead, if set.
+func WithRateLimiter(rl RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = rl
+	}
+}
+
+// WithOrderRateLimiter makes the Client wait for rl before sending each request placing, replacing or cancelling
+// an order or a saved order, e.g. rl from NewOrderRateLimiter. Getting orders waits for the limiter of
+// WithRateLimiter like other requests.
+func WithOrderRateLimiter(rl RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.orderRateLimiter = rl
+	}
+}
+
+// WithLogger makes the Client log each request it sends to l, including retries.
+func WithLogger(l Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithRetryPolicy makes the Client send failed requests again as decided by p.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = p
+	}
+}
+
+// WithDefaultTimeout limits each request to d unless its context already has a deadline.
+// Retries count towards the limit.
+func WithDefaultTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.defaultTimeout = d
+	}
+}
+
+// WithConnectTimeout limits how long the Client waits to connect to TD Ameritrade.
+// It configures a copy of the http.Transport of the http.Client passed to NewClient, or of an oauth2.Transport's base,
+// so that http.Client is not changed. NewClient returns an error for other transports.
+func WithConnectTimeout(d time.Duration) ClientOption {
+	return withTransport(func(t *http.Transport) {
+		t.DialContext = (&net.Dialer{Timeout: d, KeepAlive: 30 * time.Second}).DialContext
+	})
+}
+
+// WithResponseHeaderTimeout limits how long the Client waits for the headers of a response once a request is sent.
+// Like WithConnectTimeout, it configures a copy of the http.Client's transport.
+func WithResponseHeaderTimeout(d time.Duration) ClientOption {
+	return withTransport(func(t *http.Transport) {
+		t.ResponseHeaderTimeout = d
+	})
+}
+
+// DefaultTimeouts returns options with timeouts suitable for most uses:
+// 5 seconds to connect, 10 seconds for response headers and 30 seconds for each request.
+//
+// Usage example:
+// client, err := tdameritrade.NewClient(httpClient, tdameritrade.DefaultTimeouts()...)
+func DefaultTimeouts() []ClientOption {
+	return []ClientOption{
+		WithConnectTimeout(5 * time.Second),
+		WithResponseHeaderTimeout(10 * time.Second),
+		WithDefaultTimeout(30 * time.Second),
+	}
+}
+
+// WithBaseURL sets the URL requests are made relative to, e.g. a local mock of the TD Ameritrade API.
+// u must be an absolute URL; a trailing slash is added to its path if missing.
+func WithBaseURL(u string) ClientOption {
+	return func(c *Client) {
+		b, err := url.Parse(u)
+		if err != nil {
+			c.setOptionErr(fmt.Errorf("%w: base URL %q: %v", ErrInvalidParams, u, err))
+			return
+		}
+		if !b.IsAbs() || b.Host == "" {
+			c.setOptionErr(fmt.Errorf("%w: base URL must be absolute, got %q", ErrInvalidParams, u))
+			return
+		}
+		if !strings.HasSuffix(b.Path, "/") {
+			b.Path += "/"
+		}
+		c.BaseURL = b
+	}
+}
+
+// WithHTTPClient sets the http.Client requests are sent with, e.g. one with a recording or replaying transport.
+// It replaces the client passed to NewClient, so it must come before options configuring the transport,
+// such as WithConnectTimeout.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		if hc == nil {
+			c.setOptionErr(fmt.Errorf("%w: nil http.Client", ErrInvalidParams))
+			return
+		}
+		c.client = hc
+	}
+}
+
+// withTransport returns an option applying configure to a copy of the Client's http.Transport.
+func withTransport(configure func(*http.Transport)) ClientOption {
+	return func(c *Client) {
+		hc := *c.client
+		switch t := hc.Transport.(type) {
+		case nil:
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			configure(transport)
+			hc.Transport = transport
+		case *http.Transport:
+			transport := t.Clone()
+			configure(transport)
+			hc.Transport = transport
+		case *oauth2.Transport:
+			base, ok := t.Base.(*http.Transport)
+			if t.Base == nil {
+				base, ok = http.DefaultTransport.(*http.Transport), true
+			}
+			if !ok {
+				c.setOptionErr(fmt.Errorf("cannot configure timeouts of oauth2 base transport %T", t.Base))
+				return
+			}
+			transport := base.Clone()
+			configure(transport)
+			hc.Transport = &oauth2.Transport{Source: t.Source, Base: transport}
+		default:
+			c.setOptionErr(fmt.Errorf("cannot configure timeouts of transport %T", t))
+			return
+		}
+		c.client = &hc
+	}
+}
+
+func (c *Client) setOptionErr(err error) {
+	if c.optionErr == nil {
+		c.optionErr = err
+	}
 }
 
 type Response struct {
 	*http.Response
 
-	// TODO add additional items if needed
+	// OrderID is the ID of the order created by the request, parsed from the Location header.
+	// It is only set by OrdersService.PlaceOrder.
+	OrderID string
+
+	// FromCache reports whether the response was served by a CachedChainsService rather than TD Ameritrade,
+	// in which case the other fields are those of the cached response.
+	FromCache bool
+
+	// Rate limit of the requests made with the caller's credentials, parsed from the X-RateLimit headers.
+	// They are zero when TD Ameritrade does not send the headers.
+	RateLimitLimit     int
+	RateLimitRemaining int
+	RateLimitReset     time.Time
+}
+
+// RetryAfter returns how long until the rate limit window resets, or zero if the reset time is unknown or has passed.
+func (r *Response) RetryAfter() time.Duration {
+	if r.RateLimitReset.IsZero() {
+		return 0
+	}
+	if d := time.Until(r.RateLimitReset); d > 0 {
+		return d
+	}
+	return 0
 }
 
 // NewClient returns a new TD-Ameritrade API client. If a nil httpClient is
 // provided, a new http.Client will be used. To use API methods which require
 // authentication, provide an http.Client that will perform the authentication
 // for you (such as that provided by the golang.org/x/oauth2 library).
-func NewClient(httpClient *http.Client) (*Client, error) {
+func NewClient(httpClient *http.Client, opts ...ClientOption) (*Client, error) {
 	if httpClient == nil {
 		httpClient = &http.Client{}
 	}
@@ -64,18 +253,41 @@ func NewClient(httpClient *http.Client) (*Client, error) {
 	c := &Client{client: httpClient, BaseURL: b}
 	c.PriceHistory = &PriceHistoryService{client: c}
 	c.Account = &AccountsService{client: c}
+	c.Orders = &OrdersService{client: c}
+	c.SavedOrders = &SavedOrdersService{client: c}
 	c.MarketHours = &MarketHoursService{client: c}
 	c.Quotes = &QuotesService{client: c}
-	c.Instrument = &InstrumentService{client: c}
+	c.Instrument = &InstrumentsService{client: c}
+	c.Fundamentals = &FundamentalsService{client: c}
 	c.Chains = &ChainsService{client: c}
-	c.Mover = &MoverService{client: c}
-	c.TransactionHistory = &TransactionHistoryService{client: c}
-	c.User = &UserService{client: c}
+	c.Mover = &MoversService{client: c}
+	c.TransactionHistory = &TransactionsService{client: c}
+	c.User = &UserInfoService{client: c}
 	c.Watchlist = &WatchlistService{client: c}
 
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+
 	return c, nil
 }
 
+// NewSandboxClient returns a client for testing against TD Ameritrade without OAuth, authenticating every request
+// with apiKey, the consumer key of a TD Ameritrade developer app. Requests without OAuth get delayed market data
+// and cannot access accounts.
+// TD Ameritrade has no paper trading API, so requests go to the public API by default; WithBaseURL points the client
+// at a mock or another environment instead.
+func NewSandboxClient(apiKey string, opts ...ClientOption) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("%w: no api key present", ErrInvalidParams)
+	}
+	opts = append([]ClientOption{func(c *Client) { c.apiKey = apiKey }}, opts...)
+	return NewClient(nil, opts...)
+}
+
 func (c *Client) UpdateBaseURL(baseURL string) error {
 	b, err := url.Parse(baseURL)
 	if err != nil {
@@ -85,29 +297,53 @@ func (c *Client) UpdateBaseURL(baseURL string) error {
 	return nil
 }
 
+// Ping checks that TD Ameritrade can be reached and accepts the client's credentials, e.g. at startup to fail
+// fast, by getting the user principals without any optional fields. It returns an error matching ErrUnauthorized
+// with errors.Is if the credentials are rejected, the APIError of any other error status, and an error wrapping
+// the cause if the request could not be made.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := c.NewRequest("GET", "userprincipals", nil)
+	if err != nil {
+		return err
+	}
+	if _, err := c.Do(ctx, req, ioutil.Discard); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			return err
+		}
+		return fmt.Errorf("pinging TD Ameritrade: %w", err)
+	}
+	return nil
+}
+
+// IsAuthenticated reports whether Ping succeeds.
+func (c *Client) IsAuthenticated(ctx context.Context) bool {
+	return c.Ping(ctx) == nil
+}
+
 // The following custom value can be added to context before invoking client methods:
 // DumpHttpResponseContent - will print TDA's http response, used for troubleshooting
 //
 // Usage example:
 // ctx = context.WithValue(ctx, tdameritrade.DumpHttpResponseContent, true)
-// tdameritrade.Client.Chains.GetChains(ctx, queryValues)
+// tdameritrade.Client.Chains.GetChains(ctx, params)
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
 	if ctx == nil {
 		return nil, errors.New("context must be non-nil")
 	}
 
+	if _, ok := ctx.Deadline(); !ok && c.defaultTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.defaultTimeout)
+		defer cancel()
+	}
+
 	req = req.WithContext(ctx)
+	c.setRequestID(ctx, req)
 
-	resp, err := c.client.Do(req)
+	resp, err := c.send(ctx, req)
 	if err != nil {
-		// If we got an error, and the context has been canceled,
-		// the context's error is probably more useful.
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-			return nil, err
-		}
+		return nil, err
 	}
 
 	defer resp.Body.Close()
@@ -128,7 +364,7 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 		if w, ok := v.(io.Writer); ok {
 			_, _ = io.Copy(w, resp.Body)
 		} else {
- 			decErr := json.NewDecoder(resp.Body).Decode(v)
+			decErr := json.NewDecoder(resp.Body).Decode(v)
 			if decErr == io.EOF {
 				decErr = nil // ignore EOF errors caused by empty response body
 			} else {
@@ -140,16 +376,141 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	return response, err
 }
 
+// limiterFor returns the RateLimiter req must wait for, nil if none.
+func (c *Client) limiterFor(req *http.Request) RateLimiter {
+	if c.orderRateLimiter != nil && isOrderRequest(req) {
+		return c.orderRateLimiter
+	}
+	return c.rateLimiter
+}
+
+// send sends req, retrying it as decided by the Client's RetryPolicy.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		if rl := c.limiterFor(req); rl != nil {
+			if err := rl.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		start := time.Now()
+		resp, err := c.client.Do(req)
+		if c.logger != nil {
+			c.logger.Log(req, resp, time.Since(start), err)
+		}
+		if err != nil {
+			// If we got an error, and the context has been canceled,
+			// the context's error is probably more useful.
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+
+		if c.retryPolicy == nil || !isRetryable(req, resp) || !c.retryPolicy.ShouldRetry(attempt, resp, err) {
+			return resp, err
+		}
+
+		backoff := c.retryPolicy.Backoff(attempt)
+		lastErr := err
+		if resp != nil {
+			lastErr = errors.New(resp.Status)
+			// Drain the body so the connection can be reused by the next attempt.
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return nil, fmt.Errorf("retrying after %v would exceed the context deadline, last error: %v: %w", backoff, lastErr, context.DeadlineExceeded)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Errors matching API errors by status code with errors.Is.
+var (
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// APIError is an error response from TD Ameritrade.
+// Use errors.As to get the status code and message, or errors.Is with ErrUnauthorized, ErrForbidden, ErrNotFound
+// and ErrTooManyRequests to check for common statuses.
+type APIError struct {
+	StatusCode int
+	// Message is the error TD Ameritrade returned, or the whole body if it was not JSON.
+	Message string
+	RawBody []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Is reports whether target is the sentinel error for e's status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrTooManyRequests:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
 func checkResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
-	errMsg, _ := ioutil.ReadAll(r.Body)
-	return errors.New(string(errMsg))
+
+	body, _ := ioutil.ReadAll(r.Body)
+	apiErr := &APIError{StatusCode: r.StatusCode, RawBody: body, Message: strings.TrimSpace(string(body))}
+
+	// TD Ameritrade usually returns {"error":"..."}, but some endpoints use "message" instead.
+	var errBody struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &errBody); err == nil {
+		if errBody.Error != "" {
+			apiErr.Message = errBody.Error
+		} else if errBody.Message != "" {
+			apiErr.Message = errBody.Message
+		}
+	}
+	return apiErr
 }
 
 func newResponse(r *http.Response) *Response {
 	response := &Response{Response: r}
+	response.RateLimitLimit, _ = strconv.Atoi(r.Header.Get("X-RateLimit-Limit"))
+	response.RateLimitRemaining, _ = strconv.Atoi(r.Header.Get("X-RateLimit-Remaining"))
+	// The reset time is sent in seconds since the epoch.
+	if reset, err := strconv.ParseInt(r.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		response.RateLimitReset = time.Unix(reset, 0)
+	}
 	return response
 }
 
@@ -179,6 +540,14 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		}
 	}
 
+	if c.apiKey != "" {
+		q := u.Query()
+		if q.Get("apikey") == "" {
+			q.Set("apikey", c.apiKey)
+			u.RawQuery = q.Encode()
+		}
+	}
+
 	req, err := http.NewRequest(method, u.String(), buf)
 	if err != nil {
 		return nil, err
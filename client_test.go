@@ -0,0 +1,414 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// setup sets up a test HTTP server along with a tdameritrade.Client that is
+// configured to talk to that test server. Tests should register handlers on
+// mux which provide mock responses for the API method being tested.
+func setup(t *testing.T, opts ...ClientOption) (client *Client, mux *http.ServeMux, teardown func()) {
+	mux = http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	client, err := NewClient(nil, opts...)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if err := client.UpdateBaseURL(server.URL + "/"); err != nil {
+		t.Fatalf("UpdateBaseURL returned error: %v", err)
+	}
+
+	return client, mux, server.Close
+}
+
+func testMethod(t *testing.T, r *http.Request, want string) {
+	t.Helper()
+	if got := r.Method; got != want {
+		t.Errorf("Request method: %v, want %v", got, want)
+	}
+}
+
+func testFormValue(t *testing.T, r *http.Request, key, want string) {
+	t.Helper()
+	if got := r.FormValue(key); got != want {
+		t.Errorf("Request query %s: %q, want %q", key, got, want)
+	}
+}
+
+// jsonRoundTripDiffs decodes the fixture into v, encodes v again and returns where the two documents differ.
+// Key order is ignored, but a key only one side has is a difference whatever its value, so that a fixture field
+// v has no field for is reported even when it is false or 0. Fixtures therefore hold exactly the fields of v,
+// with zero values for those TD Ameritrade leaves out. Numbers encoded as strings, as decimal.Decimal prices are,
+// equal the number they hold, and null equals a zero value.
+func jsonRoundTripDiffs(t *testing.T, fixture string, v interface{}) []string {
+	t.Helper()
+	b, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatalf("decoding %s: %v", fixture, err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encoding %s: %v", fixture, err)
+	}
+
+	var want, got interface{}
+	if err := json.Unmarshal(b, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	return jsonDiff("$", want, got)
+}
+
+// testJSONRoundTrip fails the test if v does not survive a round trip of the fixture, see jsonRoundTripDiffs.
+func testJSONRoundTrip(t *testing.T, fixture string, v interface{}) {
+	t.Helper()
+	for _, diff := range jsonRoundTripDiffs(t, fixture, v) {
+		t.Error(diff)
+	}
+}
+
+func jsonDiff(path string, want, got interface{}) []string {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w)+len(g))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range g {
+			if _, ok := w[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var diffs []string
+		for _, k := range keys {
+			wv, inWant := w[k]
+			gv, inGot := g[k]
+			switch {
+			case !inGot:
+				diffs = append(diffs, fmt.Sprintf("%s.%s: dropped %v", path, k, wv))
+			case !inWant:
+				diffs = append(diffs, fmt.Sprintf("%s.%s: added %v", path, k, gv))
+			default:
+				diffs = append(diffs, jsonDiff(path+"."+k, wv, gv)...)
+			}
+		}
+		return diffs
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			break
+		}
+		var diffs []string
+		for i := range w {
+			diffs = append(diffs, jsonDiff(fmt.Sprintf("%s[%d]", path, i), w[i], g[i])...)
+		}
+		return diffs
+	default:
+		if reflect.DeepEqual(want, got) || isZeroJSON(want) && isZeroJSON(got) {
+			return nil
+		}
+		if w, ok := jsonNumber(want); ok {
+			if g, ok := jsonNumber(got); ok && w == g {
+				return nil
+			}
+		}
+	}
+	return []string{fmt.Sprintf("%s: got %v, want %v", path, got, want)}
+}
+
+// jsonNumber returns the number v holds, either as a number or as a string.
+func jsonNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// isZeroJSON reports whether v is a decoded JSON zero value: null, false, 0, "", [] or an object of zero values,
+// which is how a struct field TD Ameritrade sends as null is encoded.
+func isZeroJSON(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		n, ok := jsonNumber(v)
+		return v == "" || ok && n == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		for _, e := range v {
+			if !isZeroJSON(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func TestAPIError(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"The access token being passed has expired or is invalid."}`)
+	})
+	mux.HandleFunc("/marketdata/QQQ/quotes", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, "upstream unavailable\n")
+	})
+
+	_, _, err := client.Quotes.GetQuote(context.Background(), "SPY")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "The access token being passed has expired or is invalid." {
+		t.Errorf("unexpected APIError: %+v", apiErr)
+	}
+	if !errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is did not match the status code of %v", err)
+	}
+
+	_, _, err = client.Quotes.GetQuote(context.Background(), "QQQ")
+	if !errors.As(err, &apiErr) || apiErr.Message != "upstream unavailable" || string(apiErr.RawBody) != "upstream unavailable\n" {
+		t.Errorf("expected the body as the message of a non-JSON error, got %v", err)
+	}
+
+	statuses := []struct {
+		code     int
+		sentinel error
+	}{
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrTooManyRequests},
+	}
+	for _, s := range statuses {
+		if err := error(&APIError{StatusCode: s.code}); !errors.Is(err, s.sentinel) {
+			t.Errorf("errors.Is(%d, %v) = false", s.code, s.sentinel)
+		}
+	}
+}
+
+func TestPing(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	authorized := true
+	mux.HandleFunc("/userprincipals", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			t.Errorf("expected no query, got %q", r.URL.RawQuery)
+		}
+		if !authorized {
+			http.Error(w, `{"error":"The access token being passed has expired or is invalid."}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"userId":"user"}`)
+	})
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping returned error: %v", err)
+	}
+	if !client.IsAuthenticated(context.Background()) {
+		t.Error("expected the client to be authenticated")
+	}
+
+	authorized = false
+	if err := client.Ping(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if client.IsAuthenticated(context.Background()) {
+		t.Error("expected the client not to be authenticated")
+	}
+
+	teardown()
+	if err := client.Ping(context.Background()); err == nil || errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected a connection error, got %v", err)
+	}
+}
+
+func TestWithDefaultTimeout(t *testing.T) {
+	client, mux, teardown := setup(t, WithDefaultTimeout(time.Second))
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY"}}`)
+	})
+
+	start := time.Now()
+	_, _, err := client.Quotes.GetQuote(context.Background(), "SPY")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("request took %v, expected it to time out after 1s", elapsed)
+	}
+}
+
+func TestTransportTimeouts(t *testing.T) {
+	httpClient := &http.Client{}
+	client, err := NewClient(httpClient, DefaultTimeouts()...)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	transport, ok := client.client.Transport.(*http.Transport)
+	if !ok || transport.ResponseHeaderTimeout != 10*time.Second || transport.DialContext == nil {
+		t.Errorf("unexpected transport: %+v", client.client.Transport)
+	}
+	if client.defaultTimeout != 30*time.Second {
+		t.Errorf("defaultTimeout = %v, want 30s", client.defaultTimeout)
+	}
+	if httpClient.Transport != nil {
+		t.Error("expected the http.Client passed to NewClient to be left unchanged")
+	}
+
+	oauthClient := &http.Client{Transport: &oauth2.Transport{Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})}}
+	client, err = NewClient(oauthClient, WithResponseHeaderTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if base := client.client.Transport.(*oauth2.Transport).Base.(*http.Transport); base.ResponseHeaderTimeout != time.Second {
+		t.Errorf("ResponseHeaderTimeout = %v, want 1s", base.ResponseHeaderTimeout)
+	}
+
+	unknown := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+	if _, err := NewClient(unknown, WithConnectTimeout(time.Second)); err == nil {
+		t.Error("expected an error for a transport that cannot be configured")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestNewResponseRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "120")
+	header.Set("X-RateLimit-Remaining", "7")
+	header.Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+
+	resp := newResponse(&http.Response{Header: header})
+	if resp.RateLimitLimit != 120 || resp.RateLimitRemaining != 7 || !resp.RateLimitReset.Equal(reset) {
+		t.Errorf("unexpected rate limit: %d %d %v", resp.RateLimitLimit, resp.RateLimitRemaining, resp.RateLimitReset)
+	}
+	if d := resp.RetryAfter(); d <= 58*time.Second || d > time.Minute {
+		t.Errorf("RetryAfter = %v, want about 1m", d)
+	}
+
+	resp = newResponse(&http.Response{Header: http.Header{}})
+	if resp.RateLimitLimit != 0 || resp.RateLimitRemaining != 0 || !resp.RateLimitReset.IsZero() || resp.RetryAfter() != 0 {
+		t.Errorf("expected no rate limit without headers, got %+v", resp)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	client, err := NewClient(nil, WithBaseURL("http://localhost:8080/v1"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if got, want := client.BaseURL.String(), "http://localhost:8080/v1/"; got != want {
+		t.Errorf("BaseURL = %s, want %s", got, want)
+	}
+
+	for _, u := range []string{"", "localhost:8080/v1/", "/v1/", "http://%zz/"} {
+		if _, err := NewClient(nil, WithBaseURL(u)); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("WithBaseURL(%q): expected ErrInvalidParams, got %v", u, err)
+		}
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	var requests int
+	hc := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{}`)), Header: http.Header{}}, nil
+	})}
+
+	client, err := NewClient(nil, WithHTTPClient(hc))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if _, _, err := client.Quotes.GetQuotes(context.Background(), []string{"SPY"}); err != nil {
+		t.Fatalf("GetQuotes returned error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the request to go through the injected client, got %d requests", requests)
+	}
+
+	if _, err := NewClient(nil, WithHTTPClient(nil)); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for a nil client, got %v", err)
+	}
+}
+
+func TestNewSandboxClient(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v1/marketdata/quotes", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "apikey", "KEY")
+		testFormValue(t, r, "symbol", "SPY")
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY"}}`)
+	})
+
+	client, err := NewSandboxClient("KEY", WithBaseURL(server.URL+"/v1"))
+	if err != nil {
+		t.Fatalf("NewSandboxClient returned error: %v", err)
+	}
+	quotes, _, err := client.Quotes.GetQuotes(context.Background(), []string{"SPY"})
+	if err != nil {
+		t.Fatalf("GetQuotes returned error: %v", err)
+	}
+	if quotes["SPY"] == nil {
+		t.Errorf("expected a quote for SPY, got %v", quotes)
+	}
+
+	if client, err := NewSandboxClient("KEY"); err != nil || client.BaseURL.String() != baseURL {
+		t.Errorf("expected the default base URL, got %v, %v", client, err)
+	}
+	if _, err := NewSandboxClient(""); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for an empty api key, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package tdameritrade
+
+import "math"
+
+// NormalizedGreeks are the Greeks of one contract of an option in dollars, which unlike the Greeks TD Ameritrade
+// reports per share can be summed across underlyings of different prices.
+// DollarDelta is the value of the shares the contract moves like, Delta * underlyingPrice * Multiplier.
+// DollarGamma is the change of DollarDelta for a 1% move of the underlying, Gamma * underlyingPrice² * Multiplier / 100.
+// DollarTheta is the dollars lost per day, Theta * Multiplier, and DollarVega is Vega * Multiplier / 100.
+// Charm is the change of Delta over the next day.
+type NormalizedGreeks struct {
+	DollarDelta float64
+	DollarGamma float64
+	DollarTheta float64
+	DollarVega  float64
+	Charm       float64
+}
+
+// NormalizeGreeks returns the dollar Greeks of opt when its underlying is at underlyingPrice.
+// Options without a Multiplier are taken to be for 100 shares, and Greeks TD Ameritrade reports as NaN are NaN.
+//
+// Charm is estimated with a finite difference of the Black-Scholes delta at the option's Volatility, between
+// DaysToExpiration and one day less, assuming no dividends and interest rates of zero. It is NaN if the option
+// has no volatility or underlyingPrice is not positive.
+func NormalizeGreeks(opt *ExpDateOption, underlyingPrice float64) *NormalizedGreeks {
+	multiplier := opt.Multiplier
+	if multiplier == 0 {
+		multiplier = 100
+	}
+	return &NormalizedGreeks{
+		DollarDelta: float64(opt.Delta) * underlyingPrice * multiplier,
+		DollarGamma: float64(opt.Gamma) * underlyingPrice * underlyingPrice * multiplier / 100,
+		DollarTheta: float64(opt.Theta) * multiplier,
+		DollarVega:  float64(opt.Vega) * multiplier / 100,
+		Charm:       charm(opt, underlyingPrice),
+	}
+}
+
+// charm returns the change of the Black-Scholes delta of opt over the next day.
+func charm(opt *ExpDateOption, underlyingPrice float64) float64 {
+	sigma := float64(opt.Volatility) / 100
+	if !opt.Volatility.IsValid() || sigma <= 0 || underlyingPrice <= 0 || opt.StrikePrice <= 0 {
+		return math.NaN()
+	}
+	years := float64(opt.DaysToExpiration) / 365
+	today := blackScholesDelta(opt.PutCall, underlyingPrice, opt.StrikePrice, sigma, years)
+	tomorrow := blackScholesDelta(opt.PutCall, underlyingPrice, opt.StrikePrice, sigma, years-1.0/365)
+	return tomorrow - today
+}
+
+// blackScholesDelta returns the delta of a European option expiring in years, which is that of the intrinsic
+// value at expiration.
+func blackScholesDelta(putCall PutCall, price, strike, sigma, years float64) float64 {
+	var callDelta float64
+	if years <= 0 {
+		if price > strike {
+			callDelta = 1
+		}
+	} else {
+		d1 := (math.Log(price/strike) + sigma*sigma*years/2) / (sigma * math.Sqrt(years))
+		callDelta = 0.5 * math.Erfc(-d1/math.Sqrt2)
+	}
+	if putCall == PutCallPut {
+		return callDelta - 1
+	}
+	return callDelta
+}
@@ -0,0 +1,48 @@
+package tdameritrade
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeGreeks(t *testing.T) {
+	call := &ExpDateOption{PutCall: PutCallCall, StrikePrice: 110, DaysToExpiration: 30, Volatility: 20,
+		Delta: 0.25, Gamma: 0.03, Theta: -0.05, Vega: 0.1}
+
+	g := NormalizeGreeks(call, 100)
+	for _, tt := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"DollarDelta", g.DollarDelta, 2500},
+		{"DollarGamma", g.DollarGamma, 300},
+		{"DollarTheta", g.DollarTheta, -5},
+		{"DollarVega", g.DollarVega, 0.1},
+	} {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	// The delta of an out of the money option falls as expiration nears.
+	if g.Charm >= 0 || g.Charm < -0.01 {
+		t.Errorf("Charm of an out of the money call = %v, want a small negative value", g.Charm)
+	}
+
+	put := *call
+	put.PutCall = PutCallPut
+	if got := NormalizeGreeks(&put, 100).Charm; math.Abs(got-g.Charm) > 1e-12 {
+		t.Errorf("Charm of a put = %v, want that of the call, %v", got, g.Charm)
+	}
+
+	// Expiring tomorrow in the money, the delta goes to 1.
+	call.DaysToExpiration, call.StrikePrice = 1, 99
+	if got := NormalizeGreeks(call, 100).Charm; got <= 0 {
+		t.Errorf("Charm of an in the money call expiring tomorrow = %v, want positive", got)
+	}
+
+	call.Volatility, call.Multiplier = Float64WithSpecial(math.NaN()), 10
+	g = NormalizeGreeks(call, 100)
+	if !math.IsNaN(g.Charm) || g.DollarDelta != 250 {
+		t.Errorf("unexpected greeks without volatility: %+v", g)
+	}
+}
@@ -0,0 +1,281 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The types below are the enum-like strings of the TD Ameritrade API. Each has a Validate method reporting
+// values other than its constants, which are also rejected when encoding or decoding JSON so that a typo such
+// as "CAL" fails rather than silently matching nothing. The empty value stands for a field not set and is
+// encoded and decoded as is.
+
+// PutCall is whether an option is a call or a put.
+type PutCall string
+
+const (
+	PutCallCall PutCall = "CALL"
+	PutCallPut  PutCall = "PUT"
+	// PutCallAll selects both calls and puts where a PutCall filters options.
+	PutCallAll PutCall = "ALL"
+)
+
+// ContractType selects the options of a chain, see ChainsParams.
+type ContractType string
+
+const (
+	ContractTypeCall ContractType = "CALL"
+	ContractTypePut  ContractType = "PUT"
+	ContractTypeAll  ContractType = "ALL"
+)
+
+// OrderType is how an order is priced.
+type OrderType string
+
+const (
+	OrderTypeMarket            OrderType = "MARKET"
+	OrderTypeLimit             OrderType = "LIMIT"
+	OrderTypeStop              OrderType = "STOP"
+	OrderTypeStopLimit         OrderType = "STOP_LIMIT"
+	OrderTypeTrailingStop      OrderType = "TRAILING_STOP"
+	OrderTypeMarketOnClose     OrderType = "MARKET_ON_CLOSE"
+	OrderTypeExercise          OrderType = "EXERCISE"
+	OrderTypeTrailingStopLimit OrderType = "TRAILING_STOP_LIMIT"
+	OrderTypeNetDebit          OrderType = "NET_DEBIT"
+	OrderTypeNetCredit         OrderType = "NET_CREDIT"
+	OrderTypeNetZero           OrderType = "NET_ZERO"
+)
+
+// Session is the trading session an order may be filled in.
+type Session string
+
+const (
+	SessionNormal   Session = "NORMAL"
+	SessionAM       Session = "AM"
+	SessionPM       Session = "PM"
+	SessionSeamless Session = "SEAMLESS"
+)
+
+// Duration is how long an order remains working.
+type Duration string
+
+const (
+	DurationDay            Duration = "DAY"
+	DurationGoodTillCancel Duration = "GOOD_TILL_CANCEL"
+	DurationFillOrKill     Duration = "FILL_OR_KILL"
+)
+
+// OrderStrategyType is how an order relates to its child orders.
+type OrderStrategyType string
+
+const (
+	OrderStrategyTypeSingle  OrderStrategyType = "SINGLE"
+	OrderStrategyTypeOCO     OrderStrategyType = "OCO"
+	OrderStrategyTypeTrigger OrderStrategyType = "TRIGGER"
+)
+
+// AssetType is the type of a security.
+type AssetType string
+
+const (
+	AssetTypeEquity         AssetType = "EQUITY"
+	AssetTypeETF            AssetType = "ETF"
+	AssetTypeOption         AssetType = "OPTION"
+	AssetTypeMutualFund     AssetType = "MUTUAL_FUND"
+	AssetTypeCashEquivalent AssetType = "CASH_EQUIVALENT"
+	AssetTypeFixedIncome    AssetType = "FIXED_INCOME"
+	AssetTypeBond           AssetType = "BOND"
+	AssetTypeIndex          AssetType = "INDEX"
+	AssetTypeForex          AssetType = "FOREX"
+	AssetTypeFuture         AssetType = "FUTURE"
+	AssetTypeFutureOption   AssetType = "FUTURE_OPTION"
+	AssetTypeCurrency       AssetType = "CURRENCY"
+)
+
+// ExpirationType is the expiration cycle of an option.
+type ExpirationType string
+
+const (
+	ExpirationTypeRegular    ExpirationType = "R"
+	ExpirationTypeStandard   ExpirationType = "S"
+	ExpirationTypeQuarterly  ExpirationType = "Q"
+	ExpirationTypeWeekly     ExpirationType = "W"
+	ExpirationTypeEndOfMonth ExpirationType = "M"
+)
+
+// RangeFilter selects the options of a chain by moneyness, see ChainsParams.
+type RangeFilter string
+
+const (
+	// RangeITM selects options in the money.
+	RangeITM RangeFilter = "ITM"
+	// RangeNTM selects options near the money.
+	RangeNTM RangeFilter = "NTM"
+	// RangeOTM selects options out of the money.
+	RangeOTM RangeFilter = "OTM"
+	// RangeSAK selects options with strikes above the market.
+	RangeSAK RangeFilter = "SAK"
+	// RangeSBK selects options with strikes below the market.
+	RangeSBK RangeFilter = "SBK"
+	// RangeSNK selects options with strikes near the market.
+	RangeSNK RangeFilter = "SNK"
+	RangeAll RangeFilter = "ALL"
+)
+
+var (
+	rangeFilterValues       = []string{string(RangeITM), string(RangeNTM), string(RangeOTM), string(RangeSAK), string(RangeSBK), string(RangeSNK), string(RangeAll)}
+	putCallValues           = []string{string(PutCallCall), string(PutCallPut), string(PutCallAll)}
+	contractTypeValues      = []string{string(ContractTypeCall), string(ContractTypePut), string(ContractTypeAll)}
+	orderTypeValues         = []string{string(OrderTypeMarket), string(OrderTypeLimit), string(OrderTypeStop), string(OrderTypeStopLimit), string(OrderTypeTrailingStop), string(OrderTypeMarketOnClose), string(OrderTypeExercise), string(OrderTypeTrailingStopLimit), string(OrderTypeNetDebit), string(OrderTypeNetCredit), string(OrderTypeNetZero)}
+	sessionValues           = []string{string(SessionNormal), string(SessionAM), string(SessionPM), string(SessionSeamless)}
+	durationValues          = []string{string(DurationDay), string(DurationGoodTillCancel), string(DurationFillOrKill)}
+	orderStrategyTypeValues = []string{string(OrderStrategyTypeSingle), string(OrderStrategyTypeOCO), string(OrderStrategyTypeTrigger)}
+	assetTypeValues         = []string{string(AssetTypeEquity), string(AssetTypeETF), string(AssetTypeOption), string(AssetTypeMutualFund), string(AssetTypeCashEquivalent), string(AssetTypeFixedIncome), string(AssetTypeBond), string(AssetTypeIndex), string(AssetTypeForex), string(AssetTypeFuture), string(AssetTypeFutureOption), string(AssetTypeCurrency)}
+	expirationTypeValues    = []string{string(ExpirationTypeRegular), string(ExpirationTypeStandard), string(ExpirationTypeQuarterly), string(ExpirationTypeWeekly), string(ExpirationTypeEndOfMonth)}
+	strategyValues          = []string{string(StrategySingle), string(StrategyAnalytical), string(StrategyCovered), string(StrategyVertical), string(StrategyCalendar), string(StrategyStrangle), string(StrategyStraddle), string(StrategyButterfly), string(StrategyCondor), string(StrategyDiagonal), string(StrategyCollar), string(StrategyRoll)}
+)
+
+func validateEnum(name, value string, values []string) error {
+	if !contains(value, values) {
+		return fmt.Errorf("%w: unknown %s %q, want one of %v", ErrInvalidParams, name, value, values)
+	}
+	return nil
+}
+
+func marshalEnum(name, value string, values []string) ([]byte, error) {
+	if value != "" {
+		if err := validateEnum(name, value, values); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(value)
+}
+
+func unmarshalEnum(b []byte, name string, values []string) (string, error) {
+	var value string
+	if err := json.Unmarshal(b, &value); err != nil {
+		return "", err
+	}
+	if value != "" {
+		if err := validateEnum(name, value, values); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+func (v PutCall) String() string  { return string(v) }
+func (v PutCall) Validate() error { return validateEnum("put/call", string(v), putCallValues) }
+func (v PutCall) MarshalJSON() ([]byte, error) {
+	return marshalEnum("put/call", string(v), putCallValues)
+}
+func (v *PutCall) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "put/call", putCallValues)
+	*v = PutCall(s)
+	return err
+}
+
+func (v ContractType) String() string { return string(v) }
+func (v ContractType) Validate() error {
+	return validateEnum("contract type", string(v), contractTypeValues)
+}
+func (v ContractType) MarshalJSON() ([]byte, error) {
+	return marshalEnum("contract type", string(v), contractTypeValues)
+}
+func (v *ContractType) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "contract type", contractTypeValues)
+	*v = ContractType(s)
+	return err
+}
+
+func (v OrderType) String() string  { return string(v) }
+func (v OrderType) Validate() error { return validateEnum("order type", string(v), orderTypeValues) }
+func (v OrderType) MarshalJSON() ([]byte, error) {
+	return marshalEnum("order type", string(v), orderTypeValues)
+}
+func (v *OrderType) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "order type", orderTypeValues)
+	*v = OrderType(s)
+	return err
+}
+
+func (v Session) String() string  { return string(v) }
+func (v Session) Validate() error { return validateEnum("session", string(v), sessionValues) }
+func (v Session) MarshalJSON() ([]byte, error) {
+	return marshalEnum("session", string(v), sessionValues)
+}
+func (v *Session) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "session", sessionValues)
+	*v = Session(s)
+	return err
+}
+
+func (v Duration) String() string  { return string(v) }
+func (v Duration) Validate() error { return validateEnum("duration", string(v), durationValues) }
+func (v Duration) MarshalJSON() ([]byte, error) {
+	return marshalEnum("duration", string(v), durationValues)
+}
+func (v *Duration) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "duration", durationValues)
+	*v = Duration(s)
+	return err
+}
+
+func (v OrderStrategyType) String() string { return string(v) }
+func (v OrderStrategyType) Validate() error {
+	return validateEnum("order strategy type", string(v), orderStrategyTypeValues)
+}
+func (v OrderStrategyType) MarshalJSON() ([]byte, error) {
+	return marshalEnum("order strategy type", string(v), orderStrategyTypeValues)
+}
+func (v *OrderStrategyType) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "order strategy type", orderStrategyTypeValues)
+	*v = OrderStrategyType(s)
+	return err
+}
+
+func (v AssetType) String() string  { return string(v) }
+func (v AssetType) Validate() error { return validateEnum("asset type", string(v), assetTypeValues) }
+func (v AssetType) MarshalJSON() ([]byte, error) {
+	return marshalEnum("asset type", string(v), assetTypeValues)
+}
+func (v *AssetType) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "asset type", assetTypeValues)
+	*v = AssetType(s)
+	return err
+}
+
+func (v ExpirationType) String() string { return string(v) }
+func (v ExpirationType) Validate() error {
+	return validateEnum("expiration type", string(v), expirationTypeValues)
+}
+func (v ExpirationType) MarshalJSON() ([]byte, error) {
+	return marshalEnum("expiration type", string(v), expirationTypeValues)
+}
+func (v *ExpirationType) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "expiration type", expirationTypeValues)
+	*v = ExpirationType(s)
+	return err
+}
+
+func (v Strategy) String() string  { return string(v) }
+func (v Strategy) Validate() error { return validateEnum("strategy", string(v), strategyValues) }
+func (v Strategy) MarshalJSON() ([]byte, error) {
+	return marshalEnum("strategy", string(v), strategyValues)
+}
+func (v *Strategy) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "strategy", strategyValues)
+	*v = Strategy(s)
+	return err
+}
+
+func (v RangeFilter) String() string  { return string(v) }
+func (v RangeFilter) Validate() error { return validateEnum("range", string(v), rangeFilterValues) }
+func (v RangeFilter) MarshalJSON() ([]byte, error) {
+	return marshalEnum("range", string(v), rangeFilterValues)
+}
+func (v *RangeFilter) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "range", rangeFilterValues)
+	*v = RangeFilter(s)
+	return err
+}
@@ -0,0 +1,68 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEnumValidate(t *testing.T) {
+	for _, v := range []interface{ Validate() error }{
+		PutCallPut, PutCallAll, ContractTypeAll, OrderTypeNetCredit, SessionSeamless, DurationFillOrKill,
+		OrderStrategyTypeTrigger, AssetTypeMutualFund, ExpirationTypeRegular, StrategyVertical,
+	} {
+		if err := v.Validate(); err != nil {
+			t.Errorf("%v.Validate() returned error: %v", v, err)
+		}
+	}
+	for _, v := range []interface{ Validate() error }{PutCall("CAL"), ContractType(""), OrderType("limit"), AssetType("STOCK")} {
+		if err := v.Validate(); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("%q.Validate() = %v, want ErrInvalidParams", v, err)
+		}
+	}
+	if s := OrderTypeStopLimit.String(); s != "STOP_LIMIT" {
+		t.Errorf("String() = %q, want STOP_LIMIT", s)
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+	var option ExpDateOption
+	if err := json.Unmarshal([]byte(`{"putCall":"CALL","expirationType":"R"}`), &option); err != nil {
+		t.Fatalf("could not decode option: %v", err)
+	}
+	if option.PutCall != PutCallCall || option.ExpirationType != ExpirationTypeRegular {
+		t.Errorf("unexpected option: %+v", option)
+	}
+	if err := json.Unmarshal([]byte(`{"putCall":"CAL"}`), &option); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("decoding an unknown put/call returned %v, want ErrInvalidParams", err)
+	}
+	for _, tt := range []struct {
+		name string
+		v    interface{}
+		json string
+	}{
+		{"option instrument", &OptionInstrument{}, `{"putCall":"CAL"}`},
+		{"transaction put/call", &TransactionInstrument{}, `{"putCall":"CAL"}`},
+		{"transaction asset type", &TransactionInstrument{}, `{"assetType":"STOCK"}`},
+		{"watchlist instrument", &WatchlistInstrument{}, `{"assetType":"STOCK"}`},
+		{"option deliverable", &OptionDeliverable{}, `{"assetType":"STOCK"}`},
+	} {
+		if err := json.Unmarshal([]byte(tt.json), tt.v); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("decoding a %s of %s returned %v, want ErrInvalidParams", tt.name, tt.json, err)
+		}
+	}
+
+	// Fields not set are encoded as empty strings, as before they were typed.
+	b, err := json.Marshal(Order{OrderType: OrderTypeLimit})
+	if err != nil {
+		t.Fatalf("could not encode order: %v", err)
+	}
+	var order Order
+	if err := json.Unmarshal(b, &order); err != nil || order.OrderType != OrderTypeLimit || order.Session != "" {
+		t.Errorf("order round trip = %+v, %v", order, err)
+	}
+
+	if _, err := json.Marshal(Order{OrderType: "LIMT"}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("encoding an unknown order type returned %v, want ErrInvalidParams", err)
+	}
+}
@@ -0,0 +1,188 @@
+package tdameritrade
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// expDateKeyLayout is the layout of the date in ExpDateMap keys.
+const expDateKeyLayout = "2006-01-02"
+
+// ParseExpDateKey parses an ExpDateMap key such as 2024-01-19:1, made of the expiration date and the number
+// of days to expiration when the chain was fetched. The date is returned at midnight UTC.
+func ParseExpDateKey(key string) (date time.Time, dte int, err error) {
+	i := strings.Index(key, ":")
+	if i < 0 {
+		return time.Time{}, 0, fmt.Errorf("expiration key %q is not of the form date:days", key)
+	}
+	date, err = time.Parse(expDateKeyLayout, key[:i])
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("expiration key %q: %w", key, err)
+	}
+	dte, err = strconv.Atoi(key[i+1:])
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("expiration key %q: days to expiration: %w", key, err)
+	}
+	return date, dte, nil
+}
+
+// FormatExpDateKey returns the ExpDateMap key of options expiring on date in dte days, e.g. 2024-01-19:1.
+func FormatExpDateKey(date time.Time, dte int) string {
+	return fmt.Sprintf("%s:%d", date.Format(expDateKeyLayout), dte)
+}
+
+// ExpDateKeyLess reports whether the ExpDateMap key a expires before b, for sorting keys.
+// Keys with the same date are ordered by days to expiration.
+func ExpDateKeyLess(a, b string) bool {
+	// Dates are in ISO 8601 form, so they sort as strings.
+	if da, db := expirationDate(a), expirationDate(b); da != db {
+		return da < db
+	}
+	_, dteA, errA := ParseExpDateKey(a)
+	_, dteB, errB := ParseExpDateKey(b)
+	if errA != nil || errB != nil || dteA == dteB {
+		return a < b
+	}
+	return dteA < dteB
+}
+
+// FilterByDTE returns the options expiring in minDTE to maxDTE days, inclusive.
+func (m ExpDateMap) FilterByDTE(minDTE, maxDTE int) ExpDateMap {
+	return m.filter(func(o ExpDateOption) bool {
+		return o.DaysToExpiration >= minDTE && o.DaysToExpiration <= maxDTE
+	})
+}
+
+// FilterByStrike returns the options with strikes from minStrike to maxStrike, inclusive.
+func (m ExpDateMap) FilterByStrike(minStrike, maxStrike float64) ExpDateMap {
+	return m.filter(func(o ExpDateOption) bool {
+		return o.StrikePrice >= minStrike && o.StrikePrice <= maxStrike
+	})
+}
+
+// FilterByDelta returns the options with deltas from minDelta to maxDelta, inclusive.
+// Puts have negative deltas, so filter puts with negative bounds, e.g. FilterByDelta(-0.3, -0.2).
+// Options without a delta, which TD Ameritrade reports as NaN, are left out.
+func (m ExpDateMap) FilterByDelta(minDelta, maxDelta float64) ExpDateMap {
+	return m.filter(func(o ExpDateOption) bool {
+		delta := float64(o.Delta)
+		return !math.IsNaN(delta) && delta >= minDelta && delta <= maxDelta
+	})
+}
+
+// FilterExpDateMap returns the options in em that filter returns true for, for filtering chains more finely
+// than TD Ameritrade can, e.g. FilterExpDateMap(chains.CallExpDateMap, func(o *ExpDateOption) bool { return o.InTheMoney }).
+// Strikes and expirations with no options left are left out.
+func FilterExpDateMap(em ExpDateMap, filter func(*ExpDateOption) bool) ExpDateMap {
+	return em.filter(func(o ExpDateOption) bool { return filter(&o) })
+}
+
+// Flatten returns all of the options in m, ordered by expiration date and then strike.
+func (m ExpDateMap) Flatten() []ExpDateOption {
+	return m.appendFlattened(make([]ExpDateOption, 0, m.len()))
+}
+
+// appendFlattened appends the options in m to options in the order of Flatten.
+func (m ExpDateMap) appendFlattened(options []ExpDateOption) []ExpDateOption {
+	for _, expiration := range m.sortedKeys() {
+		strikes := m[expiration]
+		for _, strike := range sortedStrikes(strikes) {
+			options = append(options, strikes[strike]...)
+		}
+	}
+	return options
+}
+
+// len returns the number of options in m.
+func (m ExpDateMap) len() int {
+	n := 0
+	for _, strikes := range m {
+		for _, options := range strikes {
+			n += len(options)
+		}
+	}
+	return n
+}
+
+// SortedExpirations returns the expiration dates in m, e.g. 2024-01-19, in ascending order.
+// TD Ameritrade keys expirations by date and days to expiration, e.g. 2024-01-19:1.
+func (m ExpDateMap) SortedExpirations() []string {
+	keys := m.sortedKeys()
+	expirations := make([]string, len(keys))
+	for i, key := range keys {
+		expirations[i] = expirationDate(key)
+	}
+	return expirations
+}
+
+// filter returns the options in m that keep returns true for, leaving out strikes and expirations with no options left.
+func (m ExpDateMap) filter(keep func(ExpDateOption) bool) ExpDateMap {
+	filtered := ExpDateMap{}
+	for expiration, strikes := range m {
+		for strike, options := range strikes {
+			for _, o := range options {
+				if !keep(o) {
+					continue
+				}
+				if filtered[expiration] == nil {
+					filtered[expiration] = map[string][]ExpDateOption{}
+				}
+				filtered[expiration][strike] = append(filtered[expiration][strike], o)
+			}
+		}
+	}
+	return filtered
+}
+
+// sortedKeys returns the keys of m in order of expiration date.
+func (m ExpDateMap) sortedKeys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return ExpDateKeyLess(keys[i], keys[j])
+	})
+	return keys
+}
+
+// sortedStrikes returns the strike keys of strikes in ascending numeric order.
+func sortedStrikes(strikes map[string][]ExpDateOption) []string {
+	type strike struct {
+		key   string
+		price float64
+	}
+	// Parse each key once rather than on every comparison.
+	parsed := make([]strike, 0, len(strikes))
+	for key := range strikes {
+		price, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			price = math.Inf(1)
+		}
+		parsed = append(parsed, strike{key, price})
+	}
+	sort.Slice(parsed, func(i, j int) bool {
+		if parsed[i].price == parsed[j].price {
+			return parsed[i].key < parsed[j].key
+		}
+		return parsed[i].price < parsed[j].price
+	})
+
+	keys := make([]string, len(parsed))
+	for i, s := range parsed {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// expirationDate returns the date of an ExpDateMap key such as 2024-01-19:1.
+func expirationDate(key string) string {
+	if i := strings.Index(key, ":"); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
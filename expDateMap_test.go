@@ -0,0 +1,120 @@
+package tdameritrade
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func testExpDateMap() ExpDateMap {
+	return ExpDateMap{
+		"2020-08-21:45": {
+			"300.0": {{Symbol: "SPY_082120C300", StrikePrice: 300, DaysToExpiration: 45, Delta: 0.7}},
+			"310.0": {{Symbol: "SPY_082120C310", StrikePrice: 310, DaysToExpiration: 45, Delta: 0.5}},
+		},
+		"2020-07-17:10": {
+			"95.0":  {{Symbol: "SPY_071720C95", StrikePrice: 95, DaysToExpiration: 10, Delta: Float64WithSpecial(math.NaN())}},
+			"310.0": {{Symbol: "SPY_071720C310", StrikePrice: 310, DaysToExpiration: 10, Delta: 0.45}},
+			"320.0": {{Symbol: "SPY_071720C320", StrikePrice: 320, DaysToExpiration: 10, Delta: 0.2}},
+		},
+	}
+}
+
+func optionSymbols(options []ExpDateOption) []string {
+	var s []string
+	for _, o := range options {
+		s = append(s, o.Symbol)
+	}
+	return s
+}
+
+func TestExpDateMapFlatten(t *testing.T) {
+	want := []string{"SPY_071720C95", "SPY_071720C310", "SPY_071720C320", "SPY_082120C300", "SPY_082120C310"}
+	if got := optionSymbols(testExpDateMap().Flatten()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flatten() = %v, want %v", got, want)
+	}
+}
+
+func TestExpDateMapSortedExpirations(t *testing.T) {
+	want := []string{"2020-07-17", "2020-08-21"}
+	if got := testExpDateMap().SortedExpirations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedExpirations() = %v, want %v", got, want)
+	}
+}
+
+func TestExpDateMapFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		got  ExpDateMap
+		want []string
+	}{
+		{"FilterByDTE", testExpDateMap().FilterByDTE(30, 60), []string{"SPY_082120C300", "SPY_082120C310"}},
+		{"FilterByStrike", testExpDateMap().FilterByStrike(305, 315), []string{"SPY_071720C310", "SPY_082120C310"}},
+		{"FilterByDelta", testExpDateMap().FilterByDelta(0.4, 0.5), []string{"SPY_071720C310", "SPY_082120C310"}},
+		{"chained", testExpDateMap().FilterByDTE(0, 30).FilterByStrike(0, 315), []string{"SPY_071720C95", "SPY_071720C310"}},
+	}
+
+	for _, tt := range tests {
+		if got := optionSymbols(tt.got.Flatten()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	filtered := testExpDateMap().FilterByDTE(30, 60)
+	if _, ok := filtered["2020-07-17:10"]; ok {
+		t.Errorf("expected expirations with no options left to be removed, got %v", filtered.SortedExpirations())
+	}
+}
+
+func TestFilterExpDateMap(t *testing.T) {
+	m := testExpDateMap()
+	// The calls below the underlying price of 305 are in the money.
+	for _, strikes := range m {
+		for strike, options := range strikes {
+			for i := range options {
+				options[i].InTheMoney = options[i].StrikePrice < 305
+			}
+			strikes[strike] = options
+		}
+	}
+
+	itm := FilterExpDateMap(m, func(o *ExpDateOption) bool { return o.InTheMoney })
+	want := []string{"SPY_071720C95", "SPY_082120C300"}
+	if got := optionSymbols(itm.Flatten()); !reflect.DeepEqual(got, want) {
+		t.Errorf("in the money options = %v, want %v", got, want)
+	}
+	if _, ok := itm["2020-07-17:10"]["310.0"]; ok {
+		t.Error("FilterExpDateMap kept a strike with no options left")
+	}
+}
+
+func TestParseExpDateKey(t *testing.T) {
+	date, dte, err := ParseExpDateKey("2024-01-19:1")
+	if err != nil {
+		t.Fatalf("ParseExpDateKey returned error: %v", err)
+	}
+	if want := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC); !date.Equal(want) || dte != 1 {
+		t.Errorf("ParseExpDateKey = %v, %d, want %v, 1", date, dte, want)
+	}
+	if got := FormatExpDateKey(date, dte); got != "2024-01-19:1" {
+		t.Errorf("FormatExpDateKey = %s, want 2024-01-19:1", got)
+	}
+
+	for _, key := range []string{"", "2024-01-19", "2024-01-19:", "2024-01-19:x", "01/19/2024:1"} {
+		if _, _, err := ParseExpDateKey(key); err == nil {
+			t.Errorf("ParseExpDateKey(%q): expected an error", key)
+		}
+	}
+}
+
+func TestExpDateKeyLess(t *testing.T) {
+	keys := []string{"2024-03-15:57", "2023-12-29:-1", "2024-01-19:1", "2024-01-19:0", "2024-01-05:12", "weekly"}
+	sort.Slice(keys, func(i, j int) bool { return ExpDateKeyLess(keys[i], keys[j]) })
+
+	want := []string{"2023-12-29:-1", "2024-01-05:12", "2024-01-19:0", "2024-01-19:1", "2024-03-15:57", "weekly"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("sorted keys = %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,74 @@
+package tdameritrade
+
+import (
+	"math"
+	"sort"
+)
+
+// SortByStrike sorts opts by strike price, in ascending or descending order.
+func SortByStrike(opts []ExpDateOption, ascending bool) {
+	sortOptions(opts, ascending, func(o *ExpDateOption) float64 { return o.StrikePrice })
+}
+
+// SortByDTE sorts opts by days to expiration, in ascending or descending order.
+func SortByDTE(opts []ExpDateOption, ascending bool) {
+	sortOptions(opts, ascending, func(o *ExpDateOption) float64 { return float64(o.DaysToExpiration) })
+}
+
+// SortByVolume sorts opts by the number of contracts traded today, in ascending or descending order.
+func SortByVolume(opts []ExpDateOption, ascending bool) {
+	sortOptions(opts, ascending, func(o *ExpDateOption) float64 { return float64(o.TotalVolume) })
+}
+
+// SortByOpenInterest sorts opts by open interest, in ascending or descending order.
+func SortByOpenInterest(opts []ExpDateOption, ascending bool) {
+	sortOptions(opts, ascending, func(o *ExpDateOption) float64 { return float64(o.OpenInterest) })
+}
+
+// SortByDelta sorts opts by delta, in ascending or descending order. Puts have negative deltas, so they sort
+// before calls in ascending order. Options without a delta, which TD Ameritrade reports as NaN, sort last.
+func SortByDelta(opts []ExpDateOption, ascending bool) {
+	sortOptions(opts, ascending, func(o *ExpDateOption) float64 { return float64(o.Delta) })
+}
+
+// MaxByOpenInterest returns the option in opts with the most open interest, the first of them on ties,
+// or nil if opts is empty.
+func MaxByOpenInterest(opts []ExpDateOption) *ExpDateOption {
+	var max *ExpDateOption
+	for i := range opts {
+		if max == nil || opts[i].OpenInterest > max.OpenInterest {
+			max = &opts[i]
+		}
+	}
+	return max
+}
+
+// MinByDelta returns the option in opts with the delta closest to 0, i.e. the furthest out of the money
+// whether a call or a put, the first of them on ties. Options without a delta are skipped, and nil is returned
+// if none has one.
+func MinByDelta(opts []ExpDateOption) *ExpDateOption {
+	var min *ExpDateOption
+	for i := range opts {
+		if opts[i].Delta.IsNaN() {
+			continue
+		}
+		if min == nil || math.Abs(float64(opts[i].Delta)) < math.Abs(float64(min.Delta)) {
+			min = &opts[i]
+		}
+	}
+	return min
+}
+
+// sortOptions sorts opts by the value of key, keeping NaN values last in either order.
+func sortOptions(opts []ExpDateOption, ascending bool, key func(*ExpDateOption) float64) {
+	sort.Slice(opts, func(i, j int) bool {
+		a, b := key(&opts[i]), key(&opts[j])
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return !math.IsNaN(a) && math.IsNaN(b)
+		}
+		if ascending {
+			return a < b
+		}
+		return a > b
+	})
+}
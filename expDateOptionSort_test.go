@@ -0,0 +1,53 @@
+package tdameritrade
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func testSortOptions() []ExpDateOption {
+	return []ExpDateOption{
+		{Symbol: "A", StrikePrice: 310, DaysToExpiration: 30, TotalVolume: 5, OpenInterest: 100, Delta: 0.45},
+		{Symbol: "B", StrikePrice: 290, DaysToExpiration: 10, TotalVolume: 50, OpenInterest: 300, Delta: -0.3},
+		{Symbol: "C", StrikePrice: 300, DaysToExpiration: 20, TotalVolume: 1, OpenInterest: 200, Delta: Float64WithSpecial(math.NaN())},
+		{Symbol: "D", StrikePrice: 320, DaysToExpiration: 40, TotalVolume: 20, OpenInterest: 10, Delta: 0.1},
+	}
+}
+
+func TestSortOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		sort      func([]ExpDateOption, bool)
+		ascending bool
+		want      []string
+	}{
+		{"SortByStrike", SortByStrike, true, []string{"B", "C", "A", "D"}},
+		{"SortByStrike", SortByStrike, false, []string{"D", "A", "C", "B"}},
+		{"SortByDTE", SortByDTE, true, []string{"B", "C", "A", "D"}},
+		{"SortByVolume", SortByVolume, false, []string{"B", "D", "A", "C"}},
+		{"SortByOpenInterest", SortByOpenInterest, true, []string{"D", "A", "C", "B"}},
+		{"SortByDelta", SortByDelta, true, []string{"B", "D", "A", "C"}},
+		{"SortByDelta", SortByDelta, false, []string{"A", "D", "B", "C"}},
+	}
+	for _, tt := range tests {
+		opts := testSortOptions()
+		tt.sort(opts, tt.ascending)
+		if got := optionSymbols(opts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s(ascending %v) = %v, want %v", tt.name, tt.ascending, got, tt.want)
+		}
+	}
+}
+
+func TestMaxByOpenInterestAndMinByDelta(t *testing.T) {
+	opts := testSortOptions()
+	if got := MaxByOpenInterest(opts); got != &opts[1] {
+		t.Errorf("MaxByOpenInterest = %+v, want B", got)
+	}
+	if got := MinByDelta(opts); got != &opts[3] {
+		t.Errorf("MinByDelta = %+v, want D", got)
+	}
+	if MaxByOpenInterest(nil) != nil || MinByDelta(opts[2:3]) != nil {
+		t.Error("expected nil without options")
+	}
+}
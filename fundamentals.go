@@ -0,0 +1,140 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// fundamentalDateLayout is the layout of the dates of fundamental data, e.g. 2020-05-08 00:00:00.000.
+const fundamentalDateLayout = "2006-01-02 15:04:05.000"
+
+// FundamentalsService handles communication with the fundamental data of the instruments related methods of
+// the TDAmeritrade API.
+//
+// TDAmeritrade API docs: https://developer.tdameritrade.com/instruments/apis
+type FundamentalsService struct {
+	client *Client
+}
+
+// Fundamentals is the fundamental data of a symbol, with every field TD Ameritrade returns, as returned by
+// GetFundamentals and in the InstrumentInfo of the fundamental projection.
+// Ratios and margins are in percent, as TD Ameritrade reports them. DividendDate and DividendPayDate are zero
+// for symbols without dividends.
+type Fundamentals struct {
+	Symbol              string    `json:"symbol"`
+	High52              float64   `json:"high52"`
+	Low52               float64   `json:"low52"`
+	DividendAmount      float64   `json:"dividendAmount"`
+	DividendYield       float64   `json:"dividendYield"`
+	DividendDate        time.Time `json:"-"`
+	PeRatio             float64   `json:"peRatio"`
+	PegRatio            float64   `json:"pegRatio"`
+	PbRatio             float64   `json:"pbRatio"`
+	PrRatio             float64   `json:"prRatio"`
+	PcfRatio            float64   `json:"pcfRatio"`
+	GrossMarginTTM      float64   `json:"grossMarginTTM"`
+	GrossMarginMRQ      float64   `json:"grossMarginMRQ"`
+	NetProfitMarginTTM  float64   `json:"netProfitMarginTTM"`
+	NetProfitMarginMRQ  float64   `json:"netProfitMarginMRQ"`
+	OperatingMarginTTM  float64   `json:"operatingMarginTTM"`
+	OperatingMarginMRQ  float64   `json:"operatingMarginMRQ"`
+	ReturnOnEquity      float64   `json:"returnOnEquity"`
+	ReturnOnAssets      float64   `json:"returnOnAssets"`
+	ReturnOnInvestment  float64   `json:"returnOnInvestment"`
+	QuickRatio          float64   `json:"quickRatio"`
+	CurrentRatio        float64   `json:"currentRatio"`
+	InterestCoverage    float64   `json:"interestCoverage"`
+	TotalDebtToCapital  float64   `json:"totalDebtToCapital"`
+	LtDebtToEquity      float64   `json:"ltDebtToEquity"`
+	TotalDebtToEquity   float64   `json:"totalDebtToEquity"`
+	EpsTTM              float64   `json:"epsTTM"`
+	EpsChangePercentTTM float64   `json:"epsChangePercentTTM"`
+	EpsChangeYear       float64   `json:"epsChangeYear"`
+	EpsChange           float64   `json:"epsChange"`
+	RevChangeYear       float64   `json:"revChangeYear"`
+	RevChangeTTM        float64   `json:"revChangeTTM"`
+	RevChangeIn         float64   `json:"revChangeIn"`
+	SharesOutstanding   float64   `json:"sharesOutstanding"`
+	MarketCapFloat      float64   `json:"marketCapFloat"`
+	MarketCap           float64   `json:"marketCap"`
+	BookValuePerShare   float64   `json:"bookValuePerShare"`
+	ShortIntToFloat     float64   `json:"shortIntToFloat"`
+	ShortIntDayToCover  float64   `json:"shortIntDayToCover"`
+	DivGrowthRate3Year  float64   `json:"divGrowthRate3Year"`
+	DividendPayAmount   float64   `json:"dividendPayAmount"`
+	DividendPayDate     time.Time `json:"-"`
+	Beta                float64   `json:"beta"`
+	Vol1DayAvg          float64   `json:"vol1DayAvg"`
+	Vol10DayAvg         float64   `json:"vol10DayAvg"`
+	Vol3MonthAvg        float64   `json:"vol3MonthAvg"`
+}
+
+// UnmarshalJSON decodes fundamental data, parsing its dates.
+func (f *Fundamentals) UnmarshalJSON(b []byte) error {
+	type fundamentals Fundamentals
+	var raw struct {
+		*fundamentals
+		DividendDate    string `json:"dividendDate"`
+		DividendPayDate string `json:"dividendPayDate"`
+	}
+	raw.fundamentals = (*fundamentals)(f)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Time
+	}{
+		{"dividendDate", raw.DividendDate, &f.DividendDate},
+		{"dividendPayDate", raw.DividendPayDate, &f.DividendPayDate},
+	} {
+		value := strings.TrimSpace(d.value)
+		if value == "" {
+			*d.dst = time.Time{}
+			continue
+		}
+		t, err := time.Parse(fundamentalDateLayout, value)
+		if err != nil {
+			return fmt.Errorf("could not parse %s of %s: %w", d.name, f.Symbol, err)
+		}
+		*d.dst = t
+	}
+	return nil
+}
+
+// GetFundamentals returns the fundamental data of symbol, e.g. AAPL.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/instruments/apis/get/instruments
+func (s *FundamentalsService) GetFundamentals(ctx context.Context, symbol string) (*Fundamentals, *Response, error) {
+	if symbol == "" {
+		return nil, nil, fmt.Errorf("%w: no symbol present", ErrInvalidParams)
+	}
+
+	q := url.Values{}
+	q.Set("symbol", symbol)
+	q.Set("projection", ProjectionFundamental)
+	u := fmt.Sprintf("instruments?%s", q.Encode())
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var instruments map[string]struct {
+		Fundamental *Fundamentals `json:"fundamental"`
+	}
+	resp, err := s.client.Do(ctx, req, &instruments)
+	if err != nil {
+		return nil, resp, err
+	}
+	instrument, ok := instruments[symbol]
+	if !ok || instrument.Fundamental == nil {
+		return nil, resp, fmt.Errorf("no fundamental data found for %s", symbol)
+	}
+	return instrument.Fundamental, resp, nil
+}
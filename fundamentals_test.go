@@ -0,0 +1,73 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetFundamentals(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/instruments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "symbol", "AAPL")
+		testFormValue(t, r, "projection", "fundamental")
+		fmt.Fprint(w, `{"AAPL":{"cusip":"037833100","symbol":"AAPL","assetType":"EQUITY","exchange":"NASDAQ",
+			"fundamental":{"symbol":"AAPL","high52":372.38,"low52":192.58,"dividendAmount":3.28,"dividendYield":0.9,
+			"dividendDate":"2020-05-08 00:00:00.000","peRatio":28.7,"pegRatio":2.1,"pbRatio":18.3,"prRatio":5.9,"pcfRatio":22.4,
+			"grossMarginTTM":38.2,"grossMarginMRQ":38.4,"netProfitMarginTTM":21.3,"netProfitMarginMRQ":19.1,
+			"operatingMarginTTM":24.5,"operatingMarginMRQ":22.2,"returnOnEquity":62.1,"returnOnAssets":16.3,
+			"returnOnInvestment":25.2,"quickRatio":1.4,"currentRatio":1.5,"interestCoverage":24.8,"totalDebtToCapital":59.1,
+			"ltDebtToEquity":110.2,"totalDebtToEquity":144.5,"epsTTM":12.73,"epsChangePercentTTM":7.9,"epsChangeYear":0.3,
+			"epsChange":0.1,"revChangeYear":-2.4,"revChangeTTM":3.9,"revChangeIn":0.5,"sharesOutstanding":4334335000,
+			"marketCapFloat":4330.1,"marketCap":1530000,"bookValuePerShare":18.1,"shortIntToFloat":0.7,"shortIntDayToCover":1.1,
+			"divGrowthRate3Year":9.5,"dividendPayAmount":0.82,"dividendPayDate":"2020-05-14 00:00:00.000","beta":1.17,
+			"vol1DayAvg":33000000,"vol10DayAvg":31000000,"vol3MonthAvg":820000000}}}`)
+	})
+
+	f, _, err := client.Fundamentals.GetFundamentals(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatalf("GetFundamentals returned error: %v", err)
+	}
+
+	if f.Symbol != "AAPL" || f.High52 != 372.38 || f.PeRatio != 28.7 || f.GrossMarginMRQ != 38.4 || f.LtDebtToEquity != 110.2 ||
+		f.EpsChangePercentTTM != 7.9 || f.RevChangeIn != 0.5 || f.SharesOutstanding != 4334335000 || f.ShortIntDayToCover != 1.1 ||
+		f.Beta != 1.17 || f.Vol3MonthAvg != 820000000 {
+		t.Errorf("unexpected fundamentals: %+v", f)
+	}
+	if want := time.Date(2020, 5, 8, 0, 0, 0, 0, time.UTC); !f.DividendDate.Equal(want) {
+		t.Errorf("DividendDate = %v, want %v", f.DividendDate, want)
+	}
+	if want := time.Date(2020, 5, 14, 0, 0, 0, 0, time.UTC); !f.DividendPayDate.Equal(want) {
+		t.Errorf("DividendPayDate = %v, want %v", f.DividendPayDate, want)
+	}
+}
+
+func TestGetFundamentalsWithoutDividend(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/instruments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"TSLA":{"symbol":"TSLA","fundamental":{"symbol":"TSLA","dividendDate":" ","dividendPayDate":" ","marketCap":180000}}}`)
+	})
+
+	f, _, err := client.Fundamentals.GetFundamentals(context.Background(), "TSLA")
+	if err != nil {
+		t.Fatalf("GetFundamentals returned error: %v", err)
+	}
+	if !f.DividendDate.IsZero() || !f.DividendPayDate.IsZero() || f.MarketCap != 180000 {
+		t.Errorf("unexpected fundamentals: %+v", f)
+	}
+
+	if _, _, err := client.Fundamentals.GetFundamentals(context.Background(), "AAPL"); err == nil {
+		t.Error("expected an error for a symbol missing from the response")
+	}
+	if _, _, err := client.Fundamentals.GetFundamentals(context.Background(), ""); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams without a symbol, got %v", err)
+	}
+}
@@ -4,6 +4,10 @@ go 1.14
 
 require (
 	github.com/google/go-querystring v1.0.0
+	github.com/google/uuid v1.3.0
 	github.com/gorilla/websocket v1.4.2
+	github.com/shopspring/decimal v1.4.0
 	golang.org/x/oauth2 v0.0.0-20191202225959-858c2ad4c8b6
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
+	gonum.org/v1/gonum v0.8.2
 )
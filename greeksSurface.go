@@ -0,0 +1,101 @@
+package tdameritrade
+
+import (
+	"sort"
+	"strings"
+)
+
+// GreeksSurface holds the options of one side of a chain indexed by expiration and strike.
+// Expirations are dates such as 2020-07-17 in ascending order and Strikes are in ascending order.
+// Not every expiration has every strike, so Get may return nil for a combination of the two.
+type GreeksSurface struct {
+	Expirations []string
+	Strikes     []float64
+
+	points map[string]map[float64]*GreekPoint
+}
+
+// GreekPoint is an option on a GreeksSurface.
+type GreekPoint struct {
+	ExpDateOption
+	// ImpliedVolatility is the option's Volatility, which TD Ameritrade reports as a percentage.
+	ImpliedVolatility float64
+}
+
+// VolSkewPoint is the implied volatility of the option at a strike.
+type VolSkewPoint struct {
+	Strike            float64
+	ImpliedVolatility float64
+}
+
+// BuildGreeksSurface indexes the calls or puts of chain by expiration and strike.
+// putCall is CALL or PUT; the surface is empty for anything else.
+func BuildGreeksSurface(chain *Chains, putCall string) *GreeksSurface {
+	surface := &GreeksSurface{points: map[string]map[float64]*GreekPoint{}}
+
+	var m ExpDateMap
+	switch strings.ToUpper(putCall) {
+	case "CALL":
+		m = chain.CallExpDateMap
+	case "PUT":
+		m = chain.PutExpDateMap
+	}
+
+	strikes := map[float64]bool{}
+	for key, byStrike := range m {
+		expiry := expirationDate(key)
+		for _, options := range byStrike {
+			for _, o := range options {
+				if surface.points[expiry] == nil {
+					surface.points[expiry] = map[float64]*GreekPoint{}
+				}
+				surface.points[expiry][o.StrikePrice] = &GreekPoint{ExpDateOption: o, ImpliedVolatility: float64(o.Volatility)}
+				strikes[o.StrikePrice] = true
+			}
+		}
+	}
+
+	for expiry := range surface.points {
+		surface.Expirations = append(surface.Expirations, expiry)
+	}
+	sort.Strings(surface.Expirations)
+	for strike := range strikes {
+		surface.Strikes = append(surface.Strikes, strike)
+	}
+	sort.Float64s(surface.Strikes)
+
+	return surface
+}
+
+// Get returns the option expiring on expiry, e.g. 2020-07-17, at strike, or nil if there is none.
+func (s *GreeksSurface) Get(expiry string, strike float64) *GreekPoint {
+	return s.points[expiry][strike]
+}
+
+// VolatilitySkew returns the implied volatility at each strike of expiry, in ascending order of strike.
+func (s *GreeksSurface) VolatilitySkew(expiry string) []VolSkewPoint {
+	var skew []VolSkewPoint
+	for _, strike := range s.Strikes {
+		if p := s.Get(expiry, strike); p != nil {
+			skew = append(skew, VolSkewPoint{Strike: strike, ImpliedVolatility: p.ImpliedVolatility})
+		}
+	}
+	return skew
+}
+
+// Clone returns a copy of s whose points can be changed through Get without changing s.
+func (s *GreeksSurface) Clone() *GreeksSurface {
+	clone := &GreeksSurface{
+		Expirations: append([]string(nil), s.Expirations...),
+		Strikes:     append([]float64(nil), s.Strikes...),
+		points:      make(map[string]map[float64]*GreekPoint, len(s.points)),
+	}
+	for expiry, byStrike := range s.points {
+		clone.points[expiry] = make(map[float64]*GreekPoint, len(byStrike))
+		for strike, p := range byStrike {
+			point := *p
+			clone.points[expiry][strike] = &point
+		}
+	}
+	return clone
+}
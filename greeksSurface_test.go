@@ -0,0 +1,61 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// loadChains decodes the chain recorded in testdata/chains.json.
+func loadChains(t testing.TB) *Chains {
+	t.Helper()
+	b, err := ioutil.ReadFile("testdata/chains.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chains := new(Chains)
+	if err := json.Unmarshal(b, chains); err != nil {
+		t.Fatalf("could not decode chain: %v", err)
+	}
+	return chains
+}
+
+func TestBuildGreeksSurface(t *testing.T) {
+	surface := BuildGreeksSurface(loadChains(t), "PUT")
+
+	if want := []string{"2020-07-17", "2020-08-21"}; !reflect.DeepEqual(surface.Expirations, want) {
+		t.Errorf("Expirations = %v, want %v", surface.Expirations, want)
+	}
+	if want := []float64{305, 310, 315}; !reflect.DeepEqual(surface.Strikes, want) {
+		t.Errorf("Strikes = %v, want %v", surface.Strikes, want)
+	}
+
+	p := surface.Get("2020-08-21", 315)
+	if p == nil {
+		t.Fatal("Get returned nil for an option in the chain")
+	}
+	if p.Symbol != "SPY_082120P315" || p.PutCall != "PUT" || p.Delta != -0.56 || p.ImpliedVolatility != float64(p.Volatility) {
+		t.Errorf("unexpected point: %+v", p)
+	}
+
+	if p := surface.Get("2020-08-21", 320); p != nil {
+		t.Errorf("expected nil for a strike not in the chain, got %+v", p)
+	}
+	if p := surface.Get("2020-09-18", 310); p != nil {
+		t.Errorf("expected nil for an expiration not in the chain, got %+v", p)
+	}
+}
+
+func TestGreeksSurfaceVolatilitySkew(t *testing.T) {
+	surface := BuildGreeksSurface(loadChains(t), "call")
+
+	want := []VolSkewPoint{{305, 19.1}, {310, 18.5}, {315, 17.9}}
+	if got := surface.VolatilitySkew("2020-07-17"); !reflect.DeepEqual(got, want) {
+		t.Errorf("VolatilitySkew = %v, want %v", got, want)
+	}
+
+	if empty := BuildGreeksSurface(loadChains(t), "STRADDLE"); len(empty.Expirations) != 0 || empty.Get("2020-07-17", 310) != nil {
+		t.Errorf("expected an empty surface, got %+v", empty)
+	}
+}
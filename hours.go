@@ -3,9 +3,22 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 )
 
+// validMarkets maps the markets accepted by MarketHoursService to the names TD Ameritrade expects.
+var validMarkets = map[string]string{
+	"equity":        "EQUITY",
+	"option":        "OPTION",
+	"bond":          "BOND",
+	"forex":         "FOREX",
+	"futures":       "FUTURE",
+	"future_option": "FUTURE_OPTION",
+}
+
 // MarketHoursService handles communication with the marketdata related methods of
 // the TDAmeritrade API.
 //
@@ -14,75 +27,148 @@ type MarketHoursService struct {
 	client *Client
 }
 
-type MarketHours map[string]map[string]*Hours
-
-type Period struct {
-	Start string `json:"start"`
-	End   string `json:"end"`
-}
-
-type SessionHours struct {
-	PreMarket     []Period `json:"preMarket"`
-	RegularMarket []Period `json:"regularMarket"`
-	PostMarket    []Period `json:"postMarket"`
+// MarketHours is the trading schedule of a single product on a given date.
+// SessionHours is keyed by session name: "preMarket", "regularMarket" and "postMarket".
+// It is empty on days the market is closed.
+type MarketHours struct {
+	Category     string                   `json:"category"`
+	Date         string                   `json:"date"`
+	Exchange     string                   `json:"exchange"`
+	IsOpen       bool                     `json:"isOpen"`
+	MarketType   string                   `json:"marketType"`
+	Product      string                   `json:"product"`
+	ProductName  string                   `json:"productName"`
+	SessionHours map[string][]SessionHour `json:"sessionHours"`
 }
 
-type Hours struct {
-	Category     string       `json:"category"`
-	Date         string       `json:"date"`
-	Exchange     string       `json:"exchange"`
-	IsOpen       bool         `json:"isOpen"`
-	MarketType   string       `json:"marketType"`
-	Product      string       `json:"product"`
-	ProductName  string       `json:"productName"`
-	SessionHours SessionHours `json:"sessionHours"`
+// SessionHour is a period of time in which a session is open.
+type SessionHour struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
 }
 
-func (s *MarketHoursService) GetMarketHoursMulti(ctx context.Context, markets string, date time.Time) (*MarketHours, *Response, error) {
-	u := fmt.Sprintf("marketdata/hours")
-	if markets == "" {
+// GetMarketHours returns the trading schedules of several markets on date, keyed by product code (e.g. "EQ" for equities).
+// Markets that trade several products, such as options, return one MarketHours per product.
+// A zero date returns today's schedule.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/market-hours/apis/get/marketdata/hours
+func (s *MarketHoursService) GetMarketHours(ctx context.Context, markets []string, date time.Time) (map[string]*MarketHours, *Response, error) {
+	if len(markets) == 0 {
 		return nil, nil, fmt.Errorf("no markets present")
 	}
-	u = fmt.Sprintf("%s?markets=%s", u, markets)
+
+	names := make([]string, 0, len(markets))
+	for _, market := range markets {
+		name, err := marketName(market)
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(names, name)
+	}
+
+	q := url.Values{}
+	q.Set("markets", strings.Join(names, ","))
 	if !date.IsZero() {
-		u = fmt.Sprintf("%s&date=%s", u, date.Format("2006-01-02"))
+		q.Set("date", date.Format("2006-01-02"))
 	}
+	u := fmt.Sprintf("marketdata/hours?%s", q.Encode())
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	return s.getMarketHours(ctx, u)
+}
 
+// GetMarketHoursForMarket returns the trading schedule of a single market on date.
+// For markets that trade several products it returns the first product in product code order;
+// use GetMarketHours to get the schedule of each product.
+// A zero date returns today's schedule.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/market-hours/apis/get/marketdata/%7Bmarket%7D/hours
+func (s *MarketHoursService) GetMarketHoursForMarket(ctx context.Context, market string, date time.Time) (*MarketHours, *Response, error) {
+	name, err := marketName(market)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	hours := new(MarketHours)
+	u := fmt.Sprintf("marketdata/%s/hours", name)
+	if !date.IsZero() {
+		u = fmt.Sprintf("%s?date=%s", u, date.Format("2006-01-02"))
+	}
 
-	resp, err := s.client.Do(ctx, req, hours)
+	hours, resp, err := s.getMarketHours(ctx, u)
 	if err != nil {
 		return nil, resp, err
 	}
+	if len(hours) == 0 {
+		return nil, resp, fmt.Errorf("no market hours returned for %s", market)
+	}
 
-	return hours, resp, nil
+	products := make([]string, 0, len(hours))
+	for product := range hours {
+		products = append(products, product)
+	}
+	sort.Strings(products)
+
+	return hours[products[0]], resp, nil
 }
 
-func (s *MarketHoursService) GetMarketHours(ctx context.Context, market string, date time.Time) (*MarketHours, *Response, error) {
-	u := fmt.Sprintf("marketdata/%s/hours", market)
+// marketLocation is the time zone of the markets, loaded once by MarketLocation.
+var marketLocation = loadMarketLocation()
 
-	if !date.IsZero() {
-		u = fmt.Sprintf("%s?date=%s", u, date.Format("2006-01-02"))
+func loadMarketLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}
+
+// MarketLocation returns the time zone of the trading day, America/New_York, falling back to US Eastern Standard
+// Time where the time zone database is not installed.
+func MarketLocation() *time.Location {
+	return marketLocation
+}
+
+// IsMarketOpen reports whether any product in market trades today, the current day in New York.
+func (s *MarketHoursService) IsMarketOpen(ctx context.Context, market string) (bool, error) {
+	hours, _, err := s.GetMarketHours(ctx, []string{market}, time.Now().In(MarketLocation()))
+	if err != nil {
+		return false, err
 	}
 
+	for _, h := range hours {
+		if h.IsOpen {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getMarketHours flattens TD Ameritrade's market -> product -> hours response into product -> hours.
+func (s *MarketHoursService) getMarketHours(ctx context.Context, u string) (map[string]*MarketHours, *Response, error) {
 	req, err := s.client.NewRequest("GET", u, nil)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	hours := new(MarketHours)
+	byMarket := map[string]map[string]*MarketHours{}
 
-	resp, err := s.client.Do(ctx, req, hours)
+	resp, err := s.client.Do(ctx, req, &byMarket)
 	if err != nil {
 		return nil, resp, err
 	}
 
+	hours := map[string]*MarketHours{}
+	for _, products := range byMarket {
+		for product, h := range products {
+			hours[product] = h
+		}
+	}
+
 	return hours, resp, nil
 }
+
+func marketName(market string) (string, error) {
+	name, ok := validMarkets[strings.ToLower(market)]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown market %q, must be one of equity, option, bond, forex, futures or future_option", ErrInvalidParams, market)
+	}
+	return name, nil
+}
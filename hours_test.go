@@ -0,0 +1,95 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const optionHoursJSON = `{"option":{
+	"EQO":{"category":"OPTION","date":"2020-06-15","exchange":"NULL","isOpen":true,"marketType":"OPTION","product":"EQO","productName":"equity option",
+		"sessionHours":{"regularMarket":[{"start":"2020-06-15T09:30:00-04:00","end":"2020-06-15T16:00:00-04:00"}]}},
+	"IND":{"category":"OPTION","date":"2020-06-15","exchange":"NULL","isOpen":true,"marketType":"OPTION","product":"IND","productName":"index option",
+		"sessionHours":{"regularMarket":[{"start":"2020-06-15T09:30:00-04:00","end":"2020-06-15T16:15:00-04:00"}]}}}}`
+
+func TestGetMarketHours(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/hours", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "markets", "OPTION")
+		testFormValue(t, r, "date", "2020-06-15")
+		fmt.Fprint(w, optionHoursJSON)
+	})
+
+	hours, _, err := client.MarketHours.GetMarketHours(context.Background(), []string{"option"}, time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetMarketHours returned error: %v", err)
+	}
+
+	if len(hours) != 2 {
+		t.Fatalf("expected 2 products, got %d", len(hours))
+	}
+	regular := hours["IND"].SessionHours["regularMarket"]
+	if len(regular) != 1 {
+		t.Fatalf("unexpected session hours: %+v", hours["IND"].SessionHours)
+	}
+	eastern := time.FixedZone("EDT", -4*60*60)
+	if !regular[0].End.Equal(time.Date(2020, 6, 15, 16, 15, 0, 0, eastern)) {
+		t.Errorf("End = %v, want 16:15 EDT", regular[0].End)
+	}
+}
+
+func TestGetMarketHoursForMarket(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/OPTION/hours", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, optionHoursJSON)
+	})
+
+	hours, _, err := client.MarketHours.GetMarketHoursForMarket(context.Background(), "option", time.Time{})
+	if err != nil {
+		t.Fatalf("GetMarketHoursForMarket returned error: %v", err)
+	}
+	if hours.Product != "EQO" || !hours.IsOpen {
+		t.Errorf("unexpected hours: %+v", hours)
+	}
+}
+
+func TestGetMarketHoursUnknownMarket(t *testing.T) {
+	client, _, teardown := setup(t)
+	defer teardown()
+
+	_, _, err := client.MarketHours.GetMarketHours(context.Background(), []string{"equity", "crypto"}, time.Time{})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+}
+
+func TestIsMarketOpen(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/hours", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "date", time.Now().In(MarketLocation()).Format("2006-01-02"))
+		fmt.Fprint(w, `{"equity":{"equity":{"date":"2020-06-13","marketType":"EQUITY","product":"equity","isOpen":false}}}`)
+	})
+
+	open, err := client.MarketHours.IsMarketOpen(context.Background(), "equity")
+	if err != nil {
+		t.Fatalf("IsMarketOpen returned error: %v", err)
+	}
+	if open {
+		t.Error("expected market to be closed")
+	}
+}
+
+func TestUnmarshalMarketHours(t *testing.T) {
+	testJSONRoundTrip(t, "testdata/hours.json", &map[string]map[string]*MarketHours{})
+}
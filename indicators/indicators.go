@@ -0,0 +1,202 @@
+// Package indicators computes technical indicators from the candles of the tdameritrade package,
+// such as those of PriceHistoryService.GetPriceHistory.
+//
+// Each indicator returns a value per candle, in the order of the candles, computed from the candles up to
+// and including it. Values are NaN until enough candles are available, and all values are NaN for periods
+// smaller than 1.
+package indicators
+
+import (
+	"math"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// MACDResult is the moving average convergence divergence of a series of candles.
+// Histogram is MACD less Signal.
+type MACDResult struct {
+	MACD      []float64
+	Signal    []float64
+	Histogram []float64
+}
+
+// BollingerResult are the Bollinger bands of a series of candles: Middle is the simple moving average of the
+// closes, and Upper and Lower are a multiple of their standard deviation above and below it.
+type BollingerResult struct {
+	Upper  []float64
+	Middle []float64
+	Lower  []float64
+}
+
+// SMA returns the simple moving average of the closes of the last period candles.
+func SMA(candles []tdameritrade.Candle, period int) []float64 {
+	return sma(closes(candles), period)
+}
+
+// EMA returns the exponential moving average of the closes with a smoothing of 2/(period+1),
+// starting from the simple moving average of the first period candles.
+func EMA(candles []tdameritrade.Candle, period int) []float64 {
+	return ema(closes(candles), period)
+}
+
+// RSI returns the relative strength index of the closes, between 0 and 100, using Wilder's smoothing of the
+// gains and losses of the last period candles. The first value is for candle period, as it needs period changes.
+func RSI(candles []tdameritrade.Candle, period int) []float64 {
+	out := nans(len(candles))
+	if period < 1 || len(candles) <= period {
+		return out
+	}
+
+	var gain, loss float64
+	for i := 1; i < len(candles); i++ {
+		change := candles[i].Close - candles[i-1].Close
+		up, down := math.Max(change, 0), math.Max(-change, 0)
+		if i <= period {
+			gain += up / float64(period)
+			loss += down / float64(period)
+			if i < period {
+				continue
+			}
+		} else {
+			gain = (gain*float64(period-1) + up) / float64(period)
+			loss = (loss*float64(period-1) + down) / float64(period)
+		}
+
+		switch {
+		case gain == 0 && loss == 0:
+			out[i] = 50
+		case loss == 0:
+			out[i] = 100
+		default:
+			out[i] = 100 - 100/(1+gain/loss)
+		}
+	}
+	return out
+}
+
+// MACD returns the difference between the fast and slow exponential moving averages of the closes, usually
+// of 12 and 26 candles, and its signal line, the exponential moving average of signal MACD values, usually 9.
+func MACD(candles []tdameritrade.Candle, fast, slow, signal int) MACDResult {
+	fastEMA, slowEMA := EMA(candles, fast), EMA(candles, slow)
+
+	result := MACDResult{MACD: make([]float64, len(candles))}
+	for i := range candles {
+		result.MACD[i] = fastEMA[i] - slowEMA[i]
+	}
+	result.Signal = ema(result.MACD, signal)
+	result.Histogram = make([]float64, len(candles))
+	for i := range candles {
+		result.Histogram[i] = result.MACD[i] - result.Signal[i]
+	}
+	return result
+}
+
+// BollingerBands returns the bands stdDevMult population standard deviations of the closes of the last period
+// candles above and below their simple moving average, usually of 20 candles and 2 deviations.
+func BollingerBands(candles []tdameritrade.Candle, period int, stdDevMult float64) BollingerResult {
+	values := closes(candles)
+	result := BollingerResult{
+		Upper:  nans(len(candles)),
+		Middle: sma(values, period),
+		Lower:  nans(len(candles)),
+	}
+	for i, mean := range result.Middle {
+		if math.IsNaN(mean) {
+			continue
+		}
+		variance := 0.0
+		for _, v := range values[i-period+1 : i+1] {
+			variance += (v - mean) * (v - mean)
+		}
+		stdDev := math.Sqrt(variance / float64(period))
+		result.Upper[i] = mean + stdDevMult*stdDev
+		result.Lower[i] = mean - stdDevMult*stdDev
+	}
+	return result
+}
+
+// ATR returns the average true range of the candles using Wilder's smoothing, starting from the mean true range
+// of the first period candles. The true range of the first candle is its high less its low.
+func ATR(candles []tdameritrade.Candle, period int) []float64 {
+	out := nans(len(candles))
+	if period < 1 || len(candles) < period {
+		return out
+	}
+
+	atr := 0.0
+	for i, c := range candles {
+		trueRange := c.High - c.Low
+		if i > 0 {
+			prev := candles[i-1].Close
+			trueRange = math.Max(trueRange, math.Max(math.Abs(c.High-prev), math.Abs(c.Low-prev)))
+		}
+		if i < period {
+			atr += trueRange / float64(period)
+			if i < period-1 {
+				continue
+			}
+		} else {
+			atr = (atr*float64(period-1) + trueRange) / float64(period)
+		}
+		out[i] = atr
+	}
+	return out
+}
+
+func closes(candles []tdameritrade.Candle) []float64 {
+	values := make([]float64, len(candles))
+	for i, c := range candles {
+		values[i] = c.Close
+	}
+	return values
+}
+
+// sma returns the simple moving average of values, which must not hold NaNs.
+func sma(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if period < 1 {
+		return out
+	}
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// ema returns the exponential moving average of values, skipping their leading NaNs.
+func ema(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if period < 1 {
+		return out
+	}
+	first := 0
+	for first < len(values) && math.IsNaN(values[first]) {
+		first++
+	}
+	if len(values)-first < period {
+		return out
+	}
+
+	alpha := 2 / float64(period+1)
+	seed := sma(values[first:first+period], period)
+	out[first+period-1] = seed[period-1]
+	for i := first + period; i < len(values); i++ {
+		out[i] = alpha*values[i] + (1-alpha)*out[i-1]
+	}
+	return out
+}
+
+func nans(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// closeCandles returns candles closing at each of closes.
+func closeCandles(closes ...float64) []tdameritrade.Candle {
+	candles := make([]tdameritrade.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = tdameritrade.Candle{Open: c, High: c, Low: c, Close: c}
+	}
+	return candles
+}
+
+// equalNaN reports whether a and b are equal to within 1e-9, treating NaNs as equal.
+func equalNaN(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.IsNaN(a[i]) != math.IsNaN(b[i]) || math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMovingAverages(t *testing.T) {
+	nan := math.NaN()
+	candles := closeCandles(1, 2, 3, 4, 5, 6)
+
+	if got, want := SMA(candles, 3), []float64{nan, nan, 2, 3, 4, 5}; !equalNaN(got, want) {
+		t.Errorf("SMA = %v, want %v", got, want)
+	}
+	if got, want := EMA(candles, 2), []float64{nan, 1.5, 2.5, 3.5, 4.5, 5.5}; !equalNaN(got, want) {
+		t.Errorf("EMA = %v, want %v", got, want)
+	}
+	if got, want := SMA(candles, 7), []float64{nan, nan, nan, nan, nan, nan}; !equalNaN(got, want) {
+		t.Errorf("SMA longer than the candles = %v, want %v", got, want)
+	}
+	if got, want := EMA(candles, 0), []float64{nan, nan, nan, nan, nan, nan}; !equalNaN(got, want) {
+		t.Errorf("EMA of period 0 = %v, want %v", got, want)
+	}
+}
+
+func TestRSI(t *testing.T) {
+	nan := math.NaN()
+	if got, want := RSI(closeCandles(1, 2, 3, 2), 2), []float64{nan, nan, 100, 50}; !equalNaN(got, want) {
+		t.Errorf("RSI = %v, want %v", got, want)
+	}
+	if got, want := RSI(closeCandles(5, 5, 5), 2), []float64{nan, nan, 50}; !equalNaN(got, want) {
+		t.Errorf("RSI of flat closes = %v, want %v", got, want)
+	}
+}
+
+func TestMACD(t *testing.T) {
+	nan := math.NaN()
+	result := MACD(closeCandles(1, 2, 3, 4, 5, 6), 2, 3, 2)
+
+	if want := []float64{nan, nan, 0.5, 0.5, 0.5, 0.5}; !equalNaN(result.MACD, want) {
+		t.Errorf("MACD = %v, want %v", result.MACD, want)
+	}
+	if want := []float64{nan, nan, nan, 0.5, 0.5, 0.5}; !equalNaN(result.Signal, want) {
+		t.Errorf("Signal = %v, want %v", result.Signal, want)
+	}
+	if want := []float64{nan, nan, nan, 0, 0, 0}; !equalNaN(result.Histogram, want) {
+		t.Errorf("Histogram = %v, want %v", result.Histogram, want)
+	}
+}
+
+func TestBollingerBands(t *testing.T) {
+	nan := math.NaN()
+	result := BollingerBands(closeCandles(1, 2, 3, 5), 3, 2)
+
+	stdDev := math.Sqrt(2.0 / 3)
+	stdDev2 := math.Sqrt((1.0/9 + 16.0/9 + 25.0/9) / 3)
+	if want := []float64{nan, nan, 2, 10.0 / 3}; !equalNaN(result.Middle, want) {
+		t.Errorf("Middle = %v, want %v", result.Middle, want)
+	}
+	if want := []float64{nan, nan, 2 + 2*stdDev, 10.0/3 + 2*stdDev2}; !equalNaN(result.Upper, want) {
+		t.Errorf("Upper = %v, want %v", result.Upper, want)
+	}
+	if want := []float64{nan, nan, 2 - 2*stdDev, 10.0/3 - 2*stdDev2}; !equalNaN(result.Lower, want) {
+		t.Errorf("Lower = %v, want %v", result.Lower, want)
+	}
+}
+
+func TestATR(t *testing.T) {
+	nan := math.NaN()
+	candles := []tdameritrade.Candle{
+		{High: 10, Low: 8, Close: 9},
+		{High: 11, Low: 9, Close: 10},
+		{High: 12, Low: 9, Close: 11},
+		// A gap up, whose true range is from the previous close.
+		{High: 15, Low: 14, Close: 14.5},
+	}
+	if got, want := ATR(candles, 2), []float64{nan, 2, 2.5, 3.25}; !equalNaN(got, want) {
+		t.Errorf("ATR = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package indicators
+
+import (
+	"math"
+	"time"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// VWAP returns the volume weighted average of the typical price, (high + low + close) / 3, of the candles since
+// the start of their trading day, the day of their DateTime in New York. It is NaN for candles without volume.
+func VWAP(candles []tdameritrade.Candle) []float64 {
+	out := nans(len(candles))
+	var day time.Time
+	var priceVolume, volume float64
+	for i, c := range candles {
+		if d := tradingDay(c); !d.Equal(day) {
+			day = d
+			priceVolume, volume = 0, 0
+		}
+		out[i] = accumulate(c, &priceVolume, &volume)
+	}
+	return out
+}
+
+// AnchoredVWAP returns the volume weighted average of the typical price of the candles from candles[anchorIndex],
+// across trading days. It is NaN before the anchor, for candles without volume, and for all candles if anchorIndex
+// is out of range.
+func AnchoredVWAP(candles []tdameritrade.Candle, anchorIndex int) []float64 {
+	out := nans(len(candles))
+	if anchorIndex < 0 || anchorIndex >= len(candles) {
+		return out
+	}
+	var priceVolume, volume float64
+	for i := anchorIndex; i < len(candles); i++ {
+		out[i] = accumulate(candles[i], &priceVolume, &volume)
+	}
+	return out
+}
+
+// accumulate adds c to the sums of price times volume and of volume and returns their ratio,
+// or NaN if c has no volume.
+func accumulate(c tdameritrade.Candle, priceVolume, volume *float64) float64 {
+	*priceVolume += (c.High + c.Low + c.Close) / 3 * c.Volume
+	*volume += c.Volume
+	if c.Volume == 0 {
+		return math.NaN()
+	}
+	return *priceVolume / *volume
+}
+
+// tradingDay returns the start of the trading day of c.
+func tradingDay(c tdameritrade.Candle) time.Time {
+	loc := tdameritrade.MarketLocation()
+	t := time.Unix(0, c.DateTime*int64(time.Millisecond)).In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,51 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// vwapCandles are five candles, the first four on June 1st 2020 and the last on June 2nd.
+func vwapCandles() []tdameritrade.Candle {
+	open := time.Date(2020, 6, 1, 13, 30, 0, 0, time.UTC)
+	at := func(d time.Duration) int64 { return tdameritrade.ConvertToEpoch(open.Add(d)) }
+	return []tdameritrade.Candle{
+		{High: 10, Low: 8, Close: 9, Volume: 100, DateTime: at(0)},
+		{High: 11, Low: 9, Close: 10, Volume: 200, DateTime: at(time.Minute)},
+		{High: 12, Low: 10, Close: 11, Volume: 0, DateTime: at(2 * time.Minute)},
+		{High: 12, Low: 10, Close: 11.5, Volume: 300, DateTime: at(3 * time.Minute)},
+		{High: 20, Low: 18, Close: 19, Volume: 100, DateTime: at(24 * time.Hour)},
+	}
+}
+
+// round4 rounds values to 4 decimal places.
+func round4(values []float64) []float64 {
+	rounded := make([]float64, len(values))
+	for i, v := range values {
+		rounded[i] = math.Round(v*1e4) / 1e4
+	}
+	return rounded
+}
+
+func TestVWAP(t *testing.T) {
+	nan := math.NaN()
+	// Typical prices 9, 10, 11, 11.1667 and 19; the last candle starts a new day.
+	want := []float64{9, 9.6667, nan, 10.4167, 19}
+	if got := round4(VWAP(vwapCandles())); !equalNaN(got, want) {
+		t.Errorf("VWAP = %v, want %v", got, want)
+	}
+}
+
+func TestAnchoredVWAP(t *testing.T) {
+	nan := math.NaN()
+	want := []float64{nan, 10, nan, 10.7, 12.0833}
+	if got := round4(AnchoredVWAP(vwapCandles(), 1)); !equalNaN(got, want) {
+		t.Errorf("AnchoredVWAP = %v, want %v", got, want)
+	}
+	if got := AnchoredVWAP(vwapCandles(), 5); !equalNaN(got, []float64{nan, nan, nan, nan, nan}) {
+		t.Errorf("AnchoredVWAP out of range = %v, want NaNs", got)
+	}
+}
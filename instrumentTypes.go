@@ -0,0 +1,126 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TypedInstrument is an instrument decoded into a type specific to its asset type, such as *OptionInstrument.
+// Use a type switch to get at the fields of each asset type.
+// It complements Instrument, which positions and orders use to hold the same data.
+type TypedInstrument interface {
+	Symbol() string
+	AssetType() string
+}
+
+// BaseInstrument holds the fields common to instruments of every asset type and implements TypedInstrument
+// for the types embedding it.
+type BaseInstrument struct {
+	AssetTypeName string `json:"assetType"`
+	SymbolName    string `json:"symbol"`
+	Cusip         string `json:"cusip,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Exchange      string `json:"exchange,omitempty"`
+}
+
+// Symbol returns the symbol of the instrument, e.g. SPY or SPY_071720C310.
+func (b BaseInstrument) Symbol() string {
+	return b.SymbolName
+}
+
+// AssetType returns the asset type of the instrument, e.g. EQUITY or OPTION.
+func (b BaseInstrument) AssetType() string {
+	return b.AssetTypeName
+}
+
+// EquityInstrument is a stock.
+type EquityInstrument struct {
+	BaseInstrument
+}
+
+// ETFInstrument is an exchange traded fund.
+type ETFInstrument struct {
+	BaseInstrument
+}
+
+// OptionInstrument is an option contract.
+type OptionInstrument struct {
+	BaseInstrument
+	Type               string              `json:"type,omitempty"`
+	PutCall            PutCall             `json:"putCall,omitempty"`
+	UnderlyingSymbol   string              `json:"underlyingSymbol,omitempty"`
+	OptionMultiplier   float64             `json:"optionMultiplier,omitempty"`
+	OptionDeliverables []OptionDeliverable `json:"optionDeliverables,omitempty"`
+}
+
+// MutualFundInstrument is a mutual fund.
+type MutualFundInstrument struct {
+	BaseInstrument
+	Type string `json:"type,omitempty"` //"'NOT_APPLICABLE' or 'OPEN_END_NON_TAXABLE' or 'OPEN_END_TAXABLE' or 'NO_LOAD_NON_TAXABLE' or 'NO_LOAD_TAXABLE'"
+}
+
+// BondInstrument is a bond or other fixed income security, of asset type BOND or FIXED_INCOME.
+type BondInstrument struct {
+	BaseInstrument
+	MaturityDate string  `json:"maturityDate,omitempty"`
+	VariableRate float64 `json:"variableRate,omitempty"`
+	Factor       float64 `json:"factor,omitempty"`
+	BondPrice    float64 `json:"bondPrice,omitempty"`
+}
+
+// ForexInstrument is a currency pair, e.g. EUR/USD.
+type ForexInstrument struct {
+	BaseInstrument
+}
+
+// FutureInstrument is a futures contract, e.g. /ESU20.
+// FutureExpirationDate is in milliseconds since the Unix epoch.
+type FutureInstrument struct {
+	BaseInstrument
+	FutureMultiplier     float64 `json:"futureMultiplier,omitempty"`
+	FutureExpirationDate int64   `json:"futureExpirationDate,omitempty"`
+	FutureActiveSymbol   string  `json:"futureActiveSymbol,omitempty"`
+}
+
+// UnmarshalInstrument decodes the JSON of an instrument into the type for its assetType:
+// *EquityInstrument, *ETFInstrument, *OptionInstrument, *MutualFundInstrument, *BondInstrument
+// for BOND and FIXED_INCOME, *ForexInstrument or *FutureInstrument.
+func UnmarshalInstrument(b []byte) (TypedInstrument, error) {
+	var base BaseInstrument
+	if err := json.Unmarshal(b, &base); err != nil {
+		return nil, err
+	}
+
+	var instrument TypedInstrument
+	switch base.AssetTypeName {
+	case "EQUITY":
+		instrument = &EquityInstrument{}
+	case "ETF":
+		instrument = &ETFInstrument{}
+	case "OPTION":
+		instrument = &OptionInstrument{}
+	case "MUTUAL_FUND":
+		instrument = &MutualFundInstrument{}
+	case "BOND", "FIXED_INCOME":
+		instrument = &BondInstrument{}
+	case "FOREX":
+		instrument = &ForexInstrument{}
+	case "FUTURE":
+		instrument = &FutureInstrument{}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", base.AssetTypeName)
+	}
+	if err := json.Unmarshal(b, instrument); err != nil {
+		return nil, err
+	}
+	return instrument, nil
+}
+
+// Typed returns the instrument as a TypedInstrument.
+func (i *Instrument) Typed() (TypedInstrument, error) {
+	b, err := i.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalInstrument(b)
+}
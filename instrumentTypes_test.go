@@ -0,0 +1,96 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalInstrument(t *testing.T) {
+	tests := []struct {
+		json string
+		want TypedInstrument
+	}{
+		{
+			`{"assetType":"EQUITY","symbol":"AAPL","cusip":"037833100","description":"Apple Inc. - Common Stock","exchange":"NASDAQ"}`,
+			&EquityInstrument{BaseInstrument{AssetTypeName: "EQUITY", SymbolName: "AAPL", Cusip: "037833100", Description: "Apple Inc. - Common Stock", Exchange: "NASDAQ"}},
+		},
+		{
+			`{"assetType":"ETF","symbol":"SPY","cusip":"78462F103","description":"SPDR S&P 500","exchange":"Pacific"}`,
+			&ETFInstrument{BaseInstrument{AssetTypeName: "ETF", SymbolName: "SPY", Cusip: "78462F103", Description: "SPDR S&P 500", Exchange: "Pacific"}},
+		},
+		{
+			`{"assetType":"OPTION","symbol":"SPY_071720C310","cusip":"0SPY..GH00310000","type":"VANILLA","putCall":"CALL","underlyingSymbol":"SPY","optionMultiplier":100,"optionDeliverables":[{"symbol":"SPY","deliverableUnits":100,"currencyType":"USD","assetType":"EQUITY"}]}`,
+			&OptionInstrument{
+				BaseInstrument:     BaseInstrument{AssetTypeName: "OPTION", SymbolName: "SPY_071720C310", Cusip: "0SPY..GH00310000"},
+				Type:               "VANILLA",
+				PutCall:            "CALL",
+				UnderlyingSymbol:   "SPY",
+				OptionMultiplier:   100,
+				OptionDeliverables: []OptionDeliverable{{Symbol: "SPY", DeliverableUnits: 100, CurrencyType: "USD", AssetType: "EQUITY"}},
+			},
+		},
+		{
+			`{"assetType":"MUTUAL_FUND","symbol":"VFIAX","description":"Vanguard 500 Index Admiral","type":"NO_LOAD_TAXABLE"}`,
+			&MutualFundInstrument{BaseInstrument: BaseInstrument{AssetTypeName: "MUTUAL_FUND", SymbolName: "VFIAX", Description: "Vanguard 500 Index Admiral"}, Type: "NO_LOAD_TAXABLE"},
+		},
+		{
+			`{"assetType":"BOND","symbol":"912828YV6","cusip":"912828YV6","description":"US Treasury Note 1.5% 11/30/2024","bondPrice":104.5}`,
+			&BondInstrument{BaseInstrument: BaseInstrument{AssetTypeName: "BOND", SymbolName: "912828YV6", Cusip: "912828YV6", Description: "US Treasury Note 1.5% 11/30/2024"}, BondPrice: 104.5},
+		},
+		{
+			`{"assetType":"FIXED_INCOME","symbol":"912828YV6","maturityDate":"2024-11-30T00:00:00+0000","variableRate":1.5,"factor":1}`,
+			&BondInstrument{BaseInstrument: BaseInstrument{AssetTypeName: "FIXED_INCOME", SymbolName: "912828YV6"}, MaturityDate: "2024-11-30T00:00:00+0000", VariableRate: 1.5, Factor: 1},
+		},
+		{
+			`{"assetType":"FOREX","symbol":"EUR/USD","description":"Euro/USDollar Spot","exchange":"GFT"}`,
+			&ForexInstrument{BaseInstrument{AssetTypeName: "FOREX", SymbolName: "EUR/USD", Description: "Euro/USDollar Spot", Exchange: "GFT"}},
+		},
+		{
+			`{"assetType":"FUTURE","symbol":"/ESU20","description":"E-mini S&P 500 Index Futures,Sep-2020,ETH","futureMultiplier":50,"futureExpirationDate":1600401600000,"futureActiveSymbol":"/ESU20"}`,
+			&FutureInstrument{BaseInstrument: BaseInstrument{AssetTypeName: "FUTURE", SymbolName: "/ESU20", Description: "E-mini S&P 500 Index Futures,Sep-2020,ETH"}, FutureMultiplier: 50, FutureExpirationDate: 1600401600000, FutureActiveSymbol: "/ESU20"},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := UnmarshalInstrument([]byte(tt.json))
+		if err != nil {
+			t.Errorf("UnmarshalInstrument(%s) returned error: %v", tt.json, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("UnmarshalInstrument(%s) = %+v, want %+v", tt.json, got, tt.want)
+		}
+		if got.Symbol() != tt.want.Symbol() || got.AssetType() != tt.want.AssetType() {
+			t.Errorf("unexpected symbol or asset type: %s %s", got.Symbol(), got.AssetType())
+		}
+
+		// Encoding and decoding again gives the same instrument.
+		b, err := json.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again, err := UnmarshalInstrument(b); err != nil || !reflect.DeepEqual(again, got) {
+			t.Errorf("round trip of %s = %+v, %v", b, again, err)
+		}
+	}
+
+	if _, err := UnmarshalInstrument([]byte(`{"assetType":"INDEX","symbol":"$SPX.X"}`)); err == nil {
+		t.Error("expected an error for an unsupported asset type")
+	}
+	if _, err := UnmarshalInstrument([]byte(`not json`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestInstrumentTyped(t *testing.T) {
+	instrument := Instrument{AssetType: "OPTION", Data: &OptionA{Symbol: "SPY_071720C310", PutCall: "CALL", UnderlyingSymbol: "SPY"}}
+	typed, err := instrument.Typed()
+	if err != nil {
+		t.Fatalf("Typed returned error: %v", err)
+	}
+	option, ok := typed.(*OptionInstrument)
+	if !ok || option.Symbol() != "SPY_071720C310" || option.PutCall != "CALL" || option.UnderlyingSymbol != "SPY" {
+		t.Errorf("unexpected typed instrument: %#v", typed)
+	}
+}
@@ -3,55 +3,98 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"sync"
+	"time"
 )
 
-// InstrumentService handles communication with the marketdata related methods of
+// maxSearchConcurrency is the most concurrent requests SearchBatch makes, to stay within TD Ameritrade's rate limit.
+const maxSearchConcurrency = 5
+
+// Projections accepted by SearchInstruments.
+const (
+	// ProjectionSymbolSearch returns the instrument whose symbol matches exactly.
+	ProjectionSymbolSearch = "symbol-search"
+	// ProjectionSymbolRegex returns instruments whose symbols match a regular expression.
+	ProjectionSymbolRegex = "symbol-regex"
+	// ProjectionDescSearch returns instruments whose descriptions contain a keyword.
+	ProjectionDescSearch = "desc-search"
+	// ProjectionDescRegex returns instruments whose descriptions match a regular expression.
+	ProjectionDescRegex = "desc-regex"
+	// ProjectionFundamental returns the instrument whose symbol matches exactly along with its fundamental data.
+	ProjectionFundamental = "fundamental"
+)
+
+var validProjections = []string{
+	ProjectionSymbolSearch,
+	ProjectionSymbolRegex,
+	ProjectionDescSearch,
+	ProjectionDescRegex,
+	ProjectionFundamental,
+}
+
+// InstrumentsService handles communication with the marketdata related methods of
 // the TDAmeritrade API.
 //
 // TDAmeritrade API docs: https://developer.tdameritrade.com/instruments/apis
-type InstrumentService struct {
+type InstrumentsService struct {
 	client *Client
 }
 
 type Instruments map[string]*InstrumentInfo
 
 type InstrumentInfo struct {
-	Cusip       string `json:"cusip,omitempty"`
-	Symbol      string `json:"symbol"`
-	Description string `json:"description,omitempty"`
-	Type        string `json:"assetType"` //"'NOT_APPLICABLE' or 'OPEN_END_NON_TAXABLE' or 'OPEN_END_TAXABLE' or 'NO_LOAD_NON_TAXABLE' or 'NO_LOAD_TAXABLE'"
-	Exchange    string `json:"exchange"`
+	Cusip       string        `json:"cusip,omitempty"`
+	Symbol      string        `json:"symbol"`
+	Description string        `json:"description,omitempty"`
+	Type        string        `json:"assetType"` //"'NOT_APPLICABLE' or 'OPEN_END_NON_TAXABLE' or 'OPEN_END_TAXABLE' or 'NO_LOAD_NON_TAXABLE' or 'NO_LOAD_TAXABLE'"
+	Exchange    string        `json:"exchange"`
+	Fundamental *Fundamentals `json:"fundamental,omitempty"`
 }
 
-func (s *InstrumentService) GetInstrument(ctx context.Context, cusip string) (*Instruments, *Response, error) {
+// GetInstrument returns the instrument identified by a CUSIP.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/instruments/apis/get/instruments/%7Bcusip%7D
+func (s *InstrumentsService) GetInstrument(ctx context.Context, cusip string) (*InstrumentInfo, *Response, error) {
 	if cusip == "" {
 		return nil, nil, fmt.Errorf("no cusip present")
 	}
-	u := fmt.Sprintf("instruments/%s", cusip)
+	u := fmt.Sprintf("instruments/%s", url.PathEscape(cusip))
 	req, err := s.client.NewRequest("GET", u, nil)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	instruments := new(Instruments)
-
-	resp, err := s.client.Do(ctx, req, instruments)
+	var instruments []*InstrumentInfo
+	resp, err := s.client.Do(ctx, req, &instruments)
 	if err != nil {
 		return nil, resp, err
 	}
-	return nil, nil, nil
+	if len(instruments) == 0 {
+		return nil, resp, fmt.Errorf("no instrument found for cusip %s", cusip)
+	}
+	return instruments[0], resp, nil
 }
 
-func (s *InstrumentService) SearchInstruments(ctx context.Context, symbol, projection string) (*Instruments, *Response, error) {
-	u := fmt.Sprintf("instruments")
+// SearchInstruments returns the instruments matching symbol, keyed by symbol.
+// projection must be one of the Projection constants and defaults to ProjectionSymbolSearch when empty.
+// An unknown projection returns an error wrapping ErrInvalidParams without calling TD Ameritrade.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/instruments/apis/get/instruments
+func (s *InstrumentsService) SearchInstruments(ctx context.Context, symbol, projection string) (Instruments, *Response, error) {
 	if symbol == "" {
 		return nil, nil, fmt.Errorf("no symbol present")
 	}
 	if projection == "" {
-		projection = "symbol-search"
+		projection = ProjectionSymbolSearch
 	}
-	u = fmt.Sprintf("%s?symbol=%s&projection=%s", u, symbol, projection)
+	if !contains(projection, validProjections) {
+		return nil, nil, fmt.Errorf("%w: projection must be one of %v, got %q", ErrInvalidParams, validProjections, projection)
+	}
+
+	q := url.Values{}
+	q.Set("symbol", symbol)
+	q.Set("projection", projection)
+	u := fmt.Sprintf("instruments?%s", q.Encode())
 
 	req, err := s.client.NewRequest("GET", u, nil)
 
@@ -59,12 +102,93 @@ func (s *InstrumentService) SearchInstruments(ctx context.Context, symbol, proje
 		return nil, nil, err
 	}
 
-	instruments := new(Instruments)
+	instruments := Instruments{}
 
-	resp, err := s.client.Do(ctx, req, instruments)
+	resp, err := s.client.Do(ctx, req, &instruments)
 	if err != nil {
 		return nil, resp, err
 	}
 
 	return instruments, resp, nil
 }
+
+// SearchBatch searches for each of symbols with projection, making up to concurrency requests at a time,
+// and returns the instruments found keyed by symbol.
+// concurrency is clamped between 1 and 5. A failed search does not stop the others; its error is returned
+// along with the instruments that were found.
+func (s *InstrumentsService) SearchBatch(ctx context.Context, symbols []string, projection string, concurrency int) (map[string]*InstrumentInfo, []error) {
+	return s.SearchBatchWithRateLimit(ctx, symbols, projection, concurrency, 0)
+}
+
+// SearchBatchWithRateLimit is like SearchBatch but starts requests at least delay apart.
+func (s *InstrumentsService) SearchBatchWithRateLimit(ctx context.Context, symbols []string, projection string, concurrency int, delay time.Duration) (map[string]*InstrumentInfo, []error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > maxSearchConcurrency {
+		concurrency = maxSearchConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		found   = map[string]*InstrumentInfo{}
+		errs    []error
+		next    = time.Now()
+		pending = make(chan string)
+		wg      sync.WaitGroup
+	)
+
+	// wait blocks until the next request may start.
+	wait := func() error {
+		if delay <= 0 {
+			return nil
+		}
+		mu.Lock()
+		start := next
+		if now := time.Now(); start.Before(now) {
+			start = now
+		}
+		next = start.Add(delay)
+		mu.Unlock()
+
+		timer := time.NewTimer(time.Until(start))
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range pending {
+				var instruments Instruments
+				err := wait()
+				if err == nil {
+					instruments, _, err = s.SearchInstruments(ctx, symbol, projection)
+				}
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("searching for %s: %w", symbol, err))
+				}
+				for k, v := range instruments {
+					found[k] = v
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, symbol := range symbols {
+		pending <- symbol
+	}
+	close(pending)
+	wg.Wait()
+
+	return found, errs
+}
@@ -0,0 +1,133 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSearchInstrumentsFundamental(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/instruments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "symbol", "AAPL")
+		testFormValue(t, r, "projection", "fundamental")
+		fmt.Fprint(w, `{"AAPL":{"cusip":"037833100","symbol":"AAPL","assetType":"EQUITY","exchange":"NASDAQ",
+			"fundamental":{"symbol":"AAPL","high52":372.38,"low52":192.58,"peRatio":28.7,"dividendYield":0.9,"marketCap":1530000,
+			"dividendDate":"2020-05-08 00:00:00.000"}}}`)
+	})
+
+	instruments, _, err := client.Instrument.SearchInstruments(context.Background(), "AAPL", ProjectionFundamental)
+	if err != nil {
+		t.Fatalf("SearchInstruments returned error: %v", err)
+	}
+
+	aapl, ok := instruments["AAPL"]
+	if !ok || aapl.Cusip != "037833100" {
+		t.Fatalf("unexpected instruments: %+v", instruments)
+	}
+	if aapl.Fundamental == nil || aapl.Fundamental.High52 != 372.38 || aapl.Fundamental.PeRatio != 28.7 || aapl.Fundamental.MarketCap != 1530000 {
+		t.Errorf("unexpected fundamental: %+v", aapl.Fundamental)
+	}
+	if want := time.Date(2020, 5, 8, 0, 0, 0, 0, time.UTC); !aapl.Fundamental.DividendDate.Equal(want) {
+		t.Errorf("DividendDate = %v, want %v", aapl.Fundamental.DividendDate, want)
+	}
+}
+
+func TestSearchInstrumentsInvalidProjection(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/instruments", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request made despite invalid projection")
+	})
+
+	_, _, err := client.Instrument.SearchInstruments(context.Background(), "AAPL", "fundamentals")
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+}
+
+func TestGetInstrument(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/instruments/037833100", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"cusip":"037833100","symbol":"AAPL","assetType":"EQUITY"}]`)
+	})
+
+	instrument, _, err := client.Instrument.GetInstrument(context.Background(), "037833100")
+	if err != nil {
+		t.Fatalf("GetInstrument returned error: %v", err)
+	}
+	if instrument.Symbol != "AAPL" {
+		t.Errorf("unexpected instrument: %+v", instrument)
+	}
+}
+
+func TestSearchBatch(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	mux.HandleFunc("/instruments", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		symbol := r.FormValue("symbol")
+		if symbol == "BAD" {
+			http.Error(w, `{"error":"bad symbol"}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"%s":{"symbol":"%s","assetType":"EQUITY"}}`, symbol, symbol)
+	})
+
+	symbols := []string{"AAPL", "MSFT", "BAD", "SPY", "QQQ", "IWM", "DIA", "GLD", "TLT", "XLF"}
+	instruments, errs := client.Instrument.SearchBatch(context.Background(), symbols, "", 10)
+	if len(instruments) != len(symbols)-1 || instruments["SPY"] == nil || instruments["SPY"].Symbol != "SPY" {
+		t.Errorf("unexpected instruments: %v", instruments)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "BAD") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if maxInFlight > maxSearchConcurrency || maxInFlight < 2 {
+		t.Errorf("made %d concurrent requests, want between 2 and %d", maxInFlight, maxSearchConcurrency)
+	}
+}
+
+func TestSearchBatchWithRateLimit(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/instruments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"%s":{"symbol":"%[1]s"}}`, r.FormValue("symbol"))
+	})
+
+	start := time.Now()
+	instruments, errs := client.Instrument.SearchBatchWithRateLimit(context.Background(), []string{"AAPL", "MSFT", "SPY"}, "", 3, 50*time.Millisecond)
+	if len(errs) != 0 || len(instruments) != 3 {
+		t.Fatalf("unexpected results: %v %v", instruments, errs)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("three requests 50ms apart took %v", elapsed)
+	}
+}
@@ -0,0 +1,68 @@
+package tdameritrade
+
+// IVStats is the implied volatility rank and percentile of a reading compared with a window of readings that ends
+// with it, so that the rank is between 0 and 100.
+type IVStats struct {
+	IV         float64
+	Rank       float64
+	Percentile float64
+}
+
+// IVRank returns where currentIV falls between the lowest and highest of historicalIVs, from 0 to 100.
+// It is below 0 or above 100 when currentIV is outside of the historical range,
+// and 0 when there are no historical readings or they are all the same.
+func IVRank(historicalIVs []float64, currentIV float64) float64 {
+	if len(historicalIVs) == 0 {
+		return 0
+	}
+
+	min, max := historicalIVs[0], historicalIVs[0]
+	for _, iv := range historicalIVs[1:] {
+		if iv < min {
+			min = iv
+		}
+		if iv > max {
+			max = iv
+		}
+	}
+	if max == min {
+		return 0
+	}
+	return (currentIV - min) / (max - min) * 100
+}
+
+// IVPercentile returns the percentage of historicalIVs below currentIV, from 0 to 100.
+func IVPercentile(historicalIVs []float64, currentIV float64) float64 {
+	if len(historicalIVs) == 0 {
+		return 0
+	}
+
+	below := 0
+	for _, iv := range historicalIVs {
+		if iv < currentIV {
+			below++
+		}
+	}
+	return float64(below) / float64(len(historicalIVs)) * 100
+}
+
+// RollingIVStats returns the rank and percentile of each reading in ivs over the window readings ending with it,
+// the reading itself included: a new high has a rank of 100, and is not counted as below itself by the percentile.
+// The first window-1 readings do not have enough history, so the result starts with ivs[window-1].
+// It returns nil if window is not between 1 and len(ivs).
+func RollingIVStats(ivs []float64, window int) []IVStats {
+	if window < 1 || window > len(ivs) {
+		return nil
+	}
+
+	stats := make([]IVStats, 0, len(ivs)-window+1)
+	for i := window - 1; i < len(ivs); i++ {
+		history := ivs[i-window+1 : i+1]
+		stats = append(stats, IVStats{
+			IV:         ivs[i],
+			Rank:       IVRank(history, ivs[i]),
+			Percentile: IVPercentile(history, ivs[i]),
+		})
+	}
+	return stats
+}
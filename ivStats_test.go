@@ -0,0 +1,64 @@
+package tdameritrade
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIVRankAndPercentile(t *testing.T) {
+	history := []float64{20, 25, 30, 35, 40}
+
+	tests := []struct {
+		current      float64
+		rank, pctile float64
+	}{
+		{30, 50, 40},
+		{20, 0, 0},
+		{40, 100, 80},
+		{45, 125, 100},
+	}
+	for _, tt := range tests {
+		if got := IVRank(history, tt.current); got != tt.rank {
+			t.Errorf("IVRank(%v) = %v, want %v", tt.current, got, tt.rank)
+		}
+		if got := IVPercentile(history, tt.current); got != tt.pctile {
+			t.Errorf("IVPercentile(%v) = %v, want %v", tt.current, got, tt.pctile)
+		}
+	}
+
+	if got := IVRank([]float64{30, 30}, 30); got != 0 {
+		t.Errorf("IVRank of a flat history = %v, want 0", got)
+	}
+	if got := IVRank(nil, 30); got != 0 {
+		t.Errorf("IVRank of no history = %v, want 0", got)
+	}
+}
+
+func TestRollingIVStats(t *testing.T) {
+	ivs := []float64{20, 30, 25, 40, 10}
+
+	want := []IVStats{
+		{IV: 25, Rank: 50, Percentile: 100.0 / 3},
+		{IV: 40, Rank: 100, Percentile: 200.0 / 3},
+		{IV: 10, Rank: 0, Percentile: 0},
+	}
+	got := RollingIVStats(ivs, 3)
+	if len(got) != len(want) {
+		t.Fatalf("RollingIVStats = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].IV != want[i].IV || math.Abs(got[i].Rank-want[i].Rank) > 1e-9 || math.Abs(got[i].Percentile-want[i].Percentile) > 1e-9 {
+			t.Errorf("RollingIVStats[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// The window includes the current reading: against the previous reading alone, 40 would have a rank of 0 for a
+	// flat history and a percentile of 100.
+	if got, want := RollingIVStats([]float64{20, 30, 40}, 2)[1], (IVStats{IV: 40, Rank: 100, Percentile: 50}); got != want {
+		t.Errorf("RollingIVStats of a new high = %+v, want %+v", got, want)
+	}
+
+	if got := RollingIVStats(ivs, 6); got != nil {
+		t.Errorf("expected nil for a window longer than the readings, got %+v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger logs each request sent by a Client.
+// resp is nil when err is not.
+type Logger interface {
+	Log(req *http.Request, resp *http.Response, duration time.Duration, err error)
+}
+
+// sensitiveParams are query parameters whose values are redacted from logs.
+var sensitiveParams = []string{"apikey", "access_token", "refresh_token", "code", "client_id"}
+
+// NewStructuredLogger returns a Logger that writes a JSON object per request to w:
+// {"method":"GET","path":"/v1/marketdata/SPY/quotes","query":"apikey=REDACTED","status":200,"duration_ms":35,"request_id":"..."}
+// The path is logged without its query string, which is logged separately with sensitive values such as apikey redacted.
+// request_id is the request's X-Request-ID header and error is only set if the request failed.
+func NewStructuredLogger(w io.Writer) Logger {
+	return &structuredLogger{w: w}
+}
+
+type structuredLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+type logEntry struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Query      string `json:"query,omitempty"`
+	Status     int    `json:"status,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+	RequestID  string `json:"request_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (l *structuredLogger) Log(req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	entry := logEntry{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Query:      redactQuery(req.URL.Query()),
+		DurationMS: int64(duration / time.Millisecond),
+		RequestID:  req.Header.Get("X-Request-ID"),
+	}
+	if resp != nil {
+		entry.Status = resp.StatusCode
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	b, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(append(b, '\n'))
+}
+
+func redactQuery(q url.Values) string {
+	for key := range q {
+		if contains(strings.ToLower(key), sensitiveParams) {
+			q.Set(key, "REDACTED")
+		}
+	}
+	return q.Encode()
+}
+
+// NewNoOpLogger returns a Logger that discards everything, for use in tests.
+func NewNoOpLogger() Logger {
+	return noOpLogger{}
+}
+
+type noOpLogger struct{}
+
+func (noOpLogger) Log(*http.Request, *http.Response, time.Duration, error) {}
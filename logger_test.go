@@ -0,0 +1,57 @@
+package tdameritrade
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStructuredLogger(t *testing.T) {
+	var buf bytes.Buffer
+	client, mux, teardown := setup(t, WithLogger(NewStructuredLogger(&buf)))
+
+	mux.HandleFunc("/marketdata/quotes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY"}}`)
+	})
+
+	if _, _, err := client.Quotes.GetQuotesNoAuth(context.Background(), "SECRETKEY", []string{"SPY"}); err != nil {
+		t.Fatalf("GetQuotesNoAuth returned error: %v", err)
+	}
+
+	// Requests that fail are logged too.
+	teardown()
+	if _, _, err := client.Quotes.GetQuote(context.Background(), "SPY"); err == nil {
+		t.Fatal("expected an error after the server was closed")
+	}
+
+	if strings.Contains(buf.String(), "SECRETKEY") {
+		t.Errorf("apikey was logged: %s", buf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %q", buf.String())
+	}
+
+	var ok, failed logEntry
+	if err := json.Unmarshal([]byte(lines[0]), &ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &failed); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok.Method != "GET" || ok.Path != "/marketdata/quotes" || ok.Status != http.StatusOK || ok.Error != "" {
+		t.Errorf("unexpected log entry: %+v", ok)
+	}
+	if !strings.Contains(ok.Query, "apikey=REDACTED") || !strings.Contains(ok.Query, "symbol=SPY") {
+		t.Errorf("unexpected query: %q", ok.Query)
+	}
+	if failed.Path != "/marketdata/SPY/quotes" || failed.Status != 0 || failed.Error == "" {
+		t.Errorf("unexpected log entry for a failed request: %+v", failed)
+	}
+}
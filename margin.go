@@ -0,0 +1,140 @@
+package tdameritrade
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Rates of the Reg-T requirement for naked short options.
+const (
+	// nakedOptionRate is the share of the underlying value required, less the amount the option is out of the money.
+	nakedOptionRate = 0.15
+	// nakedOptionMinimumRate is the share of the underlying value, or of the strike for puts, always required.
+	nakedOptionMinimumRate = 0.10
+)
+
+// OptionPosition is a position in an option of a chain, identified by PutCall, Expiry (e.g. 2020-07-17) and Strike.
+// Quantity is in contracts and negative for short positions.
+// A position with an empty PutCall is a position of Quantity shares of the underlying, used to cover short calls.
+type OptionPosition struct {
+	Symbol   string
+	PutCall  string
+	Strike   float64
+	Expiry   string
+	Quantity int
+}
+
+// MarginEstimate is the estimated requirement and the profit and loss at expiration of a set of positions.
+// MaxRisk and MaxProfit are positive amounts in dollars and +Inf when unlimited.
+type MarginEstimate struct {
+	TotalRequirement float64
+	MaxRisk          float64
+	MaxProfit        float64
+	// BreakEven are the underlying prices at expiration where the positions neither make nor lose money, in ascending order.
+	BreakEven []float64
+}
+
+// EstimateMarginRequirement estimates the Reg-T margin requirement of positions in the options of chain.
+// It is a rough guide for sizing positions, not the requirement TD Ameritrade will apply. It assumes that:
+//   - options are valued at their mark and the underlying at the chain's underlying price;
+//   - long options require their cost;
+//   - short calls covered by shares of the underlying require no margin beyond the premium received;
+//   - other short options are naked and require their premium plus the greater of 15% of the underlying value
+//     less the out of the money amount and 10% of the underlying value, or of the strike for puts. Spreads are not
+//     recognized, so short legs of spreads are treated as naked;
+//   - shares only cover calls and add no requirement of their own;
+//   - all of the positions expire together, for MaxRisk, MaxProfit and BreakEven.
+func EstimateMarginRequirement(positions []OptionPosition, chain *Chains) (*MarginEstimate, error) {
+	if chain == nil {
+		return nil, errors.New("no chain present")
+	}
+	underlying := chain.UnderlyingPrice
+	surfaces := map[string]*GreeksSurface{
+		"CALL": BuildGreeksSurface(chain, "CALL"),
+		"PUT":  BuildGreeksSurface(chain, "PUT"),
+	}
+
+	type leg struct {
+		OptionPosition
+		mark       float64
+		multiplier float64
+	}
+	var legs []leg
+	shares := 0
+	for _, p := range positions {
+		putCall := strings.ToUpper(p.PutCall)
+		if putCall == "" {
+			shares += p.Quantity
+			continue
+		}
+		surface, ok := surfaces[putCall]
+		if !ok {
+			return nil, fmt.Errorf("%w: position %s has putCall %q, must be CALL, PUT or empty for shares", ErrInvalidParams, p.Symbol, p.PutCall)
+		}
+		option := surface.Get(p.Expiry, p.Strike)
+		if option == nil {
+			return nil, fmt.Errorf("no %s expiring %s at strike %v in the chain of %s", putCall, p.Expiry, p.Strike, chain.Symbol)
+		}
+		multiplier := option.Multiplier
+		if multiplier == 0 {
+			multiplier = 100
+		}
+		p.PutCall = putCall
+		legs = append(legs, leg{OptionPosition: p, mark: option.Mark, multiplier: multiplier})
+	}
+
+	estimate := &MarginEstimate{}
+	coveringShares := shares
+	for _, l := range legs {
+		contracts := float64(abs(l.Quantity))
+		if l.Quantity > 0 {
+			estimate.TotalRequirement += contracts * l.mark * l.multiplier
+			continue
+		}
+
+		if l.PutCall == "CALL" && coveringShares > 0 {
+			covered := math.Min(contracts, math.Floor(float64(coveringShares)/l.multiplier))
+			coveringShares -= int(covered * l.multiplier)
+			contracts -= covered
+		}
+		if contracts == 0 {
+			continue
+		}
+
+		outOfMoney := math.Max(l.Strike-underlying, 0)
+		minimum := nakedOptionMinimumRate * underlying
+		if l.PutCall == "PUT" {
+			outOfMoney = math.Max(underlying-l.Strike, 0)
+			minimum = nakedOptionMinimumRate * l.Strike
+		}
+		perShare := l.mark + math.Max(nakedOptionRate*underlying-outOfMoney, minimum)
+		estimate.TotalRequirement += contracts * perShare * l.multiplier
+	}
+
+	p := payoff{slope: float64(shares), kinks: []float64{0}}
+	p.pnl = func(price float64) float64 {
+		total := float64(shares) * (price - underlying)
+		for _, l := range legs {
+			total += float64(l.Quantity) * l.multiplier * (intrinsicValue(l.PutCall, l.Strike, price) - l.mark)
+		}
+		return total
+	}
+	for _, l := range legs {
+		if l.PutCall == "CALL" {
+			p.slope += float64(l.Quantity) * l.multiplier
+		}
+		p.kinks = append(p.kinks, l.Strike)
+	}
+	estimate.MaxProfit, estimate.MaxRisk, estimate.BreakEven = p.stats()
+
+	return estimate, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
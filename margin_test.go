@@ -0,0 +1,94 @@
+package tdameritrade
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEstimateMarginRequirement(t *testing.T) {
+	chain := loadChains(t)
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-6 }
+
+	tests := []struct {
+		name        string
+		positions   []OptionPosition
+		requirement float64
+		maxRisk     float64
+		maxProfit   float64
+		breakEven   []float64
+	}{
+		{
+			name:        "naked put",
+			positions:   []OptionPosition{{Symbol: "SPY_071720P305", PutCall: "PUT", Strike: 305, Expiry: "2020-07-17", Quantity: -1}},
+			requirement: (1.7 + 0.15*310.52 - 5.52) * 100,
+			maxRisk:     (305 - 1.7) * 100,
+			maxProfit:   170,
+			breakEven:   []float64{303.3},
+		},
+		{
+			name: "covered call",
+			positions: []OptionPosition{
+				{Symbol: "SPY", Quantity: 100},
+				{Symbol: "SPY_071720C310", PutCall: "CALL", Strike: 310, Expiry: "2020-07-17", Quantity: -1},
+			},
+			requirement: 0,
+			maxRisk:     31052 - 262,
+			maxProfit:   262 - 52,
+			breakEven:   []float64{307.9},
+		},
+		{
+			name:        "naked call",
+			positions:   []OptionPosition{{Symbol: "SPY_071720C315", PutCall: "CALL", Strike: 315, Expiry: "2020-07-17", Quantity: -2}},
+			requirement: 2 * (1.7 + math.Max(0.15*310.52-4.48, 0.1*310.52)) * 100,
+			maxRisk:     math.Inf(1),
+			maxProfit:   340,
+			breakEven:   []float64{316.7},
+		},
+		{
+			name:        "long call",
+			positions:   []OptionPosition{{Symbol: "SPY_082120C310", PutCall: "call", Strike: 310, Expiry: "2020-08-21", Quantity: 1}},
+			requirement: 602,
+			maxRisk:     602,
+			maxProfit:   math.Inf(1),
+			breakEven:   []float64{316.02},
+		},
+	}
+
+	for _, tt := range tests {
+		estimate, err := EstimateMarginRequirement(tt.positions, chain)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if !near(estimate.TotalRequirement, tt.requirement) {
+			t.Errorf("%s: TotalRequirement = %v, want %v", tt.name, estimate.TotalRequirement, tt.requirement)
+		}
+		if estimate.MaxRisk != tt.maxRisk && !near(estimate.MaxRisk, tt.maxRisk) {
+			t.Errorf("%s: MaxRisk = %v, want %v", tt.name, estimate.MaxRisk, tt.maxRisk)
+		}
+		if estimate.MaxProfit != tt.maxProfit && !near(estimate.MaxProfit, tt.maxProfit) {
+			t.Errorf("%s: MaxProfit = %v, want %v", tt.name, estimate.MaxProfit, tt.maxProfit)
+		}
+		if len(estimate.BreakEven) != len(tt.breakEven) {
+			t.Errorf("%s: BreakEven = %v, want %v", tt.name, estimate.BreakEven, tt.breakEven)
+			continue
+		}
+		for i := range tt.breakEven {
+			if !near(estimate.BreakEven[i], tt.breakEven[i]) {
+				t.Errorf("%s: BreakEven = %v, want %v", tt.name, estimate.BreakEven, tt.breakEven)
+			}
+		}
+	}
+}
+
+func TestEstimateMarginRequirementErrors(t *testing.T) {
+	chain := loadChains(t)
+
+	if _, err := EstimateMarginRequirement([]OptionPosition{{PutCall: "STRADDLE", Quantity: 1}}, chain); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams, got %v", err)
+	}
+	if _, err := EstimateMarginRequirement([]OptionPosition{{PutCall: "CALL", Strike: 400, Expiry: "2020-07-17", Quantity: 1}}, chain); err == nil {
+		t.Error("expected an error for an option missing from the chain")
+	}
+}
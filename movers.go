@@ -3,86 +3,196 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
-	"github.com/google/go-querystring/query"
+	"net/url"
+	"sort"
 )
 
+// Indexes accepted by MoversService.
 const (
-	defaultChangeType    = "percent"
-	defaultDirectionType = "up"
+	IndexCompx = "$COMPX"
+	IndexDJI   = "$DJI"
+	IndexSPX   = "$SPX.X"
 )
 
+// Directions accepted by MoversService.
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// Changes accepted by MoversService, deciding whether movers are ranked by value or percent change.
+const (
+	ChangeValue   = "value"
+	ChangePercent = "percent"
+)
+
+// MoverSortVolume sorts movers by TotalVolume in SortMovers, which also sorts by ChangeValue and ChangePercent.
+const MoverSortVolume = "volume"
+
 var (
-	ChangeTypes    = []string{"value", "percent"}
-	DirectionTypes = []string{"up", "down"}
+	Indexes        = []string{IndexCompx, IndexDJI, IndexSPX}
+	ChangeTypes    = []string{ChangeValue, ChangePercent}
+	DirectionTypes = []string{DirectionUp, DirectionDown}
 )
 
-type MoverService struct {
+// MoversService handles communication with the movers related methods of
+// the TDAmeritrade API.
+//
+// TDAmeritrade API docs: https://developer.tdameritrade.com/movers/apis
+type MoversService struct {
 	client *Client
 }
 
-type MoverOptions struct {
-	Direction  string `url:"direction"`
-	ChangeType string `url:"change"`
-}
-
+// Mover is one of the top ten movers of an index.
+// Change is in the unit the movers were ranked by, dollars for ChangeValue and a fraction of the previous close for ChangePercent.
+// PercentChange is always the change as a fraction of the previous close.
 type Mover struct {
-	Change      float64 `json:"change"`
-	Description string  `json:"description"`
-	Direction   string  `json:"direction"`
-	Last        float64 `json:"last"`
-	TotalVolume float64 `json:"totalVolume"`
-	Symbol      string  `json:"symbol"`
+	Change        float64 `json:"change"`
+	PercentChange float64 `json:"-"`
+	Description   string  `json:"description"`
+	Direction     string  `json:"direction"`
+	Last          float64 `json:"last"`
+	TotalVolume   float64 `json:"totalVolume"`
+	Symbol        string  `json:"symbol"`
 }
 
-func (s *MoverService) Mover(ctx context.Context, symbol string, opts *MoverOptions) (*[]Mover, *Response, error) {
-	u := fmt.Sprintf("marketdata/%s/movers", symbol)
-	if opts != nil {
-		if err := opts.validate(); err != nil {
-			return nil, nil, err
-		}
-
-	} else {
-		opts = &MoverOptions{
-			Direction:  defaultDirectionType,
-			ChangeType: defaultChangeType,
-		}
+// GetMovers returns the top ten movers of index in direction, ranked by change.
+// Empty direction and change default to DirectionUp and ChangePercent.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/movers/apis/get/marketdata/%7Bindex%7D/movers
+func (s *MoversService) GetMovers(ctx context.Context, index, direction, change string) ([]*Mover, *Response, error) {
+	if direction == "" {
+		direction = DirectionUp
 	}
-	q, err := query.Values(opts)
-	if err != nil {
-		return nil, nil, err
+	if change == "" {
+		change = ChangePercent
+	}
+	if !contains(index, Indexes) {
+		return nil, nil, fmt.Errorf("%w: index must be one of %v, got %q", ErrInvalidParams, Indexes, index)
+	}
+	if !contains(direction, DirectionTypes) {
+		return nil, nil, fmt.Errorf("%w: direction must be one of %v, got %q", ErrInvalidParams, DirectionTypes, direction)
+	}
+	if !contains(change, ChangeTypes) {
+		return nil, nil, fmt.Errorf("%w: change must be one of %v, got %q", ErrInvalidParams, ChangeTypes, change)
 	}
-	u = fmt.Sprintf("%s?%s", u, q.Encode())
+
+	q := url.Values{}
+	q.Set("direction", direction)
+	q.Set("change", change)
+	u := fmt.Sprintf("marketdata/%s/movers?%s", url.PathEscape(index), q.Encode())
 
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	movers := new([]Mover)
-	resp, err := s.client.Do(ctx, req, movers)
+	var movers []*Mover
+	resp, err := s.client.Do(ctx, req, &movers)
 	if err != nil {
 		return nil, resp, err
 	}
 
+	for _, m := range movers {
+		if change == ChangePercent {
+			m.PercentChange = m.Change
+		} else if previousClose := m.Last - m.Change; previousClose != 0 {
+			m.PercentChange = m.Change / previousClose
+		}
+	}
+
 	return movers, resp, nil
 }
 
-func (opts *MoverOptions) validate() error {
-	if opts.ChangeType != "" {
-		if !contains(opts.ChangeType, ChangeTypes) {
-			return fmt.Errorf("invalid changeType, must have the value of one of the following %v", ChangeTypes)
-		}
-	} else {
-		opts.ChangeType = defaultChangeType
+// GetTopMovers returns the up and down movers of index by percent change.
+// Both lists are requested concurrently.
+func (s *MoversService) GetTopMovers(ctx context.Context, index string) ([]*Mover, []*Mover, error) {
+	type result struct {
+		movers []*Mover
+		err    error
 	}
 
-	if opts.Direction != "" {
-		if !contains(opts.Direction, DirectionTypes) {
-			return fmt.Errorf("invalid direction, must have the value of one of the following %v", DirectionTypes)
+	fetch := func(direction string) <-chan result {
+		ch := make(chan result, 1)
+		go func() {
+			movers, _, err := s.GetMovers(ctx, index, direction, ChangePercent)
+			ch <- result{movers, err}
+		}()
+		return ch
+	}
+
+	upCh, downCh := fetch(DirectionUp), fetch(DirectionDown)
+	up, down := <-upCh, <-downCh
+	if up.err != nil {
+		return nil, nil, up.err
+	}
+	if down.err != nil {
+		return nil, nil, down.err
+	}
+
+	return up.movers, down.movers, nil
+}
+
+// GetTopN returns the first n movers of index in direction by percent change.
+// TD Ameritrade returns at most ten movers and cannot page through more, so n above ten returns the ten there are.
+func (s *MoversService) GetTopN(ctx context.Context, index, direction string, n int) ([]*Mover, *Response, error) {
+	if n < 1 {
+		return nil, nil, fmt.Errorf("%w: n must be positive, got %d", ErrInvalidParams, n)
+	}
+	movers, resp, err := s.GetMovers(ctx, index, direction, ChangePercent)
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(movers) > n {
+		movers = movers[:n]
+	}
+	return movers, resp, nil
+}
+
+// SortMovers returns a copy of movers sorted by by, ChangePercent, ChangeValue or MoverSortVolume, in ascending
+// or descending order. Movers that compare equal keep their order. The value change is in dollars, computed
+// from Last and PercentChange, so movers ranked either way sort together.
+// An unknown by returns the copy in the original order.
+func SortMovers(movers []*Mover, by string, ascending bool) []*Mover {
+	sorted := make([]*Mover, len(movers))
+	copy(sorted, movers)
+
+	var key func(*Mover) float64
+	switch by {
+	case ChangePercent:
+		key = func(m *Mover) float64 { return m.PercentChange }
+	case ChangeValue:
+		key = func(m *Mover) float64 { return m.valueChange() }
+	case MoverSortVolume:
+		key = func(m *Mover) float64 { return m.TotalVolume }
+	default:
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ascending {
+			return key(sorted[i]) < key(sorted[j])
 		}
-	} else {
-		opts.ChangeType = defaultDirectionType
+		return key(sorted[i]) > key(sorted[j])
+	})
+	return sorted
+}
+
+// FilterMovers returns the movers last traded between minPrice and maxPrice with a volume of at least minVolume.
+// A maxPrice of 0 leaves the price unbounded above.
+func FilterMovers(movers []*Mover, minPrice, maxPrice, minVolume float64) []*Mover {
+	var filtered []*Mover
+	for _, m := range movers {
+		if m.Last < minPrice || (maxPrice != 0 && m.Last > maxPrice) || m.TotalVolume < minVolume {
+			continue
+		}
+		filtered = append(filtered, m)
 	}
+	return filtered
+}
 
-	return nil
+// valueChange returns the change of m in dollars, or 0 if it fell to nothing and its previous close is unknown.
+func (m *Mover) valueChange() float64 {
+	if m.PercentChange == -1 {
+		return 0
+	}
+	return m.Last * m.PercentChange / (1 + m.PercentChange)
 }
@@ -0,0 +1,146 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestGetMovers(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/$SPX.X/movers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "direction", "down")
+		testFormValue(t, r, "change", "value")
+		fmt.Fprint(w, `[{"change":-5,"description":"APPLE INC","direction":"down","last":95,"symbol":"AAPL","totalVolume":1000000}]`)
+	})
+
+	movers, _, err := client.Mover.GetMovers(context.Background(), IndexSPX, DirectionDown, ChangeValue)
+	if err != nil {
+		t.Fatalf("GetMovers returned error: %v", err)
+	}
+
+	if len(movers) != 1 {
+		t.Fatalf("expected 1 mover, got %d", len(movers))
+	}
+	m := movers[0]
+	if m.Symbol != "AAPL" || m.Description != "APPLE INC" || m.Change != -5 || m.Last != 95 || m.TotalVolume != 1000000 {
+		t.Errorf("unexpected mover: %+v", m)
+	}
+	if math.Abs(m.PercentChange-(-0.05)) > 1e-9 {
+		t.Errorf("PercentChange = %v, want -0.05", m.PercentChange)
+	}
+}
+
+func TestGetMoversInvalidParams(t *testing.T) {
+	client, _, teardown := setup(t)
+	defer teardown()
+
+	if _, _, err := client.Mover.GetMovers(context.Background(), "SPY", DirectionUp, ChangePercent); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for index, got %v", err)
+	}
+	if _, _, err := client.Mover.GetMovers(context.Background(), IndexDJI, "sideways", ChangePercent); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for direction, got %v", err)
+	}
+}
+
+func TestGetTopMovers(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/$COMPX/movers", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "change", "percent")
+		fmt.Fprintf(w, `[{"change":0.1,"direction":"%s","symbol":"%s"}]`, r.FormValue("direction"), r.FormValue("direction"))
+	})
+
+	up, down, err := client.Mover.GetTopMovers(context.Background(), IndexCompx)
+	if err != nil {
+		t.Fatalf("GetTopMovers returned error: %v", err)
+	}
+	if len(up) != 1 || up[0].Direction != "up" || up[0].PercentChange != 0.1 {
+		t.Errorf("unexpected up movers: %+v", up)
+	}
+	if len(down) != 1 || down[0].Direction != "down" {
+		t.Errorf("unexpected down movers: %+v", down)
+	}
+}
+
+func TestGetTopN(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/$DJI/movers", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "direction", "up")
+		testFormValue(t, r, "change", "percent")
+		fmt.Fprint(w, `[{"change":0.05,"symbol":"AAPL"},{"change":0.04,"symbol":"MSFT"},{"change":0.03,"symbol":"IBM"}]`)
+	})
+
+	movers, _, err := client.Mover.GetTopN(context.Background(), IndexDJI, DirectionUp, 2)
+	if err != nil {
+		t.Fatalf("GetTopN returned error: %v", err)
+	}
+	if len(movers) != 2 || movers[0].Symbol != "AAPL" || movers[1].Symbol != "MSFT" {
+		t.Errorf("unexpected movers: %+v", movers)
+	}
+
+	movers, _, err = client.Mover.GetTopN(context.Background(), IndexDJI, DirectionUp, 20)
+	if err != nil || len(movers) != 3 {
+		t.Errorf("expected all 3 movers, got %d and error %v", len(movers), err)
+	}
+
+	if _, _, err := client.Mover.GetTopN(context.Background(), IndexDJI, DirectionUp, 0); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams, got %v", err)
+	}
+}
+
+func TestSortMovers(t *testing.T) {
+	movers := []*Mover{
+		// A $2 rise from $100.
+		{Symbol: "A", Last: 102, PercentChange: 0.02, TotalVolume: 300},
+		// A $4 rise from $20.
+		{Symbol: "B", Last: 24, PercentChange: 0.2, TotalVolume: 100},
+		// A $5 rise from $500.
+		{Symbol: "C", Last: 505, PercentChange: 0.01, TotalVolume: 200},
+		// A $1 fall from $50.
+		{Symbol: "D", Last: 49, PercentChange: -0.02, TotalVolume: 400},
+	}
+	symbols := func(movers []*Mover) string {
+		s := ""
+		for _, m := range movers {
+			s += m.Symbol
+		}
+		return s
+	}
+
+	tests := []struct {
+		by        string
+		ascending bool
+		want      string
+	}{
+		{ChangePercent, false, "BACD"},
+		{ChangePercent, true, "DCAB"},
+		{ChangeValue, false, "CBAD"},
+		{MoverSortVolume, true, "BCAD"},
+		{"name", true, "ABCD"},
+	}
+	for _, tt := range tests {
+		if got := symbols(SortMovers(movers, tt.by, tt.ascending)); got != tt.want {
+			t.Errorf("SortMovers(%q, %v) = %s, want %s", tt.by, tt.ascending, got, tt.want)
+		}
+	}
+	if got := symbols(movers); got != "ABCD" {
+		t.Errorf("movers were reordered: %s", got)
+	}
+
+	if got := symbols(FilterMovers(movers, 30, 200, 150)); got != "AD" {
+		t.Errorf("FilterMovers = %s, want AD", got)
+	}
+	if got := symbols(FilterMovers(movers, 30, 0, 0)); got != "ACD" {
+		t.Errorf("FilterMovers without a maximum price = %s, want ACD", got)
+	}
+}
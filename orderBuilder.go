@@ -0,0 +1,401 @@
+package tdameritrade
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderBuilder constructs orders with method chaining.
+// Orders default to equities in the NORMAL session with a DAY duration.
+// Build validates the order so mistakes are caught before they are sent to TD Ameritrade.
+//
+// Usage example:
+// order, err := tdameritrade.NewOrderBuilder().Buy(10, "SPY").AsLimitOrder(310.25).GoodTillCanceled().Build()
+//
+// Orders of a single option say whether they open or close a position:
+// order, err := tdameritrade.NewOrderBuilder().Sell(1, "SPY").ForOption("SPY_071720C310").ToOpen().AsLimitOrder(2.5).Build()
+//
+// Spreads are built from option legs, e.g. a bull call spread:
+// order, err := tdameritrade.NewOrderBuilder().AddOptionLeg("BUY_TO_OPEN", "SPY_071720C305", 1).AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 1).AsNetDebitOrder(4.6).Build()
+type OrderBuilder struct {
+	instruction string
+	quantity    int
+	symbol      string
+	assetType   AssetType
+	orderType   OrderType
+	price       float64
+	stopPrice   float64
+	duration    Duration
+	session     Session
+	// positionEffect is OPEN or CLOSE for options, set by ToOpen and ToClose.
+	positionEffect string
+
+	// legs are the option legs added by AddOptionLeg, after the leg set by Buy or Sell if any.
+	legs []builderLeg
+}
+
+type builderLeg struct {
+	instruction string
+	symbol      string
+	quantity    int
+}
+
+// optionInstructions are the instructions of option legs.
+var optionInstructions = []string{"BUY_TO_OPEN", "BUY_TO_CLOSE", "SELL_TO_OPEN", "SELL_TO_CLOSE"}
+
+// NewOrderBuilder returns an OrderBuilder with the default asset type, session and duration.
+func NewOrderBuilder() *OrderBuilder {
+	return &OrderBuilder{
+		assetType: "EQUITY",
+		duration:  "DAY",
+		session:   "NORMAL",
+	}
+}
+
+// Buy buys qty of symbol.
+// For options, call ToOpen or ToClose to buy with BUY_TO_OPEN or BUY_TO_CLOSE.
+func (b *OrderBuilder) Buy(qty int, symbol string) *OrderBuilder {
+	b.instruction = "BUY"
+	b.quantity = qty
+	b.symbol = symbol
+	return b
+}
+
+// Sell sells qty of symbol.
+// For options, call ToOpen or ToClose to sell with SELL_TO_OPEN or SELL_TO_CLOSE.
+func (b *OrderBuilder) Sell(qty int, symbol string) *OrderBuilder {
+	b.instruction = "SELL"
+	b.quantity = qty
+	b.symbol = symbol
+	return b
+}
+
+// AsMarketOrder fills the order at the best available price.
+func (b *OrderBuilder) AsMarketOrder() *OrderBuilder {
+	b.orderType = "MARKET"
+	return b
+}
+
+// AsLimitOrder fills the order at price or better.
+func (b *OrderBuilder) AsLimitOrder(price float64) *OrderBuilder {
+	b.orderType = "LIMIT"
+	b.price = price
+	return b
+}
+
+// AsStopOrder sends a market order once stopPrice is reached.
+func (b *OrderBuilder) AsStopOrder(stopPrice float64) *OrderBuilder {
+	b.orderType = "STOP"
+	b.stopPrice = stopPrice
+	return b
+}
+
+// AddOptionLeg adds a leg of qty contracts of the option symbol, e.g. SPY_071720C310, to the order.
+// instruction is one of BUY_TO_OPEN, BUY_TO_CLOSE, SELL_TO_OPEN and SELL_TO_CLOSE.
+// All of the legs of an order must be options, so it cannot be combined with an equity leg set by Buy or Sell.
+func (b *OrderBuilder) AddOptionLeg(instruction, symbol string, qty int) *OrderBuilder {
+	b.legs = append(b.legs, builderLeg{instruction: instruction, symbol: symbol, quantity: qty})
+	return b
+}
+
+// AsNetDebitOrder fills a multi-leg order for a net debit of price or less, per share.
+func (b *OrderBuilder) AsNetDebitOrder(price float64) *OrderBuilder {
+	b.orderType = "NET_DEBIT"
+	b.price = price
+	return b
+}
+
+// AsNetCreditOrder fills a multi-leg order for a net credit of price or more, per share.
+func (b *OrderBuilder) AsNetCreditOrder(price float64) *OrderBuilder {
+	b.orderType = "NET_CREDIT"
+	b.price = price
+	return b
+}
+
+// AsMarketSpreadOrder fills a multi-leg order at the best available net price.
+func (b *OrderBuilder) AsMarketSpreadOrder() *OrderBuilder {
+	b.orderType = "MARKET"
+	return b
+}
+
+// GoodTillCanceled keeps the order open until it is filled or canceled.
+func (b *OrderBuilder) GoodTillCanceled() *OrderBuilder {
+	b.duration = "GOOD_TILL_CANCEL"
+	return b
+}
+
+// DayOrder cancels the order if it has not been filled by the end of the session.
+func (b *OrderBuilder) DayOrder() *OrderBuilder {
+	b.duration = "DAY"
+	return b
+}
+
+// ForEquity trades the symbol passed to Buy or Sell as an equity.
+func (b *OrderBuilder) ForEquity() *OrderBuilder {
+	b.assetType = "EQUITY"
+	return b
+}
+
+// ForOption trades the option contract symbol, e.g. SPY_071720C310, instead of the symbol passed to Buy or Sell.
+func (b *OrderBuilder) ForOption(symbol string) *OrderBuilder {
+	b.assetType = "OPTION"
+	b.symbol = symbol
+	return b
+}
+
+// ToOpen makes the option order set by Buy or Sell open a position, with BUY_TO_OPEN or SELL_TO_OPEN.
+func (b *OrderBuilder) ToOpen() *OrderBuilder {
+	b.positionEffect = "OPEN"
+	return b
+}
+
+// ToClose makes the option order set by Buy or Sell close a position, with BUY_TO_CLOSE or SELL_TO_CLOSE.
+func (b *OrderBuilder) ToClose() *OrderBuilder {
+	b.positionEffect = "CLOSE"
+	return b
+}
+
+// Build validates the order and returns it.
+func (b *OrderBuilder) Build() (*Order, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	return b.order(), nil
+}
+
+func (b *OrderBuilder) order() *Order {
+	var legs []*OrderLeg
+	if b.instruction != "" {
+		leg := &OrderLeg{
+			Instruction: b.instruction,
+			Quantity:    float64(b.quantity),
+		}
+		switch b.assetType {
+		case "OPTION":
+			leg.Instruction = b.instruction + "_TO_" + b.positionEffect
+			leg.Instrument = Instrument{AssetType: b.assetType, Data: &OptionA{Symbol: b.symbol}}
+		default:
+			leg.Instrument = Instrument{AssetType: b.assetType, Data: &Equity{Symbol: b.symbol}}
+		}
+		legs = append(legs, leg)
+	}
+	for _, l := range b.legs {
+		legs = append(legs, &OrderLeg{
+			Instruction: l.instruction,
+			Quantity:    float64(l.quantity),
+			Instrument:  Instrument{AssetType: "OPTION", Data: &OptionA{Symbol: l.symbol}},
+		})
+	}
+
+	order := &Order{
+		Session:            b.session,
+		Duration:           b.duration,
+		OrderType:          b.orderType,
+		OrderStrategyType:  "SINGLE",
+		OrderLegCollection: legs,
+	}
+	switch b.orderType {
+	case "LIMIT", "NET_DEBIT", "NET_CREDIT":
+		order.Price = decimal.NewFromFloat(b.price)
+	case "STOP":
+		order.StopPrice = b.stopPrice
+	}
+
+	return order
+}
+
+func (b *OrderBuilder) validate() error {
+	if b.instruction == "" && len(b.legs) == 0 {
+		return errors.New("order has no instruction, call Buy, Sell or AddOptionLeg")
+	}
+	if b.instruction != "" {
+		if b.symbol == "" {
+			return errors.New("order has no symbol")
+		}
+		if b.quantity <= 0 {
+			return fmt.Errorf("order quantity must be positive, got %d", b.quantity)
+		}
+		if len(b.legs) > 0 && b.assetType != "OPTION" {
+			return fmt.Errorf("all legs must be options, got an %s leg for %s", b.assetType, b.symbol)
+		}
+		if b.assetType == "OPTION" && b.positionEffect == "" {
+			return fmt.Errorf("option order for %s must open or close a position, call ToOpen or ToClose", b.symbol)
+		}
+	}
+	if b.positionEffect != "" && (b.instruction == "" || b.assetType != "OPTION") {
+		return errors.New("ToOpen and ToClose only apply to options set with Buy or Sell and ForOption")
+	}
+	for _, l := range b.legs {
+		if !contains(l.instruction, optionInstructions) {
+			return fmt.Errorf("option leg instruction must be one of %v, got %q", optionInstructions, l.instruction)
+		}
+		if l.symbol == "" {
+			return errors.New("option leg has no symbol")
+		}
+		if l.quantity <= 0 {
+			return fmt.Errorf("option leg quantity must be positive, got %d for %s", l.quantity, l.symbol)
+		}
+	}
+	multiLeg := len(b.legs) > 1 || len(b.legs) == 1 && b.instruction != ""
+
+	switch b.orderType {
+	case "":
+		return errors.New("order has no type, call AsMarketOrder, AsLimitOrder, AsStopOrder or, for spreads, AsNetDebitOrder, AsNetCreditOrder or AsMarketSpreadOrder")
+	case "MARKET":
+		// TD Ameritrade only accepts market orders for the current session.
+		if b.duration == "GOOD_TILL_CANCEL" {
+			return errors.New("market orders cannot be good till canceled")
+		}
+	case "LIMIT":
+		if b.price <= 0 {
+			return fmt.Errorf("limit price must be positive, got %v", b.price)
+		}
+	case "STOP":
+		if b.stopPrice <= 0 {
+			return fmt.Errorf("stop price must be positive, got %v", b.stopPrice)
+		}
+	case "NET_DEBIT", "NET_CREDIT":
+		if !multiLeg {
+			return fmt.Errorf("%s orders must have more than one leg", b.orderType)
+		}
+		if b.price <= 0 {
+			return fmt.Errorf("net price must be positive, got %v", b.price)
+		}
+	}
+
+	return nil
+}
+
+// The canned constructors below are built with an OrderBuilder, so they return the same errors as Build,
+// e.g. for a quantity or limit price that is not positive.
+
+// NewEquityMarketBuyOrder returns a day order to buy qty shares of symbol at market.
+func NewEquityMarketBuyOrder(symbol string, qty int) (*Order, error) {
+	return NewOrderBuilder().Buy(qty, symbol).AsMarketOrder().Build()
+}
+
+// NewEquityMarketSellOrder returns a day order to sell qty shares of symbol at market.
+func NewEquityMarketSellOrder(symbol string, qty int) (*Order, error) {
+	return NewOrderBuilder().Sell(qty, symbol).AsMarketOrder().Build()
+}
+
+// NewEquityLimitBuyOrder returns a day order to buy qty shares of symbol at price or better.
+func NewEquityLimitBuyOrder(symbol string, qty int, price float64) (*Order, error) {
+	return NewOrderBuilder().Buy(qty, symbol).AsLimitOrder(price).Build()
+}
+
+// NewEquityLimitSellOrder returns a day order to sell qty shares of symbol at price or better.
+func NewEquityLimitSellOrder(symbol string, qty int, price float64) (*Order, error) {
+	return NewOrderBuilder().Sell(qty, symbol).AsLimitOrder(price).Build()
+}
+
+// exitInstructions maps the instruction opening a position to the instruction closing it.
+var exitInstructions = map[string]string{
+	"BUY":          "SELL",
+	"SELL_SHORT":   "BUY_TO_COVER",
+	"BUY_TO_OPEN":  "SELL_TO_CLOSE",
+	"SELL_TO_OPEN": "BUY_TO_CLOSE",
+}
+
+// NewBracketOrder returns entry wrapped in a bracket: once entry fills, it triggers a one-cancels-other pair
+// of orders closing the position, a limit order at targetPrice and a stop order at stopPrice.
+// entry must be a single-leg order opening a position, such as one built by an OrderBuilder; it is not changed.
+// A long bracket, one buying to open, must have stopPrice < entry price < targetPrice; a short bracket
+// must have targetPrice < entry price < stopPrice. Entries without a price, such as market orders, only
+// have the stop and target checked against each other.
+func NewBracketOrder(entry *Order, stopPrice, targetPrice float64) (*Order, error) {
+	if entry == nil {
+		return nil, errors.New("entry order is nil")
+	}
+	if len(entry.OrderLegCollection) != 1 {
+		return nil, fmt.Errorf("entry order must have a single leg, got %d", len(entry.OrderLegCollection))
+	}
+	leg := entry.OrderLegCollection[0]
+	exit, ok := exitInstructions[leg.Instruction]
+	if !ok {
+		return nil, fmt.Errorf("entry order must open a position, got instruction %q", leg.Instruction)
+	}
+	if stopPrice <= 0 || targetPrice <= 0 {
+		return nil, fmt.Errorf("stop and target prices must be positive, got %v and %v", stopPrice, targetPrice)
+	}
+
+	long := leg.Instruction == "BUY" || leg.Instruction == "BUY_TO_OPEN"
+	price, _ := entry.Price.Float64()
+	if entry.Price.IsZero() {
+		price = (stopPrice + targetPrice) / 2
+	}
+	if long && !(stopPrice < price && price < targetPrice) {
+		return nil, fmt.Errorf("long bracket must have stop %v < entry %v < target %v", stopPrice, entry.Price, targetPrice)
+	}
+	if !long && !(targetPrice < price && price < stopPrice) {
+		return nil, fmt.Errorf("short bracket must have target %v < entry %v < stop %v", targetPrice, entry.Price, stopPrice)
+	}
+
+	exitOrder := func(orderType OrderType) *Order {
+		return &Order{
+			Session:           entry.Session,
+			Duration:          entry.Duration,
+			OrderType:         orderType,
+			OrderStrategyType: "SINGLE",
+			OrderLegCollection: []*OrderLeg{{
+				Instruction: exit,
+				Quantity:    leg.Quantity,
+				Instrument:  leg.Instrument,
+			}},
+		}
+	}
+	target := exitOrder("LIMIT")
+	target.Price = decimal.NewFromFloat(targetPrice)
+	stop := exitOrder("STOP")
+	stop.StopPrice = stopPrice
+
+	bracket := *entry
+	bracket.OrderStrategyType = "TRIGGER"
+	bracket.ChildOrderStrategies = []*Order{{
+		OrderStrategyType:    "OCO",
+		ChildOrderStrategies: []*Order{target, stop},
+	}}
+	return &bracket, nil
+}
+
+// trailTypes maps the trail types accepted by NewTrailingStopOrder to TD Ameritrade's stop price link types.
+var trailTypes = map[string]string{
+	"DOLLAR":  "VALUE",
+	"PERCENT": "PERCENT",
+}
+
+// NewTrailingStopOrder returns a day order to sell qty shares of symbol at market once the bid falls trailDelta
+// below its highest price since the order was placed. trailType is DOLLAR for a trailDelta in dollars or
+// PERCENT for a trailDelta in percent of the highest bid.
+func NewTrailingStopOrder(symbol string, qty int, trailDelta float64, trailType string) (*Order, error) {
+	linkType, ok := trailTypes[trailType]
+	if !ok {
+		return nil, fmt.Errorf("trail type must be DOLLAR or PERCENT, got %q", trailType)
+	}
+	if trailDelta <= 0 || trailType == "PERCENT" && trailDelta >= 100 {
+		return nil, fmt.Errorf("trail delta must be positive, and less than 100 for percents, got %v", trailDelta)
+	}
+	if symbol == "" {
+		return nil, errors.New("order has no symbol")
+	}
+	if qty <= 0 {
+		return nil, fmt.Errorf("order quantity must be positive, got %d", qty)
+	}
+
+	return &Order{
+		Session:            "NORMAL",
+		Duration:           "DAY",
+		OrderType:          "TRAILING_STOP",
+		OrderStrategyType:  "SINGLE",
+		StopPriceLinkBasis: "BID",
+		StopPriceLinkType:  linkType,
+		StopPriceOffset:    trailDelta,
+		OrderLegCollection: []*OrderLeg{{
+			Instruction: "SELL",
+			Quantity:    float64(qty),
+			Instrument:  Instrument{AssetType: "EQUITY", Data: &Equity{Symbol: symbol}},
+		}},
+	}, nil
+}
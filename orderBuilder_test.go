@@ -0,0 +1,270 @@
+package tdameritrade
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestOrderBuilder(t *testing.T) {
+	order, err := NewOrderBuilder().Buy(10, "SPY").AsLimitOrder(310.25).GoodTillCanceled().Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	if order.OrderType != "LIMIT" || order.Duration != "GOOD_TILL_CANCEL" || order.Session != "NORMAL" || order.OrderStrategyType != "SINGLE" {
+		t.Errorf("unexpected order: %+v", order)
+	}
+	if !order.Price.Equal(decimal.RequireFromString("310.25")) {
+		t.Errorf("Price = %v, want 310.25", order.Price)
+	}
+	leg := order.OrderLegCollection[0]
+	if leg.Instruction != "BUY" || leg.Quantity != 10 || leg.Instrument.AssetType != "EQUITY" || leg.Instrument.Data.(*Equity).Symbol != "SPY" {
+		t.Errorf("unexpected leg: %+v", leg)
+	}
+
+	order, err = NewOrderBuilder().Sell(1, "SPY").ForOption("SPY_071720C310").ToClose().AsStopOrder(1.5).Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	leg = order.OrderLegCollection[0]
+	if order.StopPrice != 1.5 || leg.Instruction != "SELL_TO_CLOSE" || leg.Instrument.AssetType != "OPTION" || leg.Instrument.Data.(*OptionA).Symbol != "SPY_071720C310" {
+		t.Errorf("unexpected option order: %+v %+v", order, leg)
+	}
+
+	for _, tt := range []struct {
+		b    *OrderBuilder
+		want string
+	}{
+		{NewOrderBuilder().Sell(1, "SPY").ForOption("SPY_071720C310").ToOpen(), "SELL_TO_OPEN"},
+		{NewOrderBuilder().Buy(1, "SPY").ForOption("SPY_071720C310").ToOpen(), "BUY_TO_OPEN"},
+		{NewOrderBuilder().Buy(1, "SPY").ForOption("SPY_071720C310").ToClose(), "BUY_TO_CLOSE"},
+	} {
+		order, err := tt.b.AsLimitOrder(2).Build()
+		if err != nil {
+			t.Fatalf("Build returned error: %v", err)
+		}
+		if got := order.OrderLegCollection[0].Instruction; got != tt.want {
+			t.Errorf("instruction = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+// mustOrder returns a function returning the order of a constructor, failing the test on an error.
+func mustOrder(t *testing.T) func(*Order, error) *Order {
+	return func(order *Order, err error) *Order {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("could not build order: %v", err)
+		}
+		return order
+	}
+}
+
+func TestOrderBuilderErrors(t *testing.T) {
+	tests := map[string]*OrderBuilder{
+		"no instruction":    NewOrderBuilder().AsMarketOrder(),
+		"no order type":     NewOrderBuilder().Buy(1, "SPY"),
+		"zero quantity":     NewOrderBuilder().Buy(0, "SPY").AsMarketOrder(),
+		"no symbol":         NewOrderBuilder().Buy(1, "").AsMarketOrder(),
+		"negative limit":    NewOrderBuilder().Buy(1, "SPY").AsLimitOrder(-1),
+		"missing stop":      NewOrderBuilder().Sell(1, "SPY").AsStopOrder(0),
+		"option market gtc": NewOrderBuilder().Buy(1, "SPY").ForOption("SPY_071720C310").ToOpen().AsMarketOrder().GoodTillCanceled(),
+		"option sell":       NewOrderBuilder().Sell(1, "SPY").ForOption("SPY_071720C310").AsLimitOrder(2),
+		"equity to open":    NewOrderBuilder().Buy(1, "SPY").ToOpen().AsMarketOrder(),
+	}
+
+	for name, b := range tests {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNewEquityMarketBuyOrder(t *testing.T) {
+	order := mustOrder(t)(NewEquityMarketBuyOrder("SPY", 5))
+	leg := order.OrderLegCollection[0]
+	if order.OrderType != "MARKET" || order.Duration != "DAY" || leg.Instruction != "BUY" || leg.Quantity != 5 {
+		t.Errorf("unexpected order: %+v %+v", order, leg)
+	}
+
+	for name, build := range map[string]func() (*Order, error){
+		"zero quantity":  func() (*Order, error) { return NewEquityMarketBuyOrder("SPY", 0) },
+		"negative sell":  func() (*Order, error) { return NewEquityMarketSellOrder("SPY", -5) },
+		"zero limit":     func() (*Order, error) { return NewEquityLimitBuyOrder("SPY", 5, 0) },
+		"negative limit": func() (*Order, error) { return NewEquityLimitSellOrder("SPY", 5, -310) },
+		"no symbol":      func() (*Order, error) { return NewEquityLimitBuyOrder("", 5, 310) },
+	} {
+		if order, err := build(); err == nil {
+			t.Errorf("%s: expected error, got %+v", name, order)
+		}
+	}
+}
+
+func TestNewBracketOrder(t *testing.T) {
+	must := mustOrder(t)
+	entry := must(NewEquityLimitBuyOrder("SPY", 10, 310))
+	bracket, err := NewBracketOrder(entry, 300, 330)
+	if err != nil {
+		t.Fatalf("NewBracketOrder returned error: %v", err)
+	}
+	if bracket.OrderStrategyType != "TRIGGER" || entry.OrderStrategyType != "SINGLE" || len(entry.ChildOrderStrategies) != 0 {
+		t.Errorf("expected a triggering copy of the entry, got %+v from %+v", bracket, entry)
+	}
+	if len(bracket.ChildOrderStrategies) != 1 || bracket.ChildOrderStrategies[0].OrderStrategyType != "OCO" {
+		t.Fatalf("expected a single OCO child, got %+v", bracket.ChildOrderStrategies)
+	}
+	exits := bracket.ChildOrderStrategies[0].ChildOrderStrategies
+	if len(exits) != 2 {
+		t.Fatalf("expected a target and a stop, got %d orders", len(exits))
+	}
+	target, stop := exits[0], exits[1]
+	if target.OrderType != "LIMIT" || !target.Price.Equal(decimal.NewFromInt(330)) || target.OrderLegCollection[0].Instruction != "SELL" {
+		t.Errorf("unexpected target: %+v %+v", target, target.OrderLegCollection[0])
+	}
+	if stop.OrderType != "STOP" || stop.StopPrice != 300 || stop.OrderLegCollection[0].Instruction != "SELL" || stop.OrderLegCollection[0].Quantity != 10 {
+		t.Errorf("unexpected stop: %+v %+v", stop, stop.OrderLegCollection[0])
+	}
+
+	short := must(NewEquityLimitSellOrder("SPY", 10, 310))
+	short.OrderLegCollection[0].Instruction = "SELL_SHORT"
+	bracket, err = NewBracketOrder(short, 320, 290)
+	if err != nil {
+		t.Fatalf("NewBracketOrder returned error for a short bracket: %v", err)
+	}
+	if instruction := bracket.ChildOrderStrategies[0].ChildOrderStrategies[0].OrderLegCollection[0].Instruction; instruction != "BUY_TO_COVER" {
+		t.Errorf("expected the short to be covered, got %s", instruction)
+	}
+
+	option := must(NewOrderBuilder().Buy(1, "SPY").ForOption("SPY_071720C310").ToOpen().AsMarketOrder().Build())
+	bracket, err = NewBracketOrder(option, 1, 4)
+	if err != nil {
+		t.Fatalf("NewBracketOrder returned error for a market entry: %v", err)
+	}
+	if instruction := bracket.ChildOrderStrategies[0].ChildOrderStrategies[1].OrderLegCollection[0].Instruction; instruction != "SELL_TO_CLOSE" {
+		t.Errorf("expected the option to be sold to close, got %s", instruction)
+	}
+}
+
+func TestNewBracketOrderErrors(t *testing.T) {
+	must := mustOrder(t)
+	short := must(NewEquityLimitSellOrder("SPY", 10, 310))
+	short.OrderLegCollection[0].Instruction = "SELL_SHORT"
+
+	tests := map[string]struct {
+		entry             *Order
+		stop, targetPrice float64
+	}{
+		"nil entry":         {nil, 300, 330},
+		"closing entry":     {must(NewEquityLimitSellOrder("SPY", 10, 310)), 300, 330},
+		"long stop above":   {must(NewEquityLimitBuyOrder("SPY", 10, 310)), 315, 330},
+		"long target below": {must(NewEquityLimitBuyOrder("SPY", 10, 310)), 300, 305},
+		"short inverted":    {short, 290, 320},
+		"market inverted":   {must(NewEquityMarketBuyOrder("SPY", 10)), 330, 300},
+		"zero stop":         {must(NewEquityMarketBuyOrder("SPY", 10)), 0, 300},
+	}
+	for name, tt := range tests {
+		if _, err := NewBracketOrder(tt.entry, tt.stop, tt.targetPrice); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNewTrailingStopOrder(t *testing.T) {
+	order, err := NewTrailingStopOrder("SPY", 10, 2.5, "PERCENT")
+	if err != nil {
+		t.Fatalf("NewTrailingStopOrder returned error: %v", err)
+	}
+	leg := order.OrderLegCollection[0]
+	if order.OrderType != "TRAILING_STOP" || order.StopPriceLinkType != "PERCENT" || order.StopPriceLinkBasis != "BID" || order.StopPriceOffset != 2.5 {
+		t.Errorf("unexpected order: %+v", order)
+	}
+	if leg.Instruction != "SELL" || leg.Quantity != 10 || leg.Instrument.Data.(*Equity).Symbol != "SPY" {
+		t.Errorf("unexpected leg: %+v", leg)
+	}
+
+	if order, _ := NewTrailingStopOrder("SPY", 10, 1, "DOLLAR"); order.StopPriceLinkType != "VALUE" {
+		t.Errorf("expected a DOLLAR trail to link by VALUE, got %s", order.StopPriceLinkType)
+	}
+
+	for _, args := range []struct {
+		symbol    string
+		qty       int
+		delta     float64
+		trailType string
+	}{
+		{"SPY", 10, 1, "POINTS"},
+		{"SPY", 10, 0, "DOLLAR"},
+		{"SPY", 10, 100, "PERCENT"},
+		{"SPY", 0, 1, "DOLLAR"},
+		{"", 10, 1, "DOLLAR"},
+	} {
+		if _, err := NewTrailingStopOrder(args.symbol, args.qty, args.delta, args.trailType); err == nil {
+			t.Errorf("%+v: expected error", args)
+		}
+	}
+}
+
+func TestOrderBuilderSpreads(t *testing.T) {
+	order, err := NewOrderBuilder().
+		AddOptionLeg("BUY_TO_OPEN", "SPY_071720C305", 2).
+		AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 2).
+		AsNetDebitOrder(4.6).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if order.OrderType != "NET_DEBIT" || !order.Price.Equal(decimal.RequireFromString("4.6")) || len(order.OrderLegCollection) != 2 {
+		t.Fatalf("unexpected order: %+v", order)
+	}
+	for i, want := range []struct{ instruction, symbol string }{{"BUY_TO_OPEN", "SPY_071720C305"}, {"SELL_TO_OPEN", "SPY_071720C310"}} {
+		leg := order.OrderLegCollection[i]
+		if leg.Instruction != want.instruction || leg.Quantity != 2 || leg.Instrument.AssetType != "OPTION" || leg.Instrument.Data.(*OptionA).Symbol != want.symbol {
+			t.Errorf("unexpected leg %d: %+v", i, leg)
+		}
+	}
+
+	order, err = NewOrderBuilder().
+		AddOptionLeg("SELL_TO_OPEN", "SPY_071720P305", 1).
+		AddOptionLeg("BUY_TO_OPEN", "SPY_071720P300", 1).
+		AsNetCreditOrder(1.2).
+		GoodTillCanceled().
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if order.OrderType != "NET_CREDIT" || !order.Price.Equal(decimal.RequireFromString("1.2")) || order.Duration != "GOOD_TILL_CANCEL" {
+		t.Errorf("unexpected credit order: %+v", order)
+	}
+
+	// A leg set with Buy and ForOption combines with added legs.
+	order, err = NewOrderBuilder().Buy(1, "SPY").ForOption("SPY_071720C305").ToOpen().AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 1).AsMarketSpreadOrder().Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if order.OrderType != "MARKET" || !order.Price.IsZero() || len(order.OrderLegCollection) != 2 || order.OrderLegCollection[0].Instruction != "BUY_TO_OPEN" {
+		t.Errorf("unexpected market spread: %+v", order)
+	}
+}
+
+func TestOrderBuilderSpreadErrors(t *testing.T) {
+	spread := func() *OrderBuilder {
+		return NewOrderBuilder().AddOptionLeg("BUY_TO_OPEN", "SPY_071720C305", 1).AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 1)
+	}
+	tests := map[string]*OrderBuilder{
+		"equity and option legs": NewOrderBuilder().Buy(100, "SPY").AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 1).AsNetDebitOrder(300),
+		"zero leg quantity":      NewOrderBuilder().AddOptionLeg("BUY_TO_OPEN", "SPY_071720C305", 1).AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 0).AsNetDebitOrder(4.6),
+		"equity instruction":     NewOrderBuilder().AddOptionLeg("BUY", "SPY_071720C305", 1).AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 1).AsNetDebitOrder(4.6),
+		"no leg symbol":          NewOrderBuilder().AddOptionLeg("BUY_TO_OPEN", "", 1).AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 1).AsNetDebitOrder(4.6),
+		"zero debit":             spread().AsNetDebitOrder(0),
+		"negative credit":        spread().AsNetCreditOrder(-1),
+		"single leg debit":       NewOrderBuilder().AddOptionLeg("BUY_TO_OPEN", "SPY_071720C305", 1).AsNetDebitOrder(4.6),
+		"no order type":          spread(),
+		"market spread gtc":      spread().AsMarketSpreadOrder().GoodTillCanceled(),
+	}
+	for name, b := range tests {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
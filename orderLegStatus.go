@@ -0,0 +1,126 @@
+package tdameritrade
+
+import (
+	"math"
+	"strings"
+)
+
+// Statuses of the legs of a MultiLegStatus.
+const (
+	LegStatusPending         = "PENDING"
+	LegStatusPartiallyFilled = "PARTIALLY_FILLED"
+	LegStatusFilled          = "FILLED"
+)
+
+// MultiLegStatus is how much of each leg of an order, such as an iron condor, has been filled.
+// PendingLegs are the legs not completely filled. AverageFillPrice is the average price of the fills of all legs,
+// weighted by quantity. NetDebit is the net price per share of one unit of the order, from the average prices of
+// the legs filled so far, and negative for net credits. A unit of the order holds each leg in the ratio of its
+// quantity to that of the smallest leg, as in SimulateOrderFill. Legs without a quantity yet, as in partially
+// populated orders, are left out of NetDebit.
+type MultiLegStatus struct {
+	TotalLegs        int
+	FilledLegs       int
+	PendingLegs      int
+	AverageFillPrice float64
+	NetDebit         float64
+	Legs             []LegStatus
+}
+
+// LegStatus is the fills of a leg of an order. Status is LegStatusPending, LegStatusPartiallyFilled or
+// LegStatusFilled, and AveragePrice is the average price per share of its fills, 0 without fills.
+type LegStatus struct {
+	Symbol       string
+	Instruction  string
+	OrderedQty   int
+	FilledQty    int
+	AveragePrice float64
+	Status       string
+}
+
+// MultiLegOrderStatus returns the fills of each leg of order, in the order of its OrderLegCollection, from the
+// executions in its OrderActivityCollection, matched to legs by leg ID. Poll the order, e.g. with
+// PollOrderStatus, and call MultiLegOrderStatus on each update to track its fills.
+func MultiLegOrderStatus(order *Order) *MultiLegStatus {
+	type fills struct{ quantity, cost float64 }
+	byLeg := map[int64]*fills{}
+	for _, activity := range order.OrderActivityCollection {
+		if activity == nil || activity.ActivityType != "EXECUTION" {
+			continue
+		}
+		for _, leg := range activity.ExecutionLegs {
+			f, ok := byLeg[leg.LegID]
+			if !ok {
+				f = &fills{}
+				byLeg[leg.LegID] = f
+			}
+			f.quantity += leg.Quantity
+			f.cost += leg.Quantity * leg.Price
+		}
+	}
+
+	status := &MultiLegStatus{Legs: make([]LegStatus, 0, len(order.OrderLegCollection))}
+	units := math.Inf(1)
+	for _, leg := range order.OrderLegCollection {
+		if leg.Quantity > 0 {
+			units = math.Min(units, leg.Quantity)
+		}
+	}
+	if math.IsInf(units, 1) {
+		units = 1
+	}
+
+	var filledQty, cost float64
+	for _, leg := range order.OrderLegCollection {
+		ls := LegStatus{
+			Symbol:      leg.Instrument.symbol(),
+			Instruction: leg.Instruction,
+			OrderedQty:  int(leg.Quantity),
+			Status:      LegStatusPending,
+		}
+		if f, ok := byLeg[int64(leg.LegID)]; ok && f.quantity > 0 {
+			ls.FilledQty = int(f.quantity)
+			ls.AveragePrice = f.cost / f.quantity
+			ls.Status = LegStatusPartiallyFilled
+			filledQty += f.quantity
+			cost += f.cost
+
+			price := ls.AveragePrice * leg.Quantity / units
+			if strings.HasPrefix(leg.Instruction, "BUY") {
+				status.NetDebit += price
+			} else {
+				status.NetDebit -= price
+			}
+		}
+		if ls.FilledQty >= ls.OrderedQty && ls.OrderedQty > 0 {
+			ls.Status = LegStatusFilled
+			status.FilledLegs++
+		} else {
+			status.PendingLegs++
+		}
+		status.Legs = append(status.Legs, ls)
+	}
+	status.TotalLegs = len(status.Legs)
+	if filledQty > 0 {
+		status.AverageFillPrice = cost / filledQty
+	}
+	return status
+}
+
+// IsFullyFilled reports whether every leg of the order has been filled.
+func (s *MultiLegStatus) IsFullyFilled() bool {
+	return s.TotalLegs > 0 && s.FilledLegs == s.TotalLegs
+}
+
+// FilledPercent returns the percentage of the quantity of all legs that has been filled, from 0 to 100.
+func (s *MultiLegStatus) FilledPercent() float64 {
+	var ordered, filled int
+	for _, leg := range s.Legs {
+		ordered += leg.OrderedQty
+		filled += leg.FilledQty
+	}
+	if ordered == 0 {
+		return 0
+	}
+	return math.Min(float64(filled)/float64(ordered)*100, 100)
+}
@@ -0,0 +1,88 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMultiLegOrderStatus(t *testing.T) {
+	var order Order
+	err := json.Unmarshal([]byte(`{
+		"orderType": "NET_CREDIT",
+		"status": "WORKING",
+		"orderLegCollection": [
+			{"legId": 1, "instruction": "BUY_TO_OPEN", "quantity": 2, "instrument": {"assetType": "OPTION", "symbol": "SPY_071720P290"}},
+			{"legId": 2, "instruction": "SELL_TO_OPEN", "quantity": 2, "instrument": {"assetType": "OPTION", "symbol": "SPY_071720P300"}},
+			{"legId": 3, "instruction": "SELL_TO_OPEN", "quantity": 2, "instrument": {"assetType": "OPTION", "symbol": "SPY_071720C320"}},
+			{"legId": 4, "instruction": "BUY_TO_OPEN", "quantity": 2, "instrument": {"assetType": "OPTION", "symbol": "SPY_071720C330"}}
+		],
+		"orderActivityCollection": [
+			{"activityType": "EXECUTION", "executionLegs": [
+				{"legId": 1, "quantity": 1, "price": 1.0},
+				{"legId": 2, "quantity": 2, "price": 2.5},
+				{"legId": 3, "quantity": 2, "price": 2.0}
+			]},
+			{"activityType": "ORDER_ACTION", "executionLegs": [{"legId": 4, "quantity": 2, "price": 9}]},
+			{"activityType": "EXECUTION", "executionLegs": [{"legId": 1, "quantity": 1, "price": 1.2}]}
+		]
+	}`), &order)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := MultiLegOrderStatus(&order)
+	if status.TotalLegs != 4 || status.FilledLegs != 3 || status.PendingLegs != 1 {
+		t.Errorf("unexpected leg counts: %+v", status)
+	}
+	want := []LegStatus{
+		{Symbol: "SPY_071720P290", Instruction: "BUY_TO_OPEN", OrderedQty: 2, FilledQty: 2, AveragePrice: 1.1, Status: LegStatusFilled},
+		{Symbol: "SPY_071720P300", Instruction: "SELL_TO_OPEN", OrderedQty: 2, FilledQty: 2, AveragePrice: 2.5, Status: LegStatusFilled},
+		{Symbol: "SPY_071720C320", Instruction: "SELL_TO_OPEN", OrderedQty: 2, FilledQty: 2, AveragePrice: 2, Status: LegStatusFilled},
+		{Symbol: "SPY_071720C330", Instruction: "BUY_TO_OPEN", OrderedQty: 2, Status: LegStatusPending},
+	}
+	if !reflect.DeepEqual(status.Legs, want) {
+		t.Errorf("Legs = %+v, want %+v", status.Legs, want)
+	}
+	if got, want := status.NetDebit, 1.1-2.5-2; math.Abs(got-want) > 1e-9 {
+		t.Errorf("NetDebit = %v, want %v", got, want)
+	}
+	if got, want := status.AverageFillPrice, (2.2+5+4)/6; math.Abs(got-want) > 1e-9 {
+		t.Errorf("AverageFillPrice = %v, want %v", got, want)
+	}
+	if status.IsFullyFilled() || status.FilledPercent() != 75 {
+		t.Errorf("IsFullyFilled = %v, FilledPercent = %v, want false and 75", status.IsFullyFilled(), status.FilledPercent())
+	}
+
+	order.OrderActivityCollection = append(order.OrderActivityCollection, &Execution{
+		ActivityType:  "EXECUTION",
+		ExecutionLegs: []*ExecutionLeg{{LegID: 4, Quantity: 1, Price: 0.5}},
+	})
+	status = MultiLegOrderStatus(&order)
+	if status.Legs[3].Status != LegStatusPartiallyFilled || status.IsFullyFilled() || status.FilledPercent() != 87.5 {
+		t.Errorf("unexpected status of a partially filled leg: %+v", status)
+	}
+
+	order.OrderActivityCollection[len(order.OrderActivityCollection)-1].ExecutionLegs[0].Quantity = 2
+	if status = MultiLegOrderStatus(&order); !status.IsFullyFilled() || status.FilledPercent() != 100 {
+		t.Errorf("expected the order to be fully filled: %+v", status)
+	}
+
+	// Legs without a quantity yet do not count towards the units of the order.
+	netDebit := status.NetDebit
+	order.OrderLegCollection = append(order.OrderLegCollection, &OrderLeg{LegID: 5, Instruction: "BUY_TO_OPEN"})
+	order.OrderActivityCollection = append(order.OrderActivityCollection, &Execution{
+		ActivityType:  "EXECUTION",
+		ExecutionLegs: []*ExecutionLeg{{LegID: 5, Quantity: 1, Price: 3}},
+	})
+	if status = MultiLegOrderStatus(&order); !(math.Abs(status.NetDebit-netDebit) <= 1e-9) {
+		t.Errorf("NetDebit with a leg without quantity = %v, want %v", status.NetDebit, netDebit)
+	}
+	for _, leg := range order.OrderLegCollection {
+		leg.Quantity = 0
+	}
+	if status = MultiLegOrderStatus(&order); math.IsNaN(status.NetDebit) || math.IsInf(status.NetDebit, 0) {
+		t.Errorf("NetDebit of an order without quantities = %v, want a finite value", status.NetDebit)
+	}
+}
@@ -0,0 +1,155 @@
+package tdameritrade
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// OptionCommissionPerContract is the commission SimulateOrderFill charges per option contract.
+// Equities trade without commission.
+var OptionCommissionPerContract = 0.65
+
+// ErrOrderNotFilled is returned by SimulateOrderFill when the limit price of an order would not be reached.
+var ErrOrderNotFilled = errors.New("order not filled")
+
+// SimulatedFill is the estimated fill of an order.
+// FillPrice is per share, net of all legs for spreads, and negative for net credits.
+// SlippageEstimate is the dollars by which a real fill may be worse than FillPrice.
+// TotalCost is the dollars the order pays, negative when it receives a net credit, Commission included.
+type SimulatedFill struct {
+	FillPrice        float64
+	SlippageEstimate float64
+	TotalCost        float64
+	Commission       float64
+}
+
+// SimulateOrderFill estimates the fill of order from the bids and asks of its legs, for backtesting and paper
+// trading. Equities are priced from quotes and options from quotes or, failing that, from chains.
+//
+// It is an approximation that ignores liquidity, partial fills and queue position:
+//   - market orders fill at the mid price, net of all legs for spreads, and are estimated to slip by half the
+//     net bid ask spread;
+//   - single leg limit orders fill at the ask for buys, or the bid for sells, when it reaches the limit price;
+//   - spreads with a NET_DEBIT or NET_CREDIT limit fill at the limit price when it is at or through their net mid
+//     price, as market makers fill spreads between the natural prices of their legs.
+//
+// Spreads are priced per unit of the leg with the smallest quantity, so a 2x1 ratio spread of 1 contract has
+// legs of 2 and 1 contracts. ErrOrderNotFilled is returned when a limit is not reached.
+func SimulateOrderFill(order *Order, quotes map[string]*Quote, chains map[string]*Chains) (*SimulatedFill, error) {
+	if order == nil || len(order.OrderLegCollection) == 0 {
+		return nil, fmt.Errorf("%w: order has no legs", ErrInvalidParams)
+	}
+
+	units := math.Inf(1)
+	for _, leg := range order.OrderLegCollection {
+		units = math.Min(units, leg.Quantity)
+	}
+	if units <= 0 {
+		return nil, fmt.Errorf("%w: order legs must have a positive quantity", ErrInvalidParams)
+	}
+
+	// The net prices of one unit of the order, positive when paid.
+	var bid, ask, commission float64
+	multiplier := 1.0
+	for _, leg := range order.OrderLegCollection {
+		symbol := leg.Instrument.symbol()
+		legBid, legAsk, legMultiplier, ok := legPrices(symbol, leg.Instrument.AssetType, quotes, chains)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingQuote, symbol)
+		}
+		ratio := leg.Quantity / units
+		if strings.HasPrefix(leg.Instruction, "BUY") {
+			bid += ratio * legBid
+			ask += ratio * legAsk
+		} else {
+			bid -= ratio * legAsk
+			ask -= ratio * legBid
+		}
+		multiplier = legMultiplier
+		if leg.Instrument.AssetType == "OPTION" {
+			commission += leg.Quantity * OptionCommissionPerContract
+		}
+	}
+	mid := (bid + ask) / 2
+	limit, _ := order.Price.Float64()
+
+	fill := &SimulatedFill{Commission: commission}
+	switch order.OrderType {
+	case "MARKET":
+		fill.FillPrice = mid
+		fill.SlippageEstimate = (ask - mid) * units * multiplier
+	case "LIMIT":
+		if len(order.OrderLegCollection) > 1 {
+			return nil, fmt.Errorf("%w: spreads must be NET_DEBIT or NET_CREDIT orders", ErrInvalidParams)
+		}
+		if strings.HasPrefix(order.OrderLegCollection[0].Instruction, "BUY") {
+			if ask > limit {
+				return nil, fmt.Errorf("%w: ask %v is above the limit %v", ErrOrderNotFilled, ask, limit)
+			}
+			fill.FillPrice = ask
+		} else {
+			// A sale receives the bid, which is the negated net ask.
+			if -ask < limit {
+				return nil, fmt.Errorf("%w: bid %v is below the limit %v", ErrOrderNotFilled, -ask, limit)
+			}
+			fill.FillPrice = ask
+		}
+	case "NET_DEBIT":
+		if mid > limit {
+			return nil, fmt.Errorf("%w: net mid %v is above the debit limit %v", ErrOrderNotFilled, mid, limit)
+		}
+		fill.FillPrice = limit
+	case "NET_CREDIT":
+		if -mid < limit {
+			return nil, fmt.Errorf("%w: net mid credit %v is below the credit limit %v", ErrOrderNotFilled, -mid, limit)
+		}
+		fill.FillPrice = -limit
+	default:
+		return nil, fmt.Errorf("%w: cannot simulate %s orders", ErrInvalidParams, order.OrderType)
+	}
+
+	fill.TotalCost = fill.FillPrice*units*multiplier + commission
+	return fill, nil
+}
+
+// legPrices returns the bid, ask and multiplier of symbol, from its quote if any, otherwise from the option
+// of symbol in chains.
+func legPrices(symbol string, assetType AssetType, quotes map[string]*Quote, chains map[string]*Chains) (bid, ask, multiplier float64, ok bool) {
+	if q, found := quotes[symbol]; found && q != nil {
+		multiplier = 1
+		if assetType == "OPTION" {
+			multiplier = q.Multiplier
+			if multiplier == 0 {
+				multiplier = 100
+			}
+		}
+		return q.BidPrice, q.AskPrice, multiplier, true
+	}
+	if assetType != "OPTION" {
+		return 0, 0, 0, false
+	}
+	for _, c := range chains {
+		if c == nil {
+			continue
+		}
+		for _, m := range []ExpDateMap{c.CallExpDateMap, c.PutExpDateMap} {
+			for _, strikes := range m {
+				for _, options := range strikes {
+					for _, o := range options {
+						if o.Symbol != symbol {
+							continue
+						}
+						multiplier = o.Multiplier
+						if multiplier == 0 {
+							multiplier = 100
+						}
+						return o.Bid, o.Ask, multiplier, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
@@ -0,0 +1,94 @@
+package tdameritrade
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSimulateOrderFillVertical(t *testing.T) {
+	chains := map[string]*Chains{"SPY": loadChains(t)}
+
+	// The 305 call is 7.20 x 7.24 and the 310 call 2.60 x 2.64, so the spread is 4.56 x 4.64 with a 4.60 mid.
+	order, err := NewOrderBuilder().
+		AddOptionLeg("BUY_TO_OPEN", "SPY_071720C305", 2).
+		AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 2).
+		AsMarketSpreadOrder().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill, err := SimulateOrderFill(order, nil, chains)
+	if err != nil {
+		t.Fatalf("SimulateOrderFill returned error: %v", err)
+	}
+	want := SimulatedFill{FillPrice: 4.6, SlippageEstimate: 0.04 * 2 * 100, Commission: 4 * 0.65, TotalCost: 4.6*2*100 + 4*0.65}
+	if !equalFill(*fill, want) {
+		t.Errorf("market fill = %+v, want %+v", *fill, want)
+	}
+
+	order, _ = NewOrderBuilder().
+		AddOptionLeg("BUY_TO_OPEN", "SPY_071720C305", 1).
+		AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 1).
+		AsNetDebitOrder(4.62).Build()
+	fill, err = SimulateOrderFill(order, nil, chains)
+	if err != nil {
+		t.Fatalf("SimulateOrderFill returned error: %v", err)
+	}
+	if want := (SimulatedFill{FillPrice: 4.62, Commission: 1.3, TotalCost: 462 + 1.3}); !equalFill(*fill, want) {
+		t.Errorf("net debit fill = %+v, want %+v", *fill, want)
+	}
+
+	order, _ = NewOrderBuilder().
+		AddOptionLeg("BUY_TO_OPEN", "SPY_071720C305", 1).
+		AddOptionLeg("SELL_TO_OPEN", "SPY_071720C310", 1).
+		AsNetDebitOrder(4.5).Build()
+	if _, err := SimulateOrderFill(order, nil, chains); !errors.Is(err, ErrOrderNotFilled) {
+		t.Errorf("SimulateOrderFill below the mid error = %v, want ErrOrderNotFilled", err)
+	}
+
+	// Selling the same spread for a credit.
+	order, _ = NewOrderBuilder().
+		AddOptionLeg("SELL_TO_OPEN", "SPY_071720C305", 1).
+		AddOptionLeg("BUY_TO_OPEN", "SPY_071720C310", 1).
+		AsNetCreditOrder(4.58).Build()
+	fill, err = SimulateOrderFill(order, nil, chains)
+	if err != nil {
+		t.Fatalf("SimulateOrderFill returned error: %v", err)
+	}
+	if want := (SimulatedFill{FillPrice: -4.58, Commission: 1.3, TotalCost: -458 + 1.3}); !equalFill(*fill, want) {
+		t.Errorf("net credit fill = %+v, want %+v", *fill, want)
+	}
+}
+
+func TestSimulateOrderFillEquity(t *testing.T) {
+	must := mustOrder(t)
+	quotes := map[string]*Quote{"SPY": {Symbol: "SPY", BidPrice: 310.5, AskPrice: 310.54}}
+
+	fill, err := SimulateOrderFill(must(NewEquityLimitBuyOrder("SPY", 10, 311)), quotes, nil)
+	if err != nil {
+		t.Fatalf("SimulateOrderFill returned error: %v", err)
+	}
+	if want := (SimulatedFill{FillPrice: 310.54, TotalCost: 3105.4}); !equalFill(*fill, want) {
+		t.Errorf("limit buy fill = %+v, want %+v", *fill, want)
+	}
+	fill, err = SimulateOrderFill(must(NewEquityLimitSellOrder("SPY", 10, 310)), quotes, nil)
+	if err != nil {
+		t.Fatalf("SimulateOrderFill returned error: %v", err)
+	}
+	if want := (SimulatedFill{FillPrice: -310.5, TotalCost: -3105}); !equalFill(*fill, want) {
+		t.Errorf("limit sell fill = %+v, want %+v", *fill, want)
+	}
+
+	if _, err := SimulateOrderFill(must(NewEquityLimitBuyOrder("SPY", 10, 310.5)), quotes, nil); !errors.Is(err, ErrOrderNotFilled) {
+		t.Errorf("SimulateOrderFill of a limit under the ask error = %v, want ErrOrderNotFilled", err)
+	}
+	if _, err := SimulateOrderFill(must(NewEquityMarketBuyOrder("QQQ", 10)), quotes, nil); !errors.Is(err, ErrMissingQuote) {
+		t.Errorf("SimulateOrderFill without a quote error = %v, want ErrMissingQuote", err)
+	}
+}
+
+func equalFill(a, b SimulatedFill) bool {
+	const eps = 1e-9
+	return math.Abs(a.FillPrice-b.FillPrice) < eps && math.Abs(a.SlippageEstimate-b.SlippageEstimate) < eps &&
+		math.Abs(a.TotalCost-b.TotalCost) < eps && math.Abs(a.Commission-b.Commission) < eps
+}
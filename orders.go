@@ -0,0 +1,350 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+	"github.com/shopspring/decimal"
+)
+
+// OrderLeg is a single instruction within an order.
+type OrderLeg struct {
+	OrderLegType   string     `json:"orderLegType,omitempty"`
+	LegID          int        `json:"legId,omitempty"`
+	Instrument     Instrument `json:"instrument"`
+	Instruction    string     `json:"instruction"`
+	PositionEffect string     `json:"positionEffect,omitempty"`
+	Quantity       float64    `json:"quantity"`
+	QuantityType   string     `json:"quantityType,omitempty"`
+}
+
+type CancelTime struct {
+	Date        string `json:"date,omitempty"`
+	ShortFormat bool   `json:"shortFormat,omitempty"`
+}
+
+type Orders []*Order
+
+//Per TD TDAmeritrade documentation, CancelTime should be a struct...
+// cancelTime": {
+//     "date": "string",
+//     "shortFormat": false
+//   }
+
+// However, the actual response is simply a string: YYYY-MM-DD
+// This will only apply to orders that are a limit order where the expiry is set.
+type Order struct {
+	Session                  Session           `json:"session"`
+	Duration                 Duration          `json:"duration"`
+	OrderType                OrderType         `json:"orderType"`
+	CancelTime               string            `json:"cancelTime,omitempty"`
+	ComplexOrderStrategyType string            `json:"complexOrderStrategyType,omitempty"`
+	Quantity                 float64           `json:"quantity,omitempty"`
+	FilledQuantity           float64           `json:"filledQuantity,omitempty"`
+	RemainingQuantity        float64           `json:"remainingQuantity,omitempty"`
+	RequestedDestination     string            `json:"requestedDestination,omitempty"`
+	DestinationLinkName      string            `json:"destinationLinkName,omitempty"`
+	ReleaseTime              string            `json:"releaseTime,omitempty"`
+	StopPrice                float64           `json:"stopPrice,omitempty"`
+	StopPriceLinkBasis       string            `json:"stopPriceLinkBasis,omitempty"`
+	StopPriceLinkType        string            `json:"stopPriceLinkType,omitempty"`
+	StopPriceOffset          float64           `json:"stopPriceOffset,omitempty"`
+	StopType                 string            `json:"stopType,omitempty"`
+	PriceLinkBasis           string            `json:"priceLinkBasis,omitempty"`
+	PriceLinkType            string            `json:"priceLinkType,omitempty"`
+	Price                    decimal.Decimal   `json:"price,omitempty"`
+	TaxLotMethod             string            `json:"taxLotMethod,omitempty"`
+	OrderLegCollection       []*OrderLeg       `json:"orderLegCollection"`
+	ActivationPrice          float64           `json:"activationPrice,omitempty"`
+	SpecialInstruction       string            `json:"specialInstruction,omitempty"`
+	OrderStrategyType        OrderStrategyType `json:"orderStrategyType"`
+	OrderID                  int64             `json:"orderId,omitempty"`
+	Cancelable               bool              `json:"cancelable,omitempty"`
+	Editable                 bool              `json:"editable,omitempty"`
+	Status                   string            `json:"status,omitempty"`
+	EnteredTime              string            `json:"enteredTime,omitempty"`
+	CloseTime                string            `json:"closeTime,omitempty"`
+	Tag                      string            `json:"tag,omitempty"`
+	AccountID                float64           `json:"accountId,omitempty"`
+	OrderActivityCollection  []*Execution      `json:"orderActivityCollection,omitempty"`
+	ReplacingOrderCollection []*Order          `json:"replacingOrderCollection,omitempty"`
+	ChildOrderStrategies     []*Order          `json:"childOrderStrategies,omitempty"`
+	StatusDescription        string            `json:"statusDescription,omitempty"`
+}
+
+type ExecutionLeg struct {
+	LegID             int64   `json:"legId"`
+	Quantity          float64 `json:"quantity"`
+	MismarkedQuantity float64 `json:"mismarkedQuantity"`
+	Price             float64 `json:"price"`
+	Time              string  `json:"time"`
+}
+
+type Execution struct {
+	ActivityType           string          `json:"activityType"`  //"'EXECUTION' or 'ORDER_ACTION'",
+	ExecutionType          string          `json:"executionType"` //"'FILL'",
+	Quantity               float64         `json:"quantity"`
+	OrderRemainingQuantity float64         `json:"orderRemainingQuantity"`
+	ExecutionLegs          []*ExecutionLeg `json:"executionLegs"`
+}
+
+// OrdersService handles communication with the order related methods of
+// the TDAmeritrade API.
+//
+// TDAmeritrade API docs: https://developer.tdameritrade.com/account-access/apis
+type OrdersService struct {
+	client *Client
+}
+
+// OrderQueryParams filters the orders returned by TD Ameritrade.
+// AccountId is only used by endpoints that are not scoped to an account.
+// Dates are ISO8601 formatted, day granularity yyyy-MM-dd.
+type OrderQueryParams struct {
+	AccountId  string `url:"accountId,omitempty"`
+	MaxResults int    `url:"maxResults,omitempty"`
+	From       string `url:"fromEnteredTime,omitempty"`
+	To         string `url:"toEnteredTime,omitempty"`
+	Status     string `url:"status,omitempty"`
+}
+
+// PlaceOrder places an order for an account.
+// TD Ameritrade doesn't return the new order in the response body, only its location.
+// The ID of the new order is parsed from the Location header and set on the returned Response's OrderID.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/post/accounts/%7BaccountId%7D/orders-0
+func (s *OrdersService) PlaceOrder(ctx context.Context, accountID string, order *Order) (*Response, error) {
+	u := fmt.Sprintf("accounts/%s/orders", accountID)
+	if order == nil {
+		return nil, fmt.Errorf("order is nil")
+	}
+
+	req, err := s.client.NewRequest("POST", u, order)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err != nil {
+		return resp, err
+	}
+	resp.OrderID = orderIDFromLocation(resp.Header.Get("Location"))
+	return resp, nil
+}
+
+// CancelOrder cancels an order that has not been filled.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/delete/accounts/%7BaccountId%7D/orders/%7BorderId%7D-0
+func (s *OrdersService) CancelOrder(ctx context.Context, accountID, orderID string) (*Response, error) {
+	u := fmt.Sprintf("accounts/%s/orders/%s", accountID, orderID)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(ctx, req, nil)
+}
+
+// ReplaceOrder replaces an open order of an account with order, cancelling it and placing order in its place.
+// The OrderID of order, if set, must be orderID.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/put/accounts/%7BaccountId%7D/orders/%7BorderId%7D-0
+func (s *OrdersService) ReplaceOrder(ctx context.Context, accountID, orderID string, order *Order) (*Response, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order is nil")
+	}
+	if order.OrderID != 0 && strconv.FormatInt(order.OrderID, 10) != orderID {
+		return nil, fmt.Errorf("%w: order has ID %d, replacing order %s", ErrInvalidParams, order.OrderID, orderID)
+	}
+
+	u := fmt.Sprintf("accounts/%s/orders/%s", accountID, orderID)
+	req, err := s.client.NewRequest("PUT", u, order)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(ctx, req, nil)
+}
+
+// GetOrder returns a single order for an account.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/get/accounts/%7BaccountId%7D/orders/%7BorderId%7D-0
+func (s *OrdersService) GetOrder(ctx context.Context, accountID, orderID string) (*Order, *Response, error) {
+	u := fmt.Sprintf("accounts/%s/orders/%s", accountID, orderID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order := new(Order)
+	resp, err := s.client.Do(ctx, req, order)
+	if err != nil {
+		return nil, resp, err
+	}
+	return order, resp, nil
+}
+
+// GetOrdersByQuery returns the orders of all of the caller's accounts matching params, or of params.AccountId if set.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/get/orders-0
+func (s *OrdersService) GetOrdersByQuery(ctx context.Context, params OrderQueryParams) ([]*Order, *Response, error) {
+	u := "orders"
+	q, err := query.Values(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(q) > 0 {
+		u = fmt.Sprintf("%s?%s", u, q.Encode())
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ords := Orders{}
+	resp, err := s.client.Do(ctx, req, &ords)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ords, resp, nil
+}
+
+// finalOrderStatuses are the statuses of orders that will not change again.
+// TD Ameritrade spells cancelled orders CANCELED; CANCELLED is accepted too.
+var finalOrderStatuses = []string{"FILLED", "CANCELED", "CANCELLED", "REJECTED", "EXPIRED", "REPLACED"}
+
+// IsFinalStatus reports whether an order with status will not change again: it was filled, cancelled,
+// rejected, expired or replaced by another order.
+func IsFinalStatus(status string) bool {
+	return contains(strings.ToUpper(status), finalOrderStatuses)
+}
+
+// PollOrderStatus gets an order every interval and sends it on the returned channel whenever its status changes,
+// starting with its status when PollOrderStatus is called.
+// The channel is closed once the order reaches a final status, see IsFinalStatus, or ctx is done.
+// An error getting the order the first time is returned; later errors are ignored and the order is got again
+// after interval.
+func (s *OrdersService) PollOrderStatus(ctx context.Context, accountID, orderID string, interval time.Duration) (<-chan *Order, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: interval must be positive, got %v", ErrInvalidParams, interval)
+	}
+	order, _, err := s.GetOrder(ctx, accountID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *Order, 1)
+	ch <- order
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		status := order.Status
+		for !IsFinalStatus(status) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			order, _, err := s.GetOrder(ctx, accountID, orderID)
+			if err != nil || order.Status == status {
+				continue
+			}
+			status = order.Status
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- order:
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// GetOrdersByAccount returns the orders for an account matching params.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/get/accounts/%7BaccountId%7D/orders-0
+func (s *OrdersService) GetOrdersByAccount(ctx context.Context, accountID string, params OrderQueryParams) ([]*Order, *Response, error) {
+	u := fmt.Sprintf("accounts/%s/orders", accountID)
+	q, err := query.Values(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(q) > 0 {
+		u = fmt.Sprintf("%s?%s", u, q.Encode())
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ords := Orders{}
+	resp, err := s.client.Do(ctx, req, &ords)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ords, resp, nil
+}
+
+// GetFilledOrdersToday returns the orders of an account filled today, the day in New York, see GetFilledOrdersForDate.
+func (s *OrdersService) GetFilledOrdersToday(ctx context.Context, accountID string) ([]*Order, *Response, error) {
+	return s.GetFilledOrdersForDate(ctx, accountID, time.Now().In(MarketLocation()))
+}
+
+// GetFilledOrdersForDate returns the orders of an account entered on the day of date, in its location, and filled.
+func (s *OrdersService) GetFilledOrdersForDate(ctx context.Context, accountID string, date time.Time) ([]*Order, *Response, error) {
+	day := date.Format("2006-01-02")
+	return s.GetOrdersByAccount(ctx, accountID, OrderQueryParams{From: day, To: day, Status: "FILLED"})
+}
+
+// GetOpenOrdersCount returns the number of orders of an account that may still change, see IsFinalStatus,
+// among those TD Ameritrade returns by default. Only the status of each order is decoded.
+func (s *OrdersService) GetOpenOrdersCount(ctx context.Context, accountID string) (int, error) {
+	req, err := s.client.NewRequest("GET", fmt.Sprintf("accounts/%s/orders", accountID), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		_, err := s.client.Do(ctx, req, w)
+		w.CloseWithError(err)
+	}()
+	defer r.Close()
+
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '['); err != nil {
+		return 0, err
+	}
+	n := 0
+	for dec.More() {
+		var order struct {
+			Status string `json:"status"`
+		}
+		if err := dec.Decode(&order); err != nil {
+			return 0, err
+		}
+		if !IsFinalStatus(order.Status) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// orderIDFromLocation returns the last path segment of an order's URL,
+// e.g. https://api.tdameritrade.com/v1/accounts/123/orders/456 returns 456.
+func orderIDFromLocation(location string) string {
+	if location == "" {
+		return ""
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	return path.Base(u.Path)
+}
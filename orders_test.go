@@ -0,0 +1,396 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPlaceOrder(t *testing.T) {
+	equityLeg := func(instruction string) []*OrderLeg {
+		return []*OrderLeg{{
+			Instruction: instruction,
+			Quantity:    10,
+			Instrument:  Instrument{AssetType: "EQUITY", Data: &Equity{Symbol: "SPY"}},
+		}}
+	}
+
+	tests := []struct {
+		name  string
+		order *Order
+		want  map[string]interface{}
+	}{
+		{
+			name: "market",
+			order: &Order{
+				OrderType:          "MARKET",
+				Session:            "NORMAL",
+				Duration:           "DAY",
+				OrderStrategyType:  "SINGLE",
+				OrderLegCollection: equityLeg("BUY"),
+			},
+			want: map[string]interface{}{"orderType": "MARKET"},
+		},
+		{
+			name: "limit",
+			order: &Order{
+				OrderType:          "LIMIT",
+				Session:            "NORMAL",
+				Duration:           "GOOD_TILL_CANCEL",
+				OrderStrategyType:  "SINGLE",
+				Price:              decimal.RequireFromString("310.25"),
+				OrderLegCollection: equityLeg("BUY"),
+			},
+			want: map[string]interface{}{"orderType": "LIMIT", "price": "310.25", "duration": "GOOD_TILL_CANCEL"},
+		},
+		{
+			name: "stop",
+			order: &Order{
+				OrderType:          "STOP",
+				Session:            "NORMAL",
+				Duration:           "DAY",
+				OrderStrategyType:  "SINGLE",
+				StopPrice:          295.5,
+				OrderLegCollection: equityLeg("SELL"),
+			},
+			want: map[string]interface{}{"orderType": "STOP", "stopPrice": 295.5},
+		},
+	}
+
+	for i, tt := range tests {
+		client, mux, teardown := setup(t)
+
+		orderID := fmt.Sprintf("%d", 1000+i)
+		mux.HandleFunc("/accounts/123/orders", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("%s: could not decode request body: %v", tt.name, err)
+				return
+			}
+			for k, v := range tt.want {
+				if body[k] != v {
+					t.Errorf("%s: request body %s = %v, want %v", tt.name, k, body[k], v)
+				}
+			}
+			legs := body["orderLegCollection"].([]interface{})
+			instrument := legs[0].(map[string]interface{})["instrument"].(map[string]interface{})
+			if instrument["symbol"] != "SPY" || instrument["assetType"] != "EQUITY" {
+				t.Errorf("%s: unexpected instrument: %v", tt.name, instrument)
+			}
+
+			w.Header().Set("Location", "https://api.tdameritrade.com/v1/accounts/123/orders/"+orderID)
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		resp, err := client.Orders.PlaceOrder(context.Background(), "123", tt.order)
+		if err != nil {
+			t.Fatalf("%s: PlaceOrder returned error: %v", tt.name, err)
+		}
+		if resp.OrderID != orderID {
+			t.Errorf("%s: OrderID = %q, want %q", tt.name, resp.OrderID, orderID)
+		}
+		teardown()
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123/orders/456", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+	})
+
+	if _, err := client.Orders.CancelOrder(context.Background(), "123", "456"); err != nil {
+		t.Fatalf("CancelOrder returned error: %v", err)
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123/orders/456", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"orderId":456,"orderType":"LIMIT","price":310.25,"status":"WORKING",
+			"orderLegCollection":[{"instruction":"BUY","quantity":10,"instrument":{"assetType":"EQUITY","symbol":"SPY"}}]}`)
+	})
+
+	order, _, err := client.Orders.GetOrder(context.Background(), "123", "456")
+	if err != nil {
+		t.Fatalf("GetOrder returned error: %v", err)
+	}
+	if order.OrderID != 456 || order.Status != "WORKING" || !order.Price.Equal(decimal.RequireFromString("310.25")) {
+		t.Errorf("unexpected order: %+v", order)
+	}
+}
+
+func TestGetOrdersByAccount(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123/orders", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "status", "FILLED")
+		testFormValue(t, r, "accountId", "")
+		fmt.Fprint(w, `[{"orderId":1},{"orderId":2}]`)
+	})
+
+	orders, _, err := client.Orders.GetOrdersByAccount(context.Background(), "123", OrderQueryParams{Status: "FILLED"})
+	if err != nil {
+		t.Fatalf("GetOrdersByAccount returned error: %v", err)
+	}
+	if len(orders) != 2 || orders[1].OrderID != 2 {
+		t.Errorf("unexpected orders: %+v", orders)
+	}
+}
+
+func TestDeprecatedAccountOrderMethods(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	var methods []string
+	mux.HandleFunc("/accounts/123/orders", func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == "GET" {
+			testFormValue(t, r, "status", "WORKING")
+			fmt.Fprint(w, `[{"orderId":1}]`)
+		}
+	})
+	mux.HandleFunc("/accounts/123/orders/456", func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"orderId":456}`)
+		}
+	})
+
+	ctx := context.Background()
+	if _, err := client.Account.PlaceOrder(ctx, "123", mustOrder(t)(NewEquityMarketBuyOrder("SPY", 1))); err != nil {
+		t.Fatalf("PlaceOrder returned error: %v", err)
+	}
+	if _, err := client.Account.GetOrder(ctx, "123", "456"); err != nil {
+		t.Fatalf("GetOrder returned error: %v", err)
+	}
+	orders, _, err := client.Account.GetOrderByPath(ctx, "123", &OrderParams{Status: "WORKING"})
+	if err != nil {
+		t.Fatalf("GetOrderByPath returned error: %v", err)
+	}
+	if len(*orders) != 1 || (*orders)[0].OrderID != 1 {
+		t.Errorf("unexpected orders: %+v", orders)
+	}
+	if _, err := client.Account.CancelOrder(ctx, "123", "456"); err != nil {
+		t.Fatalf("CancelOrder returned error: %v", err)
+	}
+
+	if want := []string{"POST", "GET", "GET", "DELETE"}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("requests = %v, want %v", methods, want)
+	}
+}
+
+func TestGetFilledOrders(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	wantDay := time.Now().In(MarketLocation()).Format("2006-01-02")
+	mux.HandleFunc("/accounts/123/orders", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "status", "FILLED")
+		if r.FormValue("fromEnteredTime") != wantDay || r.FormValue("toEnteredTime") != wantDay {
+			t.Errorf("requested orders entered from %s to %s, want %s", r.FormValue("fromEnteredTime"), r.FormValue("toEnteredTime"), wantDay)
+		}
+		fmt.Fprint(w, `[{"orderId":1,"status":"FILLED"}]`)
+	})
+
+	orders, _, err := client.Orders.GetFilledOrdersToday(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("GetFilledOrdersToday returned error: %v", err)
+	}
+	if len(orders) != 1 || orders[0].OrderID != 1 {
+		t.Errorf("unexpected orders: %+v", orders)
+	}
+
+	wantDay = "2020-07-06"
+	if _, _, err := client.Orders.GetFilledOrdersForDate(context.Background(), "123", time.Date(2020, 7, 6, 23, 59, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("GetFilledOrdersForDate returned error: %v", err)
+	}
+}
+
+func TestGetOpenOrdersCount(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123/orders", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"orderId":1,"status":"WORKING"},{"orderId":2,"status":"FILLED"},{"orderId":3,"status":"QUEUED"},{"orderId":4,"status":"CANCELED"}]`)
+	})
+	mux.HandleFunc("/accounts/404/orders", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	})
+
+	n, err := client.Orders.GetOpenOrdersCount(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("GetOpenOrdersCount returned error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("GetOpenOrdersCount = %d, want 2", n)
+	}
+	if _, err := client.Orders.GetOpenOrdersCount(context.Background(), "404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetOpenOrdersCount error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUnmarshalOrder(t *testing.T) {
+	testJSONRoundTrip(t, "testdata/order.json", &Order{})
+}
+
+func TestPollOrderStatus(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	statuses := []string{"WORKING", "WORKING", "FILLED"}
+	var gets int32
+	mux.HandleFunc("/accounts/123/orders/456", func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&gets, 1)) - 1
+		if n >= len(statuses) {
+			t.Errorf("order got %d times after it was filled", n-len(statuses)+1)
+			n = len(statuses) - 1
+		}
+		fmt.Fprintf(w, `{"orderId":456,"status":%q}`, statuses[n])
+	})
+
+	ch, err := client.Orders.PollOrderStatus(context.Background(), "123", "456", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("PollOrderStatus returned error: %v", err)
+	}
+	var got []string
+	for order := range ch {
+		got = append(got, order.Status)
+	}
+	if want := []string{"WORKING", "FILLED"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+	if n := atomic.LoadInt32(&gets); n != 3 {
+		t.Errorf("expected the order to be got 3 times, got %d", n)
+	}
+}
+
+func TestPollOrderStatusCancel(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123/orders/456", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"orderId":456,"status":"WORKING"}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := client.Orders.PollOrderStatus(ctx, "123", "456", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("PollOrderStatus returned error: %v", err)
+	}
+	if order := <-ch; order.Status != "WORKING" {
+		t.Errorf("expected a working order, got %s", order.Status)
+	}
+	cancel()
+	select {
+	case order, ok := <-ch:
+		if ok {
+			t.Errorf("expected the channel to be closed, got %+v", order)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after ctx was cancelled")
+	}
+
+	if _, err := client.Orders.PollOrderStatus(context.Background(), "123", "456", 0); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for a zero interval, got %v", err)
+	}
+	if _, err := client.Orders.PollOrderStatus(context.Background(), "123", "789", time.Second); err == nil {
+		t.Error("expected an error for an order that cannot be got")
+	}
+}
+
+func TestIsFinalStatus(t *testing.T) {
+	for status, want := range map[string]bool{
+		"FILLED": true, "CANCELED": true, "CANCELLED": true, "REJECTED": true, "EXPIRED": true, "REPLACED": true, "filled": true,
+		"WORKING": false, "QUEUED": false, "PENDING_ACTIVATION": false, "ACCEPTED": false, "": false,
+	} {
+		if got := IsFinalStatus(status); got != want {
+			t.Errorf("IsFinalStatus(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestReplaceOrder(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123/orders/456", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		var order Order
+		if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+			t.Errorf("could not decode order: %v", err)
+			return
+		}
+		if order.OrderType != "LIMIT" || !order.Price.Equal(decimal.RequireFromString("311")) {
+			t.Errorf("unexpected order: %+v", order)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	order := mustOrder(t)(NewEquityLimitBuyOrder("SPY", 10, 311))
+	if _, err := client.Orders.ReplaceOrder(context.Background(), "123", "456", order); err != nil {
+		t.Fatalf("ReplaceOrder returned error: %v", err)
+	}
+	order.OrderID = 456
+	if _, err := client.Orders.ReplaceOrder(context.Background(), "123", "456", order); err != nil {
+		t.Fatalf("ReplaceOrder returned error for a matching order ID: %v", err)
+	}
+	if _, err := client.Account.ReplaceOrder(context.Background(), "123", "456", order); err != nil {
+		t.Fatalf("AccountsService.ReplaceOrder returned error: %v", err)
+	}
+
+	order.OrderID = 789
+	if _, err := client.Orders.ReplaceOrder(context.Background(), "123", "456", order); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for a mismatched order ID, got %v", err)
+	}
+	if _, err := client.Orders.ReplaceOrder(context.Background(), "123", "456", nil); err == nil {
+		t.Error("expected an error for a nil order")
+	}
+}
+
+func TestGetOrdersByQuery(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "accountId", "123")
+		testFormValue(t, r, "status", "WORKING")
+		testFormValue(t, r, "fromEnteredTime", "2020-07-01")
+		fmt.Fprint(w, `[{"orderId":1,"accountId":123},{"orderId":2,"accountId":123}]`)
+	})
+
+	params := OrderQueryParams{AccountId: "123", Status: "WORKING", From: "2020-07-01"}
+	orders, _, err := client.Orders.GetOrdersByQuery(context.Background(), params)
+	if err != nil {
+		t.Fatalf("GetOrdersByQuery returned error: %v", err)
+	}
+	if len(orders) != 2 || orders[1].OrderID != 2 {
+		t.Errorf("unexpected orders: %+v", orders)
+	}
+
+	deprecated, _, err := client.Account.GetOrdersByQuery(context.Background(), &params)
+	if err != nil {
+		t.Fatalf("AccountsService.GetOrdersByQuery returned error: %v", err)
+	}
+	if len(*deprecated) != 2 {
+		t.Errorf("unexpected orders: %+v", *deprecated)
+	}
+}
@@ -0,0 +1,192 @@
+// Package portfolio tracks the value of positions of the tdameritrade package as streaming quotes arrive.
+//
+// Usage example:
+// account, _, err := client.Account.GetAccount(ctx, accountID, []string{"positions"})
+// quotes, err := streamingClient.SubscribeLevelOneEquity(equitySymbols, nil)
+// optionQuotes, err := streamingClient.SubscribeLevelOneOption(optionSymbols, nil)
+// tracker, err := portfolio.NewPnLTracker(account.Positions, quotes, optionQuotes)
+// for update := range tracker.Updates() { ... }
+package portfolio
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// updatesBufferSize is the number of updates buffered for readers of PnLTracker.Updates.
+const updatesBufferSize = 64
+
+// PnLUpdate is the unrealized profit or loss of the position in Symbol at CurrentPrice.
+// Quantity is the number of shares or contracts held and CostBasis is what they cost to open, in dollars;
+// both are negative for short positions.
+type PnLUpdate struct {
+	Symbol               string
+	Quantity             float64
+	CostBasis            float64
+	CurrentPrice         float64
+	UnrealizedPnL        float64
+	UnrealizedPnLPercent float64
+	Timestamp            time.Time
+}
+
+// PnLTracker recomputes the profit and loss of positions on each quote of their symbols.
+// It is safe for concurrent use.
+type PnLTracker struct {
+	updates chan PnLUpdate
+
+	mu        sync.RWMutex
+	positions map[string]*trackedPosition
+}
+
+type trackedPosition struct {
+	quantity   float64
+	costBasis  float64
+	multiplier float64
+	// option is set for options, whose prices come from option quotes rather than equity quotes.
+	option    bool
+	price     float64
+	timestamp time.Time
+}
+
+// NewPnLTracker returns a PnLTracker of positions, such as those of GetAccount, reading the quotes matching
+// their symbols until both channels are closed. Options are priced by the last price of optionQuotes, from
+// SubscribeLevelOneOption, and other positions by that of quotes, from SubscribeLevelOneEquity; a quote on the
+// other channel is ignored. Either channel may be nil, e.g. optionQuotes for accounts without options, whose
+// positions then keep their initial prices. The prices of positions start at those implied by their
+// MarketValue. Positions in the same symbol are merged.
+func NewPnLTracker(positions []tdameritrade.Position, quotes <-chan tdameritrade.L1EquityQuote, optionQuotes <-chan tdameritrade.L1OptionQuote) (*PnLTracker, error) {
+	t := &PnLTracker{
+		updates:   make(chan PnLUpdate, updatesBufferSize),
+		positions: map[string]*trackedPosition{},
+	}
+	now := time.Now()
+	marketValues := map[string]float64{}
+	for _, p := range positions {
+		instrument, err := p.Instrument.Typed()
+		if err != nil {
+			return nil, err
+		}
+		multiplier := 1.0
+		option, isOption := instrument.(*tdameritrade.OptionInstrument)
+		if isOption {
+			multiplier = option.OptionMultiplier
+			if multiplier == 0 {
+				multiplier = 100
+			}
+		}
+
+		tp, ok := t.positions[instrument.Symbol()]
+		if !ok {
+			tp = &trackedPosition{multiplier: multiplier, option: isOption, timestamp: now}
+			t.positions[instrument.Symbol()] = tp
+		}
+		quantity := p.LongQuantity - p.ShortQuantity
+		tp.quantity += quantity
+		tp.costBasis += p.AveragePrice * quantity * multiplier
+		marketValues[instrument.Symbol()] += p.MarketValue
+	}
+	for symbol, tp := range t.positions {
+		if tp.quantity != 0 {
+			tp.price = marketValues[symbol] / (tp.quantity * tp.multiplier)
+		}
+	}
+
+	go t.run(quotes, optionQuotes)
+	return t, nil
+}
+
+// Updates returns the channel the tracker sends an update on for each quote changing the price of a position.
+// Updates are dropped while the channel is full, so readers only interested in the latest values should use
+// Snapshot instead. The channel is closed once both quote channels are.
+func (t *PnLTracker) Updates() <-chan PnLUpdate {
+	return t.updates
+}
+
+// TotalUnrealizedPnL returns the sum of the unrealized profit and loss of the positions.
+func (t *PnLTracker) TotalUnrealizedPnL() float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var total float64
+	for symbol, p := range t.positions {
+		total += p.update(symbol).UnrealizedPnL
+	}
+	return total
+}
+
+// Snapshot returns the current profit and loss of each position, sorted by symbol.
+func (t *PnLTracker) Snapshot() []PnLUpdate {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	updates := make([]PnLUpdate, 0, len(t.positions))
+	for symbol, p := range t.positions {
+		updates = append(updates, p.update(symbol))
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Symbol < updates[j].Symbol })
+	return updates
+}
+
+func (t *PnLTracker) run(quotes <-chan tdameritrade.L1EquityQuote, optionQuotes <-chan tdameritrade.L1OptionQuote) {
+	defer close(t.updates)
+	for quotes != nil || optionQuotes != nil {
+		select {
+		case quote, ok := <-quotes:
+			if !ok {
+				quotes = nil
+				continue
+			}
+			if _, ok := quote.Fields[tdameritrade.L1EquityLastPrice]; ok {
+				t.setPrice(quote.Symbol, false, quote.LastPrice())
+			}
+		case quote, ok := <-optionQuotes:
+			if !ok {
+				optionQuotes = nil
+				continue
+			}
+			// Option quotes are merged, so fields not received yet are zero.
+			if quote.LastPrice != 0 {
+				t.setPrice(quote.Symbol, true, quote.LastPrice)
+			}
+		}
+	}
+}
+
+// setPrice sets the price of the position in symbol, if it is an option when option is set and not otherwise,
+// and sends its update.
+func (t *PnLTracker) setPrice(symbol string, option bool, price float64) {
+	t.mu.Lock()
+	p, ok := t.positions[symbol]
+	if !ok || p.option != option {
+		t.mu.Unlock()
+		return
+	}
+	p.price = price
+	p.timestamp = time.Now()
+	update := p.update(symbol)
+	t.mu.Unlock()
+
+	select {
+	case t.updates <- update:
+	default:
+	}
+}
+
+func (p *trackedPosition) update(symbol string) PnLUpdate {
+	pnl := p.price*p.quantity*p.multiplier - p.costBasis
+	var percent float64
+	if cost := math.Abs(p.costBasis); cost != 0 {
+		percent = pnl / cost * 100
+	}
+	return PnLUpdate{
+		Symbol:               symbol,
+		Quantity:             p.quantity,
+		CostBasis:            p.costBasis,
+		CurrentPrice:         p.price,
+		UnrealizedPnL:        pnl,
+		UnrealizedPnLPercent: percent,
+		Timestamp:            p.timestamp,
+	}
+}
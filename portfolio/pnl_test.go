@@ -0,0 +1,110 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+func testPositions(t *testing.T) []tdameritrade.Position {
+	t.Helper()
+	var positions []tdameritrade.Position
+	err := json.Unmarshal([]byte(`[
+		{"longQuantity":10,"averagePrice":300,"marketValue":3100,"instrument":{"assetType":"EQUITY","symbol":"SPY"}},
+		{"shortQuantity":2,"averagePrice":2.5,"marketValue":-400,"instrument":{"assetType":"OPTION","symbol":"SPY_071720C310","optionMultiplier":100}},
+		{"shortQuantity":5,"averagePrice":250,"marketValue":-1200,"instrument":{"assetType":"EQUITY","symbol":"QQQ"}}
+	]`), &positions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return positions
+}
+
+func lastPrice(symbol string, price float64) tdameritrade.L1EquityQuote {
+	return tdameritrade.L1EquityQuote{
+		Symbol: symbol,
+		Fields: map[tdameritrade.L1EquityField]interface{}{tdameritrade.L1EquityLastPrice: price},
+	}
+}
+
+func nextUpdate(t *testing.T, tracker *PnLTracker) PnLUpdate {
+	t.Helper()
+	select {
+	case update := <-tracker.Updates():
+		return update
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an update")
+		return PnLUpdate{}
+	}
+}
+
+func TestPnLTracker(t *testing.T) {
+	quotes := make(chan tdameritrade.L1EquityQuote)
+	optionQuotes := make(chan tdameritrade.L1OptionQuote)
+	tracker, err := NewPnLTracker(testPositions(t), quotes, optionQuotes)
+	if err != nil {
+		t.Fatalf("NewPnLTracker returned error: %v", err)
+	}
+
+	// Initially, the prices are those of the market values of the positions.
+	if got, want := tracker.TotalUnrealizedPnL(), 100.0+100+50; math.Abs(got-want) > 1e-9 {
+		t.Errorf("initial TotalUnrealizedPnL = %v, want %v", got, want)
+	}
+
+	quotes <- lastPrice("SPY", 312)
+	update := nextUpdate(t, tracker)
+	if update.Symbol != "SPY" || update.Quantity != 10 || update.CostBasis != 3000 || update.CurrentPrice != 312 ||
+		update.UnrealizedPnL != 120 || update.UnrealizedPnLPercent != 4 || update.Timestamp.IsZero() {
+		t.Errorf("unexpected SPY update: %+v", update)
+	}
+
+	// Options are priced by option quotes only.
+	quotes <- lastPrice("SPY_071720C310", 4)
+	optionQuotes <- tdameritrade.L1OptionQuote{Symbol: "SPY_071720C310", BidPrice: 2.9}
+	optionQuotes <- tdameritrade.L1OptionQuote{Symbol: "SPY_071720C310", BidPrice: 2.9, LastPrice: 3}
+	update = nextUpdate(t, tracker)
+	if update.Quantity != -2 || update.CostBasis != -500 || math.Abs(update.UnrealizedPnL+100) > 1e-9 || math.Abs(update.UnrealizedPnLPercent+20) > 1e-9 {
+		t.Errorf("unexpected option update: %+v", update)
+	}
+
+	// Quotes of other symbols or without a last price are ignored.
+	quotes <- lastPrice("IWM", 150)
+	optionQuotes <- tdameritrade.L1OptionQuote{Symbol: "QQQ", LastPrice: 1}
+	quotes <- tdameritrade.L1EquityQuote{Symbol: "QQQ", Fields: map[tdameritrade.L1EquityField]interface{}{tdameritrade.L1EquityBidPrice: 1.0}}
+	quotes <- lastPrice("QQQ", 260)
+	update = nextUpdate(t, tracker)
+	if update.Symbol != "QQQ" || update.UnrealizedPnL != -50 {
+		t.Errorf("unexpected QQQ update: %+v", update)
+	}
+
+	if got, want := tracker.TotalUnrealizedPnL(), 120.0-100-50; math.Abs(got-want) > 1e-9 {
+		t.Errorf("TotalUnrealizedPnL = %v, want %v", got, want)
+	}
+	snapshot := tracker.Snapshot()
+	if len(snapshot) != 3 || snapshot[0].Symbol != "QQQ" || snapshot[1].Symbol != "SPY" || snapshot[2].Symbol != "SPY_071720C310" {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+
+	close(quotes)
+	optionQuotes <- tdameritrade.L1OptionQuote{Symbol: "SPY_071720C310", LastPrice: 2.5}
+	if update := nextUpdate(t, tracker); update.UnrealizedPnL != 0 {
+		t.Errorf("unexpected option update after the equity quotes ended: %+v", update)
+	}
+	close(optionQuotes)
+	if _, ok := <-tracker.Updates(); ok {
+		t.Error("expected the updates channel to be closed")
+	}
+
+	// Without option quotes, the updates channel is closed with the equity quotes channel.
+	quotes = make(chan tdameritrade.L1EquityQuote)
+	if tracker, err = NewPnLTracker(testPositions(t), quotes, nil); err != nil {
+		t.Fatalf("NewPnLTracker returned error: %v", err)
+	}
+	close(quotes)
+	if _, ok := <-tracker.Updates(); ok {
+		t.Error("expected the updates channel to be closed")
+	}
+}
@@ -0,0 +1,102 @@
+package tdameritrade
+
+import "fmt"
+
+// PortfolioGreeks are the Greeks of a set of positions, summed over their options and shares.
+// TotalDelta and TotalGamma are in shares of the underlyings: a delta of 100 moves like 100 shares.
+// TotalTheta, TotalVega and TotalRho are in dollars, per day, per point of volatility and per point of rate.
+type PortfolioGreeks struct {
+	TotalDelta float64
+	TotalGamma float64
+	TotalTheta float64
+	TotalVega  float64
+	TotalRho   float64
+}
+
+// PortfolioDelta returns the Greeks of positions, such as those of an Account.
+// Each option position is looked up by symbol in the chain of its underlying, chain being keyed by underlying
+// symbol, and its Greeks are multiplied by LongQuantity - ShortQuantity and the option multiplier.
+// Shares of equities and ETFs have a delta of 1 per share and no other Greeks. Other positions, such as cash
+// equivalents, are left out.
+func PortfolioDelta(positions []Position, chain map[string]*Chains) (*PortfolioGreeks, error) {
+	greeks := &PortfolioGreeks{}
+	for i := range positions {
+		p := &positions[i]
+		quantity := (p.LongQuantity - p.ShortQuantity) * p.multiplier()
+		switch p.Instrument.AssetType {
+		case "EQUITY", "ETF":
+			greeks.TotalDelta += quantity
+		case "OPTION":
+			option, err := positionOption(p, chain)
+			if err != nil {
+				return nil, err
+			}
+			greeks.TotalDelta += quantity * option.Delta.OrDefault(0)
+			greeks.TotalGamma += quantity * option.Gamma.OrDefault(0)
+			greeks.TotalTheta += quantity * option.Theta.OrDefault(0)
+			greeks.TotalVega += quantity * option.Vega.OrDefault(0)
+			greeks.TotalRho += quantity * option.Rho.OrDefault(0)
+		}
+	}
+	return greeks, nil
+}
+
+// DeltaAdjustedExposure returns the dollar delta of positions: the value of the shares each position moves like,
+// its delta as in PortfolioDelta, at the price of its underlying. Prices are looked up by underlying symbol in
+// prices, falling back to the UnderlyingPrice of the chain of options. It returns an error if an option position
+// is missing from chain or the price of an underlying is unknown.
+func DeltaAdjustedExposure(positions []Position, chain map[string]*Chains, prices map[string]float64) (float64, error) {
+	exposure := 0.0
+	for i := range positions {
+		p := &positions[i]
+		quantity := (p.LongQuantity - p.ShortQuantity) * p.multiplier()
+		underlying, delta := p.Instrument.symbol(), quantity
+		switch p.Instrument.AssetType {
+		case "OPTION":
+			option, err := positionOption(p, chain)
+			if err != nil {
+				return 0, err
+			}
+			underlying = p.Instrument.Data.(*OptionA).UnderlyingSymbol
+			delta *= option.Delta.OrDefault(0)
+		case "EQUITY", "ETF":
+			// Each share moves like one share.
+		default:
+			continue
+		}
+
+		price, ok := prices[underlying]
+		if !ok && chain[underlying] != nil {
+			price, ok = chain[underlying].UnderlyingPrice, true
+		}
+		if !ok {
+			return 0, fmt.Errorf("no price present for %s", underlying)
+		}
+		exposure += delta * price
+	}
+	return exposure, nil
+}
+
+// positionOption returns the option of an option position from the chain of its underlying.
+func positionOption(p *Position, chain map[string]*Chains) (*ExpDateOption, error) {
+	data, ok := p.Instrument.Data.(*OptionA)
+	if !ok {
+		return nil, fmt.Errorf("position of asset type %s is not an option", p.Instrument.AssetType)
+	}
+	c, ok := chain[data.UnderlyingSymbol]
+	if !ok || c == nil {
+		return nil, fmt.Errorf("no chain present for %s, the underlying of %s", data.UnderlyingSymbol, data.Symbol)
+	}
+	for _, m := range []ExpDateMap{c.CallExpDateMap, c.PutExpDateMap} {
+		for _, strikes := range m {
+			for _, options := range strikes {
+				for i := range options {
+					if options[i].Symbol == data.Symbol {
+						return &options[i], nil
+					}
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("no option %s in the chain of %s", data.Symbol, data.UnderlyingSymbol)
+}
@@ -0,0 +1,61 @@
+package tdameritrade
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPortfolioDelta(t *testing.T) {
+	positions := loadAccounts(t)[0].Positions
+	chain := map[string]*Chains{"SPY": loadChains(t)}
+
+	greeks, err := PortfolioDelta(positions, chain)
+	if err != nil {
+		t.Fatalf("PortfolioDelta returned error: %v", err)
+	}
+	// 10 shares of SPY and a short SPY_071720C310 with delta 0.52, gamma 0.029, theta -0.21, vega 0.18 and rho 0.04.
+	want := PortfolioGreeks{TotalDelta: -42, TotalGamma: -2.9, TotalTheta: 21, TotalVega: -18, TotalRho: -4}
+	for _, g := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"TotalDelta", greeks.TotalDelta, want.TotalDelta},
+		{"TotalGamma", greeks.TotalGamma, want.TotalGamma},
+		{"TotalTheta", greeks.TotalTheta, want.TotalTheta},
+		{"TotalVega", greeks.TotalVega, want.TotalVega},
+		{"TotalRho", greeks.TotalRho, want.TotalRho},
+	} {
+		if math.Abs(g.got-g.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", g.name, g.got, g.want)
+		}
+	}
+
+	if _, err := PortfolioDelta(positions, map[string]*Chains{}); err == nil {
+		t.Error("expected an error for an option without a chain")
+	}
+}
+
+func TestDeltaAdjustedExposure(t *testing.T) {
+	positions := loadAccounts(t)[0].Positions
+	chain := map[string]*Chains{"SPY": loadChains(t)}
+
+	exposure, err := DeltaAdjustedExposure(positions, chain, nil)
+	if err != nil {
+		t.Fatalf("DeltaAdjustedExposure returned error: %v", err)
+	}
+	if want := -42 * 310.52; math.Abs(exposure-want) > 1e-6 {
+		t.Errorf("exposure at the chain's price = %v, want %v", exposure, want)
+	}
+
+	exposure, err = DeltaAdjustedExposure(positions, chain, map[string]float64{"SPY": 300})
+	if err != nil {
+		t.Fatalf("DeltaAdjustedExposure returned error: %v", err)
+	}
+	if want := -42 * 300.0; math.Abs(exposure-want) > 1e-6 {
+		t.Errorf("exposure at 300 = %v, want %v", exposure, want)
+	}
+
+	if _, err := DeltaAdjustedExposure(positions[:1], nil, nil); err == nil {
+		t.Error("expected an error for shares without a price")
+	}
+}
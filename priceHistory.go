@@ -2,22 +2,64 @@ package tdameritrade
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/google/go-querystring/query"
 )
 
-var (
-	validPeriodTypes    = []string{"day", "month", "year", "ytd"}
-	validFrequencyTypes = []string{"minute", "daily", "weekly", "monthly"}
-)
+// ErrInvalidParams is returned when a request's parameters are rejected before being sent to TD Ameritrade.
+// Errors returned for illegal parameter combinations wrap it, so callers can check for it with errors.Is.
+var ErrInvalidParams = errors.New("invalid parameters")
+
+// ErrDateRangeExceeded is wrapped by the errors returned for date ranges longer than TD Ameritrade serves,
+// such as more than 10 business days of minute bars.
+var ErrDateRangeExceeded = errors.New("date range exceeded")
 
 const (
-	defaultPeriodType    = "day"
-	defaultFrequencyType = "minute"
+	defaultPeriodType = "day"
+
+	// maxMinuteBarDays is the number of business days of minute bars TD Ameritrade returns for a request.
+	maxMinuteBarDays = 10
 )
 
+var validPeriodTypes = []string{"day", "month", "year", "ytd"}
+
+// validFrequencyTypes holds the frequencyType values TD Ameritrade accepts for each periodType.
+var validFrequencyTypes = map[string][]string{
+	"day":   {"minute"},
+	"month": {"daily", "weekly"},
+	"year":  {"daily", "weekly", "monthly"},
+	"ytd":   {"daily", "weekly"},
+}
+
+// defaultFrequencyTypes holds the frequencyType TD Ameritrade uses for each periodType when none is sent.
+var defaultFrequencyTypes = map[string]string{
+	"day":   "minute",
+	"month": "weekly",
+	"year":  "monthly",
+	"ytd":   "weekly",
+}
+
+// validPeriods holds the period values TD Ameritrade accepts for each periodType.
+var validPeriods = map[string][]int{
+	"day":   {1, 2, 3, 4, 5, 10},
+	"month": {1, 2, 3, 6},
+	"year":  {1, 2, 3, 5, 10, 15, 20},
+	"ytd":   {1},
+}
+
+// validFrequencies holds the frequency values TD Ameritrade accepts for each frequencyType.
+var validFrequencies = map[string][]int{
+	"minute":  {1, 5, 10, 15, 30},
+	"daily":   {1},
+	"weekly":  {1},
+	"monthly": {1},
+}
+
 // PriceHistoryService handles communication with the marketdata related methods of
 // the TDAmeritrade API.
 //
@@ -26,49 +68,50 @@ type PriceHistoryService struct {
 	client *Client
 }
 
-// PriceHistoryOptions is parsed and translated to query options in the https request
-//cannot use time.Time in StartDate and EndDate. Date must be Epoch time.
-//Use new function ConvertToEpoch() which returns an int64.
-//This is per the documentation from TD AMERITRADE.
-//also, omitempty must be set because if you set a start and end date, you cannot send the "period" value or it will error.
-type PriceHistoryOptions struct {
-	PeriodType            string `url:"periodType,omitempty"`
-	Period                int    `url:"period,omitempty"`
-	FrequencyType         string `url:"frequencyType,omitempty"`
-	Frequency             int    `url:"frequency,omitempty"`
-	EndDate               int64  `url:"endDate,omitempty"`
-	StartDate             int64  `url:"startDate,omitempty"`
-	NeedExtendedHoursData *bool  `url:"needExtendedHoursData"`
+// PriceHistoryParams is parsed and translated to query options in the https request.
+// Zero values are left out of the request so TD Ameritrade's defaults apply.
+// If both StartDate and EndDate are set, Period must be left empty or TD Ameritrade will reject the request.
+type PriceHistoryParams struct {
+	PeriodType            string    `url:"periodType,omitempty"`
+	Period                int       `url:"period,omitempty"`
+	FrequencyType         string    `url:"frequencyType,omitempty"`
+	Frequency             int       `url:"frequency,omitempty"`
+	EndDate               time.Time `url:"-"`
+	StartDate             time.Time `url:"-"`
+	NeedExtendedHoursData *bool     `url:"needExtendedHoursData,omitempty"`
 }
 
+// PriceHistory is the list of candles TD Ameritrade returns for a symbol.
 type PriceHistory struct {
-	Candles []struct {
-		Close    float64 `json:"close"`
-		Datetime int     `json:"datetime"`
-		High     float64 `json:"high"`
-		Low      float64 `json:"low"`
-		Open     float64 `json:"open"`
-		Volume   float64 `json:"volume"`
-	} `json:"candles"`
-	Empty  bool   `json:"empty"`
-	Symbol string `json:"symbol"`
-}
-
-// PriceHistory get the price history for a symbol
+	Candles []Candle `json:"candles"`
+	Empty   bool     `json:"empty"`
+	Symbol  string   `json:"symbol"`
+}
+
+// Candle is a single OHLCV bar.
+// DateTime is the start of the bar in milliseconds since the Unix epoch.
+type Candle struct {
+	Open     float64 `json:"open"`
+	High     float64 `json:"high"`
+	Low      float64 `json:"low"`
+	Close    float64 `json:"close"`
+	Volume   float64 `json:"volume"`
+	DateTime int64   `json:"datetime"`
+}
+
+// GetPriceHistory get the price history for a symbol
 // TDAmeritrade API Docs: https://developer.tdameritrade.com/price-history/apis/get/marketdata/%7Bsymbol%7D/pricehistory
-func (s *PriceHistoryService) PriceHistory(ctx context.Context, symbol string, opts *PriceHistoryOptions) (*PriceHistory, *Response, error) {
-	u := fmt.Sprintf("marketdata/%s/pricehistory", symbol)
-	if opts != nil {
-		if err := opts.validate(); err != nil {
-			return nil, nil, err
-		}
-		q, err := query.Values(opts)
-		if err != nil {
-			return nil, nil, err
-		}
-		u = fmt.Sprintf("%s?%s", u, q.Encode())
+func (s *PriceHistoryService) GetPriceHistory(ctx context.Context, symbol string, params PriceHistoryParams) (*PriceHistory, *Response, error) {
+	if err := params.Validate(); err != nil {
+		return nil, nil, err
 	}
 
+	q, err := params.values()
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf("marketdata/%s/pricehistory?%s", url.PathEscape(symbol), q.Encode())
+
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -85,26 +128,160 @@ func (s *PriceHistoryService) PriceHistory(ctx context.Context, symbol string, o
 	return priceHistory, resp, nil
 }
 
-func (opts *PriceHistoryOptions) validate() error {
-	if opts.PeriodType != "" {
-		if !contains(opts.PeriodType, validPeriodTypes) {
-			return fmt.Errorf("invalid periodType, must have the value of one of the following %v", validPeriodTypes)
+// GetDailyBars returns the daily candles of symbol from the day of from to the day of to.
+// It picks the periodType for the range, month for up to six months and year beyond, so callers do not
+// have to; period is left out of the request since both dates are sent.
+func (s *PriceHistoryService) GetDailyBars(ctx context.Context, symbol string, from, to time.Time) ([]Candle, error) {
+	params, err := dailyBarsParams(from, to)
+	if err != nil {
+		return nil, err
+	}
+	ph, _, err := s.GetPriceHistory(ctx, symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	return ph.Candles, nil
+}
+
+// GetMinuteBars returns the candles of freqMin minutes, one of 1, 5, 10, 15 or 30, of symbol from from to to.
+// TD Ameritrade returns at most 10 business days of minute bars, so longer ranges, counting weekdays from
+// the day of from to the day of to, return an error wrapping ErrDateRangeExceeded. Holidays are counted as
+// business days.
+func (s *PriceHistoryService) GetMinuteBars(ctx context.Context, symbol string, from, to time.Time, freqMin int) ([]Candle, error) {
+	params, err := minuteBarsParams(from, to, freqMin)
+	if err != nil {
+		return nil, err
+	}
+	ph, _, err := s.GetPriceHistory(ctx, symbol, params)
+	if err != nil {
+		return nil, err
+	}
+	return ph.Candles, nil
+}
+
+// dailyBarsParams returns the params of GetDailyBars.
+func dailyBarsParams(from, to time.Time) (PriceHistoryParams, error) {
+	if err := validateDateRange(from, to); err != nil {
+		return PriceHistoryParams{}, err
+	}
+	periodType := "month"
+	if to.After(from.AddDate(0, 6, 0)) {
+		periodType = "year"
+	}
+	return PriceHistoryParams{
+		PeriodType:    periodType,
+		FrequencyType: "daily",
+		Frequency:     1,
+		StartDate:     from,
+		EndDate:       to,
+	}, nil
+}
+
+// minuteBarsParams returns the params of GetMinuteBars.
+func minuteBarsParams(from, to time.Time, freqMin int) (PriceHistoryParams, error) {
+	if err := validateDateRange(from, to); err != nil {
+		return PriceHistoryParams{}, err
+	}
+	if !containsInt(freqMin, validFrequencies["minute"]) {
+		return PriceHistoryParams{}, fmt.Errorf("%w: minute bars must be of %v minutes, got %d", ErrInvalidParams, validFrequencies["minute"], freqMin)
+	}
+	if days := businessDays(from, to); days > maxMinuteBarDays {
+		return PriceHistoryParams{}, fmt.Errorf("%w: minute bars are limited to %d business days, got %d from %s to %s",
+			ErrDateRangeExceeded, maxMinuteBarDays, days, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	return PriceHistoryParams{
+		PeriodType:    "day",
+		FrequencyType: "minute",
+		Frequency:     freqMin,
+		StartDate:     from,
+		EndDate:       to,
+	}, nil
+}
+
+func validateDateRange(from, to time.Time) error {
+	if from.IsZero() || to.IsZero() {
+		return fmt.Errorf("%w: both from and to must be set", ErrInvalidParams)
+	}
+	if to.Before(from) {
+		return fmt.Errorf("%w: to %v is before from %v", ErrInvalidParams, to, from)
+	}
+	return nil
+}
+
+// businessDays returns the number of weekdays from the day of from to the day of to, both included,
+// in the location of from.
+func businessDays(from, to time.Time) int {
+	day := Day1.start(from)
+	last := Day1.start(to.In(from.Location()))
+	days := 0
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return days
+}
+
+// Validate checks that the combination of period and frequency parameters is one TD Ameritrade accepts.
+// An empty PeriodType is treated as TD Ameritrade's default of "day".
+// Errors returned by Validate wrap ErrInvalidParams.
+func (p PriceHistoryParams) Validate() error {
+	periodType := p.PeriodType
+	if periodType == "" {
+		periodType = defaultPeriodType
+	}
+
+	frequencyTypes, ok := validFrequencyTypes[periodType]
+	if !ok {
+		return fmt.Errorf("%w: periodType must be one of %v, got %q", ErrInvalidParams, validPeriodTypes, p.PeriodType)
+	}
+
+	if p.FrequencyType != "" && !contains(p.FrequencyType, frequencyTypes) {
+		return fmt.Errorf("%w: periodType %q only allows frequencyType %v, got %q", ErrInvalidParams, periodType, frequencyTypes, p.FrequencyType)
+	}
+
+	if p.Period != 0 && !containsInt(p.Period, validPeriods[periodType]) {
+		return fmt.Errorf("%w: periodType %q only allows period %v, got %d", ErrInvalidParams, periodType, validPeriods[periodType], p.Period)
+	}
+
+	if p.Frequency != 0 {
+		frequencyType := p.FrequencyType
+		if frequencyType == "" {
+			frequencyType = defaultFrequencyTypes[periodType]
+		}
+		if !containsInt(p.Frequency, validFrequencies[frequencyType]) {
+			return fmt.Errorf("%w: frequencyType %q only allows frequency %v, got %d", ErrInvalidParams, frequencyType, validFrequencies[frequencyType], p.Frequency)
 		}
-	} else {
-		opts.PeriodType = defaultPeriodType
 	}
 
-	if opts.FrequencyType != "" {
-		if !contains(opts.FrequencyType, validFrequencyTypes) {
-			return fmt.Errorf("invalid frequencyType, must have the value of one of the following %v", validFrequencyTypes)
+	if !p.StartDate.IsZero() && !p.EndDate.IsZero() {
+		if p.Period != 0 {
+			return fmt.Errorf("%w: period cannot be set along with both startDate and endDate", ErrInvalidParams)
+		}
+		if p.EndDate.Before(p.StartDate) {
+			return fmt.Errorf("%w: endDate %v is before startDate %v", ErrInvalidParams, p.EndDate, p.StartDate)
 		}
-	} else {
-		opts.PeriodType = defaultFrequencyType
 	}
 
 	return nil
 }
 
+// values encodes the params as query options.
+// TD Ameritrade expects dates as milliseconds since the Unix epoch.
+func (p PriceHistoryParams) values() (url.Values, error) {
+	q, err := query.Values(p)
+	if err != nil {
+		return nil, err
+	}
+	if !p.StartDate.IsZero() {
+		q.Set("startDate", strconv.FormatInt(ConvertToEpoch(p.StartDate), 10))
+	}
+	if !p.EndDate.IsZero() {
+		q.Set("endDate", strconv.FormatInt(ConvertToEpoch(p.EndDate), 10))
+	}
+	return q, nil
+}
+
 func contains(s string, lst []string) bool {
 	for _, e := range lst {
 		if e == s {
@@ -114,6 +291,15 @@ func contains(s string, lst []string) bool {
 	return false
 }
 
+func containsInt(i int, lst []int) bool {
+	for _, e := range lst {
+		if e == i {
+			return true
+		}
+	}
+	return false
+}
+
 func ConvertToEpoch(t time.Time) int64 {
 
 	return t.Round(time.Millisecond).UnixNano() / 1e6
@@ -0,0 +1,188 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPriceHistoryParamsValidate(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name   string
+		params PriceHistoryParams
+		valid  bool
+	}{
+		{"defaults", PriceHistoryParams{}, true},
+		{"day minute", PriceHistoryParams{PeriodType: "day", Period: 5, FrequencyType: "minute", Frequency: 15}, true},
+		{"day daily", PriceHistoryParams{PeriodType: "day", FrequencyType: "daily"}, false},
+		{"month weekly", PriceHistoryParams{PeriodType: "month", Period: 6, FrequencyType: "weekly"}, true},
+		{"month minute", PriceHistoryParams{PeriodType: "month", FrequencyType: "minute"}, false},
+		{"year bad period", PriceHistoryParams{PeriodType: "year", Period: 4}, false},
+		{"daily frequency 5", PriceHistoryParams{PeriodType: "year", FrequencyType: "daily", Frequency: 5}, false},
+		{"unknown periodType", PriceHistoryParams{PeriodType: "week"}, false},
+		{"date range", PriceHistoryParams{PeriodType: "month", FrequencyType: "daily", StartDate: start, EndDate: end}, true},
+		{"date range with period", PriceHistoryParams{Period: 1, StartDate: start, EndDate: end}, false},
+		{"reversed date range", PriceHistoryParams{StartDate: end, EndDate: start}, false},
+	}
+
+	for _, tt := range tests {
+		err := tt.params.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("%s: expected ErrInvalidParams, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestGetPriceHistory(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	mux.HandleFunc("/marketdata/SPY/pricehistory", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "periodType", "month")
+		testFormValue(t, r, "frequencyType", "daily")
+		testFormValue(t, r, "startDate", "1590969600000")
+		testFormValue(t, r, "period", "")
+		fmt.Fprint(w, `{"candles":[{"open":300.1,"high":305.2,"low":299.3,"close":304.4,"volume":1000,"datetime":1590969600000}],"symbol":"SPY","empty":false}`)
+	})
+
+	params := PriceHistoryParams{PeriodType: "month", FrequencyType: "daily", StartDate: start}
+	ph, _, err := client.PriceHistory.GetPriceHistory(context.Background(), "SPY", params)
+	if err != nil {
+		t.Fatalf("GetPriceHistory returned error: %v", err)
+	}
+
+	want := Candle{Open: 300.1, High: 305.2, Low: 299.3, Close: 304.4, Volume: 1000, DateTime: 1590969600000}
+	if len(ph.Candles) != 1 || ph.Candles[0] != want {
+		t.Errorf("unexpected candles: %+v", ph.Candles)
+	}
+}
+
+func TestGetPriceHistoryInvalidParams(t *testing.T) {
+	client, _, teardown := setup(t)
+	defer teardown()
+
+	_, resp, err := client.PriceHistory.GetPriceHistory(context.Background(), "SPY", PriceHistoryParams{PeriodType: "day", FrequencyType: "monthly"})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	if resp != nil {
+		t.Errorf("expected no request to be made")
+	}
+}
+
+func TestUnmarshalPriceHistory(t *testing.T) {
+	testJSONRoundTrip(t, "testdata/pricehistory.json", &PriceHistory{})
+}
+
+func TestBarsParams(t *testing.T) {
+	// Monday June 1st 2020.
+	monday := time.Date(2020, 6, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		params  func() (PriceHistoryParams, error)
+		want    PriceHistoryParams
+		wantErr error
+	}{
+		{
+			name:   "daily bars over a week",
+			params: func() (PriceHistoryParams, error) { return dailyBarsParams(monday, monday.AddDate(0, 0, 7)) },
+			want:   PriceHistoryParams{PeriodType: "month", FrequencyType: "daily", Frequency: 1, StartDate: monday, EndDate: monday.AddDate(0, 0, 7)},
+		},
+		{
+			name:   "daily bars over six months",
+			params: func() (PriceHistoryParams, error) { return dailyBarsParams(monday, monday.AddDate(0, 6, 0)) },
+			want:   PriceHistoryParams{PeriodType: "month", FrequencyType: "daily", Frequency: 1, StartDate: monday, EndDate: monday.AddDate(0, 6, 0)},
+		},
+		{
+			name:   "daily bars over two years",
+			params: func() (PriceHistoryParams, error) { return dailyBarsParams(monday, monday.AddDate(2, 0, 0)) },
+			want:   PriceHistoryParams{PeriodType: "year", FrequencyType: "daily", Frequency: 1, StartDate: monday, EndDate: monday.AddDate(2, 0, 0)},
+		},
+		{
+			name:    "daily bars reversed",
+			params:  func() (PriceHistoryParams, error) { return dailyBarsParams(monday, monday.AddDate(0, 0, -1)) },
+			wantErr: ErrInvalidParams,
+		},
+		{
+			name:   "minute bars over a day",
+			params: func() (PriceHistoryParams, error) { return minuteBarsParams(monday, monday.Add(6*time.Hour), 5) },
+			want:   PriceHistoryParams{PeriodType: "day", FrequencyType: "minute", Frequency: 5, StartDate: monday, EndDate: monday.Add(6 * time.Hour)},
+		},
+		{
+			// Monday to the Friday of the next week is 10 business days.
+			name:   "minute bars over two weeks",
+			params: func() (PriceHistoryParams, error) { return minuteBarsParams(monday, monday.AddDate(0, 0, 11), 1) },
+			want:   PriceHistoryParams{PeriodType: "day", FrequencyType: "minute", Frequency: 1, StartDate: monday, EndDate: monday.AddDate(0, 0, 11)},
+		},
+		{
+			name:    "minute bars over eleven business days",
+			params:  func() (PriceHistoryParams, error) { return minuteBarsParams(monday, monday.AddDate(0, 0, 14), 1) },
+			wantErr: ErrDateRangeExceeded,
+		},
+		{
+			name:    "minute bars of 2 minutes",
+			params:  func() (PriceHistoryParams, error) { return minuteBarsParams(monday, monday.Add(time.Hour), 2) },
+			wantErr: ErrInvalidParams,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.params()
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: params = %+v, want %+v", tt.name, got, tt.want)
+		}
+		if err := got.Validate(); err != nil {
+			t.Errorf("%s: params rejected: %v", tt.name, err)
+		}
+	}
+}
+
+func TestGetMinuteBars(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	mux.HandleFunc("/marketdata/SPY/pricehistory", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "periodType", "day")
+		testFormValue(t, r, "frequencyType", "minute")
+		testFormValue(t, r, "frequency", "15")
+		testFormValue(t, r, "startDate", "1590969600000")
+		testFormValue(t, r, "endDate", "1591056000000")
+		fmt.Fprint(w, `{"candles":[{"open":300.1,"high":305.2,"low":299.3,"close":304.4,"volume":1000,"datetime":1590969600000}],"symbol":"SPY","empty":false}`)
+	})
+
+	candles, err := client.PriceHistory.GetMinuteBars(context.Background(), "SPY", start, start.AddDate(0, 0, 1), 15)
+	if err != nil {
+		t.Fatalf("GetMinuteBars returned error: %v", err)
+	}
+	if len(candles) != 1 || candles[0].Close != 304.4 {
+		t.Errorf("unexpected candles: %+v", candles)
+	}
+
+	if _, err := client.PriceHistory.GetMinuteBars(context.Background(), "SPY", start, start.AddDate(0, 1, 0), 15); !errors.Is(err, ErrDateRangeExceeded) {
+		t.Errorf("expected ErrDateRangeExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,337 @@
+// Package proxy serves the TD Ameritrade API to browsers through a tdameritrade.Client, so that web front-ends
+// can call the API without holding its credentials, which stay with the Client.
+//
+// The handler spends the Client's credentials on behalf of whoever can reach it, so by default it only answers
+// requests addressed to a loopback host, only proxies GET requests for market data and instruments, and only
+// proxies requests that may change state, such as placing orders, when enabled with WithOrderEndpoints and
+// sent by an allowed origin.
+//
+// Usage example:
+// http.Handle("/api/", proxy.NewProxyHandler(client, proxy.WithPathPrefix("/api"), proxy.WithAllowedOrigins("http://localhost:3000")))
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// DefaultMaxBodySize is the largest request body proxied by default.
+const DefaultMaxBodySize = 1 << 20
+
+// readMethods are the methods proxied for allowed endpoints and orderMethods those proxied for order endpoints,
+// once enabled with WithOrderEndpoints.
+var (
+	readMethods  = []string{"GET"}
+	orderMethods = []string{"GET", "POST", "PUT", "DELETE"}
+)
+
+// defaultEndpoints are the endpoints proxied without WithAllowedEndpoints: market data and instruments.
+var defaultEndpoints = []string{"marketdata/", "instruments", "instruments/"}
+
+// defaultHosts are the hosts the handler answers without WithAllowedHosts.
+var defaultHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// forwardedHeaders are the headers of TD Ameritrade responses sent on to the browser.
+var forwardedHeaders = []string{"Content-Type", "Location", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
+// Option configures a handler returned by NewProxyHandler.
+type Option func(*handler)
+
+// WithPathPrefix strips prefix, e.g. /api/tda, from the paths of requests before proxying them.
+// Requests for paths without it are answered with 404 Not Found.
+func WithPathPrefix(prefix string) Option {
+	return func(h *handler) {
+		h.prefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithAllowedEndpoints sets the endpoints proxied for GET requests, paths relative to the API such as
+// marketdata/chains. An endpoint ending with a slash, such as marketdata/, allows every path under it.
+// Requests for other endpoints are answered with 403 Forbidden. Without this option the market data and
+// instruments endpoints are proxied.
+func WithAllowedEndpoints(endpoints ...string) Option {
+	return func(h *handler) {
+		h.endpoints = append(h.endpoints, endpoints...)
+	}
+}
+
+// WithOrderEndpoints proxies the orders of accounts, accounts/{accountId}/orders and
+// accounts/{accountId}/orders/{orderId}, including POST, PUT and DELETE requests to place, replace and cancel
+// orders. Those requests must come from an allowed origin, see WithAllowedOrigins.
+func WithOrderEndpoints() Option {
+	return func(h *handler) {
+		h.orders = true
+	}
+}
+
+// WithAllowedHosts sets the hosts, e.g. trading.example.com, the handler answers requests for, matched against
+// the Host header without its port. Requests for other hosts are answered with 403 Forbidden, so that a page
+// whose domain is rebound to the handler's address cannot use it. Without this option only localhost,
+// 127.0.0.1 and ::1 are answered.
+func WithAllowedHosts(hosts ...string) Option {
+	return func(h *handler) {
+		h.hosts = append(h.hosts, hosts...)
+	}
+}
+
+// WithAllowedOrigins sets the origins, e.g. http://localhost:3000, allowed to call the handler from another
+// origin, or "*" for any origin. Cross-origin requests from other origins are answered with 403 Forbidden.
+// Without this option only requests from the handler's own origin are proxied.
+func WithAllowedOrigins(origins ...string) Option {
+	return func(h *handler) {
+		h.origins = append(h.origins, origins...)
+	}
+}
+
+// WithMaxBodySize limits request bodies to n bytes, DefaultMaxBodySize by default.
+// Larger requests are answered with 413 Request Entity Too Large.
+func WithMaxBodySize(n int64) Option {
+	return func(h *handler) {
+		h.maxBodySize = n
+	}
+}
+
+// WithLogger logs each proxied request to l, with the apikey parameter removed from its URL.
+func WithLogger(l *log.Logger) Option {
+	return func(h *handler) {
+		h.logger = l
+	}
+}
+
+// NewProxyHandler returns an http.Handler sending the requests it receives to TD Ameritrade with client,
+// which authenticates them, and answering them with TD Ameritrade's responses and CORS headers.
+// Without options it only answers requests for loopback hosts, and proxies GET requests for market data and
+// instruments from the same origin or without an origin; see WithAllowedHosts, WithAllowedEndpoints,
+// WithOrderEndpoints and WithAllowedOrigins.
+func NewProxyHandler(client *tdameritrade.Client, opts ...Option) http.Handler {
+	h := &handler{client: client, maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+type handler struct {
+	client      *tdameritrade.Client
+	prefix      string
+	endpoints   []string
+	orders      bool
+	hosts       []string
+	origins     []string
+	maxBodySize int64
+	logger      *log.Logger
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.allowHost(r.Host) {
+		http.Error(w, "host not allowed", http.StatusForbidden)
+		return
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin != "" {
+		if !h.allowOrigin(origin, r) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
+
+	if r.Method == "OPTIONS" {
+		h.preflight(w, r)
+		return
+	}
+	if !contains(r.Method, h.methods()) {
+		w.Header().Set("Allow", strings.Join(h.methods(), ", "))
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Browsers send the Origin of every request that is not a GET or HEAD, so one without it is not from a page
+	// whose origin could be checked.
+	if r.Method != "GET" && origin == "" {
+		http.Error(w, "origin required", http.StatusForbidden)
+		return
+	}
+
+	endpoint, ok := h.endpoint(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	methods := h.endpointMethods(endpoint)
+	if methods == nil {
+		http.Error(w, "endpoint not allowed", http.StatusForbidden)
+		return
+	}
+	if !contains(r.Method, methods) {
+		w.Header().Set("Allow", strings.Join(methods, ", "))
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	u := endpoint
+	if r.URL.RawQuery != "" {
+		u += "?" + r.URL.RawQuery
+	}
+	req, err := h.client.NewRequest(r.Method, u, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > 0 {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(bytes.NewReader(body)), nil }
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+	}
+
+	var buf bytes.Buffer
+	resp, err := h.client.Do(tdameritrade.WithTraceContext(r.Context(), r), req, &buf)
+	var apiErr *tdameritrade.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		buf.Reset()
+		buf.Write(apiErr.RawBody)
+	case err != nil:
+		h.log(req, http.StatusBadGateway)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	for _, key := range forwardedHeaders {
+		if v := resp.Header.Get(key); v != "" {
+			w.Header().Set(key, v)
+		}
+	}
+	h.log(req, resp.StatusCode)
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// preflight answers a CORS preflight request, whose origin is already allowed.
+func (h *handler) preflight(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Origin") != "" {
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(h.methods(), ", "))
+		headers := r.Header.Get("Access-Control-Request-Headers")
+		if headers == "" {
+			headers = "Content-Type"
+		}
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+		w.Header().Set("Access-Control-Max-Age", "600")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// endpoint returns the path of the API requested by p, cleaned so that it cannot escape the allowed endpoints,
+// and whether p has the handler's prefix.
+func (h *handler) endpoint(p string) (string, bool) {
+	p = path.Clean("/" + p)
+	if h.prefix != "" {
+		if p != h.prefix && !strings.HasPrefix(p, h.prefix+"/") {
+			return "", false
+		}
+		p = strings.TrimPrefix(p, h.prefix)
+	}
+	p = strings.TrimPrefix(p, "/")
+	return p, p != ""
+}
+
+// methods returns the methods the handler proxies for any endpoint.
+func (h *handler) methods() []string {
+	if h.orders {
+		return orderMethods
+	}
+	return readMethods
+}
+
+// endpointMethods returns the methods proxied for endpoint, or nil if it is not proxied.
+func (h *handler) endpointMethods(endpoint string) []string {
+	if h.orders && isOrderEndpoint(endpoint) {
+		return orderMethods
+	}
+	endpoints := h.endpoints
+	if len(endpoints) == 0 {
+		endpoints = defaultEndpoints
+	}
+	for _, e := range endpoints {
+		if endpoint == strings.TrimSuffix(e, "/") || strings.HasSuffix(e, "/") && strings.HasPrefix(endpoint, e) {
+			return readMethods
+		}
+	}
+	return nil
+}
+
+// isOrderEndpoint reports whether endpoint is accounts/{accountId}/orders or accounts/{accountId}/orders/{orderId}.
+func isOrderEndpoint(endpoint string) bool {
+	parts := strings.Split(endpoint, "/")
+	return (len(parts) == 3 || len(parts) == 4) && parts[0] == "accounts" && parts[1] != "" && parts[2] == "orders" &&
+		(len(parts) == 3 || parts[3] != "")
+}
+
+func (h *handler) allowHost(host string) bool {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.Trim(host, "[]")
+	hosts := h.hosts
+	if len(hosts) == 0 {
+		hosts = defaultHosts
+	}
+	for _, allowed := range hosts {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowOrigin reports whether origin may call the handler: it is the handler's own origin, whose host was
+// already allowed, or one of the allowed origins.
+func (h *handler) allowOrigin(origin string, r *http.Request) bool {
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		return true
+	}
+	for _, o := range h.origins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *handler) log(req *http.Request, status int) {
+	if h.logger == nil {
+		return
+	}
+	u := *req.URL
+	q := u.Query()
+	q.Del("apikey")
+	u.RawQuery = q.Encode()
+	h.logger.Printf("%s %s %d", req.Method, u.String(), status)
+}
+
+func contains(s string, values []string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package proxy
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kuzmak/go-tdameritrade"
+	"github.com/kuzmak/go-tdameritrade/testutil"
+)
+
+// setup returns a MockServer for TD Ameritrade and a proxy to it, authenticating with an api key.
+func setup(t *testing.T, opts ...Option) (*testutil.MockServer, *httptest.Server) {
+	t.Helper()
+	server := testutil.NewMockServer()
+	client, err := tdameritrade.NewSandboxClient("secret", tdameritrade.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, httptest.NewServer(NewProxyHandler(client, opts...))
+}
+
+func TestProxyHandler(t *testing.T) {
+	var logs bytes.Buffer
+	server, proxy := setup(t, WithPathPrefix("/api/tda"), WithOrderEndpoints(), WithLogger(log.New(&logs, "", 0)))
+	defer server.Close()
+	defer proxy.Close()
+
+	server.ExpectGET("/marketdata/SPY/quotes", `{"SPY":{"symbol":"SPY","lastPrice":310.5}}`).
+		WithHeader("X-RateLimit-Remaining", "119")
+	server.ExpectPOST("/accounts/123/orders", func(body []byte) error { return nil }, "", http.StatusCreated).
+		WithHeader("Location", "https://api.tdameritrade.com/v1/accounts/123/orders/456")
+
+	resp, err := http.Get(proxy.URL + "/api/tda/marketdata/SPY/quotes?fields=lastPrice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"lastPrice":310.5`) {
+		t.Errorf("GET quotes returned %d %s", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "119" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 119", got)
+	}
+
+	req, _ := http.NewRequest("POST", proxy.URL+"/api/tda/accounts/123/orders", strings.NewReader(`{"orderType":"MARKET"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", proxy.URL)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || !strings.HasSuffix(resp.Header.Get("Location"), "/orders/456") {
+		t.Errorf("POST order returned %d with Location %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	server.AssertExpectationsMet(t)
+	calls := server.Calls()
+	if !strings.Contains(calls[0].Query, "apikey=secret") || !strings.Contains(calls[0].Query, "fields=lastPrice") {
+		t.Errorf("proxied query %q, want the api key and the query of the request", calls[0].Query)
+	}
+	if string(calls[1].Body) != `{"orderType":"MARKET"}` {
+		t.Errorf("proxied body %s, want the body of the request", calls[1].Body)
+	}
+	if strings.Contains(logs.String(), "secret") || !strings.Contains(logs.String(), "/marketdata/SPY/quotes?fields=lastPrice 200") {
+		t.Errorf("unexpected logs:\n%s", logs.String())
+	}
+}
+
+func TestProxyHandlerCORS(t *testing.T) {
+	server, proxy := setup(t, WithAllowedOrigins("http://localhost:3000"))
+	defer server.Close()
+	defer proxy.Close()
+
+	req, _ := http.NewRequest("OPTIONS", proxy.URL+"/marketdata/chains", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type, X-Request-ID")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("preflight returned %d, want 204", resp.StatusCode)
+	}
+	for key, want := range map[string]string{
+		"Access-Control-Allow-Origin":  "http://localhost:3000",
+		"Access-Control-Allow-Methods": "GET",
+		"Access-Control-Allow-Headers": "Content-Type, X-Request-ID",
+		"Vary":                         "Origin",
+	} {
+		if got := resp.Header.Get(key); got != want {
+			t.Errorf("preflight %s = %q, want %q", key, got, want)
+		}
+	}
+
+	server.ExpectGET("/marketdata/chains", `{"symbol":"SPY"}`)
+	req, _ = http.NewRequest("GET", proxy.URL+"/marketdata/chains", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); resp.StatusCode != http.StatusOK || got != "http://localhost:3000" {
+		t.Errorf("GET returned %d with Access-Control-Allow-Origin %q", resp.StatusCode, got)
+	}
+
+	for _, method := range []string{"OPTIONS", "GET"} {
+		req, _ = http.NewRequest(method, proxy.URL+"/marketdata/chains", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("%s from another origin returned %d, want 403 without CORS headers", method, resp.StatusCode)
+		}
+	}
+	server.AssertExpectationsMet(t)
+}
+
+func TestProxyHandlerRejections(t *testing.T) {
+	server, proxy := setup(t, WithPathPrefix("/api"), WithAllowedEndpoints("marketdata/", "instruments"), WithOrderEndpoints(), WithMaxBodySize(16))
+	defer server.Close()
+	defer proxy.Close()
+
+	server.ExpectGET("/marketdata/SPY/quotes", `{}`)
+	server.ExpectGET("/instruments", `{}`)
+	server.ExpectPOST("/accounts/123/orders", nil, ``, http.StatusCreated)
+
+	for _, test := range []struct {
+		method, path, body, origin string
+		want                       int
+	}{
+		{"GET", "/api/marketdata/SPY/quotes", "", "", http.StatusOK},
+		{"GET", "/api/instruments", "", "", http.StatusOK},
+		{"GET", "/api/instruments/123", "", "", http.StatusForbidden},
+		{"GET", "/api/accounts", "", "", http.StatusForbidden},
+		{"GET", "/api/marketdata/../accounts", "", "", http.StatusForbidden},
+		{"GET", "/marketdata/SPY/quotes", "", "", http.StatusNotFound},
+		{"HEAD", "/api/marketdata/SPY/quotes", "", "", http.StatusMethodNotAllowed},
+		{"PATCH", "/api/marketdata/SPY/quotes", "", proxy.URL, http.StatusMethodNotAllowed},
+		{"POST", "/api/marketdata/SPY/quotes", `{"a":1}`, proxy.URL, http.StatusMethodNotAllowed},
+		{"POST", "/api/accounts/123/orders", `{"a":1}`, "", http.StatusForbidden},
+		{"DELETE", "/api/accounts/123/orders/456", "", "", http.StatusForbidden},
+		{"POST", "/api/accounts/123/orders", `{"a":1}`, proxy.URL, http.StatusCreated},
+		{"POST", "/api/accounts/123/orders", `{"a":"longer than 16 bytes"}`, proxy.URL, http.StatusRequestEntityTooLarge},
+		{"POST", "/api/accounts/123/watchlists", `{"a":1}`, proxy.URL, http.StatusForbidden},
+	} {
+		req, _ := http.NewRequest(test.method, proxy.URL+test.path, strings.NewReader(test.body))
+		if test.origin != "" {
+			req.Header.Set("Origin", test.origin)
+		}
+		// Keep the client from cleaning the path itself.
+		req.URL.Opaque = test.path
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.want {
+			t.Errorf("%s %s returned %d, want %d", test.method, test.path, resp.StatusCode, test.want)
+		}
+	}
+	server.AssertExpectationsMet(t)
+}
+
+func TestProxyHandlerDefaults(t *testing.T) {
+	server, proxy := setup(t)
+	defer server.Close()
+	defer proxy.Close()
+
+	server.ExpectGET("/marketdata/SPY/quotes", `{}`)
+	server.ExpectGET("/instruments", `{}`)
+	for _, test := range []struct {
+		method, path, host string
+		want               int
+	}{
+		{"GET", "/marketdata/SPY/quotes", "", http.StatusOK},
+		{"GET", "/instruments", "localhost", http.StatusOK},
+		// A page whose domain was rebound to the proxy's address.
+		{"GET", "/marketdata/SPY/quotes", "evil.example.com", http.StatusForbidden},
+		{"GET", "/accounts/123/orders", "", http.StatusForbidden},
+		{"DELETE", "/accounts/123/orders/456", "", http.StatusMethodNotAllowed},
+	} {
+		req, _ := http.NewRequest(test.method, proxy.URL+test.path, nil)
+		if test.host != "" {
+			req.Host = test.host
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.want {
+			t.Errorf("%s %s for host %q returned %d, want %d", test.method, test.path, test.host, resp.StatusCode, test.want)
+		}
+	}
+	server.AssertExpectationsMet(t)
+
+	hostedServer, hosted := setup(t, WithAllowedHosts("trading.example.com"))
+	defer hostedServer.Close()
+	defer hosted.Close()
+	req, _ := http.NewRequest("GET", hosted.URL+"/marketdata/SPY/quotes", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("request for a host left out of WithAllowedHosts returned %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestProxyHandlerAPIError(t *testing.T) {
+	server, proxy := setup(t)
+	defer server.Close()
+	defer proxy.Close()
+
+	resp, err := http.Get(proxy.URL + "/marketdata/QQQ/quotes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotImplemented || !strings.Contains(string(body), "unexpected request") {
+		t.Errorf("unexpected request returned %d %s, want the response of TD Ameritrade", resp.StatusCode, body)
+	}
+}
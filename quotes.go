@@ -3,6 +3,8 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 )
 
 // QuotesService handles communication with the marketdata related methods of
@@ -16,97 +18,213 @@ type QuotesService struct {
 type Quotes map[string]*Quote
 
 type Quote struct {
-	AssetType                          string  `json:"assetType"`
-	AssetMainType                      string  `json:"assetMainType"`
-	Cusip                              string  `json:"cusip"`
-	AssetSubType                       string  `json:"assetSubType"`
-	Symbol                             string  `json:"symbol"`
-	Description                        string  `json:"description"`
-	BidPrice                           float64 `json:"bidPrice"`
-	BidSize                            float64 `json:"bidSize"`
-	BidID                              string  `json:"bidId"`
-	AskPrice                           float64 `json:"askPrice"`
-	AskSize                            float64 `json:"askSize"`
-	AskID                              string  `json:"askId"`
-	LastPrice                          float64 `json:"lastPrice"`
-	LastSize                           float64 `json:"lastSize"`
-	LastID                             string  `json:"lastId"`
-	OpenPrice                          float64 `json:"openPrice"`
-	HighPrice                          float64 `json:"highPrice"`
-	LowPrice                           float64 `json:"lowPrice"`
-	BidTick                            string  `json:"bidTick"`
-	ClosePrice                         float64 `json:"closePrice"`
-	NetChange                          float64 `json:"netChange"`
-	TotalVolume                        float64 `json:"totalVolume"`
-	QuoteTimeInLong                    int64   `json:"quoteTimeInLong"`
-	TradeTimeInLong                    int64   `json:"tradeTimeInLong"`
-	Mark                               float64 `json:"mark"`
-	Exchange                           string  `json:"exchange"`
-	ExchangeName                       string  `json:"exchangeName"`
-	Marginable                         bool    `json:"marginable"`
-	Shortable                          bool    `json:"shortable"`
-	Volatility                         float64 `json:"volatility"`
-	Digits                             int     `json:"digits"`
-	Five2WkHigh                        float64 `json:"52WkHigh"`
-	Five2WkLow                         float64 `json:"52WkLow"`
-	NAV                                float64 `json:"nAV"`
-	PeRatio                            float64 `json:"peRatio"`
-	DivAmount                          float64 `json:"divAmount"`
-	DivYield                           float64 `json:"divYield"`
-	DivDate                            string  `json:"divDate"`
-	SecurityStatus                     string  `json:"securityStatus"`
-	RegularMarketLastPrice             float64 `json:"regularMarketLastPrice"`
-	RegularMarketLastSize              int     `json:"regularMarketLastSize"`
-	RegularMarketNetChange             float64 `json:"regularMarketNetChange"`
-	RegularMarketTradeTimeInLong       int64   `json:"regularMarketTradeTimeInLong"`
-	NetPercentChangeInDouble           float64 `json:"netPercentChangeInDouble"`
-	MarkChangeInDouble                 float64 `json:"markChangeInDouble"`
-	MarkPercentChangeInDouble          float64 `json:"markPercentChangeInDouble"`
-	RegularMarketPercentChangeInDouble float64 `json:"regularMarketPercentChangeInDouble"`
-	Delayed                            bool    `json:"delayed"`
+	AssetType                          AssetType `json:"assetType"`
+	AssetMainType                      string    `json:"assetMainType"`
+	Cusip                              string    `json:"cusip"`
+	AssetSubType                       string    `json:"assetSubType"`
+	Symbol                             string    `json:"symbol"`
+	Description                        string    `json:"description"`
+	BidPrice                           float64   `json:"bidPrice"`
+	BidSize                            float64   `json:"bidSize"`
+	BidID                              string    `json:"bidId"`
+	AskPrice                           float64   `json:"askPrice"`
+	AskSize                            float64   `json:"askSize"`
+	AskID                              string    `json:"askId"`
+	LastPrice                          float64   `json:"lastPrice"`
+	LastSize                           float64   `json:"lastSize"`
+	LastID                             string    `json:"lastId"`
+	OpenPrice                          float64   `json:"openPrice"`
+	HighPrice                          float64   `json:"highPrice"`
+	LowPrice                           float64   `json:"lowPrice"`
+	BidTick                            string    `json:"bidTick"`
+	ClosePrice                         float64   `json:"closePrice"`
+	NetChange                          float64   `json:"netChange"`
+	TotalVolume                        float64   `json:"totalVolume"`
+	QuoteTimeInLong                    int64     `json:"quoteTimeInLong"`
+	TradeTimeInLong                    int64     `json:"tradeTimeInLong"`
+	Mark                               float64   `json:"mark"`
+	Exchange                           string    `json:"exchange"`
+	ExchangeName                       string    `json:"exchangeName"`
+	Marginable                         bool      `json:"marginable"`
+	Shortable                          bool      `json:"shortable"`
+	Volatility                         float64   `json:"volatility"`
+	Digits                             int       `json:"digits"`
+	Five2WkHigh                        float64   `json:"52WkHigh"`
+	Five2WkLow                         float64   `json:"52WkLow"`
+	NAV                                float64   `json:"nAV"`
+	PeRatio                            float64   `json:"peRatio"`
+	DivAmount                          float64   `json:"divAmount"`
+	DivYield                           float64   `json:"divYield"`
+	DivDate                            string    `json:"divDate"`
+	SecurityStatus                     string    `json:"securityStatus"`
+	RegularMarketLastPrice             float64   `json:"regularMarketLastPrice"`
+	RegularMarketLastSize              int       `json:"regularMarketLastSize"`
+	RegularMarketNetChange             float64   `json:"regularMarketNetChange"`
+	RegularMarketTradeTimeInLong       int64     `json:"regularMarketTradeTimeInLong"`
+	NetPercentChangeInDouble           float64   `json:"netPercentChangeInDouble"`
+	MarkChangeInDouble                 float64   `json:"markChangeInDouble"`
+	MarkPercentChangeInDouble          float64   `json:"markPercentChangeInDouble"`
+	RegularMarketPercentChangeInDouble float64   `json:"regularMarketPercentChangeInDouble"`
+	Delayed                            bool      `json:"delayed"`
+
+	// Fields only set in the quotes of options.
+	Underlying string  `json:"underlying"`
+	Multiplier float64 `json:"multiplier"`
+	Delta      float64 `json:"delta"`
+
+	// Fields only set in the quotes of mutual funds.
+	TotalAssets float64 `json:"totalAssets"`
+	Yield       float64 `json:"yield"`
+
+	// Fields only set in the quotes of bonds.
+	TradeDate     string  `json:"tradeDate"`
+	BidYield      float64 `json:"bidYield"`
+	AskYield      float64 `json:"askYield"`
+	LastYield     float64 `json:"lastYield"`
+	PercentChange float64 `json:"percentChange"`
 }
 
+// MutualFundQuote is the part of a Quote relevant to mutual funds, see Quote.AsMutualFund.
+type MutualFundQuote struct {
+	NAV         float64
+	NetChange   float64
+	TotalAssets float64
+	Five2WkHigh float64
+	Five2WkLow  float64
+	Yield       float64
+	Cusip       string
+	Exchange    string
+	Description string
+	Digits      int
+}
 
-func (s *QuotesService) GetQuotes(ctx context.Context, symbols string) (*Quotes, *Response, error) {
-	u := fmt.Sprintf("marketdata/quotes")
-	if symbols == "" {
-		return nil, nil, fmt.Errorf("no symbols present")
+// AsMutualFund returns the fields of q relevant to mutual funds, and whether q is the quote of a mutual fund.
+func (q *Quote) AsMutualFund() (*MutualFundQuote, bool) {
+	if q.AssetType != "MUTUAL_FUND" {
+		return nil, false
 	}
-	u = fmt.Sprintf("%s?symbol=%s", u, symbols)
+	return &MutualFundQuote{
+		NAV:         q.NAV,
+		NetChange:   q.NetChange,
+		TotalAssets: q.TotalAssets,
+		Five2WkHigh: q.Five2WkHigh,
+		Five2WkLow:  q.Five2WkLow,
+		Yield:       q.Yield,
+		Cusip:       q.Cusip,
+		Exchange:    q.Exchange,
+		Description: q.Description,
+		Digits:      q.Digits,
+	}, true
+}
 
-	req, err := s.client.NewRequest("GET", u, nil)
+// BondQuote is the part of a Quote relevant to bonds, see Quote.AsBond.
+type BondQuote struct {
+	Cusip         string
+	Symbol        string
+	Description   string
+	BidPrice      float64
+	AskPrice      float64
+	LastPrice     float64
+	OpenPrice     float64
+	HighPrice     float64
+	LowPrice      float64
+	ClosePrice    float64
+	NetChange     float64
+	Five2WkHigh   float64
+	Five2WkLow    float64
+	Volatility    float64
+	TradeDate     string
+	Digits        int
+	BidYield      float64
+	AskYield      float64
+	LastYield     float64
+	PercentChange float64
+}
 
+// AsBond returns the fields of q relevant to bonds, and whether q is the quote of a bond.
+func (q *Quote) AsBond() (*BondQuote, bool) {
+	if q.AssetType != "BOND" && q.AssetType != "FIXED_INCOME" {
+		return nil, false
+	}
+	return &BondQuote{
+		Cusip:         q.Cusip,
+		Symbol:        q.Symbol,
+		Description:   q.Description,
+		BidPrice:      q.BidPrice,
+		AskPrice:      q.AskPrice,
+		LastPrice:     q.LastPrice,
+		OpenPrice:     q.OpenPrice,
+		HighPrice:     q.HighPrice,
+		LowPrice:      q.LowPrice,
+		ClosePrice:    q.ClosePrice,
+		NetChange:     q.NetChange,
+		Five2WkHigh:   q.Five2WkHigh,
+		Five2WkLow:    q.Five2WkLow,
+		Volatility:    q.Volatility,
+		TradeDate:     q.TradeDate,
+		Digits:        q.Digits,
+		BidYield:      q.BidYield,
+		AskYield:      q.AskYield,
+		LastYield:     q.LastYield,
+		PercentChange: q.PercentChange,
+	}, true
+}
+
+// GetQuote returns the quote for a single symbol.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/quotes/apis/get/marketdata/%7Bsymbol%7D/quotes
+func (s *QuotesService) GetQuote(ctx context.Context, symbol string) (*Quote, *Response, error) {
+	if symbol == "" {
+		return nil, nil, fmt.Errorf("no symbol present")
+	}
+	u := fmt.Sprintf("marketdata/%s/quotes", url.PathEscape(symbol))
+
+	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	quotes := new(Quotes)
-
-	resp, err := s.client.Do(ctx, req, quotes)
+	quotes := Quotes{}
+	resp, err := s.client.Do(ctx, req, &quotes)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return quotes, resp, nil
+	quote, ok := quotes[symbol]
+	if !ok {
+		return nil, resp, fmt.Errorf("no quote returned for symbol %s", symbol)
+	}
+
+	return quote, resp, nil
+}
+
+// GetQuotes returns quotes for several symbols in a single request, keyed by symbol.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/quotes/apis/get/marketdata/quotes
+func (s *QuotesService) GetQuotes(ctx context.Context, symbols []string) (Quotes, *Response, error) {
+	return s.getQuotes(ctx, url.Values{}, symbols)
 }
 
+// GetQuotesNoAuth is GetQuotes for unauthenticated clients, which identify themselves with an API key instead.
+// Quotes returned to unauthenticated clients are delayed.
+func (s *QuotesService) GetQuotesNoAuth(ctx context.Context, apiKey string, symbols []string) (Quotes, *Response, error) {
+	q := url.Values{}
+	q.Set("apikey", apiKey)
+	return s.getQuotes(ctx, q, symbols)
+}
 
-func (s *QuotesService) GetQuotesNoAuth(ctx context.Context, apiKey string, symbols string) (*Quotes, *Response, error) {
-	u := fmt.Sprintf("marketdata/quotes")
-	if symbols == "" {
+func (s *QuotesService) getQuotes(ctx context.Context, q url.Values, symbols []string) (Quotes, *Response, error) {
+	if len(symbols) == 0 {
 		return nil, nil, fmt.Errorf("no symbols present")
 	}
-	u = fmt.Sprintf("%s?apikey=%s&symbol=%s", u, apiKey, symbols)
+	q.Set("symbol", strings.Join(symbols, ","))
+	u := fmt.Sprintf("marketdata/quotes?%s", q.Encode())
 
 	req, err := s.client.NewRequest("GET", u, nil)
-
 	if err != nil {
 		return nil, nil, err
 	}
 
-	quotes := new(Quotes)
-
-	resp, err := s.client.Do(ctx, req, quotes)
+	quotes := Quotes{}
+	resp, err := s.client.Do(ctx, req, &quotes)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -114,3 +232,26 @@ func (s *QuotesService) GetQuotesNoAuth(ctx context.Context, apiKey string, symb
 	return quotes, resp, nil
 }
 
+// GetPreviousClose returns the close price of symbol on the previous trading session, for computing the profit
+// and loss of the day.
+func (s *QuotesService) GetPreviousClose(ctx context.Context, symbol string) (float64, error) {
+	quote, _, err := s.GetQuote(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	return quote.ClosePrice, nil
+}
+
+// GetMultiPreviousClose returns the previous close prices of symbols, keyed by symbol, in a single request.
+// Symbols TD Ameritrade returns no quote for are missing from the map.
+func (s *QuotesService) GetMultiPreviousClose(ctx context.Context, symbols []string) (map[string]float64, error) {
+	quotes, _, err := s.GetQuotes(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+	closes := make(map[string]float64, len(quotes))
+	for symbol, quote := range quotes {
+		closes[symbol] = quote.ClosePrice
+	}
+	return closes, nil
+}
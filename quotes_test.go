@@ -0,0 +1,153 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetQuote(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"SPY":{"assetType":"ETF","symbol":"SPY","bidPrice":310.5,"askPrice":310.52,"lastPrice":310.51,"52WkHigh":339.08}}`)
+	})
+
+	quote, _, err := client.Quotes.GetQuote(context.Background(), "SPY")
+	if err != nil {
+		t.Fatalf("GetQuote returned error: %v", err)
+	}
+
+	if quote.Symbol != "SPY" || quote.AssetType != "ETF" {
+		t.Errorf("unexpected quote: %+v", quote)
+	}
+	if quote.BidPrice != 310.5 || quote.AskPrice != 310.52 || quote.LastPrice != 310.51 || quote.Five2WkHigh != 339.08 {
+		t.Errorf("unexpected prices: %+v", quote)
+	}
+}
+
+func TestGetQuoteMissingSymbol(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/NOSUCH/quotes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"Symbol is invalid"}`)
+	})
+
+	_, resp, err := client.Quotes.GetQuote(context.Background(), "NOSUCH")
+	if err == nil {
+		t.Fatal("expected error for missing symbol")
+	}
+	if resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a 400 response, got %v", resp)
+	}
+
+	if _, resp, err := client.Quotes.GetQuote(context.Background(), ""); err == nil || resp != nil {
+		t.Errorf("expected an error without a request for an empty symbol, got %v, %v", resp, err)
+	}
+}
+
+func TestGetQuotes(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/quotes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "symbol", "AAPL,MSFT")
+		fmt.Fprint(w, `{"AAPL":{"symbol":"AAPL","lastPrice":350.1},"MSFT":{"symbol":"MSFT","lastPrice":190.2}}`)
+	})
+
+	quotes, _, err := client.Quotes.GetQuotes(context.Background(), []string{"AAPL", "MSFT"})
+	if err != nil {
+		t.Fatalf("GetQuotes returned error: %v", err)
+	}
+
+	if len(quotes) != 2 {
+		t.Fatalf("expected 2 quotes, got %d", len(quotes))
+	}
+	for _, symbol := range []string{"AAPL", "MSFT"} {
+		quote, ok := quotes[symbol]
+		if !ok {
+			t.Fatalf("quotes missing key %s", symbol)
+		}
+		if quote.Symbol != symbol {
+			t.Errorf("quotes[%s].Symbol = %s", symbol, quote.Symbol)
+		}
+	}
+}
+
+func TestGetPreviousClose(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY","lastPrice":312.1,"closePrice":310.5}}`)
+	})
+	mux.HandleFunc("/marketdata/quotes", func(w http.ResponseWriter, r *http.Request) {
+		testFormValue(t, r, "symbol", "AAPL,MSFT,NOPE")
+		fmt.Fprint(w, `{"AAPL":{"symbol":"AAPL","closePrice":349.5},"MSFT":{"symbol":"MSFT","closePrice":191.3}}`)
+	})
+
+	closePrice, err := client.Quotes.GetPreviousClose(context.Background(), "SPY")
+	if err != nil {
+		t.Fatalf("GetPreviousClose returned error: %v", err)
+	}
+	if closePrice != 310.5 {
+		t.Errorf("GetPreviousClose = %v, want 310.5", closePrice)
+	}
+	if _, err := client.Quotes.GetPreviousClose(context.Background(), ""); err == nil {
+		t.Error("expected error for missing symbol")
+	}
+
+	closes, err := client.Quotes.GetMultiPreviousClose(context.Background(), []string{"AAPL", "MSFT", "NOPE"})
+	if err != nil {
+		t.Fatalf("GetMultiPreviousClose returned error: %v", err)
+	}
+	if want := map[string]float64{"AAPL": 349.5, "MSFT": 191.3}; !reflect.DeepEqual(closes, want) {
+		t.Errorf("GetMultiPreviousClose = %v, want %v", closes, want)
+	}
+}
+
+func TestQuoteAsMutualFundAndBond(t *testing.T) {
+	quotes := Quotes{}
+	err := json.Unmarshal([]byte(`{
+		"VFIAX":{"assetType":"MUTUAL_FUND","symbol":"VFIAX","nAV":301.5,"totalAssets":5.2e11,"yield":1.8,"52WkHigh":310.2,"digits":2},
+		"912828YV6":{"assetType":"BOND","symbol":"912828YV6","bidPrice":104.5,"askPrice":104.6,"tradeDate":"2020-07-06","bidYield":0.31,"askYield":0.29}
+	}`), &quotes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fund, ok := quotes["VFIAX"].AsMutualFund()
+	if !ok {
+		t.Fatal("AsMutualFund of a mutual fund returned false")
+	}
+	if fund.NAV != 301.5 || fund.TotalAssets != 5.2e11 || fund.Yield != 1.8 || fund.Five2WkHigh != 310.2 || fund.Digits != 2 {
+		t.Errorf("unexpected mutual fund quote: %+v", fund)
+	}
+	if _, ok := quotes["VFIAX"].AsBond(); ok {
+		t.Error("AsBond of a mutual fund returned true")
+	}
+
+	bond, ok := quotes["912828YV6"].AsBond()
+	if !ok {
+		t.Fatal("AsBond of a bond returned false")
+	}
+	if bond.Symbol != "912828YV6" || bond.BidPrice != 104.5 || bond.TradeDate != "2020-07-06" || bond.BidYield != 0.31 || bond.AskYield != 0.29 {
+		t.Errorf("unexpected bond quote: %+v", bond)
+	}
+	if _, ok := quotes["912828YV6"].AsMutualFund(); ok {
+		t.Error("AsMutualFund of a bond returned true")
+	}
+}
+
+func TestUnmarshalQuote(t *testing.T) {
+	testJSONRoundTrip(t, "testdata/quote.json", &Quote{})
+}
@@ -0,0 +1,47 @@
+package tdameritrade
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter blocks requests until they can be sent without exceeding TD Ameritrade's rate limits.
+// Wait returns a non-nil error if the request must not be sent, which the Client returns without sending it.
+// *rate.Limiter from golang.org/x/time/rate satisfies this interface: it returns ctx.Err() if ctx is done while
+// waiting, and an error of its own without waiting if the request could not be sent before ctx's deadline.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// NewTokenBucketRateLimiter returns a RateLimiter allowing rps requests per second on average, in bursts of up to burst requests.
+// TD Ameritrade allows around 120 requests per minute besides orders, so NewTokenBucketRateLimiter(2, 1) stays within the limit.
+func NewTokenBucketRateLimiter(rps float64, burst int) RateLimiter {
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// NewOrderRateLimiter returns a RateLimiter for TD Ameritrade's stricter limit of 60 order requests per minute,
+// to be passed to WithOrderRateLimiter.
+func NewOrderRateLimiter() RateLimiter {
+	return rate.NewLimiter(rate.Every(time.Minute/60), 1)
+}
+
+// isOrderRequest reports whether req places, replaces or cancels an order or a saved order, which TD Ameritrade
+// limits separately from other requests.
+func isOrderRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i := 0; i+2 < len(parts); i++ {
+		if parts[i] == "accounts" && (parts[i+2] == "orders" || parts[i+2] == "savedorders") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSpacesRequests(t *testing.T) {
+	// 200 requests at 200 requests per second keep the test fast while still measuring the limiter.
+	const calls, rps = 200, 200
+	client, mux, teardown := setup(t, WithRateLimiter(NewTokenBucketRateLimiter(rps, 1)))
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY"}}`)
+	})
+
+	start := time.Now()
+	for i := 0; i < calls; i++ {
+		if _, _, err := client.Quotes.GetQuote(context.Background(), "SPY"); err != nil {
+			t.Fatalf("GetQuote returned error: %v", err)
+		}
+	}
+
+	// The first request is sent straight away from the burst.
+	want := time.Duration(calls-1) * time.Second / rps
+	if elapsed := time.Since(start); elapsed < want*9/10 || elapsed > want*2 {
+		t.Errorf("%d requests took %v, want about %v", calls, elapsed, want)
+	}
+}
+
+func TestRateLimiterContextCanceled(t *testing.T) {
+	client, mux, teardown := setup(t, WithRateLimiter(NewOrderRateLimiter()))
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY"}}`)
+	})
+
+	if _, _, err := client.Quotes.GetQuote(context.Background(), "SPY"); err != nil {
+		t.Fatalf("GetQuote returned error: %v", err)
+	}
+
+	// The next request has to wait a second, so it is still waiting when the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, _, err := client.Quotes.GetQuote(ctx, "SPY")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetQuote waited %v after the context was canceled", elapsed)
+	}
+	if requests != 1 {
+		t.Errorf("%d requests sent, want 1", requests)
+	}
+}
+
+func TestOrderRateLimiter(t *testing.T) {
+	client, mux, teardown := setup(t, WithOrderRateLimiter(NewOrderRateLimiter()))
+	defer teardown()
+
+	orders := 0
+	mux.HandleFunc("/accounts/123/orders", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			orders++
+		}
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY"}}`)
+	})
+
+	if _, err := client.Orders.PlaceOrder(context.Background(), "123", &Order{}); err != nil {
+		t.Fatalf("PlaceOrder returned error: %v", err)
+	}
+
+	// Other requests, including getting orders, are not limited by the order limiter.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		if _, _, err := client.Quotes.GetQuote(ctx, "SPY"); err != nil {
+			t.Fatalf("GetQuote returned error: %v", err)
+		}
+		if _, _, err := client.Orders.GetOrdersByAccount(ctx, "123", OrderQueryParams{}); err != nil {
+			t.Fatalf("GetOrdersByAccount returned error: %v", err)
+		}
+	}
+
+	// The next order has to wait a second, past the deadline of ctx.
+	if _, err := client.Orders.PlaceOrder(ctx, "123", &Order{}); err == nil {
+		t.Error("expected the second order to be limited")
+	}
+	if orders != 1 {
+		t.Errorf("%d orders sent, want 1", orders)
+	}
+}
+
+func TestIsOrderRequest(t *testing.T) {
+	for _, tt := range []struct {
+		method, path string
+		want         bool
+	}{
+		{"POST", "/v1/accounts/123/orders", true},
+		{"PUT", "/v1/accounts/123/orders/456", true},
+		{"DELETE", "/v1/accounts/123/savedorders/456", true},
+		{"GET", "/v1/accounts/123/orders", false},
+		{"POST", "/v1/accounts/123/watchlists", false},
+		{"POST", "/v1/oauth2/token", false},
+	} {
+		req, _ := http.NewRequest(tt.method, "https://api.tdameritrade.com"+tt.path, nil)
+		if got := isOrderRequest(req); got != tt.want {
+			t.Errorf("isOrderRequest(%s %s) = %v, want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
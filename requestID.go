@@ -0,0 +1,52 @@
+package tdameritrade
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// requestIDHeader is the header identifying a request across services.
+const requestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestIDGenerator sets the X-Request-ID header of every request without one to an ID returned by fn,
+// unless the request's context has one, see WithTraceContext. Retries of a request keep its ID.
+// The ID is logged by the Logger of the Client.
+func WithRequestIDGenerator(fn func() string) ClientOption {
+	return func(c *Client) {
+		c.requestIDGenerator = fn
+	}
+}
+
+// WithUUIDRequestIDs generates a random UUID for the X-Request-ID header of each request, see WithRequestIDGenerator.
+func WithUUIDRequestIDs() ClientOption {
+	return WithRequestIDGenerator(func() string { return uuid.New().String() })
+}
+
+// WithTraceContext returns a copy of ctx carrying the X-Request-ID header of r, the request being served,
+// so that the requests a Client makes with the context have the same ID. ctx is returned if r has no ID.
+//
+// Usage example:
+// quote, _, err := client.Quotes.GetQuote(tdameritrade.WithTraceContext(r.Context(), r), "SPY")
+func WithTraceContext(ctx context.Context, r *http.Request) context.Context {
+	id := r.Header.Get(requestIDHeader)
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// setRequestID sets the X-Request-ID header of req, if not set already, to the ID of ctx or a generated one.
+func (c *Client) setRequestID(ctx context.Context, req *http.Request) {
+	if req.Header.Get(requestIDHeader) != "" {
+		return
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		req.Header.Set(requestIDHeader, id)
+	} else if c.requestIDGenerator != nil {
+		req.Header.Set(requestIDHeader, c.requestIDGenerator())
+	}
+}
@@ -0,0 +1,67 @@
+package tdameritrade
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDs(t *testing.T) {
+	var logs bytes.Buffer
+	client, mux, teardown := setup(t, WithUUIDRequestIDs(), WithLogger(NewStructuredLogger(&logs)))
+	defer teardown()
+
+	var ids []string
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get("X-Request-ID"))
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY"}}`)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Quotes.GetQuote(context.Background(), "SPY"); err != nil {
+			t.Fatalf("GetQuote returned error: %v", err)
+		}
+	}
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if len(ids) != 2 || !uuidPattern.MatchString(ids[0]) || !uuidPattern.MatchString(ids[1]) || ids[0] == ids[1] {
+		t.Errorf("got request IDs %q, want distinct UUIDs", ids)
+	}
+	if !strings.Contains(logs.String(), `"request_id":"`+ids[0]+`"`) {
+		t.Errorf("request ID %s was not logged:\n%s", ids[0], logs.String())
+	}
+
+	incoming := httptest.NewRequest("GET", "/dashboard", nil)
+	incoming.Header.Set("X-Request-ID", "trace-123")
+	if _, _, err := client.Quotes.GetQuote(WithTraceContext(context.Background(), incoming), "SPY"); err != nil {
+		t.Fatalf("GetQuote returned error: %v", err)
+	}
+	if ids[2] != "trace-123" {
+		t.Errorf("got request ID %q, want the ID of the context", ids[2])
+	}
+
+	// Requests without a trace ID leave the context as it is.
+	ctx := context.Background()
+	if WithTraceContext(ctx, httptest.NewRequest("GET", "/", nil)) != ctx {
+		t.Error("WithTraceContext changed a context for a request without an ID")
+	}
+}
+
+func TestRequestIDGenerator(t *testing.T) {
+	client, mux, teardown := setup(t, WithRequestIDGenerator(func() string { return "generated" }))
+	defer teardown()
+
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Request-ID"); got != "generated" {
+			t.Errorf("X-Request-ID = %q, want generated", got)
+		}
+		fmt.Fprint(w, `{"SPY":{"symbol":"SPY"}}`)
+	})
+	if _, _, err := client.Quotes.GetQuote(context.Background(), "SPY"); err != nil {
+		t.Fatalf("GetQuote returned error: %v", err)
+	}
+}
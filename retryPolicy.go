@@ -0,0 +1,79 @@
+package tdameritrade
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy decides whether and when a failed request is sent again.
+// attempt is the number of times the request has been sent, starting at 1.
+// resp is nil when err is not.
+// Whatever the policy, the Client only retries requests other than GET and HEAD, such as placing an order, after
+// a 429 Too Many Requests response: after a network error or a 5xx response the request may have been acted on,
+// and sending it again could, for instance, place an order twice.
+type RetryPolicy interface {
+	ShouldRetry(attempt int, resp *http.Response, err error) bool
+	Backoff(attempt int) time.Duration
+}
+
+const (
+	retryBaseBackoff = 500 * time.Millisecond
+	retryMaxBackoff  = 30 * time.Second
+	retryJitter      = 50 * time.Millisecond
+)
+
+// DefaultRetryPolicy returns a RetryPolicy that sends a request up to maxAttempts times.
+// It retries network errors, 429 Too Many Requests and 500, 502, 503 and 504 responses, the latter and network
+// errors only for GET and HEAD requests, see RetryPolicy,
+// backing off exponentially from 500ms, with ±50ms of jitter, up to 30s.
+func DefaultRetryPolicy(maxAttempts int) RetryPolicy {
+	return &defaultRetryPolicy{maxAttempts: maxAttempts}
+}
+
+type defaultRetryPolicy struct {
+	maxAttempts int
+}
+
+func (p *defaultRetryPolicy) ShouldRetry(attempt int, resp *http.Response, err error) bool {
+	if attempt >= p.maxAttempts {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func (p *defaultRetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := retryMaxBackoff
+	// Past 2^6 * 500ms the backoff is capped anyway, and the shift would eventually overflow.
+	if attempt <= 6 {
+		backoff = retryBaseBackoff << uint(attempt-1)
+		if backoff > retryMaxBackoff {
+			backoff = retryMaxBackoff
+		}
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(2*retryJitter+1))) - retryJitter
+	return backoff + jitter
+}
+
+// isRetryable reports whether req may be sent again after resp or err, whatever the RetryPolicy decides.
+// Requests that may change state are only retried when TD Ameritrade rejected them with 429 Too Many Requests.
+func isRetryable(req *http.Request, resp *http.Response) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead:
+		return true
+	}
+	return resp != nil && resp.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,184 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyRetriesTooManyRequests(t *testing.T) {
+	client, mux, teardown := setup(t, WithRetryPolicy(DefaultRetryPolicy(3)))
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/accounts/123/watchlists", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		testMethod(t, r, "POST")
+
+		// The body has to be sent again with each attempt.
+		watchlist := new(Watchlist)
+		if err := json.NewDecoder(r.Body).Decode(watchlist); err != nil || watchlist.Name != "tech" {
+			t.Errorf("attempt %d: unexpected body %+v, err %v", requests, watchlist, err)
+		}
+
+		if requests <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	if _, err := client.Watchlist.CreateWatchlist(context.Background(), "123", &Watchlist{Name: "tech"}); err != nil {
+		t.Fatalf("CreateWatchlist returned error: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("%d requests sent, want 3", requests)
+	}
+}
+
+func TestRetryPolicyDoesNotResendOrders(t *testing.T) {
+	client, mux, teardown := setup(t, WithRetryPolicy(DefaultRetryPolicy(3)))
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/accounts/123/orders", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		testMethod(t, r, "POST")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	order := &Order{OrderType: "MARKET", OrderStrategyType: "SINGLE"}
+	if _, err := client.Orders.PlaceOrder(context.Background(), "123", order); err == nil {
+		t.Fatal("expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("%d requests sent, want 1", requests)
+	}
+
+	// A network error does not resend it either.
+	teardown()
+	if _, err := client.Orders.PlaceOrder(context.Background(), "123", order); err == nil {
+		t.Fatal("expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("%d requests sent, want 1", requests)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		method string
+		status int
+		want   bool
+	}{
+		{"GET", http.StatusInternalServerError, true},
+		{"GET", 0, true},
+		{"HEAD", http.StatusBadGateway, true},
+		{"POST", http.StatusTooManyRequests, true},
+		{"POST", http.StatusInternalServerError, false},
+		{"POST", 0, false},
+		{"PUT", http.StatusServiceUnavailable, false},
+		{"DELETE", http.StatusGatewayTimeout, false},
+	}
+	for _, tt := range tests {
+		var resp *http.Response
+		if tt.status != 0 {
+			resp = &http.Response{StatusCode: tt.status}
+		}
+		if got := isRetryable(&http.Request{Method: tt.method}, resp); got != tt.want {
+			t.Errorf("isRetryable(%s, %d) = %v, want %v", tt.method, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyGivesUpAfterMaxAttempts(t *testing.T) {
+	client, mux, teardown := setup(t, WithRetryPolicy(DefaultRetryPolicy(1)))
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	if _, _, err := client.Quotes.GetQuote(context.Background(), "SPY"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("%d requests sent, want 1", requests)
+	}
+}
+
+func TestRetryPolicyRespectsDeadline(t *testing.T) {
+	client, mux, teardown := setup(t, WithRetryPolicy(DefaultRetryPolicy(5)))
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/marketdata/SPY/quotes", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	// The first backoff is around 500ms, longer than the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := client.Quotes.GetQuote(ctx, "SPY")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("GetQuote took %v, expected it to return without waiting", elapsed)
+	}
+	if requests != 1 {
+		t.Errorf("%d requests sent, want 1", requests)
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	p := DefaultRetryPolicy(3)
+
+	tests := []struct {
+		attempt int
+		status  int
+		err     error
+		want    bool
+	}{
+		{1, http.StatusTooManyRequests, nil, true},
+		{1, http.StatusGatewayTimeout, nil, true},
+		{1, 0, errors.New("connection reset"), true},
+		{1, http.StatusBadRequest, nil, false},
+		{1, http.StatusOK, nil, false},
+		{3, http.StatusTooManyRequests, nil, false},
+	}
+	for _, tt := range tests {
+		var resp *http.Response
+		if tt.err == nil {
+			resp = &http.Response{StatusCode: tt.status}
+		}
+		if got := p.ShouldRetry(tt.attempt, resp, tt.err); got != tt.want {
+			t.Errorf("ShouldRetry(%d, %d, %v) = %v, want %v", tt.attempt, tt.status, tt.err, got, tt.want)
+		}
+	}
+
+	backoffs := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 500 * time.Millisecond},
+		{2, time.Second},
+		{4, 4 * time.Second},
+		{7, 30 * time.Second},
+		{100, 30 * time.Second},
+	}
+	for _, tt := range backoffs {
+		got := p.Backoff(tt.attempt)
+		if got < tt.want-retryJitter || got > tt.want+retryJitter {
+			t.Errorf("Backoff(%d) = %v, want %v ± %v", tt.attempt, got, tt.want, retryJitter)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+)
+
+// SavedOrdersService handles communication with the saved order related methods of
+// the TDAmeritrade API.
+// Saved orders use the same schema as orders but are stored by TD Ameritrade without being placed.
+//
+// TDAmeritrade API docs: https://developer.tdameritrade.com/account-access/apis
+type SavedOrdersService struct {
+	client *Client
+}
+
+// CreateSavedOrder saves an order for an account.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/post/accounts/%7BaccountId%7D/savedorders-0
+func (s *SavedOrdersService) CreateSavedOrder(ctx context.Context, accountID string, order *Order) (*Response, error) {
+	u := fmt.Sprintf("accounts/%s/savedorders", accountID)
+	if order == nil {
+		return nil, fmt.Errorf("order is nil")
+	}
+
+	req, err := s.client.NewRequest("POST", u, order)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(ctx, req, nil)
+}
+
+// GetSavedOrder returns a saved order of an account.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/get/accounts/%7BaccountId%7D/savedorders/%7BsavedOrderId%7D-0
+func (s *SavedOrdersService) GetSavedOrder(ctx context.Context, accountID, savedOrderID string) (*Order, *Response, error) {
+	u := fmt.Sprintf("accounts/%s/savedorders/%s", accountID, savedOrderID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order := new(Order)
+	resp, err := s.client.Do(ctx, req, order)
+	if err != nil {
+		return nil, resp, err
+	}
+	return order, resp, nil
+}
+
+// GetSavedOrdersByPath returns all of the saved orders of an account.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/get/accounts/%7BaccountId%7D/savedorders-0
+func (s *SavedOrdersService) GetSavedOrdersByPath(ctx context.Context, accountID string) ([]*Order, *Response, error) {
+	u := fmt.Sprintf("accounts/%s/savedorders", accountID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var orders []*Order
+	resp, err := s.client.Do(ctx, req, &orders)
+	if err != nil {
+		return nil, resp, err
+	}
+	return orders, resp, nil
+}
+
+// ReplaceSavedOrder replaces a saved order of an account with order.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/put/accounts/%7BaccountId%7D/savedorders/%7BsavedOrderId%7D-0
+func (s *SavedOrdersService) ReplaceSavedOrder(ctx context.Context, accountID, savedOrderID string, order *Order) (*Response, error) {
+	u := fmt.Sprintf("accounts/%s/savedorders/%s", accountID, savedOrderID)
+	if order == nil {
+		return nil, fmt.Errorf("order is nil")
+	}
+
+	req, err := s.client.NewRequest("PUT", u, order)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(ctx, req, nil)
+}
+
+// DeleteSavedOrder deletes a saved order of an account.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/account-access/apis/delete/accounts/%7BaccountId%7D/savedorders/%7BsavedOrderId%7D-0
+func (s *SavedOrdersService) DeleteSavedOrder(ctx context.Context, accountID, savedOrderID string) (*Response, error) {
+	u := fmt.Sprintf("accounts/%s/savedorders/%s", accountID, savedOrderID)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,95 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestSavedOrdersService(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	var methods []string
+	mux.HandleFunc("/accounts/123/savedorders", func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch r.Method {
+		case "POST":
+			order := new(Order)
+			if err := json.NewDecoder(r.Body).Decode(order); err != nil || order.OrderType != "LIMIT" {
+				t.Errorf("unexpected saved order %+v, err %v", order, err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "GET":
+			fmt.Fprint(w, `[{"orderType":"LIMIT","savedOrderId":1},{"orderType":"MARKET","savedOrderId":2}]`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+	mux.HandleFunc("/accounts/123/savedorders/1", func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"orderType":"LIMIT","duration":"DAY"}`)
+		case "PUT", "DELETE":
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	ctx := context.Background()
+	order := mustOrder(t)(NewEquityLimitBuyOrder("SPY", 1, 300))
+
+	if _, err := client.SavedOrders.CreateSavedOrder(ctx, "123", order); err != nil {
+		t.Fatalf("CreateSavedOrder returned error: %v", err)
+	}
+
+	orders, _, err := client.SavedOrders.GetSavedOrdersByPath(ctx, "123")
+	if err != nil {
+		t.Fatalf("GetSavedOrdersByPath returned error: %v", err)
+	}
+	if len(orders) != 2 || orders[1].OrderType != "MARKET" {
+		t.Errorf("unexpected saved orders: %+v", orders)
+	}
+
+	saved, _, err := client.SavedOrders.GetSavedOrder(ctx, "123", "1")
+	if err != nil {
+		t.Fatalf("GetSavedOrder returned error: %v", err)
+	}
+	if saved.OrderType != "LIMIT" || saved.Duration != "DAY" {
+		t.Errorf("unexpected saved order: %+v", saved)
+	}
+
+	if _, err := client.SavedOrders.ReplaceSavedOrder(ctx, "123", "1", order); err != nil {
+		t.Fatalf("ReplaceSavedOrder returned error: %v", err)
+	}
+	if _, err := client.SavedOrders.DeleteSavedOrder(ctx, "123", "1"); err != nil {
+		t.Fatalf("DeleteSavedOrder returned error: %v", err)
+	}
+
+	want := []string{"POST", "GET", "GET", "PUT", "DELETE"}
+	if fmt.Sprint(methods) != fmt.Sprint(want) {
+		t.Errorf("requests = %v, want %v", methods, want)
+	}
+
+	// The deprecated methods of AccountsService send the same requests.
+	methods = nil
+	if _, err := client.Account.CreateSavedOrder(ctx, "123", order); err != nil {
+		t.Fatalf("CreateSavedOrder returned error: %v", err)
+	}
+	if _, err := client.Account.GetSavedOrder(ctx, "123", "1", nil); err != nil {
+		t.Fatalf("GetSavedOrder returned error: %v", err)
+	}
+	if _, err := client.Account.ReplaceSavedOrder(ctx, "123", "1", order); err != nil {
+		t.Fatalf("ReplaceSavedOrder returned error: %v", err)
+	}
+	if _, err := client.Account.DeleteSavedOrder(ctx, "123", "1"); err != nil {
+		t.Fatalf("DeleteSavedOrder returned error: %v", err)
+	}
+	want = []string{"POST", "GET", "PUT", "DELETE"}
+	if fmt.Sprint(methods) != fmt.Sprint(want) {
+		t.Errorf("deprecated requests = %v, want %v", methods, want)
+	}
+}
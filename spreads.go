@@ -0,0 +1,162 @@
+package tdameritrade
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// SpreadLeg is a leg of an option strategy.
+// Quantity is in contracts and negative for short legs, PremiumPaid is the price per share paid or received for
+// the option and Multiplier is the number of shares per contract, 100 when zero.
+type SpreadLeg struct {
+	Quantity    int
+	StrikePrice float64
+	PutCall     string
+	PremiumPaid float64
+	Multiplier  float64
+}
+
+// PnLResult is the profit and loss at expiration of a set of legs.
+// MaxProfit and MaxLoss are positive amounts in dollars and +Inf when unlimited.
+type PnLResult struct {
+	MaxProfit float64
+	MaxLoss   float64
+	// BreakEven are the underlying prices at expiration where the legs neither make nor lose money, in ascending order.
+	BreakEven []float64
+	// CurrentPnL is the profit and loss if the legs expired at the underlying price given to SpreadPnL.
+	CurrentPnL  float64
+	PnLAtExpiry func(underlying float64) float64
+}
+
+// PnLPoint is the profit and loss of legs expiring at UnderlyingPrice.
+type PnLPoint struct {
+	UnderlyingPrice float64
+	PnL             float64
+}
+
+// SpreadPnL returns the profit and loss at expiration of legs, which may be any combination of calls and puts
+// on the same underlying expiring together, such as vertical spreads, iron condors, straddles and strangles.
+//
+// Usage example, a bull call spread:
+// legs := []tdameritrade.SpreadLeg{{Quantity: 1, StrikePrice: 305, PutCall: "CALL", PremiumPaid: 7.22}, {Quantity: -1, StrikePrice: 310, PutCall: "CALL", PremiumPaid: 2.62}}
+// result := tdameritrade.SpreadPnL(legs, 310.52)
+func SpreadPnL(legs []SpreadLeg, underlyingPrice float64) *PnLResult {
+	p := spreadPayoff(legs)
+	result := &PnLResult{PnLAtExpiry: p.pnl, CurrentPnL: p.pnl(underlyingPrice)}
+	result.MaxProfit, result.MaxLoss, result.BreakEven = p.stats()
+	return result
+}
+
+// SpreadPnLChart returns the profit and loss at expiration of legs at steps+1 evenly spaced prices from priceRange[0]
+// to priceRange[1], along with the strikes in between, where the payoff changes slope, in ascending order of price.
+func SpreadPnLChart(legs []SpreadLeg, priceRange [2]float64, steps int) []PnLPoint {
+	if steps < 1 {
+		steps = 1
+	}
+	low, high := priceRange[0], priceRange[1]
+	if low > high {
+		low, high = high, low
+	}
+
+	prices := make([]float64, 0, steps+1+len(legs))
+	for i := 0; i <= steps; i++ {
+		prices = append(prices, low+(high-low)*float64(i)/float64(steps))
+	}
+	for _, l := range legs {
+		if l.StrikePrice > low && l.StrikePrice < high {
+			prices = append(prices, l.StrikePrice)
+		}
+	}
+	sort.Float64s(prices)
+
+	p := spreadPayoff(legs)
+	points := make([]PnLPoint, 0, len(prices))
+	for i, price := range prices {
+		if i > 0 && price == prices[i-1] {
+			continue
+		}
+		points = append(points, PnLPoint{UnderlyingPrice: price, PnL: p.pnl(price)})
+	}
+	return points
+}
+
+func spreadPayoff(legs []SpreadLeg) payoff {
+	legs = append([]SpreadLeg(nil), legs...)
+	p := payoff{kinks: []float64{0}}
+	for i := range legs {
+		legs[i].PutCall = strings.ToUpper(legs[i].PutCall)
+		if legs[i].Multiplier == 0 {
+			legs[i].Multiplier = 100
+		}
+		if legs[i].PutCall == "CALL" {
+			p.slope += float64(legs[i].Quantity) * legs[i].Multiplier
+		}
+		p.kinks = append(p.kinks, legs[i].StrikePrice)
+	}
+
+	p.pnl = func(price float64) float64 {
+		var total float64
+		for _, l := range legs {
+			total += float64(l.Quantity) * l.Multiplier * (intrinsicValue(l.PutCall, l.StrikePrice, price) - l.PremiumPaid)
+		}
+		return total
+	}
+	return p
+}
+
+// intrinsicValue returns the value at expiration of a CALL or PUT at strike when the underlying is at price.
+func intrinsicValue(putCall string, strike, price float64) float64 {
+	if putCall == "PUT" {
+		return math.Max(strike-price, 0)
+	}
+	return math.Max(price-strike, 0)
+}
+
+// payoff is a profit and loss at expiration that is linear between kinks, with slope above the highest kink.
+// It is not defined below zero, which must be one of the kinks.
+type payoff struct {
+	pnl   func(price float64) float64
+	kinks []float64
+	slope float64
+}
+
+// stats returns the maximum profit and loss, as positive amounts that are +Inf when unlimited, and the break even prices.
+// As the payoff is linear between kinks, it is enough to evaluate it at each kink.
+func (p payoff) stats() (maxProfit, maxLoss float64, breakEven []float64) {
+	prices := append([]float64(nil), p.kinks...)
+	sort.Float64s(prices)
+
+	values := make([]float64, len(prices))
+	min, max := math.Inf(1), math.Inf(-1)
+	for i, price := range prices {
+		values[i] = p.pnl(price)
+		min, max = math.Min(min, values[i]), math.Max(max, values[i])
+	}
+	maxProfit, maxLoss = math.Max(max, 0), math.Max(-min, 0)
+	if p.slope > 0 {
+		maxProfit = math.Inf(1)
+	}
+	if p.slope < 0 {
+		maxLoss = math.Inf(1)
+	}
+
+	add := func(price float64) {
+		if n := len(breakEven); n == 0 || breakEven[n-1] != price {
+			breakEven = append(breakEven, price)
+		}
+	}
+	for i := range prices {
+		if values[i] == 0 {
+			add(prices[i])
+		}
+		if i+1 < len(prices) && values[i]*values[i+1] < 0 {
+			add(prices[i] + (prices[i+1]-prices[i])*values[i]/(values[i]-values[i+1]))
+		}
+	}
+	last := len(prices) - 1
+	if p.slope != 0 && values[last]*p.slope < 0 {
+		add(prices[last] - values[last]/p.slope)
+	}
+	return maxProfit, maxLoss, breakEven
+}
@@ -0,0 +1,108 @@
+package tdameritrade
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpreadPnL(t *testing.T) {
+	near := func(got, want float64) bool { return got == want || math.Abs(got-want) < 1e-6 }
+
+	tests := []struct {
+		name      string
+		legs      []SpreadLeg
+		maxProfit float64
+		maxLoss   float64
+		breakEven []float64
+	}{
+		{
+			name: "bull call spread",
+			legs: []SpreadLeg{
+				{Quantity: 1, StrikePrice: 305, PutCall: "CALL", PremiumPaid: 7.22},
+				{Quantity: -1, StrikePrice: 310, PutCall: "CALL", PremiumPaid: 2.62},
+			},
+			maxProfit: 40,
+			maxLoss:   460,
+			breakEven: []float64{309.6},
+		},
+		{
+			name: "iron condor",
+			legs: []SpreadLeg{
+				{Quantity: 1, StrikePrice: 300, PutCall: "PUT", PremiumPaid: 0.8},
+				{Quantity: -1, StrikePrice: 305, PutCall: "PUT", PremiumPaid: 1.7},
+				{Quantity: -1, StrikePrice: 315, PutCall: "CALL", PremiumPaid: 1.7},
+				{Quantity: 1, StrikePrice: 320, PutCall: "CALL", PremiumPaid: 0.9},
+			},
+			maxProfit: 170,
+			maxLoss:   330,
+			breakEven: []float64{303.3, 316.7},
+		},
+		{
+			name: "long straddle",
+			legs: []SpreadLeg{
+				{Quantity: 1, StrikePrice: 310, PutCall: "CALL", PremiumPaid: 2.62},
+				{Quantity: 1, StrikePrice: 310, PutCall: "PUT", PremiumPaid: 2.1},
+			},
+			maxProfit: math.Inf(1),
+			maxLoss:   472,
+			breakEven: []float64{305.28, 314.72},
+		},
+		{
+			name: "short strangle",
+			legs: []SpreadLeg{
+				{Quantity: -1, StrikePrice: 305, PutCall: "put", PremiumPaid: 1.7},
+				{Quantity: -1, StrikePrice: 315, PutCall: "call", PremiumPaid: 1.7, Multiplier: 100},
+			},
+			maxProfit: 340,
+			maxLoss:   math.Inf(1),
+			breakEven: []float64{301.6, 318.4},
+		},
+	}
+
+	for _, tt := range tests {
+		result := SpreadPnL(tt.legs, 310)
+		if !near(result.MaxProfit, tt.maxProfit) || !near(result.MaxLoss, tt.maxLoss) {
+			t.Errorf("%s: MaxProfit, MaxLoss = %v, %v, want %v, %v", tt.name, result.MaxProfit, result.MaxLoss, tt.maxProfit, tt.maxLoss)
+		}
+		if len(result.BreakEven) != len(tt.breakEven) {
+			t.Errorf("%s: BreakEven = %v, want %v", tt.name, result.BreakEven, tt.breakEven)
+			continue
+		}
+		for i := range tt.breakEven {
+			if !near(result.BreakEven[i], tt.breakEven[i]) {
+				t.Errorf("%s: BreakEven = %v, want %v", tt.name, result.BreakEven, tt.breakEven)
+			}
+			if pnl := result.PnLAtExpiry(tt.breakEven[i]); !near(pnl, 0) {
+				t.Errorf("%s: PnLAtExpiry(%v) = %v, want 0", tt.name, tt.breakEven[i], pnl)
+			}
+		}
+		if result.CurrentPnL != result.PnLAtExpiry(310) {
+			t.Errorf("%s: CurrentPnL = %v, want %v", tt.name, result.CurrentPnL, result.PnLAtExpiry(310))
+		}
+	}
+}
+
+func TestSpreadPnLChart(t *testing.T) {
+	legs := []SpreadLeg{
+		{Quantity: 1, StrikePrice: 305, PutCall: "CALL", PremiumPaid: 7.22},
+		{Quantity: -1, StrikePrice: 310, PutCall: "CALL", PremiumPaid: 2.62},
+	}
+
+	points := SpreadPnLChart(legs, [2]float64{320, 300}, 3)
+	wantPrices := []float64{300, 305, 300 + 20.0/3, 310, 300 + 40.0/3, 320}
+	if len(points) != len(wantPrices) {
+		t.Fatalf("points = %v, want prices %v", points, wantPrices)
+	}
+	for i, p := range points {
+		if math.Abs(p.UnderlyingPrice-wantPrices[i]) > 1e-9 {
+			t.Errorf("point %d price = %v, want %v", i, p.UnderlyingPrice, wantPrices[i])
+		}
+	}
+	if math.Abs(points[0].PnL+460) > 1e-6 || math.Abs(points[len(points)-1].PnL-40) > 1e-6 {
+		t.Errorf("unexpected PnL at the ends of the range: %v, %v", points[0], points[len(points)-1])
+	}
+
+	if points := SpreadPnLChart(legs, [2]float64{300, 320}, 4); len(points) != 5 {
+		t.Errorf("expected strikes on the steps not to be repeated, got %v", points)
+	}
+}
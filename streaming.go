@@ -1,10 +1,13 @@
 package tdameritrade
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,9 +32,9 @@ type StreamParams struct {
 	Fields string `json:"fields"`
 }
 
-// NewStreamAuthCommand creates a StreamAuthCommand from a TD Ameritrade UserPrincipal.
-// It validates the account ID against the accounts in the UserPrincipal to avoid creating invalid messages unneccesarily.
-func NewStreamAuthCommand(userPrincipal *UserPrincipal, accountID string) (*StreamAuthCommand, error) {
+// NewStreamAuthCommand creates a StreamAuthCommand from a TD Ameritrade UserPrincipals.
+// It validates the account ID against the accounts in the UserPrincipals to avoid creating invalid messages unneccesarily.
+func NewStreamAuthCommand(userPrincipal *UserPrincipals, accountID string) (*StreamAuthCommand, error) {
 	// findAccount ensures that a user has passed us an account they control to avoid wasting TD Ameritrade's time.
 	account, err := findAccount(userPrincipal, accountID)
 	if err != nil {
@@ -113,15 +116,451 @@ type StreamAuthParams struct {
 
 // StreamingClient provides real time updates from TD Ameritrade's streaming API.
 // See https://developer.tdameritrade.com/content/streaming-data for more information.
+//
+// A zero StreamingClient is ready to Connect. Once connected, it logs in, keeps the connection alive and reconnects
+// as decided by its ReconnectPolicy if the connection drops, sending its subscriptions again.
+// Updates are sent on the channels returned by the Subscribe methods, which pause while reconnecting and are closed
+// when the StreamingClient is closed or gives up reconnecting.
 type StreamingClient struct {
+	// ReconnectPolicy decides whether and when to reconnect, DefaultReconnectPolicy(0) if nil.
+	ReconnectPolicy ReconnectPolicy
+	// OnReconnect is called with the number of the attempt that reconnected, once subscriptions are sent again.
+	OnReconnect func(attempt int)
+	// OnReconnectFailed is called with the last error when the ReconnectPolicy gives up reconnecting,
+	// after which the StreamingClient is closed.
+	OnReconnectFailed func(err error)
+
 	connection *websocket.Conn
 	messages   chan []byte
 	errors     chan error
 	mu         sync.Mutex
+
+	// The following are only used by clients created with Connect.
+	dialer    *websocket.Dialer
+	streamURL string
+	authCmd   *StreamAuthCommand
+	account   string
+	source    string
+	// subscriptionKey identifies the account for account activity subscriptions.
+	subscriptionKey  string
+	requestID        int
+	closed           bool
+	done             chan struct{}
+	subsMu           sync.Mutex
+	handlers         map[string]*streamHandler
+	subs             []streamSubscription
+	heartbeatTimeout time.Duration
+}
+
+const (
+	// streamHeartbeatTimeout is how long a connection may go without a message before it is considered dropped.
+	// TD Ameritrade sends heartbeats every 10 seconds.
+	streamHeartbeatTimeout = 30 * time.Second
+
+	streamBaseBackoff = time.Second
+	streamMaxBackoff  = 30 * time.Second
+	streamBufferSize  = 64
+)
+
+// streamHandler decodes the content of data messages for a service and sends it on a channel.
+// Handlers are only called from the goroutine reading the connection, which also closes their channels,
+// so a channel is never closed while a handler is sending on it.
+type streamHandler struct {
+	ch     interface{}
+	handle func(content []map[string]json.RawMessage)
+	close  func()
+}
+
+// streamSubscription is a subscription to send again after reconnecting.
+type streamSubscription struct {
+	service string
+	command string
+	params  map[string]string
+}
+
+type streamCommand struct {
+	Requests []streamRequest `json:"requests"`
+}
+
+type streamRequest struct {
+	Service    string            `json:"service"`
+	Requestid  string            `json:"requestid"`
+	Command    string            `json:"command"`
+	Account    string            `json:"account"`
+	Source     string            `json:"source"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// streamMessage is a message from TD Ameritrade's streamer.
+// Responses acknowledge requests, notifications carry heartbeats, data carries updates for subscriptions
+// and snapshots carry the replies to GET requests, such as chart history.
+type streamMessage struct {
+	Response []StreamAuthResponseBody `json:"response"`
+	Notify   []json.RawMessage        `json:"notify"`
+	Data     []streamData             `json:"data"`
+	Snapshot []streamData             `json:"snapshot"`
+}
+
+type streamData struct {
+	Service   string                       `json:"service"`
+	Timestamp int64                        `json:"timestamp"`
+	Command   string                       `json:"command"`
+	Content   []map[string]json.RawMessage `json:"content"`
+}
+
+// Connect connects to the streamer in principals and logs in to its primary account.
+// ctx bounds connecting and logging in; once connected, the StreamingClient stays connected until it is closed.
+// principals must include streamerSubscriptionKeys and streamerConnectionInfo.
+func (s *StreamingClient) Connect(ctx context.Context, principals *UserPrincipals) error {
+	accountID := principals.PrimaryAccountID
+	if accountID == "" && len(principals.Accounts) > 0 {
+		accountID = principals.Accounts[0].AccountID
+	}
+
+	authCmd, err := NewStreamAuthCommand(principals, accountID)
+	if err != nil {
+		return err
+	}
+
+	streamURL := url.URL{
+		Scheme: "wss",
+		Host:   principals.StreamerInfo.StreamerSocketURL,
+		Path:   "/ws",
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return errors.New("streaming client is already connected")
+	}
+
+	if s.dialer == nil {
+		s.dialer = websocket.DefaultDialer
+	}
+	if s.heartbeatTimeout == 0 {
+		s.heartbeatTimeout = streamHeartbeatTimeout
+	}
+	s.streamURL = streamURL.String()
+	s.authCmd = authCmd
+	s.account = accountID
+	s.source = principals.StreamerInfo.AppID
+	if keys := principals.StreamerSubscriptionKeys.Keys; len(keys) > 0 {
+		s.subscriptionKey = keys[0].Key
+	}
+	s.errors = make(chan error, streamBufferSize)
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	s.connection = conn
+	s.done = make(chan struct{})
+
+	go s.run(conn)
+	return nil
+}
+
+// dial connects to the streamer and logs in.
+func (s *StreamingClient) dial(ctx context.Context) (*websocket.Conn, error) {
+	conn, _, err := s.dialer.DialContext(ctx, s.streamURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.login(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (s *StreamingClient) login(ctx context.Context, conn *websocket.Conn) error {
+	if err := conn.WriteJSON(s.authCmd); err != nil {
+		return err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(s.heartbeatTimeout)
+	}
+	conn.SetReadDeadline(deadline)
+
+	// Heartbeats may arrive before the login response.
+	for {
+		var message streamMessage
+		if err := conn.ReadJSON(&message); err != nil {
+			return err
+		}
+
+		for _, response := range message.Response {
+			if response.Service != "ADMIN" || response.Command != "LOGIN" {
+				continue
+			}
+			// Response with a code 0 means authentication succeeded.
+			if response.Content.Code != 0 {
+				return fmt.Errorf("streamer login failed: %s", response.Content.Msg)
+			}
+			return nil
+		}
+	}
+}
+
+// run reads messages from conn until the StreamingClient is closed, reconnecting when the connection drops.
+func (s *StreamingClient) run(conn *websocket.Conn) {
+	defer s.closeHandlers()
+
+	for {
+		err := s.read(conn)
+		if s.isClosed() {
+			return
+		}
+		s.reportError(err)
+
+		// The connection is dead, but its socket stays open until it is closed.
+		conn.Close()
+		conn = s.reconnect(err)
+		if conn == nil {
+			return
+		}
+	}
+}
+
+// read dispatches messages from conn until reading fails.
+func (s *StreamingClient) read(conn *websocket.Conn) error {
+	for {
+		conn.SetReadDeadline(time.Now().Add(s.heartbeatTimeout))
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var message streamMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			s.reportError(fmt.Errorf("could not decode streamer message: %w", err))
+			continue
+		}
+
+		for _, response := range message.Response {
+			if response.Content.Code != 0 {
+				s.reportError(fmt.Errorf("%s %s request %s failed: %s", response.Service, response.Command, response.Requestid, response.Content.Msg))
+			}
+		}
+
+		for _, data := range append(message.Data, message.Snapshot...) {
+			s.subsMu.Lock()
+			h := s.handlers[data.Service]
+			s.subsMu.Unlock()
+			if h != nil {
+				h.handle(data.Content)
+			}
+		}
+	}
+}
+
+// reconnect dials the streamer as decided by the ReconnectPolicy and sends the subscriptions again.
+// err is why the connection dropped. It returns nil if the StreamingClient is closed first or the policy gives up,
+// in which case it closes the StreamingClient.
+func (s *StreamingClient) reconnect(err error) *websocket.Conn {
+	policy := s.ReconnectPolicy
+	if policy == nil {
+		policy = DefaultReconnectPolicy(0)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !policy.ShouldReconnect(attempt, err) {
+			s.mu.Lock()
+			closed := s.closed
+			if !closed {
+				s.closed = true
+				close(s.done)
+			}
+			s.mu.Unlock()
+			if !closed && s.OnReconnectFailed != nil {
+				s.OnReconnectFailed(err)
+			}
+			return nil
+		}
+
+		timer := time.NewTimer(policy.ReconnectDelay(attempt))
+		select {
+		case <-s.done:
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.heartbeatTimeout)
+		var conn *websocket.Conn
+		conn, err = s.dial(ctx)
+		cancel()
+		if err != nil {
+			s.reportError(err)
+			continue
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.connection = conn
+		s.mu.Unlock()
+
+		s.subsMu.Lock()
+		subs := append([]streamSubscription(nil), s.subs...)
+		s.subsMu.Unlock()
+		for _, sub := range subs {
+			if err := s.send(sub.service, sub.command, sub.params); err != nil {
+				s.reportError(err)
+			}
+		}
+
+		if s.OnReconnect != nil {
+			s.OnReconnect(attempt)
+		}
+		return conn
+	}
+}
+
+// Subscribe sends command for service to TD Ameritrade, e.g. SUBS, ADD or UNSUBS.
+// params usually include keys and fields, both comma separated.
+// SUBS and ADD commands are sent again when the StreamingClient reconnects.
+// Updates are only delivered for services with a typed Subscribe method, such as SubscribeQuotes.
+func (s *StreamingClient) Subscribe(service, command string, params map[string]string) error {
+	if err := s.send(service, command, params); err != nil {
+		return err
+	}
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	switch command {
+	case "SUBS":
+		// SUBS replaces all of the keys subscribed to for a service.
+		subs := s.subs[:0]
+		for _, sub := range s.subs {
+			if sub.service != service {
+				subs = append(subs, sub)
+			}
+		}
+		s.subs = append(subs, streamSubscription{service, command, params})
+	case "ADD":
+		s.subs = append(s.subs, streamSubscription{service, command, params})
+	}
+	return nil
+}
+
+// Unsubscribe stops updates for keys of service.
+func (s *StreamingClient) Unsubscribe(service string, keys []string) error {
+	if err := s.send(service, "UNSUBS", map[string]string{"keys": strings.Join(keys, ",")}); err != nil {
+		return err
+	}
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	subs := s.subs[:0]
+	for _, sub := range s.subs {
+		if sub.service == service {
+			sub.params = withoutKeys(sub.params, keys)
+			if sub.params["keys"] == "" {
+				continue
+			}
+		}
+		subs = append(subs, sub)
+	}
+	s.subs = subs
+	return nil
+}
+
+func (s *StreamingClient) send(service, command string, params map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done == nil || s.closed {
+		return errors.New("streaming client is not connected")
+	}
+
+	s.requestID++
+	return s.connection.WriteJSON(streamCommand{
+		Requests: []streamRequest{
+			{
+				Service:    service,
+				Requestid:  strconv.Itoa(s.requestID),
+				Command:    command,
+				Account:    s.account,
+				Source:     s.source,
+				Parameters: params,
+			},
+		},
+	})
+}
+
+// handler returns the handler for service, creating it with newHandler if there is none.
+func (s *StreamingClient) handler(service string, newHandler func() *streamHandler) *streamHandler {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	if s.handlers == nil {
+		s.handlers = map[string]*streamHandler{}
+	}
+	h, ok := s.handlers[service]
+	if !ok {
+		h = newHandler()
+		s.handlers[service] = h
+	}
+	return h
+}
+
+func (s *StreamingClient) closeHandlers() {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for _, h := range s.handlers {
+		h.close()
+	}
+	s.handlers = nil
+	close(s.errors)
+}
+
+func (s *StreamingClient) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// reportError sends err on the errors channel returned by ReceiveText, dropping it if nobody is listening.
+func (s *StreamingClient) reportError(err error) {
+	select {
+	case s.errors <- err:
+	default:
+	}
+}
+
+func withoutKeys(params map[string]string, keys []string) map[string]string {
+	var remaining []string
+	for _, key := range strings.Split(params["keys"], ",") {
+		if !contains(key, keys) {
+			remaining = append(remaining, key)
+		}
+	}
+
+	p := make(map[string]string, len(params))
+	for k, v := range params {
+		p[k] = v
+	}
+	p["keys"] = strings.Join(remaining, ",")
+	return p
 }
 
 // Close closes the underlying websocket connection.
+// For clients created with Connect, it also stops reconnecting and closes the update channels.
 func (s *StreamingClient) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		if s.closed {
+			return nil
+		}
+		s.closed = true
+		close(s.done)
+	}
 	return s.connection.Close()
 }
 
@@ -136,6 +575,7 @@ func (s *StreamingClient) SendText(payload []byte) error {
 
 // ReceiveText returns read-only channels with the raw byte responses from TD Ameritrade and errors generated while streaming.
 // Callers should select over both of these channels to avoid blocking one.
+// Clients created with Connect deliver updates on typed channels instead, so only the error channel is used.
 // Callers are able to handle errors how thes see fit.
 // All errors will be from Gorilla's websocket library and implement the net.Error interface.
 func (s *StreamingClient) ReceiveText() (<-chan []byte, <-chan error) {
@@ -167,7 +607,7 @@ func (s *StreamingClient) Authenticate(authCmd *StreamAuthCommand) error {
 // You can get an authenticated streaming client with NewAuthenticatedStreamingClient.
 // To authenticate manually, send a JSON serialized StreamAuthCommand message with the StreamingClient's Authenticate method.
 // You'll need to Close a streaming client to free up the underlying resources.
-func NewUnauthenticatedStreamingClient(userPrincipal *UserPrincipal) (*StreamingClient, error) {
+func NewUnauthenticatedStreamingClient(userPrincipal *UserPrincipals) (*StreamingClient, error) {
 	streamURL := url.URL{
 		Scheme: "wss",
 		Host:   userPrincipal.StreamerInfo.StreamerSocketURL,
@@ -208,7 +648,7 @@ func NewUnauthenticatedStreamingClient(userPrincipal *UserPrincipal) (*Streaming
 // It sends an initial authentication message to TD Ameritrade and waits for a response before returning.
 // Use NewUnauthenticatedStreamingClient if you want to handle authentication yourself.
 // You'll need to Close a StreamingClient to free up the underlying resources.
-func NewAuthenticatedStreamingClient(userPrincipal *UserPrincipal, accountID string) (*StreamingClient, error) {
+func NewAuthenticatedStreamingClient(userPrincipal *UserPrincipals, accountID string) (*StreamingClient, error) {
 	streamingClient, err := NewUnauthenticatedStreamingClient(userPrincipal)
 	if err != nil {
 		return nil, err
@@ -246,7 +686,7 @@ func NewAuthenticatedStreamingClient(userPrincipal *UserPrincipal, accountID str
 
 }
 
-func findAccount(userPrincipal *UserPrincipal, accountID string) (*UserAccountInfo, error) {
+func findAccount(userPrincipal *UserPrincipals, accountID string) (*UserAccount, error) {
 	for _, acc := range userPrincipal.Accounts {
 		if acc.AccountID == accountID {
 			return &acc, nil
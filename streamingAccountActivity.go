@@ -0,0 +1,165 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AccountActivityType is the type of an account activity message.
+type AccountActivityType string
+
+// Account activity message types.
+// See https://developer.tdameritrade.com/content/streaming-data#_Toc504640580
+const (
+	// ActivitySubscribed is sent once the subscription starts.
+	ActivitySubscribed AccountActivityType = "SUBSCRIBED"
+	ActivityError      AccountActivityType = "ERROR"
+
+	ActivityBrokenTrade     AccountActivityType = "BrokenTrade"
+	ActivityManualExecution AccountActivityType = "ManualExecution"
+	// ActivityOrderActivation is sent when a conditional order, such as a stop order, is activated.
+	ActivityOrderActivation           AccountActivityType = "OrderActivation"
+	ActivityOrderCancelReplaceRequest AccountActivityType = "OrderCancelReplaceRequest"
+	ActivityOrderCancelRequest        AccountActivityType = "OrderCancelRequest"
+	// ActivityOrderEntryRequest acknowledges that a new order has been received.
+	ActivityOrderEntryRequest AccountActivityType = "OrderEntryRequest"
+	ActivityOrderFill         AccountActivityType = "OrderFill"
+	ActivityOrderPartialFill  AccountActivityType = "OrderPartialFill"
+	ActivityOrderRejection    AccountActivityType = "OrderRejection"
+	ActivityTooLateToCancel   AccountActivityType = "TooLateToCancel"
+	// ActivityUROUT confirms that an order has been canceled.
+	ActivityUROUT AccountActivityType = "UROUT"
+)
+
+// AccountActivityEvent is a message about activity in an account, such as an order being filled or canceled.
+// MessageData is XML, which ParseOrderFill decodes for fills.
+type AccountActivityEvent struct {
+	AccountID   string
+	MessageType AccountActivityType
+	MessageData string
+}
+
+// OrderFillActivity is an order being filled in full or in part.
+type OrderFillActivity struct {
+	AccountID      string
+	OrderID        string
+	Symbol         string
+	Instruction    string
+	OrderType      string
+	Quantity       float64
+	Price          float64
+	LeavesQuantity float64
+	ExecutionTime  time.Time
+}
+
+// orderFillMessage is the XML of OrderFill and OrderPartialFill messages.
+type orderFillMessage struct {
+	AccountKey     string    `xml:"OrderGroupID>AccountKey"`
+	OrderKey       string    `xml:"Order>OrderKey"`
+	Symbol         string    `xml:"Order>Security>Symbol"`
+	Instruction    string    `xml:"Order>OrderInstructions"`
+	OrderType      string    `xml:"Order>OrderType"`
+	Quantity       float64   `xml:"ExecutionInformation>Quantity"`
+	Price          float64   `xml:"ExecutionInformation>ExecutionPrice"`
+	LeavesQuantity float64   `xml:"ExecutionInformation>LeavesQuantity"`
+	Timestamp      time.Time `xml:"ExecutionInformation>Timestamp"`
+}
+
+// ParseOrderFill decodes the fill in an ActivityOrderFill or ActivityOrderPartialFill event.
+func (e AccountActivityEvent) ParseOrderFill() (*OrderFillActivity, error) {
+	if e.MessageType != ActivityOrderFill && e.MessageType != ActivityOrderPartialFill {
+		return nil, fmt.Errorf("%s is not an order fill", e.MessageType)
+	}
+
+	var message orderFillMessage
+	if err := xml.Unmarshal([]byte(e.MessageData), &message); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", e.MessageType, err)
+	}
+
+	return &OrderFillActivity{
+		AccountID:      message.AccountKey,
+		OrderID:        message.OrderKey,
+		Symbol:         message.Symbol,
+		Instruction:    message.Instruction,
+		OrderType:      message.OrderType,
+		Quantity:       message.Quantity,
+		Price:          message.Price,
+		LeavesQuantity: message.LeavesQuantity,
+		ExecutionTime:  message.Timestamp,
+	}, nil
+}
+
+// SubscribeAccountActivity subscribes to activity in the accounts of the UserPrincipals the StreamingClient connected with.
+// This is the recommended alternative to polling OrdersService.GetOrdersByAccount.
+// The principals must include streamerSubscriptionKeys.
+// The subscription ends when ctx is done; the channel is closed when the StreamingClient is closed.
+func (s *StreamingClient) SubscribeAccountActivity(ctx context.Context) (<-chan AccountActivityEvent, error) {
+	s.mu.Lock()
+	key := s.subscriptionKey
+	s.mu.Unlock()
+	if key == "" {
+		return nil, errors.New("no streamer subscription key, get user principals with the streamerSubscriptionKeys field")
+	}
+
+	h := s.handler("ACCT_ACTIVITY", func() *streamHandler {
+		ch := make(chan AccountActivityEvent, streamBufferSize)
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					event, err := decodeAccountActivityEvent(c)
+					if err != nil {
+						s.reportError(err)
+						continue
+					}
+					select {
+					case ch <- event:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+
+	if err := s.Subscribe("ACCT_ACTIVITY", "SUBS", streamParams([]string{key}, []int{0, 1, 2, 3})); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			if err := s.Unsubscribe("ACCT_ACTIVITY", []string{key}); err != nil && !s.isClosed() {
+				s.reportError(err)
+			}
+		case <-s.done:
+		}
+	}()
+
+	return h.ch.(chan AccountActivityEvent), nil
+}
+
+func decodeAccountActivityEvent(c map[string]json.RawMessage) (AccountActivityEvent, error) {
+	var event AccountActivityEvent
+	fields := []struct {
+		key string
+		v   interface{}
+	}{
+		{"1", &event.AccountID},
+		{"2", &event.MessageType},
+		{"3", &event.MessageData},
+	}
+	for _, f := range fields {
+		if raw, ok := c[f.key]; ok {
+			if err := json.Unmarshal(raw, f.v); err != nil {
+				return event, fmt.Errorf("could not decode ACCT_ACTIVITY field %s: %w", f.key, err)
+			}
+		}
+	}
+	return event, nil
+}
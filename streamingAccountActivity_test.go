@@ -0,0 +1,116 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// orderFillXML is an OrderFill message recorded from TD Ameritrade's ACCT_ACTIVITY service.
+const orderFillXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<OrderFillMessage xmlns="urn:xmlns:beb.ameritrade.com">
+	<OrderGroupID>
+		<Firm>150</Firm>
+		<Branch>766</Branch>
+		<ClientKey>123</ClientKey>
+		<AccountKey>123</AccountKey>
+		<SubAccountType>Margin</SubAccountType>
+		<CDDomainID>A000000012345678</CDDomainID>
+	</OrderGroupID>
+	<ActivityTimestamp>2020-06-01T10:26:23.493-05:00</ActivityTimestamp>
+	<Order>
+		<OrderKey>3652723497</OrderKey>
+		<Security>
+			<CUSIP>0SPY..CJ90280000</CUSIP>
+			<Symbol>SPY_070220C310</Symbol>
+			<SecurityType>Call Option</SecurityType>
+		</Security>
+		<OrderPricing>
+			<Limit>1.5</Limit>
+		</OrderPricing>
+		<OrderType>Limit</OrderType>
+		<OrderDuration>Day</OrderDuration>
+		<OrderEnteredDateTime>2020-06-01T10:26:23.345-05:00</OrderEnteredDateTime>
+		<OrderInstructions>Buy</OrderInstructions>
+		<OriginalQuantity>2</OriginalQuantity>
+		<AmountIndicator>Contracts</AmountIndicator>
+		<MarketCode>Normal</MarketCode>
+	</Order>
+	<OrderCompletionCode>Normal</OrderCompletionCode>
+	<SettlementDate>2020-06-02</SettlementDate>
+	<ExecutionInformation>
+		<Type>Bought</Type>
+		<Timestamp>2020-06-01T10:26:23.493-05:00</Timestamp>
+		<Quantity>2</Quantity>
+		<ExecutionPrice>1.45</ExecutionPrice>
+		<AveragePriceIndicator>false</AveragePriceIndicator>
+		<LeavesQuantity>0</LeavesQuantity>
+		<ID>11234</ID>
+		<Exchange>O</Exchange>
+		<BrokerId>CDRG</BrokerId>
+	</ExecutionInformation>
+	<TradeDate>2020-06-01</TradeDate>
+</OrderFillMessage>`
+
+func TestParseOrderFill(t *testing.T) {
+	event := AccountActivityEvent{AccountID: "123", MessageType: ActivityOrderFill, MessageData: orderFillXML}
+
+	fill, err := event.ParseOrderFill()
+	if err != nil {
+		t.Fatalf("ParseOrderFill returned error: %v", err)
+	}
+
+	want := OrderFillActivity{
+		AccountID:      "123",
+		OrderID:        "3652723497",
+		Symbol:         "SPY_070220C310",
+		Instruction:    "Buy",
+		OrderType:      "Limit",
+		Quantity:       2,
+		Price:          1.45,
+		LeavesQuantity: 0,
+		ExecutionTime:  time.Date(2020, 6, 1, 15, 26, 23, 493000000, time.UTC),
+	}
+	if !fill.ExecutionTime.Equal(want.ExecutionTime) {
+		t.Errorf("ExecutionTime = %v, want %v", fill.ExecutionTime, want.ExecutionTime)
+	}
+	fill.ExecutionTime = want.ExecutionTime
+	if *fill != want {
+		t.Errorf("ParseOrderFill = %+v, want %+v", fill, want)
+	}
+
+	event.MessageType = ActivityOrderCancelRequest
+	if _, err := event.ParseOrderFill(); err == nil {
+		t.Error("expected an error parsing a cancel request as a fill")
+	}
+}
+
+func TestStreamingClientSubscribeAccountActivity(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.SubscribeAccountActivity(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeAccountActivity returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "ACCT_ACTIVITY" || req.Command != "SUBS" || req.Parameters["keys"] != "subkey" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	data, _ := json.Marshal(orderFillXML)
+	sendStreamData(t, conn, `{"data":[{"service":"ACCT_ACTIVITY","timestamp":1591025183493,"command":"SUBS","content":[{"seq":1,"key":"subkey","1":"123","2":"OrderFill","3":`+string(data)+`}]}]}`)
+
+	event := <-events
+	if event.AccountID != "123" || event.MessageType != ActivityOrderFill || event.MessageData != orderFillXML {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	cancel()
+	if req := ts.request(t); req.Service != "ACCT_ACTIVITY" || req.Command != "UNSUBS" || req.Parameters["keys"] != "subkey" {
+		t.Errorf("expected canceling ctx to unsubscribe, got %+v", req)
+	}
+}
@@ -0,0 +1,139 @@
+package tdameritrade
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Intervals of the bars commonly built by a BarAggregator.
+const (
+	BarInterval5Min  = 5 * time.Minute
+	BarInterval15Min = 15 * time.Minute
+	BarInterval1Hour = time.Hour
+	BarIntervalDaily = 24 * time.Hour
+)
+
+// BarAggregator builds bars of longer intervals from the one minute bars of SubscribeChartEquity.
+// TD Ameritrade updates the bar of the current minute as trades come in, so each minute counts once,
+// with the values of its latest update.
+//
+// Bars start at multiples of their interval, except daily bars which start at midnight in the location of
+// the ChartTime of their minutes. A bar is sent once a minute of the same symbol in a later bar arrives,
+// so a bar is complete when it is received, and the bars still being built are sent when the input is closed.
+// ChartTime of a bar is its start, and Sequence and ChartDay are those of its last minute.
+//
+// Usage example:
+// aggregator, err := tdameritrade.NewBarAggregator(minutes, tdameritrade.BarInterval5Min, tdameritrade.BarIntervalDaily)
+// go func() { for bar := range aggregator.Bars(tdameritrade.BarIntervalDaily) { ... } }()
+// for bar := range aggregator.Bars(tdameritrade.BarInterval5Min) { ... }
+type BarAggregator struct {
+	bars map[time.Duration]chan ChartEquityBar
+}
+
+// NewBarAggregator returns a BarAggregator building bars of intervals from the one minute bars received on in.
+// Intervals must be whole minutes no longer than BarIntervalDaily.
+// The aggregator blocks until the bars of every interval are read, and closes their channels once in is closed.
+func NewBarAggregator(in <-chan ChartEquityBar, intervals ...time.Duration) (*BarAggregator, error) {
+	if len(intervals) == 0 {
+		return nil, fmt.Errorf("%w: no bar intervals present", ErrInvalidParams)
+	}
+	a := &BarAggregator{bars: map[time.Duration]chan ChartEquityBar{}}
+	for _, interval := range intervals {
+		if interval < time.Minute || interval > BarIntervalDaily || interval%time.Minute != 0 {
+			return nil, fmt.Errorf("%w: bar interval must be whole minutes up to a day, got %v", ErrInvalidParams, interval)
+		}
+		a.bars[interval] = make(chan ChartEquityBar, streamBufferSize)
+	}
+
+	go a.run(in)
+	return a, nil
+}
+
+// Bars returns the channel the bars of interval are sent on, or nil if the aggregator does not build them.
+func (a *BarAggregator) Bars(interval time.Duration) <-chan ChartEquityBar {
+	ch, ok := a.bars[interval]
+	if !ok {
+		return nil
+	}
+	return ch
+}
+
+// buildingBar holds the minutes of a bar being built, by their ChartTime.
+type buildingBar struct {
+	start   time.Time
+	minutes map[time.Time]ChartEquityBar
+}
+
+func (a *BarAggregator) run(in <-chan ChartEquityBar) {
+	building := map[time.Duration]map[string]*buildingBar{}
+	for interval := range a.bars {
+		building[interval] = map[string]*buildingBar{}
+	}
+
+	for minute := range in {
+		for interval, ch := range a.bars {
+			start := barStart(minute.ChartTime, interval)
+			bar, ok := building[interval][minute.Symbol]
+			if ok && start.Before(bar.start) {
+				// A late update of a bar already sent.
+				continue
+			}
+			if ok && start.After(bar.start) {
+				ch <- bar.aggregate()
+				ok = false
+			}
+			if !ok {
+				bar = &buildingBar{start: start, minutes: map[time.Time]ChartEquityBar{}}
+				building[interval][minute.Symbol] = bar
+			}
+			bar.minutes[minute.ChartTime] = minute
+		}
+	}
+
+	for interval, ch := range a.bars {
+		for _, bar := range building[interval] {
+			ch <- bar.aggregate()
+		}
+		close(ch)
+	}
+}
+
+// aggregate returns the bar made of the minutes of b.
+func (b *buildingBar) aggregate() ChartEquityBar {
+	minutes := make([]ChartEquityBar, 0, len(b.minutes))
+	for _, m := range b.minutes {
+		minutes = append(minutes, m)
+	}
+	sort.Slice(minutes, func(i, j int) bool { return minutes[i].ChartTime.Before(minutes[j].ChartTime) })
+
+	first, last := minutes[0], minutes[len(minutes)-1]
+	bar := ChartEquityBar{
+		Symbol:     first.Symbol,
+		OpenPrice:  first.OpenPrice,
+		HighPrice:  first.HighPrice,
+		LowPrice:   first.LowPrice,
+		ClosePrice: last.ClosePrice,
+		Sequence:   last.Sequence,
+		ChartTime:  b.start,
+		ChartDay:   last.ChartDay,
+	}
+	for _, m := range minutes {
+		if m.HighPrice > bar.HighPrice {
+			bar.HighPrice = m.HighPrice
+		}
+		if m.LowPrice < bar.LowPrice {
+			bar.LowPrice = m.LowPrice
+		}
+		bar.Volume += m.Volume
+	}
+	return bar
+}
+
+// barStart returns the start of the bar of interval containing t.
+func barStart(t time.Time, interval time.Duration) time.Time {
+	if interval == BarIntervalDaily {
+		return Day1.start(t)
+	}
+	return t.Truncate(interval)
+}
@@ -0,0 +1,91 @@
+package tdameritrade
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBarAggregator(t *testing.T) {
+	start := time.Date(2020, 6, 1, 14, 30, 0, 0, time.UTC)
+	minute := func(symbol string, offset int, open, high, low, close, volume float64) ChartEquityBar {
+		return ChartEquityBar{
+			Symbol:     symbol,
+			OpenPrice:  open,
+			HighPrice:  high,
+			LowPrice:   low,
+			ClosePrice: close,
+			Volume:     volume,
+			Sequence:   int64(offset),
+			ChartTime:  start.Add(time.Duration(offset) * time.Minute),
+			ChartDay:   18414,
+		}
+	}
+
+	in := make(chan ChartEquityBar, 10)
+	in <- minute("SPY", 0, 300, 301, 299, 300.5, 1000)
+	in <- minute("SPY", 1, 300.5, 301, 300, 300.8, 200)
+	// The minute is updated as trades come in and must only count once.
+	in <- minute("SPY", 1, 300.5, 302, 300, 301.5, 500)
+	in <- minute("QQQ", 2, 250, 251, 249, 250.5, 300)
+	in <- minute("SPY", 4, 301.5, 301.6, 298, 299, 400)
+	in <- minute("SPY", 5, 299, 300, 298.5, 299.5, 600)
+	close(in)
+
+	aggregator, err := NewBarAggregator(in, BarInterval5Min, BarIntervalDaily)
+	if err != nil {
+		t.Fatalf("NewBarAggregator returned error: %v", err)
+	}
+	if aggregator.Bars(BarInterval1Hour) != nil {
+		t.Error("Bars returned a channel for an interval not built")
+	}
+
+	daily := map[string]ChartEquityBar{}
+	done := make(chan struct{})
+	go func() {
+		for bar := range aggregator.Bars(BarIntervalDaily) {
+			daily[bar.Symbol] = bar
+		}
+		close(done)
+	}()
+
+	var spy []ChartEquityBar
+	for bar := range aggregator.Bars(BarInterval5Min) {
+		if bar.Symbol == "SPY" {
+			spy = append(spy, bar)
+		}
+	}
+	<-done
+
+	want := []ChartEquityBar{
+		{Symbol: "SPY", OpenPrice: 300, HighPrice: 302, LowPrice: 298, ClosePrice: 299, Volume: 1900, Sequence: 4, ChartTime: start, ChartDay: 18414},
+		{Symbol: "SPY", OpenPrice: 299, HighPrice: 300, LowPrice: 298.5, ClosePrice: 299.5, Volume: 600, Sequence: 5, ChartTime: start.Add(5 * time.Minute), ChartDay: 18414},
+	}
+	if len(spy) != len(want) {
+		t.Fatalf("got %d SPY 5 minute bars, want %d: %+v", len(spy), len(want), spy)
+	}
+	for i := range want {
+		if spy[i] != want[i] {
+			t.Errorf("SPY bar %d = %+v, want %+v", i, spy[i], want[i])
+		}
+	}
+
+	wantDaily := ChartEquityBar{Symbol: "SPY", OpenPrice: 300, HighPrice: 302, LowPrice: 298, ClosePrice: 299.5, Volume: 2500, Sequence: 5, ChartTime: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), ChartDay: 18414}
+	if got := daily["SPY"]; got != wantDaily {
+		t.Errorf("SPY daily bar = %+v, want %+v", got, wantDaily)
+	}
+	if got := daily["QQQ"]; got.Volume != 300 || got.ClosePrice != 250.5 {
+		t.Errorf("unexpected QQQ daily bar: %+v", got)
+	}
+}
+
+func TestNewBarAggregatorInvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, 30 * time.Second, 90 * time.Second, 48 * time.Hour} {
+		if _, err := NewBarAggregator(make(chan ChartEquityBar), interval); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("NewBarAggregator(%v) error = %v, want ErrInvalidParams", interval, err)
+		}
+	}
+	if _, err := NewBarAggregator(make(chan ChartEquityBar)); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("NewBarAggregator() error = %v, want ErrInvalidParams", err)
+	}
+}
@@ -0,0 +1,479 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChartFrequency is the length of the bars sent by SubscribeChartHistory.
+type ChartFrequency int
+
+const (
+	Minute1 ChartFrequency = iota
+	Minute5
+	Minute10
+	Minute30
+	Hour1
+	Day1
+	Week1
+	Month1
+)
+
+// chartFrequencyCodes are the frequencies of CHART_HISTORY_FUTURES requests.
+var chartFrequencyCodes = map[ChartFrequency]string{
+	Minute1:  "m1",
+	Minute5:  "m5",
+	Minute10: "m10",
+	Minute30: "m30",
+	Hour1:    "h1",
+	Day1:     "d1",
+	Week1:    "w1",
+	Month1:   "n1",
+}
+
+// start returns the start of the bar containing t.
+// Days, weeks and months start at midnight in the location of t, weeks on Mondays.
+func (f ChartFrequency) start(t time.Time) time.Time {
+	switch f {
+	case Minute5:
+		return t.Truncate(5 * time.Minute)
+	case Minute10:
+		return t.Truncate(10 * time.Minute)
+	case Minute30:
+		return t.Truncate(30 * time.Minute)
+	case Hour1:
+		return t.Truncate(time.Hour)
+	case Day1:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case Week1:
+		day := Day1.start(t)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month1:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return t.Truncate(time.Minute)
+	}
+}
+
+// end returns the end of the bar starting at start.
+func (f ChartFrequency) end(start time.Time) time.Time {
+	switch f {
+	case Minute5:
+		return start.Add(5 * time.Minute)
+	case Minute10:
+		return start.Add(10 * time.Minute)
+	case Minute30:
+		return start.Add(30 * time.Minute)
+	case Hour1:
+		return start.Add(time.Hour)
+	case Day1:
+		return start.AddDate(0, 0, 1)
+	case Week1:
+		return start.AddDate(0, 0, 7)
+	case Month1:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.Add(time.Minute)
+	}
+}
+
+// ChartBar is a bar of a symbol starting at DateTime.
+// IsPartial is true while the bar is still building; a final copy with IsPartial false is sent once it is complete.
+type ChartBar struct {
+	OpenPrice  float64
+	HighPrice  float64
+	LowPrice   float64
+	ClosePrice float64
+	Volume     float64
+	DateTime   time.Time
+	IsPartial  bool
+}
+
+// merge adds the later bar b to the bar being built.
+func (bar *ChartBar) merge(b ChartBar) {
+	if b.HighPrice > bar.HighPrice {
+		bar.HighPrice = b.HighPrice
+	}
+	if b.LowPrice < bar.LowPrice {
+		bar.LowPrice = b.LowPrice
+	}
+	bar.ClosePrice = b.ClosePrice
+	bar.Volume += b.Volume
+}
+
+// SubscribeChartHistory sends bars of frequency for symbol from startTime, followed by real-time updates.
+// Futures, e.g. /ES, are backfilled from the CHART_HISTORY_FUTURES service and updated from CHART_FUTURES.
+//
+// Equities are not backfilled: TD Ameritrade's streamer has no chart history for them, and the StreamingClient
+// cannot make REST requests. For equities, startTime only drops earlier CHART_EQUITY updates, so the first bar
+// sent is the one of the first update received, which may be partial. To backfill equity bars, get the candles
+// from startTime with PriceHistoryService.GetPriceHistory before subscribing, and pass the time after the last
+// complete candle as startTime.
+//
+// Real-time updates are one minute candles, which are merged into bars of frequency.
+// The subscription ends and the channel is closed when ctx is done or the StreamingClient is closed.
+func (s *StreamingClient) SubscribeChartHistory(ctx context.Context, symbol string, frequency ChartFrequency, startTime time.Time) (<-chan ChartBar, error) {
+	code, ok := chartFrequencyCodes[frequency]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown chart frequency %d", ErrInvalidParams, frequency)
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: no symbol present", ErrInvalidParams)
+	}
+
+	futures := strings.HasPrefix(symbol, "/")
+	service, fields := "CHART_EQUITY", []int{0, 1, 2, 3, 4, 5, 6, 7, 8}
+	if futures {
+		service, fields = "CHART_FUTURES", []int{0, 1, 2, 3, 4, 5, 6}
+	}
+
+	l := &chartListener{
+		ctx:        ctx,
+		symbol:     symbol,
+		frequency:  frequency,
+		ch:         make(chan ChartBar, streamBufferSize),
+		liveFrom:   startTime,
+		backfilled: !futures,
+	}
+	if futures {
+		// History covers the minutes completed before now and real-time updates the rest.
+		l.liveFrom = time.Now().Truncate(time.Minute)
+		s.chartHistoryFutures()
+	}
+
+	chart := s.chartStream(service)
+	chart.mu.Lock()
+	chart.listeners = append(chart.listeners, l)
+	chart.mu.Unlock()
+
+	if err := s.Subscribe(service, "ADD", streamParams([]string{symbol}, fields)); err != nil {
+		return nil, err
+	}
+	if futures {
+		params := map[string]string{
+			"keys":       symbol,
+			"frequency":  code,
+			"START_TIME": strconv.FormatInt(startTime.UnixNano()/int64(time.Millisecond), 10),
+			"END_TIME":   strconv.FormatInt(l.liveFrom.UnixNano()/int64(time.Millisecond), 10),
+		}
+		if err := s.send("CHART_HISTORY_FUTURES", "GET", params); err != nil {
+			return nil, err
+		}
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			chart.remove(l)
+			if !chart.watching(symbol) {
+				if err := s.Unsubscribe(service, []string{symbol}); err != nil && !s.isClosed() {
+					s.reportError(err)
+				}
+			}
+		case <-s.done:
+		}
+	}()
+
+	return l.ch, nil
+}
+
+// chartListener builds the bars of a SubscribeChartHistory subscription.
+// Its bars are only accessed from the goroutine reading the connection.
+type chartListener struct {
+	ctx       context.Context
+	symbol    string
+	frequency ChartFrequency
+	ch        chan ChartBar
+
+	// mu is held while sending to ch, so that ch is not closed during a send.
+	mu     sync.Mutex
+	closed bool
+
+	// liveFrom is the start of the first real-time candle to use.
+	liveFrom time.Time
+	// backfilled is set once the history has been received; real-time candles are held in pending until then.
+	backfilled bool
+	pending    []ChartBar
+	building   *ChartBar
+}
+
+// close closes the channel of l unless it is already closed.
+func (l *chartListener) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.ch)
+	}
+}
+
+// addHistory returns the bars of history, keeping the last bar to build on if it is not complete.
+func (l *chartListener) addHistory(history []ChartBar) []ChartBar {
+	bars := make([]ChartBar, 0, len(history))
+	for _, bar := range history {
+		if l.frequency.end(bar.DateTime).After(l.liveFrom) {
+			bar.IsPartial = true
+			building := bar
+			l.building = &building
+		}
+		bars = append(bars, bar)
+	}
+
+	l.backfilled = true
+	for _, candle := range l.pending {
+		bars = append(bars, l.addCandle(candle)...)
+	}
+	l.pending = nil
+	return bars
+}
+
+// addCandle merges a real-time one minute candle into the bar being built and returns the bars to send.
+func (l *chartListener) addCandle(candle ChartBar) []ChartBar {
+	if candle.DateTime.Before(l.liveFrom) {
+		return nil
+	}
+	if !l.backfilled {
+		l.pending = append(l.pending, candle)
+		return nil
+	}
+
+	var bars []ChartBar
+	start := l.frequency.start(candle.DateTime)
+	if l.building != nil && start.After(l.building.DateTime) {
+		complete := *l.building
+		complete.IsPartial = false
+		bars = append(bars, complete)
+		l.building = nil
+	}
+	if l.building == nil {
+		bar := candle
+		bar.DateTime = start
+		l.building = &bar
+	} else {
+		l.building.merge(candle)
+	}
+
+	bar := *l.building
+	bar.IsPartial = candle.DateTime.Add(time.Minute).Before(l.frequency.end(start))
+	if !bar.IsPartial {
+		l.building = nil
+	}
+	return append(bars, bar)
+}
+
+// chartStream sends the candles of a chart service to the channel returned by SubscribeChartEquity
+// and to the SubscribeChartHistory listeners of their symbols.
+// The updates channel is only sent to once it has been asked for.
+type chartStream struct {
+	mu          sync.Mutex
+	updates     chan ChartEquityBar
+	sendUpdates bool
+	listeners   []*chartListener
+}
+
+func (s *StreamingClient) chartStream(service string) *chartStream {
+	h := s.handler(service, func() *streamHandler {
+		chart := &chartStream{updates: make(chan ChartEquityBar, streamBufferSize)}
+		return &streamHandler{
+			ch: chart,
+			close: func() {
+				chart.mu.Lock()
+				defer chart.mu.Unlock()
+				close(chart.updates)
+				for _, l := range chart.listeners {
+					l.close()
+				}
+			},
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					var update ChartEquityBar
+					var err error
+					if service == "CHART_FUTURES" {
+						update, err = decodeChartFutures(c)
+					} else {
+						update, err = decodeChartEquity(c)
+					}
+					if err != nil {
+						s.reportError(err)
+						continue
+					}
+
+					chart.mu.Lock()
+					sendUpdates := chart.sendUpdates
+					chart.mu.Unlock()
+					if sendUpdates && service == "CHART_EQUITY" {
+						select {
+						case chart.updates <- update:
+						case <-s.done:
+							return
+						}
+					}
+
+					candle := ChartBar{
+						OpenPrice:  update.OpenPrice,
+						HighPrice:  update.HighPrice,
+						LowPrice:   update.LowPrice,
+						ClosePrice: update.ClosePrice,
+						Volume:     update.Volume,
+						DateTime:   update.ChartTime,
+					}
+					for _, l := range chart.active(update.Symbol) {
+						if !s.sendChartBars(l, l.addCandle(candle)) {
+							return
+						}
+					}
+				}
+			},
+		}
+	})
+	return h.ch.(*chartStream)
+}
+
+// active returns the listeners of symbol whose subscription has not ended.
+func (chart *chartStream) active(symbol string) []*chartListener {
+	chart.mu.Lock()
+	defer chart.mu.Unlock()
+	var listeners []*chartListener
+	for _, l := range chart.listeners {
+		if l.symbol == symbol && l.ctx.Err() == nil {
+			listeners = append(listeners, l)
+		}
+	}
+	return listeners
+}
+
+// remove removes l from the listeners and closes its channel.
+func (chart *chartStream) remove(l *chartListener) {
+	chart.mu.Lock()
+	for i, listener := range chart.listeners {
+		if listener == l {
+			chart.listeners = append(chart.listeners[:i], chart.listeners[i+1:]...)
+			break
+		}
+	}
+	chart.mu.Unlock()
+	l.close()
+}
+
+// watching reports whether any subscription to symbol has not ended.
+func (chart *chartStream) watching(symbol string) bool {
+	return len(chart.active(symbol)) > 0
+}
+
+// chartHistoryFutures registers the handler passing CHART_HISTORY_FUTURES snapshots to the CHART_FUTURES listeners
+// waiting for their history, oldest first.
+func (s *StreamingClient) chartHistoryFutures() {
+	s.handler("CHART_HISTORY_FUTURES", func() *streamHandler {
+		return &streamHandler{
+			close: func() {},
+			handle: func(content []map[string]json.RawMessage) {
+				chart := s.chartStream("CHART_FUTURES")
+				for _, c := range content {
+					symbol, history, err := decodeChartHistoryFutures(c)
+					if err != nil {
+						s.reportError(err)
+						continue
+					}
+
+					for _, l := range chart.active(symbol) {
+						if l.backfilled {
+							continue
+						}
+						if !s.sendChartBars(l, l.addHistory(history)) {
+							return
+						}
+						break
+					}
+				}
+			},
+		}
+	})
+}
+
+// sendChartBars sends bars to l, returning false if the StreamingClient was closed first.
+func (s *StreamingClient) sendChartBars(l *chartListener, bars []ChartBar) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return true
+	}
+	for _, bar := range bars {
+		select {
+		case l.ch <- bar:
+		case <-l.ctx.Done():
+			return true
+		case <-s.done:
+			return false
+		}
+	}
+	return true
+}
+
+func decodeChartFutures(c map[string]json.RawMessage) (ChartEquityBar, error) {
+	var update ChartEquityBar
+	var chartTime int64
+	fields := []struct {
+		key string
+		v   interface{}
+	}{
+		{"key", &update.Symbol},
+		{"1", &chartTime},
+		{"2", &update.OpenPrice},
+		{"3", &update.HighPrice},
+		{"4", &update.LowPrice},
+		{"5", &update.ClosePrice},
+		{"6", &update.Volume},
+	}
+	for _, f := range fields {
+		if raw, ok := c[f.key]; ok {
+			if err := json.Unmarshal(raw, f.v); err != nil {
+				return update, fmt.Errorf("could not decode CHART_FUTURES field %s: %w", f.key, err)
+			}
+		}
+	}
+
+	update.ChartTime = time.Unix(0, chartTime*int64(time.Millisecond))
+	return update, nil
+}
+
+// chartHistoryCandle is a bar as CHART_HISTORY_FUTURES sends it.
+type chartHistoryCandle struct {
+	DateTime int64   `json:"0"`
+	Open     float64 `json:"1"`
+	High     float64 `json:"2"`
+	Low      float64 `json:"3"`
+	Close    float64 `json:"4"`
+	Volume   float64 `json:"5"`
+}
+
+// decodeChartHistoryFutures decodes a CHART_HISTORY_FUTURES snapshot, whose field 3 holds the bars.
+func decodeChartHistoryFutures(c map[string]json.RawMessage) (string, []ChartBar, error) {
+	var symbol string
+	var candles []chartHistoryCandle
+	if err := json.Unmarshal(c["key"], &symbol); err != nil {
+		return "", nil, fmt.Errorf("could not decode CHART_HISTORY_FUTURES key: %w", err)
+	}
+	if raw, ok := c["3"]; ok {
+		if err := json.Unmarshal(raw, &candles); err != nil {
+			return symbol, nil, fmt.Errorf("could not decode CHART_HISTORY_FUTURES bars of %s: %w", symbol, err)
+		}
+	}
+
+	bars := make([]ChartBar, len(candles))
+	for i, candle := range candles {
+		bars[i] = ChartBar{
+			OpenPrice:  candle.Open,
+			HighPrice:  candle.High,
+			LowPrice:   candle.Low,
+			ClosePrice: candle.Close,
+			Volume:     candle.Volume,
+			DateTime:   time.Unix(0, candle.DateTime*int64(time.Millisecond)),
+		}
+	}
+	return symbol, bars, nil
+}
@@ -0,0 +1,111 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStreamingClientSubscribeChartHistoryFutures(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	now := time.Now().Truncate(time.Minute)
+	start := now.Add(-2 * time.Minute)
+	bars, err := client.SubscribeChartHistory(context.Background(), "/ES", Minute1, start)
+	if err != nil {
+		t.Fatalf("SubscribeChartHistory returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "CHART_FUTURES" || req.Command != "ADD" || req.Parameters["keys"] != "/ES" || req.Parameters["fields"] != "0,1,2,3,4,5,6" {
+		t.Errorf("unexpected subscription: %+v", req)
+	}
+	req := ts.request(t)
+	if req.Service != "CHART_HISTORY_FUTURES" || req.Command != "GET" || req.Parameters["keys"] != "/ES" || req.Parameters["frequency"] != "m1" {
+		t.Errorf("unexpected history request: %+v", req)
+	}
+	if want := fmt.Sprint(start.UnixNano() / int64(time.Millisecond)); req.Parameters["START_TIME"] != want {
+		t.Errorf("START_TIME = %s, want %s", req.Parameters["START_TIME"], want)
+	}
+
+	ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+	// The real-time candle arrives before the history and is held until the history has been sent.
+	live := now.Add(time.Minute)
+	sendStreamData(t, conn, fmt.Sprintf(`{"data":[{"service":"CHART_FUTURES","timestamp":1,"command":"SUBS","content":[
+		{"key":"/ES","1":%d,"2":3010,"3":3012,"4":3009,"5":3011,"6":300}]}]}`, ms(live)))
+	sendStreamData(t, conn, fmt.Sprintf(`{"snapshot":[{"service":"CHART_HISTORY_FUTURES","timestamp":1,"command":"GET","content":[{"key":"/ES","0":"2","1":0,"2":2,"3":[
+		{"0":%d,"1":3000,"2":3005,"3":2999,"4":3004,"5":100},
+		{"0":%d,"1":3004,"2":3008,"3":3003,"4":3007,"5":200}]}]}]}`, ms(start), ms(start.Add(time.Minute))))
+
+	want := []ChartBar{
+		{OpenPrice: 3000, HighPrice: 3005, LowPrice: 2999, ClosePrice: 3004, Volume: 100, DateTime: start},
+		{OpenPrice: 3004, HighPrice: 3008, LowPrice: 3003, ClosePrice: 3007, Volume: 200, DateTime: start.Add(time.Minute)},
+		{OpenPrice: 3010, HighPrice: 3012, LowPrice: 3009, ClosePrice: 3011, Volume: 300, DateTime: live},
+	}
+	for i, w := range want {
+		if got := <-bars; !got.DateTime.Equal(w.DateTime) || got.OpenPrice != w.OpenPrice || got.Volume != w.Volume || got.IsPartial {
+			t.Errorf("bar %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestStreamingClientSubscribeChartHistoryEquity(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	if _, err := client.SubscribeChartHistory(context.Background(), "SPY", ChartFrequency(42), time.Time{}); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for an unknown frequency, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Date(2020, 6, 1, 14, 30, 0, 0, time.UTC)
+	bars, err := client.SubscribeChartHistory(ctx, "SPY", Minute5, start)
+	if err != nil {
+		t.Fatalf("SubscribeChartHistory returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "CHART_EQUITY" || req.Command != "ADD" || req.Parameters["keys"] != "SPY" {
+		t.Errorf("unexpected subscription: %+v", req)
+	}
+
+	ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+	sendStreamData(t, conn, fmt.Sprintf(`{"data":[{"service":"CHART_EQUITY","timestamp":1,"command":"SUBS","content":[
+		{"key":"SPY","1":299,"2":299,"3":299,"4":299,"5":50,"6":1,"7":%d,"8":18414},
+		{"key":"SPY","1":300,"2":302,"3":299.5,"4":301,"5":100,"6":2,"7":%d,"8":18414},
+		{"key":"SPY","1":301,"2":301.5,"3":298,"4":300.5,"5":150,"6":3,"7":%d,"8":18414}
+	]}]}`, ms(start.Add(-time.Minute)), ms(start), ms(start.Add(4*time.Minute))))
+
+	partial := <-bars
+	if !partial.IsPartial || !partial.DateTime.Equal(start) || partial.Volume != 100 {
+		t.Errorf("unexpected partial bar: %+v", partial)
+	}
+	complete := <-bars
+	want := ChartBar{OpenPrice: 300, HighPrice: 302, LowPrice: 298, ClosePrice: 300.5, Volume: 250}
+	if complete.IsPartial || !complete.DateTime.Equal(start) || complete.OpenPrice != want.OpenPrice || complete.HighPrice != want.HighPrice ||
+		complete.LowPrice != want.LowPrice || complete.ClosePrice != want.ClosePrice || complete.Volume != want.Volume {
+		t.Errorf("complete bar = %+v, want %+v", complete, want)
+	}
+
+	cancel()
+	if req := ts.request(t); req.Service != "CHART_EQUITY" || req.Command != "UNSUBS" || req.Parameters["keys"] != "SPY" {
+		t.Errorf("expected chart subscription to end, got %+v", req)
+	}
+	select {
+	case bar, ok := <-bars:
+		if ok {
+			t.Errorf("unexpected bar after the subscription ended: %+v", bar)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after ctx was cancelled")
+	}
+	chart := client.chartStream("CHART_EQUITY")
+	chart.mu.Lock()
+	if n := len(chart.listeners); n != 0 {
+		t.Errorf("%d listeners left after the subscription ended, want 0", n)
+	}
+	chart.mu.Unlock()
+}
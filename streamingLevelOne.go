@@ -0,0 +1,505 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// L1EquityField is a field of the QUOTE streaming service, which provides level one quotes of equities.
+// See https://developer.tdameritrade.com/content/streaming-data#_Toc504640567
+type L1EquityField int
+
+const (
+	L1EquitySymbol L1EquityField = iota
+	L1EquityBidPrice
+	L1EquityAskPrice
+	L1EquityLastPrice
+	L1EquityBidSize
+	L1EquityAskSize
+	L1EquityAskID
+	L1EquityBidID
+	L1EquityTotalVolume
+	L1EquityLastSize
+	L1EquityTradeTime
+	L1EquityQuoteTime
+	L1EquityHighPrice
+	L1EquityLowPrice
+	L1EquityBidTick
+	L1EquityClosePrice
+	L1EquityExchangeID
+	L1EquityMarginable
+	L1EquityShortable
+	L1EquityIslandBid
+	L1EquityIslandAsk
+	L1EquityIslandVolume
+	L1EquityQuoteDay
+	L1EquityTradeDay
+	L1EquityVolatility
+	L1EquityDescription
+	L1EquityLastID
+	L1EquityDigits
+	L1EquityOpenPrice
+	L1EquityNetChange
+	L1Equity52WeekHigh
+	L1Equity52WeekLow
+	L1EquityPERatio
+	L1EquityDividendAmount
+	L1EquityDividendYield
+	L1EquityIslandBidSize
+	L1EquityIslandAskSize
+	L1EquityNAV
+	L1EquityFundPrice
+	L1EquityExchangeName
+	L1EquityDividendDate
+	L1EquityRegularMarketQuote
+	L1EquityRegularMarketTrade
+	L1EquityRegularMarketLastPrice
+	L1EquityRegularMarketLastSize
+	L1EquityRegularMarketTradeTime
+	L1EquityRegularMarketTradeDay
+	L1EquityRegularMarketNetChange
+	L1EquitySecurityStatus
+	L1EquityMark
+	L1EquityQuoteTimeInLong
+	L1EquityTradeTimeInLong
+	L1EquityRegularMarketTradeTimeInLong
+)
+
+// QuoteField is the former name of L1EquityField.
+//
+// Deprecated: use L1EquityField.
+type QuoteField = L1EquityField
+
+// QuoteUpdate is the former name of L1EquityQuote.
+//
+// Deprecated: use L1EquityQuote.
+type QuoteUpdate = L1EquityQuote
+
+// Former names of the L1EquityField constants.
+//
+// Deprecated: use the L1Equity constants, e.g. L1EquityBidPrice for QuoteFieldBidPrice.
+const (
+	QuoteFieldSymbol                       = L1EquitySymbol
+	QuoteFieldBidPrice                     = L1EquityBidPrice
+	QuoteFieldAskPrice                     = L1EquityAskPrice
+	QuoteFieldLastPrice                    = L1EquityLastPrice
+	QuoteFieldBidSize                      = L1EquityBidSize
+	QuoteFieldAskSize                      = L1EquityAskSize
+	QuoteFieldAskID                        = L1EquityAskID
+	QuoteFieldBidID                        = L1EquityBidID
+	QuoteFieldTotalVolume                  = L1EquityTotalVolume
+	QuoteFieldLastSize                     = L1EquityLastSize
+	QuoteFieldTradeTime                    = L1EquityTradeTime
+	QuoteFieldQuoteTime                    = L1EquityQuoteTime
+	QuoteFieldHighPrice                    = L1EquityHighPrice
+	QuoteFieldLowPrice                     = L1EquityLowPrice
+	QuoteFieldBidTick                      = L1EquityBidTick
+	QuoteFieldClosePrice                   = L1EquityClosePrice
+	QuoteFieldExchangeID                   = L1EquityExchangeID
+	QuoteFieldMarginable                   = L1EquityMarginable
+	QuoteFieldShortable                    = L1EquityShortable
+	QuoteFieldIslandBid                    = L1EquityIslandBid
+	QuoteFieldIslandAsk                    = L1EquityIslandAsk
+	QuoteFieldIslandVolume                 = L1EquityIslandVolume
+	QuoteFieldQuoteDay                     = L1EquityQuoteDay
+	QuoteFieldTradeDay                     = L1EquityTradeDay
+	QuoteFieldVolatility                   = L1EquityVolatility
+	QuoteFieldDescription                  = L1EquityDescription
+	QuoteFieldLastID                       = L1EquityLastID
+	QuoteFieldDigits                       = L1EquityDigits
+	QuoteFieldOpenPrice                    = L1EquityOpenPrice
+	QuoteFieldNetChange                    = L1EquityNetChange
+	QuoteField52WeekHigh                   = L1Equity52WeekHigh
+	QuoteField52WeekLow                    = L1Equity52WeekLow
+	QuoteFieldPERatio                      = L1EquityPERatio
+	QuoteFieldDividendAmount               = L1EquityDividendAmount
+	QuoteFieldDividendYield                = L1EquityDividendYield
+	QuoteFieldIslandBidSize                = L1EquityIslandBidSize
+	QuoteFieldIslandAskSize                = L1EquityIslandAskSize
+	QuoteFieldNAV                          = L1EquityNAV
+	QuoteFieldFundPrice                    = L1EquityFundPrice
+	QuoteFieldExchangeName                 = L1EquityExchangeName
+	QuoteFieldDividendDate                 = L1EquityDividendDate
+	QuoteFieldRegularMarketQuote           = L1EquityRegularMarketQuote
+	QuoteFieldRegularMarketTrade           = L1EquityRegularMarketTrade
+	QuoteFieldRegularMarketLastPrice       = L1EquityRegularMarketLastPrice
+	QuoteFieldRegularMarketLastSize        = L1EquityRegularMarketLastSize
+	QuoteFieldRegularMarketTradeTime       = L1EquityRegularMarketTradeTime
+	QuoteFieldRegularMarketTradeDay        = L1EquityRegularMarketTradeDay
+	QuoteFieldRegularMarketNetChange       = L1EquityRegularMarketNetChange
+	QuoteFieldSecurityStatus               = L1EquitySecurityStatus
+	QuoteFieldMark                         = L1EquityMark
+	QuoteFieldQuoteTimeInLong              = L1EquityQuoteTimeInLong
+	QuoteFieldTradeTimeInLong              = L1EquityTradeTimeInLong
+	QuoteFieldRegularMarketTradeTimeInLong = L1EquityRegularMarketTradeTimeInLong
+)
+
+// L1EquityQuote is a level one quote of an equity.
+// Numbers are float64s, flags are bools and everything else is a string.
+// The getters return zero values for fields that are not present.
+type L1EquityQuote struct {
+	Symbol string
+	Fields map[L1EquityField]interface{}
+}
+
+func (q L1EquityQuote) float(field L1EquityField) float64 {
+	f, _ := q.Fields[field].(float64)
+	return f
+}
+
+func (q L1EquityQuote) BidPrice() float64    { return q.float(L1EquityBidPrice) }
+func (q L1EquityQuote) AskPrice() float64    { return q.float(L1EquityAskPrice) }
+func (q L1EquityQuote) LastPrice() float64   { return q.float(L1EquityLastPrice) }
+func (q L1EquityQuote) BidSize() float64     { return q.float(L1EquityBidSize) }
+func (q L1EquityQuote) AskSize() float64     { return q.float(L1EquityAskSize) }
+func (q L1EquityQuote) TotalVolume() float64 { return q.float(L1EquityTotalVolume) }
+func (q L1EquityQuote) HighPrice() float64   { return q.float(L1EquityHighPrice) }
+func (q L1EquityQuote) LowPrice() float64    { return q.float(L1EquityLowPrice) }
+func (q L1EquityQuote) ClosePrice() float64  { return q.float(L1EquityClosePrice) }
+func (q L1EquityQuote) OpenPrice() float64   { return q.float(L1EquityOpenPrice) }
+func (q L1EquityQuote) NetChange() float64   { return q.float(L1EquityNetChange) }
+func (q L1EquityQuote) Mark() float64        { return q.float(L1EquityMark) }
+
+func (q L1EquityQuote) Description() string {
+	d, _ := q.Fields[L1EquityDescription].(string)
+	return d
+}
+
+// levelOneEquity sends QUOTE updates to the channels returned by SubscribeQuotes and SubscribeLevelOneEquity.
+// Each channel is only sent to once it has been asked for. Both are sent to by the same handler, which waits
+// while a channel is full, so once asked for, a channel must be read for the other to keep receiving.
+type levelOneEquity struct {
+	mu            sync.Mutex
+	deltas        chan L1EquityQuote
+	merged        chan L1EquityQuote
+	sendDeltas    bool
+	sendMerged    bool
+	quoteBySymbol map[string]map[L1EquityField]interface{}
+}
+
+func (s *StreamingClient) levelOneEquity() *levelOneEquity {
+	h := s.handler("QUOTE", func() *streamHandler {
+		l1 := &levelOneEquity{
+			deltas:        make(chan L1EquityQuote, streamBufferSize),
+			merged:        make(chan L1EquityQuote, streamBufferSize),
+			quoteBySymbol: map[string]map[L1EquityField]interface{}{},
+		}
+		return &streamHandler{
+			ch: l1,
+			close: func() {
+				close(l1.deltas)
+				close(l1.merged)
+			},
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					delta, err := decodeL1EquityQuote(c)
+					if err != nil {
+						s.reportError(err)
+						continue
+					}
+
+					l1.mu.Lock()
+					sendDeltas, sendMerged := l1.sendDeltas, l1.sendMerged
+					merged := l1.merge(delta)
+					l1.mu.Unlock()
+
+					if sendDeltas {
+						select {
+						case l1.deltas <- delta:
+						case <-s.done:
+							return
+						}
+					}
+					if sendMerged {
+						select {
+						case l1.merged <- merged:
+						case <-s.done:
+							return
+						}
+					}
+				}
+			},
+		}
+	})
+	return h.ch.(*levelOneEquity)
+}
+
+// merge applies delta to the last known quote of its symbol and returns a copy of the result.
+func (l1 *levelOneEquity) merge(delta L1EquityQuote) L1EquityQuote {
+	quote, ok := l1.quoteBySymbol[delta.Symbol]
+	if !ok {
+		quote = map[L1EquityField]interface{}{}
+		l1.quoteBySymbol[delta.Symbol] = quote
+	}
+	for field, value := range delta.Fields {
+		quote[field] = value
+	}
+
+	merged := L1EquityQuote{Symbol: delta.Symbol, Fields: make(map[L1EquityField]interface{}, len(quote))}
+	for field, value := range quote {
+		merged.Fields[field] = value
+	}
+	return merged
+}
+
+// SubscribeQuotes subscribes to fields of the level one quotes of symbols, replacing any previous quote subscription.
+// TD Ameritrade only sends the fields that changed, so each L1EquityQuote on the returned channel is a partial update.
+// Use SubscribeLevelOneEquity to receive complete quotes instead. If both are used, both channels must be read,
+// as updates to one wait while the other is full.
+// All fields are subscribed to when fields is empty.
+// Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeQuotes(symbols []string, fields []L1EquityField) (<-chan L1EquityQuote, error) {
+	l1 := s.levelOneEquity()
+	if err := s.subscribeLevelOneEquity(symbols, fields); err != nil {
+		return nil, err
+	}
+
+	l1.mu.Lock()
+	l1.sendDeltas = true
+	l1.mu.Unlock()
+	return l1.deltas, nil
+}
+
+// SubscribeLevelOneEquity subscribes to fields of the level one quotes of symbols, replacing any previous quote subscription.
+// Each L1EquityQuote on the returned channel holds the latest value of every field received for its symbol,
+// merging the partial updates TD Ameritrade sends.
+// All fields are subscribed to when fields is empty.
+// Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeLevelOneEquity(symbols []string, fields []L1EquityField) (<-chan L1EquityQuote, error) {
+	l1 := s.levelOneEquity()
+	if err := s.subscribeLevelOneEquity(symbols, fields); err != nil {
+		return nil, err
+	}
+
+	l1.mu.Lock()
+	l1.sendMerged = true
+	l1.mu.Unlock()
+	return l1.merged, nil
+}
+
+func (s *StreamingClient) subscribeLevelOneEquity(symbols []string, fields []L1EquityField) error {
+	if len(fields) == 0 {
+		for f := L1EquitySymbol; f <= L1EquityRegularMarketTradeTimeInLong; f++ {
+			fields = append(fields, f)
+		}
+	}
+	numbers := make([]int, len(fields))
+	for i, f := range fields {
+		numbers[i] = int(f)
+	}
+
+	return s.Subscribe("QUOTE", "SUBS", streamParams(symbols, numbers))
+}
+
+func decodeL1EquityQuote(c map[string]json.RawMessage) (L1EquityQuote, error) {
+	quote := L1EquityQuote{Fields: map[L1EquityField]interface{}{}}
+	if err := json.Unmarshal(c["key"], &quote.Symbol); err != nil {
+		return quote, fmt.Errorf("could not decode QUOTE key: %w", err)
+	}
+
+	for k, v := range c {
+		field, err := strconv.Atoi(k)
+		if err != nil {
+			// Not a field, e.g. key, delayed or assetMainType.
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(v, &value); err != nil {
+			return quote, fmt.Errorf("could not decode QUOTE field %d of %s: %w", field, quote.Symbol, err)
+		}
+		quote.Fields[L1EquityField(field)] = value
+	}
+	return quote, nil
+}
+
+// L1OptionField is a field of the OPTION streaming service, which provides level one quotes of options.
+// See https://developer.tdameritrade.com/content/streaming-data#_Toc504640573
+type L1OptionField int
+
+const (
+	L1OptionSymbol L1OptionField = iota
+	L1OptionDescription
+	L1OptionBidPrice
+	L1OptionAskPrice
+	L1OptionLastPrice
+	L1OptionHighPrice
+	L1OptionLowPrice
+	L1OptionClosePrice
+	L1OptionTotalVolume
+	L1OptionOpenInterest
+	L1OptionVolatility
+	L1OptionQuoteTime
+	L1OptionTradeTime
+	L1OptionIntrinsicValue
+	L1OptionQuoteDay
+	L1OptionTradeDay
+	L1OptionExpirationYear
+	L1OptionMultiplier
+	L1OptionDigits
+	L1OptionOpenPrice
+	L1OptionBidSize
+	L1OptionAskSize
+	L1OptionLastSize
+	L1OptionNetChange
+	L1OptionStrikePrice
+	L1OptionContractType
+	L1OptionUnderlying
+	L1OptionExpirationMonth
+	L1OptionDeliverables
+	L1OptionTimeValue
+	L1OptionExpirationDay
+	L1OptionDaysToExpiration
+	L1OptionDelta
+	L1OptionGamma
+	L1OptionTheta
+	L1OptionVega
+	L1OptionRho
+	L1OptionSecurityStatus
+	L1OptionTheoreticalValue
+	L1OptionUnderlyingPrice
+	L1OptionUVExpirationType
+	L1OptionMark
+)
+
+// L1OptionQuote is a level one quote of an option.
+// TD Ameritrade only sends the fields that changed, so each quote is merged with the previous quote of its symbol.
+// Fields that have not been received are zero.
+type L1OptionQuote struct {
+	Symbol            string
+	Description       string
+	Underlying        string
+	ContractType      string
+	StrikePrice       float64
+	DaysToExpiration  int
+	BidPrice          float64
+	AskPrice          float64
+	LastPrice         float64
+	Mark              float64
+	HighPrice         float64
+	LowPrice          float64
+	ClosePrice        float64
+	OpenPrice         float64
+	NetChange         float64
+	BidSize           float64
+	AskSize           float64
+	LastSize          float64
+	TotalVolume       float64
+	OpenInterest      float64
+	ImpliedVolatility float64
+	IntrinsicValue    float64
+	TimeValue         float64
+	TheoreticalValue  float64
+	UnderlyingPrice   float64
+	Delta             float64
+	Gamma             float64
+	Theta             float64
+	Vega              float64
+	Rho               float64
+}
+
+// apply sets the fields of q present in c.
+func (q *L1OptionQuote) apply(c map[string]json.RawMessage) error {
+	fields := map[L1OptionField]interface{}{
+		L1OptionDescription:      &q.Description,
+		L1OptionUnderlying:       &q.Underlying,
+		L1OptionContractType:     &q.ContractType,
+		L1OptionStrikePrice:      &q.StrikePrice,
+		L1OptionDaysToExpiration: &q.DaysToExpiration,
+		L1OptionBidPrice:         &q.BidPrice,
+		L1OptionAskPrice:         &q.AskPrice,
+		L1OptionLastPrice:        &q.LastPrice,
+		L1OptionMark:             &q.Mark,
+		L1OptionHighPrice:        &q.HighPrice,
+		L1OptionLowPrice:         &q.LowPrice,
+		L1OptionClosePrice:       &q.ClosePrice,
+		L1OptionOpenPrice:        &q.OpenPrice,
+		L1OptionNetChange:        &q.NetChange,
+		L1OptionBidSize:          &q.BidSize,
+		L1OptionAskSize:          &q.AskSize,
+		L1OptionLastSize:         &q.LastSize,
+		L1OptionTotalVolume:      &q.TotalVolume,
+		L1OptionOpenInterest:     &q.OpenInterest,
+		L1OptionVolatility:       &q.ImpliedVolatility,
+		L1OptionIntrinsicValue:   &q.IntrinsicValue,
+		L1OptionTimeValue:        &q.TimeValue,
+		L1OptionTheoreticalValue: &q.TheoreticalValue,
+		L1OptionUnderlyingPrice:  &q.UnderlyingPrice,
+		L1OptionDelta:            &q.Delta,
+		L1OptionGamma:            &q.Gamma,
+		L1OptionTheta:            &q.Theta,
+		L1OptionVega:             &q.Vega,
+		L1OptionRho:              &q.Rho,
+	}
+
+	for k, v := range c {
+		field, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		dst, ok := fields[L1OptionField(field)]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(v, dst); err != nil {
+			return fmt.Errorf("could not decode OPTION field %d of %s: %w", field, q.Symbol, err)
+		}
+	}
+	return nil
+}
+
+// SubscribeLevelOneOption subscribes to fields of the level one quotes of option contracts, e.g. SPY_121621C450,
+// replacing any previous option subscription.
+// All fields are subscribed to when fields is empty.
+// Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeLevelOneOption(symbols []string, fields []L1OptionField) (<-chan L1OptionQuote, error) {
+	h := s.handler("OPTION", func() *streamHandler {
+		ch := make(chan L1OptionQuote, streamBufferSize)
+		quoteBySymbol := map[string]*L1OptionQuote{}
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					var symbol string
+					if err := json.Unmarshal(c["key"], &symbol); err != nil {
+						s.reportError(fmt.Errorf("could not decode OPTION key: %w", err))
+						continue
+					}
+
+					quote, ok := quoteBySymbol[symbol]
+					if !ok {
+						quote = &L1OptionQuote{Symbol: symbol}
+						quoteBySymbol[symbol] = quote
+					}
+					if err := quote.apply(c); err != nil {
+						s.reportError(err)
+						continue
+					}
+
+					select {
+					case ch <- *quote:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+
+	if len(fields) == 0 {
+		for f := L1OptionSymbol; f <= L1OptionMark; f++ {
+			fields = append(fields, f)
+		}
+	}
+	numbers := make([]int, len(fields))
+	for i, f := range fields {
+		numbers[i] = int(f)
+	}
+
+	if err := s.Subscribe("OPTION", "SUBS", streamParams(symbols, numbers)); err != nil {
+		return nil, err
+	}
+	return h.ch.(chan L1OptionQuote), nil
+}
@@ -0,0 +1,176 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// L1ForexField is a field of the LEVELONE_FOREX streaming service, which provides level one quotes of currency pairs.
+// See https://developer.tdameritrade.com/content/streaming-data#_Toc504640595
+type L1ForexField int
+
+const (
+	L1ForexSymbol L1ForexField = iota
+	L1ForexBidPrice
+	L1ForexAskPrice
+	L1ForexLastPrice
+	L1ForexBidSize
+	L1ForexAskSize
+	L1ForexTotalVolume
+	L1ForexLastSize
+	L1ForexQuoteTime
+	L1ForexTradeTime
+	L1ForexHighPrice
+	L1ForexLowPrice
+	L1ForexClosePrice
+	L1ForexExchangeID
+	L1ForexDescription
+	L1ForexOpenPrice
+	L1ForexNetChange
+	L1ForexPercentChange
+	L1ForexExchangeName
+	L1ForexDigits
+	L1ForexSecurityStatus
+	L1ForexTick
+	L1ForexTickAmount
+	L1ForexProduct
+	L1ForexTradingHours
+	L1ForexIsTradable
+	L1ForexMarketMaker
+	L1Forex52WeekHigh
+	L1Forex52WeekLow
+	L1ForexMark
+)
+
+// L1ForexQuote is a level one quote of a currency pair, e.g. EUR/USD.
+// TD Ameritrade only sends the fields that changed, so each quote is merged with the previous quote of its symbol.
+// Fields that have not been received are zero.
+type L1ForexQuote struct {
+	Symbol        string
+	Description   string
+	BidPrice      float64
+	AskPrice      float64
+	LastPrice     float64
+	BidSize       float64
+	AskSize       float64
+	LastSize      float64
+	QuoteTime     time.Time
+	TradeTime     time.Time
+	DailyHigh     float64
+	DailyLow      float64
+	PrevDayClose  float64
+	ExchangeID    string
+	OpenPrice     float64
+	NetChange     float64
+	PercentChange float64
+	TradingHours  string
+	IsTradable    bool
+	MarketMaker   string
+}
+
+// apply sets the fields of q present in c.
+func (q *L1ForexQuote) apply(c map[string]json.RawMessage) error {
+	fields := map[L1ForexField]interface{}{
+		L1ForexDescription:   &q.Description,
+		L1ForexBidPrice:      &q.BidPrice,
+		L1ForexAskPrice:      &q.AskPrice,
+		L1ForexLastPrice:     &q.LastPrice,
+		L1ForexBidSize:       &q.BidSize,
+		L1ForexAskSize:       &q.AskSize,
+		L1ForexLastSize:      &q.LastSize,
+		L1ForexHighPrice:     &q.DailyHigh,
+		L1ForexLowPrice:      &q.DailyLow,
+		L1ForexClosePrice:    &q.PrevDayClose,
+		L1ForexExchangeID:    &q.ExchangeID,
+		L1ForexOpenPrice:     &q.OpenPrice,
+		L1ForexNetChange:     &q.NetChange,
+		L1ForexPercentChange: &q.PercentChange,
+		L1ForexTradingHours:  &q.TradingHours,
+		L1ForexIsTradable:    &q.IsTradable,
+		L1ForexMarketMaker:   &q.MarketMaker,
+	}
+	times := map[L1ForexField]*time.Time{
+		L1ForexQuoteTime: &q.QuoteTime,
+		L1ForexTradeTime: &q.TradeTime,
+	}
+
+	for k, v := range c {
+		field, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		if dst, ok := times[L1ForexField(field)]; ok {
+			var ms int64
+			if err := json.Unmarshal(v, &ms); err != nil {
+				return fmt.Errorf("could not decode LEVELONE_FOREX field %d of %s: %w", field, q.Symbol, err)
+			}
+			*dst = time.Unix(0, ms*int64(time.Millisecond))
+			continue
+		}
+		dst, ok := fields[L1ForexField(field)]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(v, dst); err != nil {
+			return fmt.Errorf("could not decode LEVELONE_FOREX field %d of %s: %w", field, q.Symbol, err)
+		}
+	}
+	return nil
+}
+
+// SubscribeLevelOneForex subscribes to fields of the level one quotes of currency pairs, e.g. EUR/USD,
+// replacing any previous forex subscription.
+// All fields are subscribed to when fields is empty.
+// Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeLevelOneForex(symbols []string, fields []L1ForexField) (<-chan L1ForexQuote, error) {
+	h := s.handler("LEVELONE_FOREX", func() *streamHandler {
+		ch := make(chan L1ForexQuote, streamBufferSize)
+		quoteBySymbol := map[string]*L1ForexQuote{}
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					var symbol string
+					if err := json.Unmarshal(c["key"], &symbol); err != nil {
+						s.reportError(fmt.Errorf("could not decode LEVELONE_FOREX key: %w", err))
+						continue
+					}
+
+					quote, ok := quoteBySymbol[symbol]
+					if !ok {
+						quote = &L1ForexQuote{Symbol: symbol}
+						quoteBySymbol[symbol] = quote
+					}
+					if err := quote.apply(c); err != nil {
+						s.reportError(err)
+						continue
+					}
+
+					select {
+					case ch <- *quote:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+
+	if len(fields) == 0 {
+		for f := L1ForexSymbol; f <= L1ForexMark; f++ {
+			fields = append(fields, f)
+		}
+	}
+	numbers := make([]int, len(fields))
+	for i, f := range fields {
+		numbers[i] = int(f)
+	}
+
+	if err := s.Subscribe("LEVELONE_FOREX", "SUBS", streamParams(symbols, numbers)); err != nil {
+		return nil, err
+	}
+	return h.ch.(chan L1ForexQuote), nil
+}
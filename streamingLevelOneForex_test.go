@@ -0,0 +1,61 @@
+package tdameritrade
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamingClientSubscribeLevelOneForex(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	quotes, err := client.SubscribeLevelOneForex([]string{"EUR/USD"}, nil)
+	if err != nil {
+		t.Fatalf("SubscribeLevelOneForex returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "LEVELONE_FOREX" || req.Parameters["keys"] != "EUR/USD" ||
+		req.Parameters["fields"] != "0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"LEVELONE_FOREX","timestamp":1591000000000,"command":"SUBS","content":[{"key":"EUR/USD",
+		"1":1.1132,"2":1.1134,"3":1.1133,"4":1000000,"5":2000000,"7":10000,"8":1591000000000,"9":1590999999000,
+		"10":1.1154,"11":1.1101,"12":1.1102,"13":"T","14":"Euro/USDollar Spot","15":1.1103,"16":0.0031,"17":0.28,
+		"24":"GLBX(de=1640;0=-17001600;1=r-17001600d-15551640;7=d-16401645)","25":true,"26":"FXCM"}]}]}`)
+	sendStreamData(t, conn, `{"data":[{"service":"LEVELONE_FOREX","timestamp":1591000001000,"command":"SUBS","content":[{"key":"EUR/USD","2":1.1135}]}]}`)
+
+	first := <-quotes
+	want := L1ForexQuote{
+		Symbol:        "EUR/USD",
+		Description:   "Euro/USDollar Spot",
+		BidPrice:      1.1132,
+		AskPrice:      1.1134,
+		LastPrice:     1.1133,
+		BidSize:       1000000,
+		AskSize:       2000000,
+		LastSize:      10000,
+		QuoteTime:     time.Unix(1591000000, 0),
+		TradeTime:     time.Unix(1590999999, 0),
+		DailyHigh:     1.1154,
+		DailyLow:      1.1101,
+		PrevDayClose:  1.1102,
+		ExchangeID:    "T",
+		OpenPrice:     1.1103,
+		NetChange:     0.0031,
+		PercentChange: 0.28,
+		TradingHours:  "GLBX(de=1640;0=-17001600;1=r-17001600d-15551640;7=d-16401645)",
+		IsTradable:    true,
+		MarketMaker:   "FXCM",
+	}
+	if first != want {
+		t.Errorf("quote = %+v, want %+v", first, want)
+	}
+
+	second := <-quotes
+	want.AskPrice = 1.1135
+	if second != want {
+		t.Errorf("merged quote = %+v, want %+v", second, want)
+	}
+}
@@ -0,0 +1,193 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// L1FutureField is a field of the LEVELONE_FUTURES streaming service, which provides level one quotes of futures.
+// See https://developer.tdameritrade.com/content/streaming-data#_Toc504640587
+type L1FutureField int
+
+const (
+	L1FutureSymbol L1FutureField = iota
+	L1FutureBidPrice
+	L1FutureAskPrice
+	L1FutureLastPrice
+	L1FutureBidSize
+	L1FutureAskSize
+	L1FutureAskID
+	L1FutureBidID
+	L1FutureTotalVolume
+	L1FutureLastSize
+	L1FutureQuoteTime
+	L1FutureTradeTime
+	L1FutureHighPrice
+	L1FutureLowPrice
+	L1FutureClosePrice
+	L1FutureExchangeID
+	L1FutureDescription
+	L1FutureLastID
+	L1FutureOpenPrice
+	L1FutureNetChange
+	L1FuturePercentChange
+	L1FutureExchangeName
+	L1FutureSecurityStatus
+	L1FutureOpenInterest
+	L1FutureMark
+	L1FutureTick
+	L1FutureTickAmount
+	L1FutureProduct
+	L1FuturePriceFormat
+	L1FutureTradingHours
+	L1FutureIsTradable
+	L1FutureMultiplier
+	L1FutureIsActive
+	L1FutureSettlementPrice
+	L1FutureActiveSymbol
+	L1FutureExpirationDate
+)
+
+// L1FutureQuote is a level one quote of a future, e.g. /ES:XCME.
+// TD Ameritrade only sends the fields that changed, so each quote is merged with the previous quote of its symbol.
+// Fields that have not been received are zero.
+// SettlementPrice is the previous day's settlement (L1FutureClosePrice) while FutureSettlementPrice is the current one.
+type L1FutureQuote struct {
+	Symbol                string
+	Description           string
+	BidPrice              float64
+	AskPrice              float64
+	LastPrice             float64
+	Mark                  float64
+	HighPrice             float64
+	LowPrice              float64
+	OpenPrice             float64
+	NetChange             float64
+	BidSize               float64
+	AskSize               float64
+	LastSize              float64
+	TotalVolume           float64
+	TradingHours          string
+	IsTradable            bool
+	QuoteTime             time.Time
+	TradeTime             time.Time
+	SettlementPrice       float64
+	OpenInterest          float64
+	FutureIsActive        bool
+	FutureExpirationDate  time.Time
+	FutureMultiplier      float64
+	FutureSettlementPrice float64
+	FutureActiveSymbol    string
+}
+
+// apply sets the fields of q present in c.
+func (q *L1FutureQuote) apply(c map[string]json.RawMessage) error {
+	fields := map[L1FutureField]interface{}{
+		L1FutureDescription:     &q.Description,
+		L1FutureBidPrice:        &q.BidPrice,
+		L1FutureAskPrice:        &q.AskPrice,
+		L1FutureLastPrice:       &q.LastPrice,
+		L1FutureMark:            &q.Mark,
+		L1FutureHighPrice:       &q.HighPrice,
+		L1FutureLowPrice:        &q.LowPrice,
+		L1FutureOpenPrice:       &q.OpenPrice,
+		L1FutureNetChange:       &q.NetChange,
+		L1FutureBidSize:         &q.BidSize,
+		L1FutureAskSize:         &q.AskSize,
+		L1FutureLastSize:        &q.LastSize,
+		L1FutureTotalVolume:     &q.TotalVolume,
+		L1FutureTradingHours:    &q.TradingHours,
+		L1FutureIsTradable:      &q.IsTradable,
+		L1FutureClosePrice:      &q.SettlementPrice,
+		L1FutureOpenInterest:    &q.OpenInterest,
+		L1FutureIsActive:        &q.FutureIsActive,
+		L1FutureMultiplier:      &q.FutureMultiplier,
+		L1FutureSettlementPrice: &q.FutureSettlementPrice,
+		L1FutureActiveSymbol:    &q.FutureActiveSymbol,
+	}
+	times := map[L1FutureField]*time.Time{
+		L1FutureQuoteTime:      &q.QuoteTime,
+		L1FutureTradeTime:      &q.TradeTime,
+		L1FutureExpirationDate: &q.FutureExpirationDate,
+	}
+
+	for k, v := range c {
+		field, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		if dst, ok := times[L1FutureField(field)]; ok {
+			var ms int64
+			if err := json.Unmarshal(v, &ms); err != nil {
+				return fmt.Errorf("could not decode LEVELONE_FUTURES field %d of %s: %w", field, q.Symbol, err)
+			}
+			*dst = time.Unix(0, ms*int64(time.Millisecond))
+			continue
+		}
+		dst, ok := fields[L1FutureField(field)]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(v, dst); err != nil {
+			return fmt.Errorf("could not decode LEVELONE_FUTURES field %d of %s: %w", field, q.Symbol, err)
+		}
+	}
+	return nil
+}
+
+// SubscribeLevelOneFutures subscribes to fields of the level one quotes of futures, e.g. /ES:XCME,
+// replacing any previous futures subscription.
+// All fields are subscribed to when fields is empty.
+// Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeLevelOneFutures(symbols []string, fields []L1FutureField) (<-chan L1FutureQuote, error) {
+	h := s.handler("LEVELONE_FUTURES", func() *streamHandler {
+		ch := make(chan L1FutureQuote, streamBufferSize)
+		quoteBySymbol := map[string]*L1FutureQuote{}
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					var symbol string
+					if err := json.Unmarshal(c["key"], &symbol); err != nil {
+						s.reportError(fmt.Errorf("could not decode LEVELONE_FUTURES key: %w", err))
+						continue
+					}
+
+					quote, ok := quoteBySymbol[symbol]
+					if !ok {
+						quote = &L1FutureQuote{Symbol: symbol}
+						quoteBySymbol[symbol] = quote
+					}
+					if err := quote.apply(c); err != nil {
+						s.reportError(err)
+						continue
+					}
+
+					select {
+					case ch <- *quote:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+
+	if len(fields) == 0 {
+		for f := L1FutureSymbol; f <= L1FutureExpirationDate; f++ {
+			fields = append(fields, f)
+		}
+	}
+	numbers := make([]int, len(fields))
+	for i, f := range fields {
+		numbers[i] = int(f)
+	}
+
+	if err := s.Subscribe("LEVELONE_FUTURES", "SUBS", streamParams(symbols, numbers)); err != nil {
+		return nil, err
+	}
+	return h.ch.(chan L1FutureQuote), nil
+}
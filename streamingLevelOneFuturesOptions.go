@@ -0,0 +1,217 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// L1FutureOptField is a field of the LEVELONE_FUTURES_OPTIONS streaming service, which provides level one quotes
+// of futures options.
+// See https://developer.tdameritrade.com/content/streaming-data
+type L1FutureOptField int
+
+const (
+	L1FutureOptSymbol L1FutureOptField = iota
+	L1FutureOptBidPrice
+	L1FutureOptAskPrice
+	L1FutureOptLastPrice
+	L1FutureOptBidSize
+	L1FutureOptAskSize
+	L1FutureOptAskID
+	L1FutureOptBidID
+	L1FutureOptTotalVolume
+	L1FutureOptLastSize
+	L1FutureOptQuoteTime
+	L1FutureOptTradeTime
+	L1FutureOptHighPrice
+	L1FutureOptLowPrice
+	L1FutureOptClosePrice
+	L1FutureOptExchangeID
+	L1FutureOptDescription
+	L1FutureOptLastID
+	L1FutureOptOpenPrice
+	L1FutureOptNetChange
+	L1FutureOptPercentChange
+	L1FutureOptExchangeName
+	L1FutureOptSecurityStatus
+	L1FutureOptOpenInterest
+	L1FutureOptMark
+	L1FutureOptTick
+	L1FutureOptTickAmount
+	L1FutureOptProduct
+	L1FutureOptPriceFormat
+	L1FutureOptTradingHours
+	L1FutureOptIsTradable
+	L1FutureOptMultiplier
+	L1FutureOptIsActive
+	L1FutureOptSettlementPrice
+	L1FutureOptActiveSymbol
+	L1FutureOptExpirationDate
+)
+
+// L1FutureOptQuote is a level one quote of a futures option, e.g. ./EW4X21C4000.
+// TD Ameritrade only sends the fields that changed, so each quote is merged with the previous quote of its symbol.
+// Fields that have not been received are zero.
+// ClosePrice is the previous day's settlement while SettlementPrice is the current one.
+type L1FutureOptQuote struct {
+	Symbol          string
+	BidPrice        float64
+	AskPrice        float64
+	LastPrice       float64
+	BidSize         float64
+	AskSize         float64
+	AskID           string
+	BidID           string
+	TotalVolume     float64
+	LastSize        float64
+	QuoteTime       time.Time
+	TradeTime       time.Time
+	HighPrice       float64
+	LowPrice        float64
+	ClosePrice      float64
+	ExchangeID      string
+	Description     string
+	LastID          string
+	OpenPrice       float64
+	NetChange       float64
+	PercentChange   float64
+	ExchangeName    string
+	SecurityStatus  string
+	OpenInterest    float64
+	Mark            float64
+	Tick            float64
+	TickAmount      float64
+	Product         string
+	PriceFormat     string
+	TradingHours    string
+	IsTradable      bool
+	Multiplier      float64
+	IsActive        bool
+	SettlementPrice float64
+	ActiveSymbol    string
+	ExpirationDate  time.Time
+}
+
+// apply sets the fields of q present in c.
+func (q *L1FutureOptQuote) apply(c map[string]json.RawMessage) error {
+	fields := map[L1FutureOptField]interface{}{
+		L1FutureOptBidPrice:        &q.BidPrice,
+		L1FutureOptAskPrice:        &q.AskPrice,
+		L1FutureOptLastPrice:       &q.LastPrice,
+		L1FutureOptBidSize:         &q.BidSize,
+		L1FutureOptAskSize:         &q.AskSize,
+		L1FutureOptAskID:           &q.AskID,
+		L1FutureOptBidID:           &q.BidID,
+		L1FutureOptTotalVolume:     &q.TotalVolume,
+		L1FutureOptLastSize:        &q.LastSize,
+		L1FutureOptHighPrice:       &q.HighPrice,
+		L1FutureOptLowPrice:        &q.LowPrice,
+		L1FutureOptClosePrice:      &q.ClosePrice,
+		L1FutureOptExchangeID:      &q.ExchangeID,
+		L1FutureOptDescription:     &q.Description,
+		L1FutureOptLastID:          &q.LastID,
+		L1FutureOptOpenPrice:       &q.OpenPrice,
+		L1FutureOptNetChange:       &q.NetChange,
+		L1FutureOptPercentChange:   &q.PercentChange,
+		L1FutureOptExchangeName:    &q.ExchangeName,
+		L1FutureOptSecurityStatus:  &q.SecurityStatus,
+		L1FutureOptOpenInterest:    &q.OpenInterest,
+		L1FutureOptMark:            &q.Mark,
+		L1FutureOptTick:            &q.Tick,
+		L1FutureOptTickAmount:      &q.TickAmount,
+		L1FutureOptProduct:         &q.Product,
+		L1FutureOptPriceFormat:     &q.PriceFormat,
+		L1FutureOptTradingHours:    &q.TradingHours,
+		L1FutureOptIsTradable:      &q.IsTradable,
+		L1FutureOptMultiplier:      &q.Multiplier,
+		L1FutureOptIsActive:        &q.IsActive,
+		L1FutureOptSettlementPrice: &q.SettlementPrice,
+		L1FutureOptActiveSymbol:    &q.ActiveSymbol,
+	}
+	times := map[L1FutureOptField]*time.Time{
+		L1FutureOptQuoteTime:      &q.QuoteTime,
+		L1FutureOptTradeTime:      &q.TradeTime,
+		L1FutureOptExpirationDate: &q.ExpirationDate,
+	}
+
+	for k, v := range c {
+		field, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		if dst, ok := times[L1FutureOptField(field)]; ok {
+			var ms int64
+			if err := json.Unmarshal(v, &ms); err != nil {
+				return fmt.Errorf("could not decode LEVELONE_FUTURES_OPTIONS field %d of %s: %w", field, q.Symbol, err)
+			}
+			*dst = time.Unix(0, ms*int64(time.Millisecond))
+			continue
+		}
+		dst, ok := fields[L1FutureOptField(field)]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(v, dst); err != nil {
+			return fmt.Errorf("could not decode LEVELONE_FUTURES_OPTIONS field %d of %s: %w", field, q.Symbol, err)
+		}
+	}
+	return nil
+}
+
+// SubscribeLevelOneFuturesOptions subscribes to fields of the level one quotes of futures options,
+// e.g. ./EW4X21C4000, replacing any previous futures option subscription.
+// Use FormatFuturesOptionSymbol to build their symbols.
+// All fields are subscribed to when fields is empty.
+// Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeLevelOneFuturesOptions(symbols []string, fields []L1FutureOptField) (<-chan L1FutureOptQuote, error) {
+	h := s.handler("LEVELONE_FUTURES_OPTIONS", func() *streamHandler {
+		ch := make(chan L1FutureOptQuote, streamBufferSize)
+		quoteBySymbol := map[string]*L1FutureOptQuote{}
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					var symbol string
+					if err := json.Unmarshal(c["key"], &symbol); err != nil {
+						s.reportError(fmt.Errorf("could not decode LEVELONE_FUTURES_OPTIONS key: %w", err))
+						continue
+					}
+
+					quote, ok := quoteBySymbol[symbol]
+					if !ok {
+						quote = &L1FutureOptQuote{Symbol: symbol}
+						quoteBySymbol[symbol] = quote
+					}
+					if err := quote.apply(c); err != nil {
+						s.reportError(err)
+						continue
+					}
+
+					select {
+					case ch <- *quote:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+
+	if len(fields) == 0 {
+		for f := L1FutureOptSymbol; f <= L1FutureOptExpirationDate; f++ {
+			fields = append(fields, f)
+		}
+	}
+	numbers := make([]int, len(fields))
+	for i, f := range fields {
+		numbers[i] = int(f)
+	}
+
+	if err := s.Subscribe("LEVELONE_FUTURES_OPTIONS", "SUBS", streamParams(symbols, numbers)); err != nil {
+		return nil, err
+	}
+	return h.ch.(chan L1FutureOptQuote), nil
+}
@@ -0,0 +1,76 @@
+package tdameritrade
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamingClientSubscribeLevelOneFuturesOptions(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	quotes, err := client.SubscribeLevelOneFuturesOptions([]string{"./EW4X21C4000"}, []L1FutureOptField{L1FutureOptSymbol, L1FutureOptBidPrice, L1FutureOptMark})
+	if err != nil {
+		t.Fatalf("SubscribeLevelOneFuturesOptions returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "LEVELONE_FUTURES_OPTIONS" || req.Parameters["keys"] != "./EW4X21C4000" || req.Parameters["fields"] != "0,1,24" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"LEVELONE_FUTURES_OPTIONS","timestamp":1636000000000,"command":"SUBS","content":[{"key":"./EW4X21C4000",
+		"1":45.25,"2":46,"3":45.5,"4":10,"5":12,"6":"?","7":"?","8":1520,"9":1,"10":1636000000000,"11":1635999999000,"12":52,"13":40.5,
+		"14":44,"15":"@","16":"E-mini S&P 500 Nov 2021 4000 Call","18":41,"19":1.5,"20":0.034,"21":"XCME","22":"Normal","23":8200,
+		"24":45.63,"25":0.25,"26":12.5,"27":"/ES","28":"D,D","29":"GLBX(de=1640;0=-1700;1=-1700d-1515;7=d-1530)","30":true,"31":50,
+		"32":true,"33":45.1,"34":"./EW4X21C4000","35":1637355600000}]}]}`)
+	sendStreamData(t, conn, `{"data":[{"service":"LEVELONE_FUTURES_OPTIONS","timestamp":1636000001000,"command":"SUBS","content":[{"key":"./EW4X21C4000","24":45.75}]}]}`)
+
+	first := <-quotes
+	want := L1FutureOptQuote{
+		Symbol:          "./EW4X21C4000",
+		BidPrice:        45.25,
+		AskPrice:        46,
+		LastPrice:       45.5,
+		BidSize:         10,
+		AskSize:         12,
+		AskID:           "?",
+		BidID:           "?",
+		TotalVolume:     1520,
+		LastSize:        1,
+		QuoteTime:       time.Unix(1636000000, 0),
+		TradeTime:       time.Unix(1635999999, 0),
+		HighPrice:       52,
+		LowPrice:        40.5,
+		ClosePrice:      44,
+		ExchangeID:      "@",
+		Description:     "E-mini S&P 500 Nov 2021 4000 Call",
+		OpenPrice:       41,
+		NetChange:       1.5,
+		PercentChange:   0.034,
+		ExchangeName:    "XCME",
+		SecurityStatus:  "Normal",
+		OpenInterest:    8200,
+		Mark:            45.63,
+		Tick:            0.25,
+		TickAmount:      12.5,
+		Product:         "/ES",
+		PriceFormat:     "D,D",
+		TradingHours:    "GLBX(de=1640;0=-1700;1=-1700d-1515;7=d-1530)",
+		IsTradable:      true,
+		Multiplier:      50,
+		IsActive:        true,
+		SettlementPrice: 45.1,
+		ActiveSymbol:    "./EW4X21C4000",
+		ExpirationDate:  time.Unix(1637355600, 0),
+	}
+	if first != want {
+		t.Errorf("quote = %+v, want %+v", first, want)
+	}
+
+	second := <-quotes
+	want.Mark = 45.75
+	if second != want {
+		t.Errorf("merged quote = %+v, want %+v", second, want)
+	}
+}
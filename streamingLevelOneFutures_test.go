@@ -0,0 +1,56 @@
+package tdameritrade
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamingClientSubscribeLevelOneFutures(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	quotes, err := client.SubscribeLevelOneFutures([]string{"/ES:XCME"}, []L1FutureField{L1FutureSymbol, L1FutureBidPrice, L1FutureAskPrice})
+	if err != nil {
+		t.Fatalf("SubscribeLevelOneFutures returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "LEVELONE_FUTURES" || req.Parameters["keys"] != "/ES:XCME" || req.Parameters["fields"] != "0,1,2" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"LEVELONE_FUTURES","timestamp":1591000000000,"command":"SUBS","content":[{"key":"/ES:XCME",
+		"1":3050.25,"2":3050.5,"3":3050.25,"4":12,"5":8,"9":2,"10":1591000000000,"14":3040,"23":2500000,
+		"29":"GLBX(de=1640;0=-17001600;1=r-17001600d-15551640;7=d-16401645)","30":true,"31":50,"32":true,"33":3049.75,"34":"/ESM20","35":1592539200000}]}]}`)
+	sendStreamData(t, conn, `{"data":[{"service":"LEVELONE_FUTURES","timestamp":1591000001000,"command":"SUBS","content":[{"key":"/ES:XCME","1":3050.5}]}]}`)
+
+	first := <-quotes
+	want := L1FutureQuote{
+		Symbol:                "/ES:XCME",
+		BidPrice:              3050.25,
+		AskPrice:              3050.5,
+		LastPrice:             3050.25,
+		BidSize:               12,
+		AskSize:               8,
+		LastSize:              2,
+		QuoteTime:             time.Unix(1591000000, 0),
+		SettlementPrice:       3040,
+		OpenInterest:          2500000,
+		TradingHours:          "GLBX(de=1640;0=-17001600;1=r-17001600d-15551640;7=d-16401645)",
+		IsTradable:            true,
+		FutureMultiplier:      50,
+		FutureIsActive:        true,
+		FutureSettlementPrice: 3049.75,
+		FutureActiveSymbol:    "/ESM20",
+		FutureExpirationDate:  time.Unix(1592539200, 0),
+	}
+	if first != want {
+		t.Errorf("quote = %+v, want %+v", first, want)
+	}
+
+	second := <-quotes
+	want.BidPrice = 3050.5
+	if second != want {
+		t.Errorf("merged quote = %+v, want %+v", second, want)
+	}
+}
@@ -0,0 +1,94 @@
+package tdameritrade
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStreamingClientSubscribeLevelOneEquity(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	quotes, err := client.SubscribeLevelOneEquity([]string{"SPY"}, []L1EquityField{L1EquityBidPrice, L1EquityAskPrice, L1EquityLastPrice, L1EquityTotalVolume})
+	if err != nil {
+		t.Fatalf("SubscribeLevelOneEquity returned error: %v", err)
+	}
+	deltas, err := client.SubscribeQuotes([]string{"SPY"}, []L1EquityField{L1EquityBidPrice, L1EquityAskPrice, L1EquityLastPrice, L1EquityTotalVolume})
+	if err != nil {
+		t.Fatalf("SubscribeQuotes returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "QUOTE" || req.Parameters["fields"] != "1,2,3,8" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	ts.request(t)
+
+	sendStreamData(t, conn, `{"data":[{"service":"QUOTE","timestamp":1591000000000,"command":"SUBS","content":[{"key":"SPY","1":300.1,"2":300.2,"3":300.15,"8":1000}]}]}`)
+	sendStreamData(t, conn, `{"data":[{"service":"QUOTE","timestamp":1591000001000,"command":"SUBS","content":[{"key":"SPY","2":300.3,"8":1200}]}]}`)
+
+	first, second := <-quotes, <-quotes
+	if first.BidPrice() != 300.1 || first.AskPrice() != 300.2 || first.TotalVolume() != 1000 {
+		t.Errorf("unexpected first quote: %+v", first)
+	}
+	if second.BidPrice() != 300.1 || second.AskPrice() != 300.3 || second.LastPrice() != 300.15 || second.TotalVolume() != 1200 {
+		t.Errorf("expected the second quote to be merged with the first, got %+v", second)
+	}
+	if first.AskPrice() != 300.2 {
+		t.Errorf("merging changed an earlier quote: %+v", first)
+	}
+
+	<-deltas
+	delta := <-deltas
+	if len(delta.Fields) != 2 || delta.BidPrice() != 0 || delta.AskPrice() != 300.3 {
+		t.Errorf("expected a partial update, got %+v", delta)
+	}
+}
+
+func TestStreamingClientSubscribeLevelOneOption(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	quotes, err := client.SubscribeLevelOneOption([]string{"SPY_121621C450"}, nil)
+	if err != nil {
+		t.Fatalf("SubscribeLevelOneOption returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "OPTION" || req.Parameters["keys"] != "SPY_121621C450" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"OPTION","timestamp":1591000000000,"command":"SUBS","content":[{"key":"SPY_121621C450",
+		"2":5.1,"3":5.3,"9":1200,"10":21.5,"13":2.5,"24":450,"25":"C","26":"SPY","29":2.7,"31":180,"32":0.45,"33":0.01,"34":-0.05,"35":0.8,"36":0.3,"39":452.5}]}]}`)
+	sendStreamData(t, conn, `{"data":[{"service":"OPTION","timestamp":1591000001000,"command":"SUBS","content":[{"key":"SPY_121621C450","2":5.2,"32":0.46}]}]}`)
+
+	first := <-quotes
+	want := L1OptionQuote{
+		Symbol: "SPY_121621C450", Underlying: "SPY", ContractType: "C", StrikePrice: 450, DaysToExpiration: 180,
+		BidPrice: 5.1, AskPrice: 5.3, OpenInterest: 1200, ImpliedVolatility: 21.5, IntrinsicValue: 2.5, TimeValue: 2.7,
+		UnderlyingPrice: 452.5, Delta: 0.45, Gamma: 0.01, Theta: -0.05, Vega: 0.8, Rho: 0.3,
+	}
+	if first != want {
+		t.Errorf("quote = %+v, want %+v", first, want)
+	}
+
+	second := <-quotes
+	want.BidPrice, want.Delta = 5.2, 0.46
+	if second != want {
+		t.Errorf("merged quote = %+v, want %+v", second, want)
+	}
+}
+
+func TestQuoteFieldAliases(t *testing.T) {
+	// The names SubscribeQuotes was first released with still compile and mean the same fields.
+	fields := []QuoteField{QuoteFieldSymbol, QuoteFieldBidPrice, QuoteFieldMark, QuoteFieldRegularMarketTradeTimeInLong}
+	want := []L1EquityField{L1EquitySymbol, L1EquityBidPrice, L1EquityMark, L1EquityRegularMarketTradeTimeInLong}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("QuoteField constants = %v, want %v", fields, want)
+	}
+	var update QuoteUpdate = L1EquityQuote{Symbol: "SPY"}
+	if update.Symbol != "SPY" {
+		t.Errorf("unexpected update %+v", update)
+	}
+}
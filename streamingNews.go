@@ -0,0 +1,129 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NewsHeadline is a news headline about a symbol.
+type NewsHeadline struct {
+	Symbol          string
+	ErrorCode       int
+	StoryDatetime   time.Time
+	HeadlineID      string
+	Status          string
+	Headline        string
+	StoryID         string
+	CountForKeyword int
+	KeywordArray    []string
+	IsHot           bool
+	StorySource     string
+}
+
+// SubscribeNewsHeadline subscribes to news headlines about symbols, replacing any previous news subscription.
+// The user needs the streaming news authorization, see Authorizations.StreamingNews.
+// Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeNewsHeadline(symbols []string) (<-chan NewsHeadline, error) {
+	h := s.handler("NEWS_HEADLINE", func() *streamHandler {
+		ch := make(chan NewsHeadline, streamBufferSize)
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					headline, err := decodeNewsHeadline(c)
+					if err != nil {
+						s.reportError(err)
+						continue
+					}
+					select {
+					case ch <- headline:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+
+	if err := s.Subscribe("NEWS_HEADLINE", "SUBS", streamParams(symbols, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10})); err != nil {
+		return nil, err
+	}
+	return h.ch.(chan NewsHeadline), nil
+}
+
+// FilterByKeyword returns a channel that only receives the headlines whose KeywordArray contains kw, ignoring case.
+// The returned channel is closed once headlines is closed.
+func FilterByKeyword(headlines <-chan NewsHeadline, kw string) <-chan NewsHeadline {
+	filtered := make(chan NewsHeadline)
+	go func() {
+		defer close(filtered)
+		for headline := range headlines {
+			for _, keyword := range headline.KeywordArray {
+				if strings.EqualFold(keyword, kw) {
+					filtered <- headline
+					break
+				}
+			}
+		}
+	}()
+	return filtered
+}
+
+// keywords decodes a keyword array, which TD Ameritrade sends as a comma separated string.
+type keywords []string
+
+func (k *keywords) UnmarshalJSON(b []byte) error {
+	var list []string
+	if err := json.Unmarshal(b, &list); err == nil {
+		*k = list
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*k = nil
+	for _, keyword := range strings.Split(s, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			*k = append(*k, keyword)
+		}
+	}
+	return nil
+}
+
+func decodeNewsHeadline(c map[string]json.RawMessage) (NewsHeadline, error) {
+	var headline NewsHeadline
+	var storyDatetime int64
+	var keywordArray keywords
+	fields := []struct {
+		key string
+		v   interface{}
+	}{
+		{"key", &headline.Symbol},
+		{"1", &headline.ErrorCode},
+		{"2", &storyDatetime},
+		{"3", &headline.HeadlineID},
+		{"4", &headline.Status},
+		{"5", &headline.Headline},
+		{"6", &headline.StoryID},
+		{"7", &headline.CountForKeyword},
+		{"8", &keywordArray},
+		{"9", &headline.IsHot},
+		{"10", &headline.StorySource},
+	}
+	for _, f := range fields {
+		if raw, ok := c[f.key]; ok {
+			if err := json.Unmarshal(raw, f.v); err != nil {
+				return headline, fmt.Errorf("could not decode NEWS_HEADLINE field %s: %w", f.key, err)
+			}
+		}
+	}
+
+	headline.StoryDatetime = time.Unix(0, storyDatetime*int64(time.Millisecond))
+	headline.KeywordArray = keywordArray
+	return headline, nil
+}
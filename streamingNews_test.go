@@ -0,0 +1,51 @@
+package tdameritrade
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamingClientSubscribeNewsHeadline(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	headlines, err := client.SubscribeNewsHeadline([]string{"AAPL", "MSFT"})
+	if err != nil {
+		t.Fatalf("SubscribeNewsHeadline returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "NEWS_HEADLINE" || req.Parameters["keys"] != "AAPL,MSFT" || req.Parameters["fields"] != "0,1,2,3,4,5,6,7,8,9,10" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"NEWS_HEADLINE","timestamp":1591000000000,"command":"SUBS","content":[
+		{"key":"AAPL","1":0,"2":1591000000000,"3":"H1","4":"U","5":"Apple beats earnings","6":"S1","7":2,"8":"EARNINGS,TECH","9":true,"10":"Dow Jones News"},
+		{"key":"MSFT","1":0,"2":1591000060000,"3":"H2","4":"U","5":"Microsoft announces event","6":"S2","7":1,"8":"EVENTS","9":false,"10":"Dow Jones News"}
+	]}]}`)
+
+	want := NewsHeadline{
+		Symbol:          "AAPL",
+		StoryDatetime:   time.Unix(1591000000, 0),
+		HeadlineID:      "H1",
+		Status:          "U",
+		Headline:        "Apple beats earnings",
+		StoryID:         "S1",
+		CountForKeyword: 2,
+		KeywordArray:    []string{"EARNINGS", "TECH"},
+		IsHot:           true,
+		StorySource:     "Dow Jones News",
+	}
+
+	earnings := FilterByKeyword(headlines, "earnings")
+	got := <-earnings
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("headline = %+v, want %+v", got, want)
+	}
+
+	client.Close()
+	if headline, ok := <-earnings; ok {
+		t.Errorf("expected headline without the keyword to be filtered, got %+v", headline)
+	}
+}
@@ -0,0 +1,134 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OptionBookSnapshot is the level two order book of an option contract.
+// Bids are in descending order of price and Asks in ascending order, so the best of each comes first.
+type OptionBookSnapshot struct {
+	Symbol       string
+	SnapshotTime time.Time
+	Bids         []OptionBookLevel
+	Asks         []OptionBookLevel
+}
+
+// OptionBookLevel is the total size, in contracts, of the orders at a price in the book of an option.
+type OptionBookLevel struct {
+	Price     float64
+	TotalSize int
+	NumOrders int
+}
+
+// MidPrice returns the price halfway between the best bid and ask, or 0 if either side of the book is empty.
+func (s *OptionBookSnapshot) MidPrice() float64 {
+	if len(s.Bids) == 0 || len(s.Asks) == 0 {
+		return 0
+	}
+	return (s.Bids[0].Price + s.Asks[0].Price) / 2
+}
+
+// BidAskSpread returns the difference between the best ask and bid, or 0 if either side of the book is empty.
+func (s *OptionBookSnapshot) BidAskSpread() float64 {
+	if len(s.Bids) == 0 || len(s.Asks) == 0 {
+		return 0
+	}
+	return s.Asks[0].Price - s.Bids[0].Price
+}
+
+// SubscribeOptionsBook subscribes to the order books of option contracts, e.g. SPY_071720C310, replacing any
+// previous option book subscription. Each update is the whole book. Calling it again returns the same channel.
+// Books change with every order, so ThrottleOptionBooks may be used to receive them at a slower pace.
+func (s *StreamingClient) SubscribeOptionsBook(symbols []string) (<-chan OptionBookSnapshot, error) {
+	ch := s.optionBookChannel()
+	if err := s.Subscribe("OPTIONS_BOOK", "SUBS", streamParams(symbols, []int{0, 1, 2, 3})); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// SubscribeOptionBook subscribes to the order book of an option contract, e.g. SPY_071720C310.
+// Books of further contracts are added to the same channel as SubscribeOptionsBook.
+func (s *StreamingClient) SubscribeOptionBook(symbol string) (<-chan OptionBookSnapshot, error) {
+	ch := s.optionBookChannel()
+	if err := s.Subscribe("OPTIONS_BOOK", "ADD", streamParams([]string{symbol}, []int{0, 1, 2, 3})); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *StreamingClient) optionBookChannel() chan OptionBookSnapshot {
+	h := s.handler("OPTIONS_BOOK", func() *streamHandler {
+		ch := make(chan OptionBookSnapshot, streamBufferSize)
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					snapshot, err := decodeOptionBook(c)
+					if err != nil {
+						s.reportError(err)
+						continue
+					}
+					select {
+					case ch <- snapshot:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+	return h.ch.(chan OptionBookSnapshot)
+}
+
+func decodeOptionBook(c map[string]json.RawMessage) (OptionBookSnapshot, error) {
+	symbol, snapshotTime, bids, asks, err := decodeBook("OPTIONS_BOOK", c)
+	return OptionBookSnapshot{Symbol: symbol, SnapshotTime: snapshotTime, Bids: optionBookLevels(bids), Asks: optionBookLevels(asks)}, err
+}
+
+// optionBookLevels converts levels, which decodeBook has sorted best first.
+func optionBookLevels(levels []BookLevel) []OptionBookLevel {
+	var optionLevels []OptionBookLevel
+	for _, l := range levels {
+		optionLevels = append(optionLevels, OptionBookLevel{Price: l.Price, TotalSize: l.Size, NumOrders: l.NumOrders})
+	}
+	return optionLevels
+}
+
+// ThrottleOptionBooks forwards the books of in at most once every d per symbol, dropping the books replaced by a
+// newer one of the same symbol in the meantime. Books are sent every d if the returned channel has room, and
+// kept until the next time otherwise, so a slow reader only ever receives recent books.
+// The returned channel is closed once in is closed and the books left have been sent.
+func ThrottleOptionBooks(in <-chan OptionBookSnapshot, d time.Duration) <-chan OptionBookSnapshot {
+	out := make(chan OptionBookSnapshot, streamBufferSize)
+	go func() {
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+
+		latest := map[string]OptionBookSnapshot{}
+		for {
+			select {
+			case snapshot, ok := <-in:
+				if !ok {
+					for _, snapshot := range latest {
+						out <- snapshot
+					}
+					close(out)
+					return
+				}
+				latest[snapshot.Symbol] = snapshot
+			case <-ticker.C:
+				for symbol, snapshot := range latest {
+					select {
+					case out <- snapshot:
+						delete(latest, symbol)
+					default:
+					}
+				}
+			}
+		}
+	}()
+	return out
+}
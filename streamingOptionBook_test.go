@@ -0,0 +1,94 @@
+package tdameritrade
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamingClientSubscribeOptionsBook(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	books, err := client.SubscribeOptionsBook([]string{"SPY_071720C310", "SPY_071720P310"})
+	if err != nil {
+		t.Fatalf("SubscribeOptionsBook returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "OPTIONS_BOOK" || req.Command != "SUBS" || req.Parameters["keys"] != "SPY_071720C310,SPY_071720P310" || req.Parameters["fields"] != "0,1,2,3" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"OPTIONS_BOOK","timestamp":1591000000000,"command":"SUBS","content":[{"key":"SPY_071720P310","1":1591000030000,
+		"2":[{"0":2.05,"1":12,"2":3,"3":[]},{"0":2,"1":40,"2":5,"3":[]}],
+		"3":[{"0":2.15,"1":8,"2":2,"3":[]}]}]}]}`)
+
+	book := <-books
+	if book.Symbol != "SPY_071720P310" || !book.SnapshotTime.Equal(time.Unix(1591000030, 0)) || len(book.Bids) != 2 || len(book.Asks) != 1 {
+		t.Fatalf("unexpected book: %+v", book)
+	}
+	if book.Bids[1] != (OptionBookLevel{Price: 2, TotalSize: 40, NumOrders: 5}) {
+		t.Errorf("unexpected second bid: %+v", book.Bids[1])
+	}
+	if math.Abs(book.MidPrice()-2.1) > 1e-9 || math.Abs(book.BidAskSpread()-0.1) > 1e-9 {
+		t.Errorf("mid = %v, spread = %v, want 2.1 and 0.1", book.MidPrice(), book.BidAskSpread())
+	}
+
+	// Levels streamed out of order are sorted best first.
+	sendStreamData(t, conn, `{"data":[{"service":"OPTIONS_BOOK","timestamp":1591000000000,"command":"SUBS","content":[{"key":"SPY_071720C310","1":1591000030000,
+		"2":[{"0":1.9,"1":5,"2":1,"3":[]},{"0":2.05,"1":12,"2":3,"3":[]},{"0":2,"1":40,"2":5,"3":[]}],
+		"3":[{"0":2.3,"1":1,"2":1,"3":[]},{"0":2.15,"1":8,"2":2,"3":[]},{"0":2.2,"1":4,"2":1,"3":[]}]}]}]}`)
+	book = <-books
+	var bids, asks []float64
+	for _, l := range book.Bids {
+		bids = append(bids, l.Price)
+	}
+	for _, l := range book.Asks {
+		asks = append(asks, l.Price)
+	}
+	if !reflect.DeepEqual(bids, []float64{2.05, 2, 1.9}) || !reflect.DeepEqual(asks, []float64{2.15, 2.2, 2.3}) {
+		t.Errorf("bids %v and asks %v, want bids descending and asks ascending", bids, asks)
+	}
+	if math.Abs(book.MidPrice()-2.1) > 1e-9 {
+		t.Errorf("mid of the sorted book = %v, want 2.1", book.MidPrice())
+	}
+
+	empty := OptionBookSnapshot{Bids: book.Bids}
+	if empty.MidPrice() != 0 || empty.BidAskSpread() != 0 {
+		t.Errorf("expected 0 for a book without asks, got mid %v and spread %v", empty.MidPrice(), empty.BidAskSpread())
+	}
+}
+
+func TestThrottleOptionBooks(t *testing.T) {
+	in := make(chan OptionBookSnapshot)
+	out := ThrottleOptionBooks(in, 20*time.Millisecond)
+
+	start := time.Now()
+	for i := 1; i <= 3; i++ {
+		in <- OptionBookSnapshot{Symbol: "SPY_071720C310", Bids: []OptionBookLevel{{Price: float64(i)}}}
+	}
+	in <- OptionBookSnapshot{Symbol: "SPY_071720P310"}
+
+	received := map[string]OptionBookSnapshot{}
+	for len(received) < 2 {
+		book := <-out
+		received[book.Symbol] = book
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("books forwarded after %v, before the first tick", elapsed)
+	}
+	if got := received["SPY_071720C310"]; len(got.Bids) != 1 || got.Bids[0].Price != 3 {
+		t.Errorf("expected only the latest call book, got %+v", got)
+	}
+
+	in <- OptionBookSnapshot{Symbol: "SPY_071720C310", Bids: []OptionBookLevel{{Price: 4}}}
+	close(in)
+	if book, ok := <-out; !ok || book.Bids[0].Price != 4 {
+		t.Errorf("expected the pending book to be flushed on close, got %+v", book)
+	}
+	if _, ok := <-out; ok {
+		t.Error("expected the channel to be closed")
+	}
+}
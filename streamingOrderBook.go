@@ -0,0 +1,72 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OrderBookSnapshot is the level two order book of an equity on an exchange.
+// Bids are in descending order of price and Asks in ascending order, so the best of each comes first.
+type OrderBookSnapshot struct {
+	Symbol       string
+	SnapshotTime time.Time
+	Bids         []BookLevel
+	Asks         []BookLevel
+}
+
+// MidPrice returns the price halfway between the best bid and ask, or 0 if either side of the book is empty.
+func (s *OrderBookSnapshot) MidPrice() float64 {
+	if len(s.Bids) == 0 || len(s.Asks) == 0 {
+		return 0
+	}
+	return (s.Bids[0].Price + s.Asks[0].Price) / 2
+}
+
+// Spread returns the difference between the best ask and bid, or 0 if either side of the book is empty.
+func (s *OrderBookSnapshot) Spread() float64 {
+	if len(s.Bids) == 0 || len(s.Asks) == 0 {
+		return 0
+	}
+	return s.Asks[0].Price - s.Bids[0].Price
+}
+
+// SubscribeNASDAQBook subscribes to the NASDAQ order books of symbols, replacing any previous NASDAQ book subscription.
+// Each update is the whole book. Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeNASDAQBook(symbols []string) (<-chan OrderBookSnapshot, error) {
+	return s.subscribeOrderBook("NASDAQ_BOOK", symbols)
+}
+
+// SubscribeNYSEBook subscribes to the NYSE order books of symbols, replacing any previous NYSE book subscription.
+// Each update is the whole book. Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeNYSEBook(symbols []string) (<-chan OrderBookSnapshot, error) {
+	return s.subscribeOrderBook("NYSE_BOOK", symbols)
+}
+
+func (s *StreamingClient) subscribeOrderBook(service string, symbols []string) (<-chan OrderBookSnapshot, error) {
+	h := s.handler(service, func() *streamHandler {
+		ch := make(chan OrderBookSnapshot, streamBufferSize)
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					symbol, snapshotTime, bids, asks, err := decodeBook(service, c)
+					if err != nil {
+						s.reportError(err)
+						continue
+					}
+					select {
+					case ch <- OrderBookSnapshot{Symbol: symbol, SnapshotTime: snapshotTime, Bids: bids, Asks: asks}:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+
+	if err := s.Subscribe(service, "SUBS", streamParams(symbols, []int{0, 1, 2, 3})); err != nil {
+		return nil, err
+	}
+	return h.ch.(chan OrderBookSnapshot), nil
+}
@@ -0,0 +1,57 @@
+package tdameritrade
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStreamingClientSubscribeOrderBooks(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	nasdaq, err := client.SubscribeNASDAQBook([]string{"AAPL"})
+	if err != nil {
+		t.Fatalf("SubscribeNASDAQBook returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "NASDAQ_BOOK" || req.Command != "SUBS" || req.Parameters["keys"] != "AAPL" || req.Parameters["fields"] != "0,1,2,3" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	nyse, err := client.SubscribeNYSEBook([]string{"IBM"})
+	if err != nil {
+		t.Fatalf("SubscribeNYSEBook returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "NYSE_BOOK" || req.Parameters["keys"] != "IBM" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"NASDAQ_BOOK","timestamp":1591000000000,"command":"SUBS","content":[{"key":"AAPL","1":1591000000000,
+		"2":[{"0":320,"1":500,"2":4,"3":[]},{"0":320.1,"1":300,"2":3,"3":[]}],
+		"3":[{"0":320.4,"1":100,"2":1,"3":[]},{"0":320.3,"1":200,"2":2,"3":[]}]}]}]}`)
+	sendStreamData(t, conn, `{"data":[{"service":"NYSE_BOOK","timestamp":1591000000000,"command":"SUBS","content":[{"key":"IBM","1":1591000001000,"2":[],"3":[]}]}]}`)
+
+	book := <-nasdaq
+	if book.Symbol != "AAPL" || !book.SnapshotTime.Equal(time.Unix(1591000000, 0)) || len(book.Bids) != 2 || len(book.Asks) != 2 {
+		t.Fatalf("unexpected book: %+v", book)
+	}
+	// Levels are sorted best first, whatever the order they were streamed in.
+	if book.Bids[0] != (BookLevel{Price: 320.1, Size: 300, NumOrders: 3}) || book.Bids[1].Price != 320 {
+		t.Errorf("unexpected bids: %+v", book.Bids)
+	}
+	if book.Asks[0] != (BookLevel{Price: 320.3, Size: 200, NumOrders: 2}) || book.Asks[1].Price != 320.4 {
+		t.Errorf("unexpected asks: %+v", book.Asks)
+	}
+	if got := book.MidPrice(); math.Abs(got-320.2) > 1e-9 {
+		t.Errorf("MidPrice = %v, want 320.2", got)
+	}
+	if got := book.Spread(); math.Abs(got-0.2) > 1e-9 {
+		t.Errorf("Spread = %v, want 0.2", got)
+	}
+
+	empty := <-nyse
+	if empty.Symbol != "IBM" || empty.MidPrice() != 0 || empty.Spread() != 0 {
+		t.Errorf("unexpected empty book: %+v", empty)
+	}
+}
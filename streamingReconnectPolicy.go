@@ -0,0 +1,38 @@
+package tdameritrade
+
+import "time"
+
+// ReconnectPolicy decides whether and when a StreamingClient reconnects after its connection drops.
+// attempt is the number of the reconnection about to be made, starting at 1, and err is why the connection
+// dropped, for the first attempt, or why the previous attempt failed.
+type ReconnectPolicy interface {
+	ShouldReconnect(attempt int, err error) bool
+	ReconnectDelay(attempt int) time.Duration
+}
+
+// DefaultReconnectPolicy returns a ReconnectPolicy that makes up to maxAttempts attempts to reconnect,
+// or any number of attempts if maxAttempts is 0, backing off exponentially from 1s up to 30s.
+// It is the policy of StreamingClients without one.
+func DefaultReconnectPolicy(maxAttempts int) ReconnectPolicy {
+	return &defaultReconnectPolicy{maxAttempts: maxAttempts}
+}
+
+type defaultReconnectPolicy struct {
+	maxAttempts int
+}
+
+func (p *defaultReconnectPolicy) ShouldReconnect(attempt int, err error) bool {
+	return p.maxAttempts == 0 || attempt <= p.maxAttempts
+}
+
+func (p *defaultReconnectPolicy) ReconnectDelay(attempt int) time.Duration {
+	// Past 2^5 * 1s the delay is capped anyway, and the shift would eventually overflow.
+	if attempt > 6 {
+		return streamMaxBackoff
+	}
+	delay := streamBaseBackoff << uint(attempt-1)
+	if delay > streamMaxBackoff {
+		delay = streamMaxBackoff
+	}
+	return delay
+}
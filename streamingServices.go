@@ -0,0 +1,137 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChartEquityBar is a one minute bar of an equity starting at ChartTime.
+// TD Ameritrade sends the bar of the current minute again each time it changes, so the latest bar
+// with a ChartTime replaces the earlier ones; see BarAggregator.
+type ChartEquityBar struct {
+	Symbol     string
+	OpenPrice  float64
+	HighPrice  float64
+	LowPrice   float64
+	ClosePrice float64
+	Volume     float64
+	Sequence   int64
+	ChartTime  time.Time
+	ChartDay   int
+}
+
+// BookLevel is the total size, in shares, of the NumOrders orders at a price in an order book, such as those of
+// SubscribeNASDAQBook.
+type BookLevel struct {
+	Price     float64
+	Size      int
+	NumOrders int
+}
+
+// SubscribeChartEquity subscribes to one minute bars of symbols, replacing any previous chart subscription,
+// including the equities of SubscribeChartHistory.
+// Calling it again returns the same channel.
+func (s *StreamingClient) SubscribeChartEquity(symbols []string) (<-chan ChartEquityBar, error) {
+	chart := s.chartStream("CHART_EQUITY")
+	if err := s.Subscribe("CHART_EQUITY", "SUBS", streamParams(symbols, []int{0, 1, 2, 3, 4, 5, 6, 7, 8})); err != nil {
+		return nil, err
+	}
+
+	chart.mu.Lock()
+	chart.sendUpdates = true
+	chart.mu.Unlock()
+	return chart.updates, nil
+}
+
+func streamParams(keys []string, fields []int) map[string]string {
+	f := make([]string, len(fields))
+	for i, field := range fields {
+		f[i] = strconv.Itoa(field)
+	}
+	return map[string]string{
+		"keys":   strings.Join(keys, ","),
+		"fields": strings.Join(f, ","),
+	}
+}
+
+func decodeChartEquity(c map[string]json.RawMessage) (ChartEquityBar, error) {
+	var update ChartEquityBar
+	var chartTime int64
+	fields := []struct {
+		key string
+		v   interface{}
+	}{
+		{"key", &update.Symbol},
+		{"1", &update.OpenPrice},
+		{"2", &update.HighPrice},
+		{"3", &update.LowPrice},
+		{"4", &update.ClosePrice},
+		{"5", &update.Volume},
+		{"6", &update.Sequence},
+		{"7", &chartTime},
+		{"8", &update.ChartDay},
+	}
+	for _, f := range fields {
+		if raw, ok := c[f.key]; ok {
+			if err := json.Unmarshal(raw, f.v); err != nil {
+				return update, fmt.Errorf("could not decode CHART_EQUITY field %s: %w", f.key, err)
+			}
+		}
+	}
+
+	update.ChartTime = time.Unix(0, chartTime*int64(time.Millisecond))
+	return update, nil
+}
+
+// bookLevel is a price level as TD Ameritrade sends it.
+// The participants at each level, field 3, are not decoded.
+type bookLevel struct {
+	Price       float64 `json:"0"`
+	TotalVolume float64 `json:"1"`
+	NumEntries  int     `json:"2"`
+}
+
+// decodeBook decodes an order book of service, which all books send in the same format.
+func decodeBook(service string, c map[string]json.RawMessage) (string, time.Time, []BookLevel, []BookLevel, error) {
+	var symbol string
+	var bookTime int64
+	var bids, asks []bookLevel
+	fields := []struct {
+		key string
+		v   interface{}
+	}{
+		{"key", &symbol},
+		{"1", &bookTime},
+		{"2", &bids},
+		{"3", &asks},
+	}
+	for _, f := range fields {
+		if raw, ok := c[f.key]; ok {
+			if err := json.Unmarshal(raw, f.v); err != nil {
+				return symbol, time.Time{}, nil, nil, fmt.Errorf("could not decode %s field %s: %w", service, f.key, err)
+			}
+		}
+	}
+
+	return symbol, time.Unix(0, bookTime*int64(time.Millisecond)), bookLevels(bids, true), bookLevels(asks, false), nil
+}
+
+// bookLevels converts levels, sorting them by price, in descending order for bids, so the best comes first
+// whatever the order they were streamed in.
+func bookLevels(levels []bookLevel, bids bool) []BookLevel {
+	var converted []BookLevel
+	for _, l := range levels {
+		converted = append(converted, BookLevel{Price: l.Price, Size: int(l.TotalVolume), NumOrders: l.NumEntries})
+	}
+	sort.SliceStable(converted, func(i, j int) bool {
+		if bids {
+			return converted[i].Price > converted[j].Price
+		}
+		return converted[i].Price < converted[j].Price
+	})
+	return converted
+}
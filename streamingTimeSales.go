@@ -0,0 +1,196 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TimeSalesService is a streaming service providing the trades of a kind of instrument.
+type TimeSalesService string
+
+const (
+	TimeSalesEquity  TimeSalesService = "TIMESALE_EQUITY"
+	TimeSalesForex   TimeSalesService = "TIMESALE_FOREX"
+	TimeSalesFutures TimeSalesService = "TIMESALE_FUTURES"
+	TimeSalesOptions TimeSalesService = "TIMESALE_OPTIONS"
+)
+
+// TimeSaleEvent is a trade of a symbol.
+// TD Ameritrade only sends the trade itself, so the Day fields are tracked from the trades received since subscribing,
+// and Conditions and MarketMaker are empty unless TD Ameritrade starts sending them.
+type TimeSaleEvent struct {
+	Symbol       string
+	TradeTime    time.Time
+	LastPrice    float64
+	LastSize     float64
+	LastSequence int
+	Conditions   string
+	MarketMaker  string
+	DayHigh      float64
+	DayLow       float64
+	DayOpen      float64
+	DayClose     float64
+}
+
+// SubscribeTimeSales subscribes to the trades of symbols on service, replacing any previous subscription to service.
+// Calling it again for the same service returns the same channel.
+func (s *StreamingClient) SubscribeTimeSales(symbols []string, service TimeSalesService) (<-chan TimeSaleEvent, error) {
+	switch service {
+	case TimeSalesEquity, TimeSalesForex, TimeSalesFutures, TimeSalesOptions:
+	default:
+		return nil, fmt.Errorf("%w: unknown time and sales service %q", ErrInvalidParams, service)
+	}
+
+	h := s.handler(string(service), func() *streamHandler {
+		ch := make(chan TimeSaleEvent, streamBufferSize)
+		days := map[string]*TimeSaleEvent{}
+		return &streamHandler{
+			ch:    ch,
+			close: func() { close(ch) },
+			handle: func(content []map[string]json.RawMessage) {
+				for _, c := range content {
+					event, err := decodeTimeSaleEvent(service, c)
+					if err != nil {
+						s.reportError(err)
+						continue
+					}
+					trackDay(days, &event)
+					select {
+					case ch <- event:
+					case <-s.done:
+						return
+					}
+				}
+			},
+		}
+	})
+
+	if err := s.Subscribe(string(service), "SUBS", streamParams(symbols, []int{0, 1, 2, 3, 4})); err != nil {
+		return nil, err
+	}
+	return h.ch.(chan TimeSaleEvent), nil
+}
+
+// trackDay sets the Day fields of event from the previous trades of its symbol on the same day.
+func trackDay(days map[string]*TimeSaleEvent, event *TimeSaleEvent) {
+	day, ok := days[event.Symbol]
+	if !ok || !sameDay(day.TradeTime, event.TradeTime) {
+		day = &TimeSaleEvent{DayOpen: event.LastPrice, DayHigh: event.LastPrice, DayLow: event.LastPrice}
+		days[event.Symbol] = day
+	}
+	if event.LastPrice > day.DayHigh {
+		day.DayHigh = event.LastPrice
+	}
+	if event.LastPrice < day.DayLow {
+		day.DayLow = event.LastPrice
+	}
+	day.DayClose = event.LastPrice
+	day.TradeTime = event.TradeTime
+
+	event.DayOpen, event.DayHigh, event.DayLow, event.DayClose = day.DayOpen, day.DayHigh, day.DayLow, day.DayClose
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func decodeTimeSaleEvent(service TimeSalesService, c map[string]json.RawMessage) (TimeSaleEvent, error) {
+	var event TimeSaleEvent
+	var tradeTime int64
+	fields := []struct {
+		key string
+		v   interface{}
+	}{
+		{"key", &event.Symbol},
+		{"1", &tradeTime},
+		{"2", &event.LastPrice},
+		{"3", &event.LastSize},
+		{"4", &event.LastSequence},
+	}
+	for _, f := range fields {
+		if raw, ok := c[f.key]; ok {
+			if err := json.Unmarshal(raw, f.v); err != nil {
+				return event, fmt.Errorf("could not decode %s field %s: %w", service, f.key, err)
+			}
+		}
+	}
+
+	event.TradeTime = time.Unix(0, tradeTime*int64(time.Millisecond))
+	return event, nil
+}
+
+// OHLCV is a bar summarizing the trades of a symbol from Start until the start of the next bar.
+type OHLCV struct {
+	Symbol string
+	Start  time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// TimeSaleAggregator builds bars of a fixed length from trades, such as those from SubscribeTimeSales.
+// Bars start at multiples of the interval and are sent on Bars once a trade of the same symbol in a later bar is added,
+// so a bar is complete when it is received. Close sends the bars still being built.
+//
+// Usage example:
+// aggregator := tdameritrade.NewTimeSaleAggregator(5 * time.Second)
+// go func() { for trade := range trades { aggregator.Add(trade) }; aggregator.Close() }()
+// for bar := range aggregator.Bars() { ... }
+type TimeSaleAggregator struct {
+	interval time.Duration
+	bars     chan OHLCV
+	building map[string]*OHLCV
+}
+
+// NewTimeSaleAggregator returns a TimeSaleAggregator building bars of interval.
+func NewTimeSaleAggregator(interval time.Duration) *TimeSaleAggregator {
+	return &TimeSaleAggregator{
+		interval: interval,
+		bars:     make(chan OHLCV, streamBufferSize),
+		building: map[string]*OHLCV{},
+	}
+}
+
+// Bars returns the channel completed bars are sent on. It is closed by Close.
+func (a *TimeSaleAggregator) Bars() <-chan OHLCV {
+	return a.bars
+}
+
+// Add adds a trade to the bar of its symbol, sending the previous bar if the trade starts a new one.
+// Add blocks if Bars is not being read. It must not be called concurrently or after Close.
+func (a *TimeSaleAggregator) Add(event TimeSaleEvent) {
+	start := event.TradeTime.Truncate(a.interval)
+
+	bar, ok := a.building[event.Symbol]
+	if ok && start.After(bar.Start) {
+		a.bars <- *bar
+		ok = false
+	}
+	if !ok {
+		bar = &OHLCV{Symbol: event.Symbol, Start: start, Open: event.LastPrice, High: event.LastPrice, Low: event.LastPrice}
+		a.building[event.Symbol] = bar
+	}
+
+	if event.LastPrice > bar.High {
+		bar.High = event.LastPrice
+	}
+	if event.LastPrice < bar.Low {
+		bar.Low = event.LastPrice
+	}
+	bar.Close = event.LastPrice
+	bar.Volume += event.LastSize
+}
+
+// Close sends the bars still being built and closes Bars.
+func (a *TimeSaleAggregator) Close() {
+	for symbol, bar := range a.building {
+		a.bars <- *bar
+		delete(a.building, symbol)
+	}
+	close(a.bars)
+}
@@ -0,0 +1,82 @@
+package tdameritrade
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamingClientSubscribeTimeSales(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	if _, err := client.SubscribeTimeSales([]string{"SPY"}, TimeSalesService("TIMESALE_BONDS")); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for an unknown service, got %v", err)
+	}
+
+	trades, err := client.SubscribeTimeSales([]string{"SPY"}, TimeSalesEquity)
+	if err != nil {
+		t.Fatalf("SubscribeTimeSales returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "TIMESALE_EQUITY" || req.Command != "SUBS" || req.Parameters["keys"] != "SPY" || req.Parameters["fields"] != "0,1,2,3,4" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"TIMESALE_EQUITY","timestamp":1591000000000,"command":"SUBS","content":[
+		{"key":"SPY","1":1591000000000,"2":305.1,"3":100,"4":1},
+		{"key":"SPY","1":1591000001000,"2":306.2,"3":50,"4":2},
+		{"key":"SPY","1":1591000002000,"2":304.3,"3":25,"4":3}
+	]}]}`)
+
+	<-trades
+	<-trades
+	got := <-trades
+	want := TimeSaleEvent{
+		Symbol:       "SPY",
+		TradeTime:    time.Unix(1591000002, 0),
+		LastPrice:    304.3,
+		LastSize:     25,
+		LastSequence: 3,
+		DayHigh:      306.2,
+		DayLow:       304.3,
+		DayOpen:      305.1,
+		DayClose:     304.3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("trade = %+v, want %+v", got, want)
+	}
+}
+
+func TestTimeSaleAggregator(t *testing.T) {
+	start := time.Date(2020, 6, 1, 14, 30, 0, 0, time.UTC)
+	trade := func(symbol string, offset time.Duration, price, size float64) TimeSaleEvent {
+		return TimeSaleEvent{Symbol: symbol, TradeTime: start.Add(offset), LastPrice: price, LastSize: size}
+	}
+
+	aggregator := NewTimeSaleAggregator(5 * time.Second)
+	aggregator.Add(trade("SPY", 0, 305, 100))
+	aggregator.Add(trade("SPY", time.Second, 307, 10))
+	aggregator.Add(trade("QQQ", 2*time.Second, 250, 5))
+	aggregator.Add(trade("SPY", 4*time.Second, 304, 20))
+	aggregator.Add(trade("SPY", 6*time.Second, 306, 30))
+
+	want := OHLCV{Symbol: "SPY", Start: start, Open: 305, High: 307, Low: 304, Close: 304, Volume: 130}
+	if got := <-aggregator.Bars(); got != want {
+		t.Errorf("bar = %+v, want %+v", got, want)
+	}
+
+	aggregator.Close()
+	remaining := map[string]OHLCV{}
+	for bar := range aggregator.Bars() {
+		remaining[bar.Symbol] = bar
+	}
+	if got := remaining["SPY"]; got.Start != start.Add(5*time.Second) || got.Open != 306 || got.Volume != 30 {
+		t.Errorf("unexpected SPY bar after close: %+v", got)
+	}
+	if got := remaining["QQQ"]; got.Start != start || got.Close != 250 || got.Volume != 5 {
+		t.Errorf("unexpected QQQ bar after close: %+v", got)
+	}
+}
@@ -0,0 +1,401 @@
+package tdameritrade
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// testStreamer is a TD Ameritrade streamer that accepts any login.
+// Each logged in connection is sent on conns and each request after the login on requests.
+// closes receives a value each time the client closes a logged in connection.
+type testStreamer struct {
+	server   *httptest.Server
+	conns    chan *websocket.Conn
+	requests chan streamRequest
+	closes   chan struct{}
+}
+
+func newTestStreamer(t *testing.T) *testStreamer {
+	ts := &testStreamer{
+		conns:    make(chan *websocket.Conn, 4),
+		requests: make(chan streamRequest, 16),
+		closes:   make(chan struct{}, 16),
+	}
+
+	upgrader := websocket.Upgrader{}
+	ts.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws" {
+			t.Errorf("unexpected streamer path %s", r.URL.Path)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("could not upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var login StreamAuthCommand
+		if err := conn.ReadJSON(&login); err != nil {
+			return
+		}
+		if login.Requests[0].Service != "ADMIN" || login.Requests[0].Command != "LOGIN" || login.Requests[0].Account != "123" {
+			t.Errorf("unexpected login request: %+v", login)
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"notify":[{"heartbeat":"1591000000000"}]}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"response":[{"service":"ADMIN","requestid":"0","command":"LOGIN","timestamp":1591000000000,"content":{"code":0,"msg":"29-3"}}]}`))
+		ts.conns <- conn
+
+		for {
+			var cmd streamCommand
+			if err := conn.ReadJSON(&cmd); err != nil {
+				select {
+				case ts.closes <- struct{}{}:
+				default:
+				}
+				return
+			}
+			for _, req := range cmd.Requests {
+				ts.requests <- req
+			}
+		}
+	}))
+
+	return ts
+}
+
+// connect returns a StreamingClient connected to the streamer and the connection the streamer accepted.
+// configure functions are applied to the client before it connects.
+func (ts *testStreamer) connect(t *testing.T, configure ...func(*StreamingClient)) (*StreamingClient, *websocket.Conn) {
+	t.Helper()
+	principals := &UserPrincipals{
+		PrimaryAccountID: "123",
+		StreamerInfo: StreamerInfo{
+			StreamerSocketURL: strings.TrimPrefix(ts.server.URL, "https://"),
+			Token:             "token",
+			TokenTimestamp:    "2020-06-01T12:00:00+0000",
+			AppID:             "APP",
+		},
+		StreamerSubscriptionKeys: StreamerSubscriptionKeys{Keys: []KeyEntry{{Key: "subkey"}}},
+		Accounts:                 []UserAccount{{AccountID: "123", Company: "AMER", Segment: "AMER"}},
+	}
+
+	client := &StreamingClient{
+		dialer: &websocket.Dialer{TLSClientConfig: ts.server.Client().Transport.(*http.Transport).TLSClientConfig},
+	}
+	for _, f := range configure {
+		f(client)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Connect(ctx, principals); err != nil {
+		t.Fatalf("Connect returned error: %v", err)
+	}
+
+	return client, ts.accept(t)
+}
+
+func (ts *testStreamer) accept(t *testing.T) *websocket.Conn {
+	t.Helper()
+	select {
+	case conn := <-ts.conns:
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a connection")
+		return nil
+	}
+}
+
+func (ts *testStreamer) request(t *testing.T) streamRequest {
+	t.Helper()
+	select {
+	case req := <-ts.requests:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a request")
+		return streamRequest{}
+	}
+}
+
+func (ts *testStreamer) close() {
+	ts.server.Close()
+}
+
+func sendStreamData(t *testing.T, conn *websocket.Conn, payload string) {
+	t.Helper()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(payload)); err != nil {
+		t.Fatalf("could not send data: %v", err)
+	}
+}
+
+func TestStreamingClientSubscribeQuotes(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	quotes, err := client.SubscribeQuotes([]string{"SPY", "QQQ"}, []L1EquityField{L1EquityBidPrice, L1EquityAskPrice, L1EquityDescription})
+	if err != nil {
+		t.Fatalf("SubscribeQuotes returned error: %v", err)
+	}
+
+	req := ts.request(t)
+	if req.Service != "QUOTE" || req.Command != "SUBS" || req.Account != "123" || req.Source != "APP" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if req.Parameters["keys"] != "SPY,QQQ" || req.Parameters["fields"] != "1,2,25" {
+		t.Errorf("unexpected parameters: %v", req.Parameters)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"QUOTE","timestamp":1591000000000,"command":"SUBS","content":[{"key":"SPY","delayed":false,"1":300.1,"2":300.2,"25":"SPDR S&P 500"}]}]}`)
+
+	update := <-quotes
+	if update.Symbol != "SPY" || update.Fields[L1EquityBidPrice] != 300.1 || update.Fields[L1EquityAskPrice] != 300.2 || update.Fields[L1EquityDescription] != "SPDR S&P 500" {
+		t.Errorf("unexpected update: %+v", update)
+	}
+	if len(update.Fields) != 3 {
+		t.Errorf("expected only the fields in the message, got %v", update.Fields)
+	}
+
+	if err := client.Unsubscribe("QUOTE", []string{"QQQ"}); err != nil {
+		t.Fatalf("Unsubscribe returned error: %v", err)
+	}
+	req = ts.request(t)
+	if req.Command != "UNSUBS" || req.Parameters["keys"] != "QQQ" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestStreamingClientChartAndBook(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	charts, err := client.SubscribeChartEquity([]string{"SPY"})
+	if err != nil {
+		t.Fatalf("SubscribeChartEquity returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "CHART_EQUITY" || req.Parameters["fields"] != "0,1,2,3,4,5,6,7,8" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	books, err := client.SubscribeOptionBook("SPY_071720C310")
+	if err != nil {
+		t.Fatalf("SubscribeOptionBook returned error: %v", err)
+	}
+	if req := ts.request(t); req.Service != "OPTIONS_BOOK" || req.Command != "ADD" || req.Parameters["keys"] != "SPY_071720C310" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[
+		{"service":"CHART_EQUITY","timestamp":1591000000000,"command":"SUBS","content":[{"seq":1,"key":"SPY","1":300.1,"2":301.2,"3":299.3,"4":300.4,"5":12000.0,"6":11,"7":1591000020000,"8":18414}]},
+		{"service":"OPTIONS_BOOK","timestamp":1591000000000,"command":"SUBS","content":[{"key":"SPY_071720C310","1":1591000030000,
+			"2":[{"0":2.1,"1":10,"2":2,"3":[{"0":"CBOE","1":5,"2":1}]}],
+			"3":[{"0":2.2,"1":7,"2":1,"3":[{"0":"ISE","1":7,"2":1}]}]}]}
+	]}`)
+
+	chart := <-charts
+	wantChart := ChartEquityBar{Symbol: "SPY", OpenPrice: 300.1, HighPrice: 301.2, LowPrice: 299.3, ClosePrice: 300.4, Volume: 12000, Sequence: 11, ChartTime: time.Unix(1591000020, 0), ChartDay: 18414}
+	if chart != wantChart {
+		t.Errorf("chart = %+v, want %+v", chart, wantChart)
+	}
+
+	book := <-books
+	if book.Symbol != "SPY_071720C310" || !book.SnapshotTime.Equal(time.Unix(1591000030, 0)) {
+		t.Errorf("unexpected book: %+v", book)
+	}
+	if len(book.Bids) != 1 || book.Bids[0] != (OptionBookLevel{Price: 2.1, TotalSize: 10, NumOrders: 2}) {
+		t.Errorf("unexpected bids: %+v", book.Bids)
+	}
+	if len(book.Asks) != 1 || book.Asks[0] != (OptionBookLevel{Price: 2.2, TotalSize: 7, NumOrders: 1}) {
+		t.Errorf("unexpected asks: %+v", book.Asks)
+	}
+}
+
+func TestStreamingClientReconnects(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, conn := ts.connect(t)
+	defer client.Close()
+
+	quotes, err := client.SubscribeQuotes([]string{"SPY"}, []L1EquityField{L1EquityLastPrice})
+	if err != nil {
+		t.Fatalf("SubscribeQuotes returned error: %v", err)
+	}
+	ts.request(t)
+
+	// Drop the connection. The client should log in again and resubscribe.
+	conn.Close()
+	conn = ts.accept(t)
+	req := ts.request(t)
+	if req.Service != "QUOTE" || req.Command != "SUBS" || req.Parameters["keys"] != "SPY" || req.Parameters["fields"] != "3" {
+		t.Errorf("unexpected resubscription: %+v", req)
+	}
+
+	sendStreamData(t, conn, `{"data":[{"service":"QUOTE","timestamp":1591000000000,"command":"SUBS","content":[{"key":"SPY","3":301.5}]}]}`)
+	if update := <-quotes; update.Fields[L1EquityLastPrice] != 301.5 {
+		t.Errorf("unexpected update: %+v", update)
+	}
+}
+
+// testReconnectPolicy makes maxAttempts attempts to reconnect in all, almost without delay,
+// recording the errors it is given.
+type testReconnectPolicy struct {
+	maxAttempts int
+	attempts    int
+	errs        chan error
+}
+
+func (p *testReconnectPolicy) ShouldReconnect(attempt int, err error) bool {
+	p.errs <- err
+	p.attempts++
+	return p.attempts <= p.maxAttempts
+}
+
+func (p *testReconnectPolicy) ReconnectDelay(attempt int) time.Duration {
+	return time.Millisecond
+}
+
+func TestStreamingClientReconnectPolicy(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+
+	policy := &testReconnectPolicy{maxAttempts: 1, errs: make(chan error, 4)}
+	reconnected := make(chan int, 1)
+	failed := make(chan error, 1)
+	client, conn := ts.connect(t, func(c *StreamingClient) {
+		c.ReconnectPolicy = policy
+		c.OnReconnect = func(attempt int) { reconnected <- attempt }
+		c.OnReconnectFailed = func(err error) { failed <- err }
+	})
+	defer client.Close()
+
+	quotes, err := client.SubscribeQuotes([]string{"SPY"}, []L1EquityField{L1EquityLastPrice})
+	if err != nil {
+		t.Fatalf("SubscribeQuotes returned error: %v", err)
+	}
+	ts.request(t)
+
+	conn.Close()
+	conn = ts.accept(t)
+	if req := ts.request(t); req.Service != "QUOTE" || req.Parameters["keys"] != "SPY" {
+		t.Errorf("unexpected resubscription: %+v", req)
+	}
+	select {
+	case attempt := <-reconnected:
+		if attempt != 1 {
+			t.Errorf("OnReconnect called with attempt %d, want 1", attempt)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnReconnect not called")
+	}
+	if err := <-policy.errs; err == nil {
+		t.Error("ShouldReconnect was not given the error that dropped the connection")
+	}
+
+	// Reconnecting again would exceed the attempts of the policy, so the client gives up and closes.
+	conn.Close()
+	select {
+	case err := <-failed:
+		if err == nil {
+			t.Error("OnReconnectFailed called with a nil error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnReconnectFailed not called")
+	}
+	select {
+	case _, ok := <-quotes:
+		if ok {
+			t.Error("expected quotes channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("quotes channel not closed after giving up")
+	}
+	if _, err := client.SubscribeQuotes([]string{"SPY"}, nil); err == nil {
+		t.Error("expected an error subscribing after giving up")
+	}
+}
+
+func TestStreamingClientClosesDroppedConnections(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+
+	// The streamer never sends heartbeats, so each connection times out and is dropped by the client.
+	policy := &testReconnectPolicy{maxAttempts: 2, errs: make(chan error, 4)}
+	failed := make(chan error, 1)
+	client, _ := ts.connect(t, func(c *StreamingClient) {
+		c.heartbeatTimeout = 200 * time.Millisecond
+		c.ReconnectPolicy = policy
+		c.OnReconnectFailed = func(err error) { failed <- err }
+	})
+	defer client.Close()
+
+	ts.accept(t)
+	ts.accept(t)
+	select {
+	case <-failed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnReconnectFailed not called")
+	}
+
+	// The first connection and the two reconnections are all closed, including the last one after giving up.
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ts.closes:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the client closed %d connections, want 3", i)
+		}
+	}
+}
+
+func TestDefaultReconnectPolicy(t *testing.T) {
+	policy := DefaultReconnectPolicy(3)
+	if !policy.ShouldReconnect(3, nil) || policy.ShouldReconnect(4, nil) {
+		t.Error("DefaultReconnectPolicy(3) must make 3 attempts")
+	}
+	if !DefaultReconnectPolicy(0).ShouldReconnect(1000, nil) {
+		t.Error("DefaultReconnectPolicy(0) must reconnect indefinitely")
+	}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 5: 16 * time.Second, 6: 30 * time.Second, 100: 30 * time.Second} {
+		if got := policy.ReconnectDelay(attempt); got != want {
+			t.Errorf("ReconnectDelay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestStreamingClientCloseClosesChannels(t *testing.T) {
+	ts := newTestStreamer(t)
+	defer ts.close()
+	client, _ := ts.connect(t)
+
+	quotes, err := client.SubscribeQuotes([]string{"SPY"}, nil)
+	if err != nil {
+		t.Fatalf("SubscribeQuotes returned error: %v", err)
+	}
+	if req := ts.request(t); !strings.HasPrefix(req.Parameters["fields"], "0,1,2,3,") {
+		t.Errorf("expected all fields, got %v", req.Parameters["fields"])
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	select {
+	case _, ok := <-quotes:
+		if ok {
+			t.Error("expected quotes channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("quotes channel not closed")
+	}
+
+	if _, err := client.SubscribeQuotes([]string{"SPY"}, nil); err == nil {
+		t.Error("expected an error subscribing after Close")
+	}
+}
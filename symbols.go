@@ -0,0 +1,86 @@
+package tdameritrade
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// osiExpiryLayout is the layout of expiration dates in TD Ameritrade option symbols.
+const osiExpiryLayout = "010206"
+
+// ParseOSISymbol splits a TD Ameritrade option symbol such as SPY_121621C450 into its parts.
+// expiry is in MMDDYY form, callPut is C or P and strike is as written in the symbol, e.g. 450 or 312.5.
+func ParseOSISymbol(osi string) (underlying, expiry, callPut, strike string, err error) {
+	i := strings.LastIndex(osi, "_")
+	if i <= 0 {
+		return "", "", "", "", fmt.Errorf("%w: option symbol %q has no underlying", ErrInvalidParams, osi)
+	}
+	underlying, contract := osi[:i], osi[i+1:]
+
+	if len(contract) < len(osiExpiryLayout)+2 {
+		return "", "", "", "", fmt.Errorf("%w: option symbol %q is too short", ErrInvalidParams, osi)
+	}
+	expiry = contract[:len(osiExpiryLayout)]
+	callPut = contract[len(osiExpiryLayout) : len(osiExpiryLayout)+1]
+	strike = contract[len(osiExpiryLayout)+1:]
+
+	if _, err := time.Parse(osiExpiryLayout, expiry); err != nil {
+		return "", "", "", "", fmt.Errorf("%w: option symbol %q has an invalid expiration date %q", ErrInvalidParams, osi, expiry)
+	}
+	if callPut != "C" && callPut != "P" {
+		return "", "", "", "", fmt.Errorf("%w: option symbol %q must be a call (C) or put (P), got %q", ErrInvalidParams, osi, callPut)
+	}
+	if _, err := strconv.ParseFloat(strike, 64); err != nil {
+		return "", "", "", "", fmt.Errorf("%w: option symbol %q has an invalid strike %q", ErrInvalidParams, osi, strike)
+	}
+
+	return underlying, expiry, callPut, strike, nil
+}
+
+// FormatOSISymbol joins the parts returned by ParseOSISymbol into a TD Ameritrade option symbol.
+func FormatOSISymbol(underlying, expiry, callPut, strike string) string {
+	return fmt.Sprintf("%s_%s%s%s", underlying, expiry, callPut, strike)
+}
+
+// ParseFuturesOptionSymbol splits a TD Ameritrade futures option symbol such as ./EW4X21C4000 into its parts.
+// root is the option's root, e.g. EW4, expiry is its contract month code and year, e.g. X21 for November 2021,
+// and callPut is C or P. The leading ./ and an exchange suffix, e.g. :XCME, are optional.
+func ParseFuturesOptionSymbol(s string) (root, expiry, callPut string, strike float64, err error) {
+	symbol := strings.TrimPrefix(strings.TrimPrefix(s, "."), "/")
+	if i := strings.Index(symbol, ":"); i >= 0 {
+		symbol = symbol[:i]
+	}
+
+	// The strike is the number after the last C or P.
+	i := strings.LastIndexAny(symbol, "CP")
+	if i < 0 {
+		return "", "", "", 0, fmt.Errorf("%w: futures option symbol %q must be a call (C) or put (P)", ErrInvalidParams, s)
+	}
+	callPut = symbol[i : i+1]
+	strike, err = strconv.ParseFloat(symbol[i+1:], 64)
+	if err != nil {
+		return "", "", "", 0, fmt.Errorf("%w: futures option symbol %q has an invalid strike %q", ErrInvalidParams, s, symbol[i+1:])
+	}
+
+	if i < 4 {
+		return "", "", "", 0, fmt.Errorf("%w: futures option symbol %q is too short", ErrInvalidParams, s)
+	}
+	root, expiry = symbol[:i-3], symbol[i-3:i]
+	if !strings.Contains(futuresMonthCodes, expiry[:1]) {
+		return "", "", "", 0, fmt.Errorf("%w: futures option symbol %q has an invalid month code %q", ErrInvalidParams, s, expiry[:1])
+	}
+	if _, err := strconv.Atoi(expiry[1:]); err != nil {
+		return "", "", "", 0, fmt.Errorf("%w: futures option symbol %q has an invalid year %q", ErrInvalidParams, s, expiry[1:])
+	}
+
+	return root, expiry, callPut, strike, nil
+}
+
+// FormatFuturesOptionSymbol joins the parts returned by ParseFuturesOptionSymbol into a TD Ameritrade futures
+// option symbol, e.g. ./EW4X21C4000.
+func FormatFuturesOptionSymbol(root, expiry, callPut string, strike float64) string {
+	root = strings.TrimPrefix(strings.TrimPrefix(root, "."), "/")
+	return fmt.Sprintf("./%s%s%s%s", root, expiry, callPut, strconv.FormatFloat(strike, 'f', -1, 64))
+}
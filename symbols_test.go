@@ -0,0 +1,73 @@
+package tdameritrade
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseOSISymbol(t *testing.T) {
+	underlying, expiry, callPut, strike, err := ParseOSISymbol("SPY_121621C450")
+	if err != nil {
+		t.Fatalf("ParseOSISymbol returned error: %v", err)
+	}
+	if underlying != "SPY" || expiry != "121621" || callPut != "C" || strike != "450" {
+		t.Errorf("ParseOSISymbol = %q, %q, %q, %q", underlying, expiry, callPut, strike)
+	}
+
+	for _, osi := range []string{"SPY_121621C450", "BRK.B_071720P312.5", "SPXW_010321C3700"} {
+		u, e, cp, s, err := ParseOSISymbol(osi)
+		if err != nil {
+			t.Errorf("ParseOSISymbol(%q) returned error: %v", osi, err)
+			continue
+		}
+		if got := FormatOSISymbol(u, e, cp, s); got != osi {
+			t.Errorf("FormatOSISymbol(ParseOSISymbol(%q)) = %q", osi, got)
+		}
+	}
+}
+
+func TestParseOSISymbolInvalid(t *testing.T) {
+	for _, osi := range []string{"SPY", "_121621C450", "SPY_1216C450", "SPY_131621C450", "SPY_121621X450", "SPY_121621C", "SPY_121621Cabc"} {
+		if _, _, _, _, err := ParseOSISymbol(osi); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("ParseOSISymbol(%q): expected ErrInvalidParams, got %v", osi, err)
+		}
+	}
+}
+
+func TestParseFuturesOptionSymbol(t *testing.T) {
+	tests := []struct {
+		symbol, root, expiry, callPut string
+		strike                        float64
+	}{
+		{"./EW4X21C4000", "EW4", "X21", "C", 4000},
+		{"./ESZ21P4550", "ES", "Z21", "P", 4550},
+		{"./OZCZ21C565.5", "OZC", "Z21", "C", 565.5},
+		{"EW4X21C4000", "EW4", "X21", "C", 4000},
+		{"./OGH22P1800:XCEC", "OG", "H22", "P", 1800},
+	}
+	for _, tt := range tests {
+		root, expiry, callPut, strike, err := ParseFuturesOptionSymbol(tt.symbol)
+		if err != nil {
+			t.Errorf("ParseFuturesOptionSymbol(%q) returned error: %v", tt.symbol, err)
+			continue
+		}
+		if root != tt.root || expiry != tt.expiry || callPut != tt.callPut || strike != tt.strike {
+			t.Errorf("ParseFuturesOptionSymbol(%q) = %q, %q, %q, %v", tt.symbol, root, expiry, callPut, strike)
+		}
+	}
+
+	for _, symbol := range []string{"./EW4X21C4000", "./OZCZ21C565.5"} {
+		root, expiry, callPut, strike, _ := ParseFuturesOptionSymbol(symbol)
+		if got := FormatFuturesOptionSymbol(root, expiry, callPut, strike); got != symbol {
+			t.Errorf("FormatFuturesOptionSymbol(ParseFuturesOptionSymbol(%q)) = %q", symbol, got)
+		}
+	}
+}
+
+func TestParseFuturesOptionSymbolInvalid(t *testing.T) {
+	for _, symbol := range []string{"./ES", "./EW4X21C", "./EW4X21Cabc", "./X21C4000", "./EW4A21C4000", "./EW4XAAC4000"} {
+		if _, _, _, _, err := ParseFuturesOptionSymbol(symbol); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("ParseFuturesOptionSymbol(%q): expected ErrInvalidParams, got %v", symbol, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package testutil_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/kuzmak/go-tdameritrade"
+	"github.com/kuzmak/go-tdameritrade/testutil"
+)
+
+// The tests below show how to test code calling the tdameritrade package with a MockServer.
+
+func TestGetChains(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.ExpectGET("/marketdata/chains", `{"symbol":"SPY","status":"SUCCESS","underlyingPrice":310.5}`)
+
+	chains, _, err := server.Client().Chains.GetChains(context.Background(), tdameritrade.ChainsParams{Symbol: "SPY"})
+	if err != nil {
+		t.Fatalf("GetChains returned error: %v", err)
+	}
+	if chains.Symbol != "SPY" || chains.UnderlyingPrice != 310.5 {
+		t.Errorf("unexpected chains: %+v", chains)
+	}
+
+	server.AssertExpectationsMet(t)
+	if query := server.Calls()[0].Query; query == "" {
+		t.Error("expected GetChains to send its parameters")
+	}
+}
+
+func TestGetQuote(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+	server.ExpectGET("/marketdata/SPY/quotes", `{"SPY":{"symbol":"SPY","lastPrice":310.5}}`)
+
+	quote, _, err := server.Client().Quotes.GetQuote(context.Background(), "SPY")
+	if err != nil {
+		t.Fatalf("GetQuote returned error: %v", err)
+	}
+	if quote.LastPrice != 310.5 {
+		t.Errorf("LastPrice = %v, want 310.5", quote.LastPrice)
+	}
+
+	server.AssertExpectationsMet(t)
+}
+
+func TestPlaceOrder(t *testing.T) {
+	server := testutil.NewMockServer()
+	defer server.Close()
+
+	assertOrder := func(body []byte) error {
+		var order tdameritrade.Order
+		if err := json.Unmarshal(body, &order); err != nil {
+			return err
+		}
+		if order.OrderType != "MARKET" || len(order.OrderLegCollection) != 1 || order.OrderLegCollection[0].Quantity != 10 {
+			return fmt.Errorf("unexpected order %s", body)
+		}
+		return nil
+	}
+	server.ExpectPOST("/accounts/123/orders", assertOrder, "", 201).
+		WithHeader("Location", server.URL+"/accounts/123/orders/456")
+
+	order, err := tdameritrade.NewEquityMarketBuyOrder("SPY", 10)
+	if err != nil {
+		t.Fatalf("NewEquityMarketBuyOrder returned error: %v", err)
+	}
+	resp, err := server.Client().Orders.PlaceOrder(context.Background(), "123", order)
+	if err != nil {
+		t.Fatalf("PlaceOrder returned error: %v", err)
+	}
+	if resp.OrderID != "456" {
+		t.Errorf("OrderID = %q, want 456", resp.OrderID)
+	}
+
+	server.AssertExpectationsMet(t)
+}
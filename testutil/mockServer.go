@@ -0,0 +1,163 @@
+// Package testutil helps test code using the tdameritrade package without calling TD Ameritrade.
+//
+// Usage example:
+// server := testutil.NewMockServer()
+// defer server.Close()
+// server.ExpectGET("/marketdata/SPY/quotes", `{"SPY":{"symbol":"SPY","lastPrice":310.5}}`)
+// quote, _, err := server.Client().Quotes.GetQuote(ctx, "SPY")
+// server.AssertExpectationsMet(t)
+package testutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kuzmak/go-tdameritrade"
+)
+
+// MockServer is an HTTP server answering the requests of a tdameritrade.Client with canned responses.
+// Each expectation answers one request with the expected method and path, in any order.
+// Requests matching no remaining expectation are answered with 501 Not Implemented and fail AssertExpectationsMet.
+type MockServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+	failures     []string
+}
+
+// Expectation is a request a MockServer expects and its response.
+type Expectation struct {
+	method     string
+	path       string
+	assertBody func([]byte) error
+	response   string
+	statusCode int
+	header     http.Header
+	met        bool
+}
+
+// WithHeader adds a header to the response, such as the Location of an order placed by OrdersService.PlaceOrder.
+func (e *Expectation) WithHeader(key, value string) *Expectation {
+	e.header.Add(key, value)
+	return e
+}
+
+// Call is a request received by a MockServer.
+type Call struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+// NewMockServer starts a MockServer. Call Close when done with it.
+func NewMockServer() *MockServer {
+	m := &MockServer{}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	return m
+}
+
+// ExpectGET expects a GET request for path, e.g. /marketdata/SPY/quotes, and answers it with responseJSON.
+func (m *MockServer) ExpectGET(path string, responseJSON string) *Expectation {
+	return m.expect(&Expectation{method: "GET", path: path, response: responseJSON, statusCode: http.StatusOK})
+}
+
+// ExpectPOST expects a POST request for path and answers it with responseJSON and statusCode.
+// assertBody, if not nil, checks the request body; an error fails AssertExpectationsMet.
+func (m *MockServer) ExpectPOST(path string, assertBody func([]byte) error, responseJSON string, statusCode int) *Expectation {
+	return m.expect(&Expectation{method: "POST", path: path, assertBody: assertBody, response: responseJSON, statusCode: statusCode})
+}
+
+func (m *MockServer) expect(e *Expectation) *Expectation {
+	e.header = http.Header{}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// Client returns a tdameritrade.Client sending its requests to the MockServer.
+func (m *MockServer) Client() *tdameritrade.Client {
+	client, err := tdameritrade.NewClient(m.Server.Client())
+	if err != nil {
+		panic(err)
+	}
+	if err := client.UpdateBaseURL(m.URL + "/"); err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// Calls returns the requests received so far.
+func (m *MockServer) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// AssertExpectationsMet fails t if an expectation was not met, a request was unexpected or a body assertion failed.
+func (m *MockServer) AssertExpectationsMet(t testing.TB) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, failure := range m.failures {
+		t.Error(failure)
+	}
+	for _, e := range m.expectations {
+		if !e.met {
+			t.Errorf("expected %s %s was not requested", e.method, e.path)
+		}
+	}
+}
+
+func (m *MockServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Body: body})
+	e := m.match(r.Method, r.URL.Path)
+	if e == nil {
+		m.failures = append(m.failures, fmt.Sprintf("unexpected request %s %s", r.Method, r.URL))
+		m.mu.Unlock()
+		http.Error(w, `{"error":"unexpected request"}`, http.StatusNotImplemented)
+		return
+	}
+	e.met = true
+	if e.assertBody != nil {
+		if err := e.assertBody(body); err != nil {
+			m.failures = append(m.failures, fmt.Sprintf("%s %s: %v", r.Method, r.URL.Path, err))
+		}
+	}
+	m.mu.Unlock()
+
+	for key, values := range e.header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	if e.response != "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(e.statusCode)
+	fmt.Fprint(w, e.response)
+}
+
+// match returns the first unmet expectation for method and path.
+func (m *MockServer) match(method, path string) *Expectation {
+	for _, e := range m.expectations {
+		if !e.met && e.method == method && e.path == path {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// recordingT records the failures of AssertExpectationsMet instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Error(args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockServerReportsFailures(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+
+	server.ExpectGET("/marketdata/SPY/quotes", `{"SPY":{"symbol":"SPY"}}`)
+	server.ExpectPOST("/accounts/123/orders", func([]byte) error { return errors.New("bad order") }, "", 201)
+
+	client := server.Client()
+	if _, _, err := client.Quotes.GetQuote(context.Background(), "QQQ"); err == nil {
+		t.Error("expected an unexpected request to return an error")
+	}
+	if _, err := client.Orders.PlaceOrder(context.Background(), "123", nil); err == nil {
+		t.Error("expected PlaceOrder with a nil order to fail")
+	}
+
+	if calls := server.Calls(); len(calls) != 1 || calls[0].Path != "/marketdata/QQQ/quotes" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	rt := &recordingT{TB: t}
+	server.AssertExpectationsMet(rt)
+	want := []string{
+		"unexpected request GET /marketdata/QQQ/quotes",
+		"expected GET /marketdata/SPY/quotes was not requested",
+		"expected POST /accounts/123/orders was not requested",
+	}
+	if fmt.Sprint(rt.errors) != fmt.Sprint(want) {
+		t.Errorf("failures = %q, want %q", rt.errors, want)
+	}
+}
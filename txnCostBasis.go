@@ -0,0 +1,171 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Terms of a TaxLot.
+const (
+	TermShort = "SHORT"
+	TermLong  = "LONG"
+)
+
+// maxCostBasisYears is the number of calendar years GetCostBasisSummary looks back for the purchases of the shares sold.
+const maxCostBasisYears = 10
+
+// CostBasisSummary is the realized gain or loss of the sales of a calendar year.
+// Amounts are in dollars and include commissions and fees, as the net amount of each transaction does.
+type CostBasisSummary struct {
+	ShortTermGain  float64
+	LongTermGain   float64
+	TotalProceeds  float64
+	TotalCostBasis float64
+	Lots           []TaxLot
+}
+
+// TaxLot is a quantity bought on OpenDate and sold on CloseDate. GainLoss is negative for a loss.
+// Term is TermLong if the lot was held for more than a year and TermShort otherwise.
+type TaxLot struct {
+	Symbol    string
+	OpenDate  time.Time
+	CloseDate time.Time
+	Quantity  float64
+	CostBasis float64
+	Proceeds  float64
+	GainLoss  float64
+	Term      string
+}
+
+// GetCostBasisSummary returns the gains and losses realized by the sales of accountID in the calendar year, matching
+// each sale to the earliest purchases of the same symbol still held (first in, first out).
+//
+// TD Ameritrade returns at most a year of transactions per request, so the trades of the year are fetched first,
+// then those of each earlier year, for up to 10 years. As an earlier purchase would be sold before later ones,
+// matching only starts once the earliest purchases are known: the years are fetched until every sale is matched
+// and a year without any trades is reached, which is taken as the start of the account's history. Short sales
+// are not supported. The summary is meant as a guide and ignores wash sales and other adjustments brokers
+// make to the cost basis they report.
+func (s *TransactionsService) GetCostBasisSummary(ctx context.Context, accountID string, year int) (*CostBasisSummary, error) {
+	var trades []*Transaction
+	for back := 0; ; back++ {
+		y := year - back
+		params := TransactionQueryParams{
+			Type:      "TRADE",
+			StartDate: time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(y, time.December, 31, 0, 0, 0, 0, time.UTC),
+		}
+		txns, _, err := s.GetTransactions(ctx, accountID, params)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, txns...)
+
+		if len(txns) > 0 && back < maxCostBasisYears-1 {
+			continue
+		}
+		summary, err := matchTaxLots(trades, year)
+		var unmatched *unmatchedSaleError
+		if errors.As(err, &unmatched) && back < maxCostBasisYears-1 {
+			// A gap in the account's trades; the purchases are further back.
+			continue
+		}
+		return summary, err
+	}
+}
+
+// unmatchedSaleError is returned by matchTaxLots for a sale of more than the purchases before it.
+type unmatchedSaleError struct {
+	symbol   string
+	quantity float64
+	date     time.Time
+}
+
+func (e *unmatchedSaleError) Error() string {
+	return fmt.Sprintf("no purchase found for %v of %s sold on %s", e.quantity, e.symbol, e.date.Format("2006-01-02"))
+}
+
+// matchTaxLots matches the sales of trades to their purchases, first in, first out,
+// and summarizes the lots sold in year.
+func matchTaxLots(trades []*Transaction, year int) (*CostBasisSummary, error) {
+	type trade struct {
+		*Transaction
+		date time.Time
+	}
+	var sorted []trade
+	for _, t := range trades {
+		item := t.TransactionItem
+		if (item.Instruction != "BUY" && item.Instruction != "SELL") || item.Amount == 0 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02T15:04:05-0700", t.TransactionDate)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the date of transaction %d: %w", t.TransactionID, err)
+		}
+		sorted = append(sorted, trade{t, date})
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].date.Equal(sorted[j].date) {
+			return sorted[i].date.Before(sorted[j].date)
+		}
+		return sorted[i].TransactionID < sorted[j].TransactionID
+	})
+
+	// The lots still held of each symbol, oldest first, with their cost per share.
+	type openLot struct {
+		date     time.Time
+		quantity float64
+		cost     float64
+	}
+	held := map[string][]openLot{}
+	summary := &CostBasisSummary{}
+	for _, t := range sorted {
+		symbol := t.TransactionItem.Instrument.Symbol
+		quantity := math.Abs(t.TransactionItem.Amount)
+		perShare := math.Abs(t.NetAmount) / quantity
+		if t.TransactionItem.Instruction == "BUY" {
+			held[symbol] = append(held[symbol], openLot{date: t.date, quantity: quantity, cost: perShare})
+			continue
+		}
+
+		for quantity > 0 {
+			if len(held[symbol]) == 0 {
+				return nil, &unmatchedSaleError{symbol: symbol, quantity: quantity, date: t.date}
+			}
+			lot := &held[symbol][0]
+			sold := math.Min(quantity, lot.quantity)
+			if t.date.Year() == year {
+				taxLot := TaxLot{
+					Symbol:    symbol,
+					OpenDate:  lot.date,
+					CloseDate: t.date,
+					Quantity:  sold,
+					CostBasis: sold * lot.cost,
+					Proceeds:  sold * perShare,
+					Term:      TermShort,
+				}
+				taxLot.GainLoss = taxLot.Proceeds - taxLot.CostBasis
+				if t.date.After(lot.date.AddDate(1, 0, 0)) {
+					taxLot.Term = TermLong
+					summary.LongTermGain += taxLot.GainLoss
+				} else {
+					summary.ShortTermGain += taxLot.GainLoss
+				}
+				summary.TotalProceeds += taxLot.Proceeds
+				summary.TotalCostBasis += taxLot.CostBasis
+				summary.Lots = append(summary.Lots, taxLot)
+			}
+
+			quantity -= sold
+			lot.quantity -= sold
+			if lot.quantity == 0 {
+				held[symbol] = held[symbol][1:]
+			}
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,143 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetCostBasisSummary(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	trade := func(id int, date, instruction string, amount, price float64) string {
+		net := amount * price
+		if instruction == "BUY" {
+			net = -net
+		}
+		return fmt.Sprintf(`{"type":"TRADE","transactionId":%d,"transactionDate":"%sT14:30:00+0000","netAmount":%v,
+			"transactionItem":{"amount":%v,"price":%v,"instruction":"%s","instrument":{"symbol":"SPY","assetType":"EQUITY"}}}`,
+			id, date, net, amount, price, instruction)
+	}
+	// Newest first, as TD Ameritrade returns them.
+	byYear := map[string]string{
+		"2020-01-01": "[" + trade(3, "2020-06-01", "SELL", 15, 300) + "," + trade(2, "2020-02-03", "BUY", 10, 320) + "]",
+		"2019-01-01": "[" + trade(1, "2019-03-01", "BUY", 10, 280) + "]",
+	}
+	var requested []string
+	mux.HandleFunc("/accounts/123/transactions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "type", "TRADE")
+		start := r.FormValue("startDate")
+		requested = append(requested, start)
+		if r.FormValue("endDate") != start[:4]+"-12-31" {
+			t.Errorf("unexpected endDate %s for startDate %s", r.FormValue("endDate"), start)
+		}
+		txns, ok := byYear[start]
+		if !ok {
+			txns = "[]"
+		}
+		fmt.Fprint(w, txns)
+	})
+
+	summary, err := client.TransactionHistory.GetCostBasisSummary(context.Background(), "123", 2020)
+	if err != nil {
+		t.Fatalf("GetCostBasisSummary returned error: %v", err)
+	}
+	if len(requested) != 3 {
+		t.Errorf("expected the trades of 2020 back to the empty 2018 to be requested, got %v", requested)
+	}
+
+	sold := time.Date(2020, 6, 1, 14, 30, 0, 0, time.UTC)
+	want := []TaxLot{
+		{Symbol: "SPY", OpenDate: time.Date(2019, 3, 1, 14, 30, 0, 0, time.UTC), CloseDate: sold, Quantity: 10, CostBasis: 2800, Proceeds: 3000, GainLoss: 200, Term: TermLong},
+		{Symbol: "SPY", OpenDate: time.Date(2020, 2, 3, 14, 30, 0, 0, time.UTC), CloseDate: sold, Quantity: 5, CostBasis: 1600, Proceeds: 1500, GainLoss: -100, Term: TermShort},
+	}
+	if len(summary.Lots) != len(want) {
+		t.Fatalf("got %d lots, want %d: %+v", len(summary.Lots), len(want), summary.Lots)
+	}
+	for i, lot := range summary.Lots {
+		if !lot.OpenDate.Equal(want[i].OpenDate) || !lot.CloseDate.Equal(want[i].CloseDate) {
+			t.Errorf("lot %d dates = %v to %v, want %v to %v", i, lot.OpenDate, lot.CloseDate, want[i].OpenDate, want[i].CloseDate)
+		}
+		lot.OpenDate, lot.CloseDate = want[i].OpenDate, want[i].CloseDate
+		if lot != want[i] {
+			t.Errorf("lot %d = %+v, want %+v", i, lot, want[i])
+		}
+	}
+
+	for _, total := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"ShortTermGain", summary.ShortTermGain, -100},
+		{"LongTermGain", summary.LongTermGain, 200},
+		{"TotalProceeds", summary.TotalProceeds, 4500},
+		{"TotalCostBasis", summary.TotalCostBasis, 4400},
+	} {
+		if math.Abs(total.got-total.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", total.name, total.got, total.want)
+		}
+	}
+}
+
+func TestGetCostBasisSummaryOlderLot(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	// The sale of 2020 is covered by the purchase of 2020, but the purchase of 2018 is older and is sold first.
+	byYear := map[string]string{
+		"2020-01-01": `[{"type":"TRADE","transactionId":3,"transactionDate":"2020-06-01T14:30:00+0000","netAmount":1500,
+			"transactionItem":{"amount":5,"price":300,"instruction":"SELL","instrument":{"symbol":"SPY"}}},
+			{"type":"TRADE","transactionId":2,"transactionDate":"2020-02-03T14:30:00+0000","netAmount":-3200,
+			"transactionItem":{"amount":10,"price":320,"instruction":"BUY","instrument":{"symbol":"SPY"}}}]`,
+		"2019-01-01": `[{"type":"TRADE","transactionId":4,"transactionDate":"2019-05-01T14:30:00+0000","netAmount":-500,
+			"transactionItem":{"amount":5,"price":100,"instruction":"BUY","instrument":{"symbol":"QQQ"}}}]`,
+		"2018-01-01": `[{"type":"TRADE","transactionId":1,"transactionDate":"2018-03-01T14:30:00+0000","netAmount":-2500,
+			"transactionItem":{"amount":10,"price":250,"instruction":"BUY","instrument":{"symbol":"SPY"}}}]`,
+	}
+	mux.HandleFunc("/accounts/123/transactions", func(w http.ResponseWriter, r *http.Request) {
+		txns, ok := byYear[r.FormValue("startDate")]
+		if !ok {
+			txns = "[]"
+		}
+		fmt.Fprint(w, txns)
+	})
+
+	summary, err := client.TransactionHistory.GetCostBasisSummary(context.Background(), "123", 2020)
+	if err != nil {
+		t.Fatalf("GetCostBasisSummary returned error: %v", err)
+	}
+	if len(summary.Lots) != 1 {
+		t.Fatalf("got %d lots, want 1: %+v", len(summary.Lots), summary.Lots)
+	}
+	if lot := summary.Lots[0]; lot.OpenDate.Year() != 2018 || lot.CostBasis != 1250 || lot.Term != TermLong || summary.LongTermGain != 250 {
+		t.Errorf("expected the sale to be matched to the lot of 2018, got %+v", summary)
+	}
+}
+
+func TestGetCostBasisSummaryUnmatchedSale(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/accounts/123/transactions", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.FormValue("startDate") != "2020-01-01" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[{"type":"TRADE","transactionId":1,"transactionDate":"2020-06-01T14:30:00+0000","netAmount":3000,
+			"transactionItem":{"amount":10,"price":300,"instruction":"SELL","instrument":{"symbol":"SPY"}}}]`)
+	})
+
+	if _, err := client.TransactionHistory.GetCostBasisSummary(context.Background(), "123", 2020); err == nil {
+		t.Error("expected an error for a sale without purchases")
+	}
+	if requests != maxCostBasisYears {
+		t.Errorf("expected %d requests, got %d", maxCostBasisYears, requests)
+	}
+}
@@ -3,6 +3,8 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -60,39 +62,54 @@ type TransactionItem struct {
 
 // TransactionInstrument is the instrumnet traded within a transaction
 type TransactionInstrument struct {
-	Symbol               string  `json:"symbol"`
-	UnderlyingSymbol     string  `json:"underlyingSymbol"`
-	OptionExpirationDate string  `json:"optionExpirationDate"`
-	OptionStrikePrice    float64 `json:"optionStrikePrice"`
-	PutCall              string  `json:"putCall"`
-	CUSIP                string  `json:"cusip"`
-	Description          string  `json:"description"`
-	AssetType            string  `json:"assetType"`
-	BondMaturityDate     string  `json:"bondMaturityDate"`
-	BondInterestRate     float64 `json:"bondInterestRate"`
+	Symbol               string    `json:"symbol"`
+	UnderlyingSymbol     string    `json:"underlyingSymbol"`
+	OptionExpirationDate string    `json:"optionExpirationDate"`
+	OptionStrikePrice    float64   `json:"optionStrikePrice"`
+	PutCall              PutCall   `json:"putCall"`
+	CUSIP                string    `json:"cusip"`
+	Description          string    `json:"description"`
+	AssetType            AssetType `json:"assetType"`
+	BondMaturityDate     string    `json:"bondMaturityDate"`
+	BondInterestRate     float64   `json:"bondInterestRate"`
 }
 
-// TransactionHistoryOptions is parsed and translated to query options in the https request
-type TransactionHistoryOptions struct {
-	Type   string `url:"type,omitempty"`
-	Symbol string `url:"symbol,omitempty"`
-	// ISO8601 format, day granularity yyyy-MM-dd
-	StartDate string `url:"startDate,omitempty"`
-	// ISO8601 format, day granularity yyyy-MM-dd
-	EndDate string `url:"endDate,omitempty"`
+// TransactionQueryParams is parsed and translated to query options in the https request.
+// Zero values are left out of the request.
+type TransactionQueryParams struct {
+	Type      string    `url:"type,omitempty"`
+	Symbol    string    `url:"symbol,omitempty"`
+	StartDate time.Time `url:"-"`
+	EndDate   time.Time `url:"-"`
 }
 
-// TransactionHistoryService handles communication with the transaction history related methods of
+// values encodes the params as query options.
+// TD Ameritrade expects dates in ISO8601 format, day granularity yyyy-MM-dd.
+func (p TransactionQueryParams) values() (url.Values, error) {
+	q, err := query.Values(p)
+	if err != nil {
+		return nil, err
+	}
+	if !p.StartDate.IsZero() {
+		q.Set("startDate", p.StartDate.Format("2006-01-02"))
+	}
+	if !p.EndDate.IsZero() {
+		q.Set("endDate", p.EndDate.Format("2006-01-02"))
+	}
+	return q, nil
+}
+
+// TransactionsService handles communication with the transaction history related methods of
 // the TDAmeritrade API.
 //
 // TDAmeritrade API docs: https://developer.tdameritrade.com/transaction-history/apis
-type TransactionHistoryService struct {
+type TransactionsService struct {
 	client *Client
 }
 
 // GetTransaction gets a specific transaction by account
 // TDAmeritrade API Docs: https://developer.tdameritrade.com/transaction-history/apis/get/accounts/%7BaccountId%7D/transactions/%7BtransactionId%7D-0
-func (s *TransactionHistoryService) GetTransaction(ctx context.Context, accountID string, transactionID string) (*Transaction, *Response, error) {
+func (s *TransactionsService) GetTransaction(ctx context.Context, accountID string, transactionID string) (*Transaction, *Response, error) {
 	u := fmt.Sprintf("accounts/%s/transactions/%s", accountID, transactionID)
 
 	req, err := s.client.NewRequest("GET", u, nil)
@@ -110,13 +127,13 @@ func (s *TransactionHistoryService) GetTransaction(ctx context.Context, accountI
 
 // GetTransactions gets all transaction by account
 // TDAmeritrade API Docs: https://developer.tdameritrade.com/transaction-history/apis/get/accounts/%7BaccountId%7D/transactions-0
-func (s *TransactionHistoryService) GetTransactions(ctx context.Context, accountID string, opts *TransactionHistoryOptions) (*Transactions, *Response, error) {
+func (s *TransactionsService) GetTransactions(ctx context.Context, accountID string, params TransactionQueryParams) ([]*Transaction, *Response, error) {
 	u := fmt.Sprintf("accounts/%s/transactions", accountID)
-	if opts != nil {
-		q, err := query.Values(opts)
-		if err != nil {
-			return nil, nil, err
-		}
+	q, err := params.values()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(q) > 0 {
 		u = fmt.Sprintf("%s?%s", u, q.Encode())
 	}
 
@@ -125,8 +142,8 @@ func (s *TransactionHistoryService) GetTransactions(ctx context.Context, account
 		return nil, nil, err
 	}
 
-	txns := new(Transactions)
-	resp, err := s.client.Do(ctx, req, txns)
+	txns := Transactions{}
+	resp, err := s.client.Do(ctx, req, &txns)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -0,0 +1,69 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetTransactions(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123/transactions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "type", "TRADE")
+		testFormValue(t, r, "symbol", "SPY")
+		testFormValue(t, r, "startDate", "2020-01-02")
+		testFormValue(t, r, "endDate", "2020-12-31")
+		fmt.Fprint(w, `[{"type":"TRADE","subAccount":"2","settlementDate":"2020-06-17","orderId":"T123","netAmount":-3105.1,
+			"transactionDate":"2020-06-15T14:30:00+0000","transactionId":42,
+			"transactionItem":{"accountId":123,"amount":10,"price":310.51,"cost":-3105.1,"instruction":"BUY",
+				"instrument":{"symbol":"SPY","assetType":"EQUITY","cusip":"78462F103"}}}]`)
+	})
+
+	params := TransactionQueryParams{
+		Type:      "TRADE",
+		Symbol:    "SPY",
+		StartDate: time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC),
+		EndDate:   time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	txns, _, err := client.TransactionHistory.GetTransactions(context.Background(), "123", params)
+	if err != nil {
+		t.Fatalf("GetTransactions returned error: %v", err)
+	}
+
+	if len(txns) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(txns))
+	}
+	txn := txns[0]
+	if txn.Type != "TRADE" || txn.SubAccount != "2" || txn.SettlementDate != "2020-06-17" || txn.OrderID != "T123" || txn.NetAmount != -3105.1 {
+		t.Errorf("unexpected transaction: %+v", txn)
+	}
+	item := txn.TransactionItem
+	if item.AccountID != 123 || item.Amount != 10 || item.Price != 310.51 || item.Cost != -3105.1 || item.Instrument.Symbol != "SPY" {
+		t.Errorf("unexpected transaction item: %+v", item)
+	}
+}
+
+func TestGetTransactionsWithoutParams(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/accounts/123/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			t.Errorf("unexpected query: %q", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	if _, _, err := client.TransactionHistory.GetTransactions(context.Background(), "123", TransactionQueryParams{}); err != nil {
+		t.Fatalf("GetTransactions returned error: %v", err)
+	}
+}
+
+func TestUnmarshalTransaction(t *testing.T) {
+	testJSONRoundTrip(t, "testdata/transaction.json", &Transaction{})
+}
@@ -3,6 +3,7 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -31,7 +32,7 @@ type KeyEntry struct {
 	Key string `json:"key"`
 }
 
-type UserPrincipal struct {
+type UserPrincipals struct {
 	AuthToken                string                   `json:"authToken"`
 	UserID                   string                   `json:"userId"`
 	UserCdDomainID           string                   `json:"userCdDomainId"`
@@ -45,10 +46,10 @@ type UserPrincipal struct {
 	ProfessionalStatus       string                   `json:"professionalStatus"`
 	Quotes                   QuoteDelays              `json:"quotes"`
 	StreamerSubscriptionKeys StreamerSubscriptionKeys `json:"streamerSubscriptionKeys"`
-	Accounts                 []UserAccountInfo        `json:"accounts"`
+	Accounts                 []UserAccount            `json:"accounts"`
 }
 
-type UserAccountInfo struct {
+type UserAccount struct {
 	AccountID         string         `json:"accountId"`
 	Description       string         `json:"description"`
 	DisplayName       string         `json:"displayName"`
@@ -84,6 +85,8 @@ type StreamerInfo struct {
 	AppID             string `json:"appId"`
 }
 
+// QuoteDelays reports which exchanges the user receives delayed quotes for.
+// TD Ameritrade only reports whether quotes are delayed, not by how long.
 type QuoteDelays struct {
 	IsNyseDelayed   bool `json:"isNyseDelayed"`
 	IsNasdaqDelayed bool `json:"isNasdaqDelayed"`
@@ -94,15 +97,15 @@ type QuoteDelays struct {
 	IsForexDelayed  bool `json:"isForexDelayed"`
 }
 
-// UserService exposes operations on a user's preferences.
+// UserInfoService exposes operations on a user's preferences.
 // See https://developer.tdameritrade.com/user-principal/apis.
-type UserService struct {
+type UserInfoService struct {
 	client *Client
 }
 
 // GetPreferences returns Preferences for a specific account.
 // See https://developer.tdameritrade.com/user-principal/apis/get/accounts/%7BaccountId%7D/preferences-0
-func (s *UserService) GetPreferences(ctx context.Context, accountID string) (*Preferences, *Response, error) {
+func (s *UserInfoService) GetPreferences(ctx context.Context, accountID string) (*Preferences, *Response, error) {
 	u := fmt.Sprintf("accounts/%s/preferences", accountID)
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
@@ -120,7 +123,7 @@ func (s *UserService) GetPreferences(ctx context.Context, accountID string) (*Pr
 
 // GetStreamerSubscriptionKeys returns Subscription Keys for provided accounts or default accounts.
 // See https://developer.tdameritrade.com/user-principal/apis/get/userprincipals/streamersubscriptionkeys-0
-func (s *UserService) GetStreamerSubscriptionKeys(ctx context.Context, accountIDs ...string) (*StreamerSubscriptionKeys, *Response, error) {
+func (s *UserInfoService) GetStreamerSubscriptionKeys(ctx context.Context, accountIDs ...string) (*StreamerSubscriptionKeys, *Response, error) {
 	u := fmt.Sprintf("userprincipals/streamersubscriptionkeys?accountIds=%s", strings.Join(accountIDs, ","))
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
@@ -139,10 +142,10 @@ func (s *UserService) GetStreamerSubscriptionKeys(ctx context.Context, accountID
 // GetUserPrincipals returns User Principal details.
 // Valid values for `fields` are "streamerSubscriptionKeys", "streamerConnectionInfo", "preferences" and  "surrogateIds"
 // See https://developer.tdameritrade.com/user-principal/apis/get/userprincipals-0
-func (s *UserService) GetUserPrincipals(ctx context.Context, fields ...string) (*UserPrincipal, *Response, error) {
+func (s *UserInfoService) GetUserPrincipals(ctx context.Context, fields []string) (*UserPrincipals, *Response, error) {
 	u := "userprincipals"
 	if len(fields) > 0 {
-		u = fmt.Sprintf("%s?fields=%s", u, strings.Join(fields, ","))
+		u = fmt.Sprintf("%s?fields=%s", u, url.QueryEscape(strings.Join(fields, ",")))
 	}
 
 	req, err := s.client.NewRequest("GET", u, nil)
@@ -150,7 +153,7 @@ func (s *UserService) GetUserPrincipals(ctx context.Context, fields ...string) (
 		return nil, nil, err
 	}
 
-	userPrincipal := new(UserPrincipal)
+	userPrincipal := new(UserPrincipals)
 	resp, err := s.client.Do(ctx, req, userPrincipal)
 	if err != nil {
 		return nil, resp, err
@@ -162,7 +165,7 @@ func (s *UserService) GetUserPrincipals(ctx context.Context, fields ...string) (
 // UpdatePreferences updates Preferences for a specific account.
 // Please note that the directOptionsRouting and directEquityRouting values cannot be modified via this operation, even though they are in the request body.
 // See https://developer.tdameritrade.com/user-principal/apis/put/accounts/%7BaccountId%7D/preferences-0
-func (s *UserService) UpdatePreferences(ctx context.Context, accountID string, newPreferences *Preferences) (*Response, error) {
+func (s *UserInfoService) UpdatePreferences(ctx context.Context, accountID string, newPreferences *Preferences) (*Response, error) {
 	if newPreferences == nil {
 		return nil, fmt.Errorf("newPreferences is nil")
 	}
@@ -0,0 +1,70 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetUserPrincipals(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/userprincipals", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValue(t, r, "fields", "streamerConnectionInfo,preferences")
+		fmt.Fprint(w, `{"userId":"user","primaryAccountId":"123","stalePassword":false,
+			"streamerInfo":{"streamerSocketUrl":"streamer-ws.tdameritrade.com","appId":"APP"},
+			"quotes":{"isNyseDelayed":false,"isOpraDelayed":true},
+			"accounts":[{"accountId":"123","company":"AMER","segment":"AMER","preferences":{"expressTrading":true}}]}`)
+	})
+
+	principals, _, err := client.User.GetUserPrincipals(context.Background(), []string{"streamerConnectionInfo", "preferences"})
+	if err != nil {
+		t.Fatalf("GetUserPrincipals returned error: %v", err)
+	}
+
+	if principals.UserID != "user" || principals.PrimaryAccountID != "123" || principals.StreamerInfo.AppID != "APP" || !principals.Quotes.IsOpraDelayed {
+		t.Errorf("unexpected principals: %+v", principals)
+	}
+	if len(principals.Accounts) != 1 || !principals.Accounts[0].Preferences.ExpressTrading {
+		t.Errorf("unexpected accounts: %+v", principals.Accounts)
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	want := &Preferences{
+		ExpressTrading:                   true,
+		DefaultEquityOrderLegInstruction: "BUY",
+		DefaultEquityOrderType:           "LIMIT",
+		DefaultEquityOrderDuration:       "DAY",
+		DefaultEquityOrderMarketSession:  "NORMAL",
+		MutualFundTaxLotMethod:           "FIFO",
+	}
+
+	mux.HandleFunc("/accounts/123/preferences", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+
+		got := new(Preferences)
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Errorf("could not decode body: %v", err)
+			return
+		}
+		if *got != *want {
+			t.Errorf("request body = %+v, want %+v", got, want)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.User.UpdatePreferences(context.Background(), "123", want); err != nil {
+		t.Fatalf("UpdatePreferences returned error: %v", err)
+	}
+}
@@ -5,52 +5,33 @@ import (
 	"fmt"
 )
 
-// NewWatchlist is a watchlist to be created by a user.
-type NewWatchlist struct {
+// Watchlist is a named list of securities in a user's account.
+// WatchlistID, AccountID and Status are set by TD Ameritrade and are ignored when creating or changing a watchlist.
+type Watchlist struct {
 	Name           string          `json:"name"`
+	WatchlistID    string          `json:"watchlistId,omitempty"`
+	AccountID      string          `json:"accountId,omitempty"`
+	Status         string          `json:"status,omitempty"`
 	WatchlistItems []WatchlistItem `json:"watchlistItems"`
 }
 
-// WatchlistItem is a security to be added to a NewWatchlist.
+// WatchlistItem is a security in a Watchlist.
+// SequenceID identifies an existing item when partially updating a watchlist.
 type WatchlistItem struct {
-	Quantity      int                 `json:"quantity"`
-	AveragePrice  int                 `json:"averagePrice"`
-	Commission    int                 `json:"commission"`
-	PurchasedDate string              `json:"purchasedDate"`
+	SequenceID    int                 `json:"sequenceId,omitempty"`
+	Quantity      float64             `json:"quantity"`
+	AveragePrice  float64             `json:"averagePrice"`
+	Commission    float64             `json:"commission"`
+	PurchasedDate string              `json:"purchasedDate,omitempty"`
 	Instrument    WatchlistInstrument `json:"instrument"`
+	Status        string              `json:"status,omitempty"`
 }
 
-// WatchlistInstrument is the specific information about the security being added to the watchlist.
+// WatchlistInstrument is the specific information about the security in the watchlist.
 type WatchlistInstrument struct {
-	Symbol    string `json:"symbol"`
-	AssetType string `json:"assetType"`
-}
-
-// StoredWatchlist is an existing watchlist in a user's account.
-type StoredWatchlist []struct {
-	Name           string                `json:"name"`
-	WatchlistID    string                `json:"watchlistId"`
-	AccountID      string                `json:"accountId"`
-	Status         string                `json:"status"`
-	WatchlistItems []StoredWatchlistItem `json:"watchlistItems"`
-}
-
-// StoredWatchlistItem is an item in the user's existing watchlist.
-type StoredWatchlistItem struct {
-	SequenceID    int                       `json:"sequenceId"`
-	Quantity      int                       `json:"quantity"`
-	AveragePrice  int                       `json:"averagePrice"`
-	Commission    int                       `json:"commission"`
-	PurchasedDate string                    `json:"purchasedDate"`
-	Instrument    StoredWatchlistInstrument `json:"instrument"`
-	Status        string                    `json:"status"`
-}
-
-// StoredWatchlistInstrument is an investment instrument in a user's watchlist.
-type StoredWatchlistInstrument struct {
-	Symbol      string `json:"symbol"`
-	Description string `json:"description"`
-	AssetType   string `json:"assetType"`
+	Symbol      string    `json:"symbol"`
+	Description string    `json:"description,omitempty"`
+	AssetType   AssetType `json:"assetType"`
 }
 
 // WatchlistService allows CRUD operations on watchlists in a user's account.
@@ -61,13 +42,13 @@ type WatchlistService struct {
 
 // CreateWatchlist adds a new watchlist to a user's account
 // See https://developer.tdameritrade.com/watchlist/apis/post/accounts/%7BaccountId%7D/watchlists-0
-func (s *WatchlistService) CreateWatchlist(ctx context.Context, accountID string, newWatchlist *NewWatchlist) (*Response, error) {
+func (s *WatchlistService) CreateWatchlist(ctx context.Context, accountID string, watchlist *Watchlist) (*Response, error) {
 	if accountID == "" {
 		return nil, fmt.Errorf("accountID cannot be empty")
 	}
 
 	u := fmt.Sprintf("accounts/%s/watchlists", accountID)
-	req, err := s.client.NewRequest("POST", u, newWatchlist)
+	req, err := s.client.NewRequest("POST", u, watchlist)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +78,7 @@ func (s *WatchlistService) DeleteWatchlist(ctx context.Context, accountID, watch
 
 // GetWatchlist returns a single watchlist in a user's account
 // See https://developer.tdameritrade.com/watchlist/apis/get/accounts/%7BaccountId%7D/watchlists/%7BwatchlistId%7D-0
-func (s *WatchlistService) GetWatchlist(ctx context.Context, accountID, watchlistID string) (*StoredWatchlist, *Response, error) {
+func (s *WatchlistService) GetWatchlist(ctx context.Context, accountID, watchlistID string) (*Watchlist, *Response, error) {
 	if accountID == "" {
 		return nil, nil, fmt.Errorf("accountID cannot be empty")
 	}
@@ -112,28 +93,34 @@ func (s *WatchlistService) GetWatchlist(ctx context.Context, accountID, watchlis
 		return nil, nil, err
 	}
 
-	storedWatchlist := new(StoredWatchlist)
-	resp, err := s.client.Do(ctx, req, storedWatchlist)
-	return storedWatchlist, resp, err
+	watchlist := new(Watchlist)
+	resp, err := s.client.Do(ctx, req, watchlist)
+	if err != nil {
+		return nil, resp, err
+	}
+	return watchlist, resp, nil
 }
 
-// GetAllWatchlists returns all watchlists for all of a user's linked accounts.
+// GetWatchlistsForAllAccounts returns all watchlists for all of a user's linked accounts.
 // See https://developer.tdameritrade.com/watchlist/apis/get/accounts/watchlists-0
-func (s *WatchlistService) GetAllWatchlists(ctx context.Context) (*[]StoredWatchlist, *Response, error) {
+func (s *WatchlistService) GetWatchlistsForAllAccounts(ctx context.Context) ([]*Watchlist, *Response, error) {
 	u := "accounts/watchlists"
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	watchlists := new([]StoredWatchlist)
-	resp, err := s.client.Do(ctx, req, watchlists)
-	return watchlists, resp, err
+	var watchlists []*Watchlist
+	resp, err := s.client.Do(ctx, req, &watchlists)
+	if err != nil {
+		return nil, resp, err
+	}
+	return watchlists, resp, nil
 }
 
-// GetAllWatchlistsForAccount returns all watchlists for a single user account.
+// GetWatchlistsForAccount returns all watchlists for a single user account.
 // See https://developer.tdameritrade.com/watchlist/apis/get/accounts/%7BaccountId%7D/watchlists-0
-func (s *WatchlistService) GetAllWatchlistsForAccount(ctx context.Context, accountID string) (*[]StoredWatchlist, *Response, error) {
+func (s *WatchlistService) GetWatchlistsForAccount(ctx context.Context, accountID string) ([]*Watchlist, *Response, error) {
 	if accountID == "" {
 		return nil, nil, fmt.Errorf("accountID cannot be empty")
 	}
@@ -144,15 +131,18 @@ func (s *WatchlistService) GetAllWatchlistsForAccount(ctx context.Context, accou
 		return nil, nil, err
 	}
 
-	watchlists := new([]StoredWatchlist)
-	resp, err := s.client.Do(ctx, req, watchlists)
-	return watchlists, resp, err
+	var watchlists []*Watchlist
+	resp, err := s.client.Do(ctx, req, &watchlists)
+	if err != nil {
+		return nil, resp, err
+	}
+	return watchlists, resp, nil
 }
 
 // ReplaceWatchlist replaces a watchlist in an account with a new watchlist.
 // It does not verify that symbols are valid.
 // See https://developer.tdameritrade.com/watchlist/apis/put/accounts/%7BaccountId%7D/watchlists/%7BwatchlistId%7D-0
-func (s *WatchlistService) ReplaceWatchlist(ctx context.Context, accountID, watchlistID string, newWatchlist *NewWatchlist) (*Response, error) {
+func (s *WatchlistService) ReplaceWatchlist(ctx context.Context, accountID, watchlistID string, watchlist *Watchlist) (*Response, error) {
 	if accountID == "" {
 		return nil, fmt.Errorf("accountID cannot be empty")
 	}
@@ -162,7 +152,7 @@ func (s *WatchlistService) ReplaceWatchlist(ctx context.Context, accountID, watc
 	}
 
 	u := fmt.Sprintf("accounts/%s/watchlists/%s", accountID, watchlistID)
-	req, err := s.client.NewRequest("PUT", u, newWatchlist)
+	req, err := s.client.NewRequest("PUT", u, watchlist)
 	if err != nil {
 		return nil, err
 	}
@@ -172,12 +162,13 @@ func (s *WatchlistService) ReplaceWatchlist(ctx context.Context, accountID, watc
 
 // UpdateWatchlist partially updates watchlist for a specific account.
 // Callers can:
-//  - change the watchlist's name
-//  - add to the beginning/end of a watchlist
-//  - update or delete items in a watchlist
+//   - change the watchlist's name
+//   - add to the beginning/end of a watchlist
+//   - update or delete items in a watchlist
+//
 // This method does not verify that the symbol or asset type are valid.
 // See https://developer.tdameritrade.com/watchlist/apis/patch/accounts/%7BaccountId%7D/watchlists/%7BwatchlistId%7D-0
-func (s *WatchlistService) UpdateWatchlist(ctx context.Context, accountID, watchlistID string, newWatchlist *NewWatchlist) (*Response, error) {
+func (s *WatchlistService) UpdateWatchlist(ctx context.Context, accountID, watchlistID string, watchlist *Watchlist) (*Response, error) {
 	if accountID == "" {
 		return nil, fmt.Errorf("accountID cannot be empty")
 	}
@@ -187,7 +178,7 @@ func (s *WatchlistService) UpdateWatchlist(ctx context.Context, accountID, watch
 	}
 
 	u := fmt.Sprintf("accounts/%s/watchlists/%s", accountID, watchlistID)
-	req, err := s.client.NewRequest("PATCH", u, newWatchlist)
+	req, err := s.client.NewRequest("PATCH", u, watchlist)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,97 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WatchlistDiff is the difference between two snapshots of a watchlist, its items being matched by symbol.
+// Items are in the order of the watchlist they are in.
+type WatchlistDiff struct {
+	Added   []WatchlistItem
+	Removed []WatchlistItem
+}
+
+// IsEmpty reports whether the watchlist did not change.
+func (d *WatchlistDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// DiffWatchlists returns the items added and removed from prev to curr. A nil prev or curr has no items.
+// Changes to items still in the watchlist, such as their quantity, are ignored.
+func DiffWatchlists(prev, curr *Watchlist) *WatchlistDiff {
+	prevSymbols, currSymbols := watchlistSymbols(prev), watchlistSymbols(curr)
+	diff := &WatchlistDiff{}
+	if curr != nil {
+		for _, item := range curr.WatchlistItems {
+			if !prevSymbols[item.Instrument.Symbol] {
+				diff.Added = append(diff.Added, item)
+			}
+		}
+	}
+	if prev != nil {
+		for _, item := range prev.WatchlistItems {
+			if !currSymbols[item.Instrument.Symbol] {
+				diff.Removed = append(diff.Removed, item)
+			}
+		}
+	}
+	return diff
+}
+
+func watchlistSymbols(w *Watchlist) map[string]bool {
+	symbols := map[string]bool{}
+	if w != nil {
+		for _, item := range w.WatchlistItems {
+			symbols[item.Instrument.Symbol] = true
+		}
+	}
+	return symbols
+}
+
+// PollWatchlist gets a watchlist every interval and sends the difference from the previous get on the returned
+// channel whenever symbols are added or removed, e.g. to subscribe to quotes of symbols added on the website.
+// The channel is closed once ctx is done.
+// An error getting the watchlist the first time is returned; later errors are ignored and the watchlist is got
+// again after interval.
+func (s *WatchlistService) PollWatchlist(ctx context.Context, accountID, watchlistID string, interval time.Duration) (<-chan *WatchlistDiff, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: interval must be positive, got %v", ErrInvalidParams, interval)
+	}
+	prev, _, err := s.GetWatchlist(ctx, accountID, watchlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *WatchlistDiff)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			curr, _, err := s.GetWatchlist(ctx, accountID, watchlistID)
+			if err != nil {
+				continue
+			}
+			diff := DiffWatchlists(prev, curr)
+			prev = curr
+			if diff.IsEmpty() {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- diff:
+			}
+		}
+	}()
+	return ch, nil
+}
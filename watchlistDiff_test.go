@@ -0,0 +1,98 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testWatchlist(symbols ...string) *Watchlist {
+	w := &Watchlist{Name: "theta"}
+	for _, symbol := range symbols {
+		w.WatchlistItems = append(w.WatchlistItems, WatchlistItem{Instrument: WatchlistInstrument{Symbol: symbol, AssetType: "EQUITY"}})
+	}
+	return w
+}
+
+func itemSymbols(items []WatchlistItem) []string {
+	var s []string
+	for _, item := range items {
+		s = append(s, item.Instrument.Symbol)
+	}
+	return s
+}
+
+func TestDiffWatchlists(t *testing.T) {
+	diff := DiffWatchlists(testWatchlist("SPY", "QQQ", "IWM"), testWatchlist("QQQ", "GLD", "SPY", "TLT"))
+	if got, want := itemSymbols(diff.Added), []string{"GLD", "TLT"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+	if got, want := itemSymbols(diff.Removed), []string{"IWM"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Removed = %v, want %v", got, want)
+	}
+
+	if diff := DiffWatchlists(nil, testWatchlist("SPY")); len(diff.Added) != 1 || len(diff.Removed) != 0 {
+		t.Errorf("unexpected diff from nil: %+v", diff)
+	}
+	if diff := DiffWatchlists(testWatchlist("SPY"), testWatchlist("SPY")); !diff.IsEmpty() {
+		t.Errorf("expected no difference, got %+v", diff)
+	}
+}
+
+func TestPollWatchlist(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	snapshots := [][]string{{"SPY"}, {"SPY"}, {"SPY", "QQQ"}, {"QQQ"}}
+	var gets int32
+	mux.HandleFunc("/accounts/123/watchlists/456", func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&gets, 1)) - 1
+		if n >= len(snapshots) {
+			n = len(snapshots) - 1
+		}
+		var items []string
+		for _, symbol := range snapshots[n] {
+			items = append(items, fmt.Sprintf(`{"instrument":{"symbol":%q}}`, symbol))
+		}
+		fmt.Fprintf(w, `{"name":"theta","watchlistItems":[%s]}`, strings.Join(items, ","))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := client.Watchlist.PollWatchlist(ctx, "123", "456", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("PollWatchlist returned error: %v", err)
+	}
+	for _, want := range []*WatchlistDiff{
+		{Added: []WatchlistItem{{Instrument: WatchlistInstrument{Symbol: "QQQ"}}}},
+		{Removed: []WatchlistItem{{Instrument: WatchlistInstrument{Symbol: "SPY"}}}},
+	} {
+		select {
+		case diff := <-ch:
+			if !reflect.DeepEqual(diff, want) {
+				t.Errorf("diff = %+v, want %+v", diff, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a diff")
+		}
+	}
+
+	cancel()
+	select {
+	case diff, ok := <-ch:
+		if ok {
+			t.Errorf("expected the channel to be closed, got %+v", diff)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after ctx was cancelled")
+	}
+
+	if _, err := client.Watchlist.PollWatchlist(context.Background(), "123", "456", 0); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams for a zero interval, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestWatchlistMethodsAndPaths(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	var gotMethod, gotPath string
+	var gotBody *Watchlist
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotBody = r.Method, r.URL.Path, nil
+		if r.ContentLength > 0 {
+			gotBody = new(Watchlist)
+			if err := json.NewDecoder(r.Body).Decode(gotBody); err != nil {
+				t.Errorf("could not decode body: %v", err)
+				return
+			}
+		}
+		switch r.URL.Path {
+		case "/accounts/123/watchlists/456":
+			fmt.Fprint(w, `{"name":"tech","watchlistId":"456","accountId":"123","watchlistItems":[{"sequenceId":1,"instrument":{"symbol":"AAPL","assetType":"EQUITY"}}]}`)
+		default:
+			fmt.Fprint(w, `[{"name":"tech","watchlistId":"456"}]`)
+		}
+	}
+	mux.HandleFunc("/accounts/watchlists", handler)
+	mux.HandleFunc("/accounts/123/watchlists", handler)
+	mux.HandleFunc("/accounts/123/watchlists/456", handler)
+
+	ctx := context.Background()
+	watchlist := &Watchlist{
+		Name: "tech",
+		WatchlistItems: []WatchlistItem{
+			{Instrument: WatchlistInstrument{Symbol: "AAPL", AssetType: "EQUITY"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		call       func() error
+		wantMethod string
+		wantPath   string
+		wantBody   bool
+	}{
+		{"CreateWatchlist", func() error {
+			_, err := client.Watchlist.CreateWatchlist(ctx, "123", watchlist)
+			return err
+		}, "POST", "/accounts/123/watchlists", true},
+		{"GetWatchlist", func() error {
+			got, _, err := client.Watchlist.GetWatchlist(ctx, "123", "456")
+			if err == nil && (got.WatchlistID != "456" || got.WatchlistItems[0].SequenceID != 1) {
+				t.Errorf("unexpected watchlist: %+v", got)
+			}
+			return err
+		}, "GET", "/accounts/123/watchlists/456", false},
+		{"GetWatchlistsForAccount", func() error {
+			got, _, err := client.Watchlist.GetWatchlistsForAccount(ctx, "123")
+			if err == nil && (len(got) != 1 || got[0].Name != "tech") {
+				t.Errorf("unexpected watchlists: %+v", got)
+			}
+			return err
+		}, "GET", "/accounts/123/watchlists", false},
+		{"GetWatchlistsForAllAccounts", func() error {
+			got, _, err := client.Watchlist.GetWatchlistsForAllAccounts(ctx)
+			if err == nil && len(got) != 1 {
+				t.Errorf("unexpected watchlists: %+v", got)
+			}
+			return err
+		}, "GET", "/accounts/watchlists", false},
+		{"UpdateWatchlist", func() error {
+			_, err := client.Watchlist.UpdateWatchlist(ctx, "123", "456", watchlist)
+			return err
+		}, "PATCH", "/accounts/123/watchlists/456", true},
+		{"ReplaceWatchlist", func() error {
+			_, err := client.Watchlist.ReplaceWatchlist(ctx, "123", "456", watchlist)
+			return err
+		}, "PUT", "/accounts/123/watchlists/456", true},
+		{"DeleteWatchlist", func() error {
+			_, err := client.Watchlist.DeleteWatchlist(ctx, "123", "456")
+			return err
+		}, "DELETE", "/accounts/123/watchlists/456", false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.call(); err != nil {
+			t.Fatalf("%s returned error: %v", tt.name, err)
+		}
+		if gotMethod != tt.wantMethod || gotPath != tt.wantPath {
+			t.Errorf("%s: got %s %s, want %s %s", tt.name, gotMethod, gotPath, tt.wantMethod, tt.wantPath)
+		}
+		if tt.wantBody && (gotBody == nil || gotBody.Name != "tech" || gotBody.WatchlistItems[0].Instrument.Symbol != "AAPL") {
+			t.Errorf("%s: unexpected body: %+v", tt.name, gotBody)
+		}
+	}
+}
+
+func TestWatchlistRequiresIDs(t *testing.T) {
+	client, _, teardown := setup(t)
+	defer teardown()
+
+	if _, _, err := client.Watchlist.GetWatchlist(context.Background(), "123", ""); err == nil {
+		t.Error("expected error for empty watchlistID")
+	}
+	if _, err := client.Watchlist.DeleteWatchlist(context.Background(), "", "456"); err == nil {
+		t.Error("expected error for empty accountID")
+	}
+}